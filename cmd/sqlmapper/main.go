@@ -1,33 +1,64 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/mstgnz/sqlmapper"
-	"github.com/mstgnz/sqlmapper/mysql"
-	"github.com/mstgnz/sqlmapper/oracle"
-	"github.com/mstgnz/sqlmapper/postgres"
-	"github.com/mstgnz/sqlmapper/sqlite"
-	"github.com/mstgnz/sqlmapper/sqlserver"
+	_ "github.com/mstgnz/sqlmapper/dialects"
+	"github.com/mstgnz/sqlmapper/monitoring"
+	"github.com/mstgnz/sqlmapper/stream"
 )
 
 func main() {
 	filePath := flag.String("file", "", "SQL dump dosyasının yolu")
 	targetDB := flag.String("to", "", "Hedef veritabanı tipi (mysql, postgres, sqlite, oracle, sqlserver)")
+	strict := flag.Bool("strict", false, "Doğrulama hatalarında çıktı oluşturmadan çık")
+	compress := flag.Bool("gzip", false, "Çıktı dosyasını gzip ile sıkıştır")
+	outDir := flag.String("out-dir", "", "Çıktıyı her nesne için ayrı bir dosya olarak bu dizine yaz")
+	metricsAddr := flag.String("metrics-addr", "", "Dönüşüm süresince Prometheus metriklerini bu adreste /metrics altında sun (ör. :9090)")
 	flag.Parse()
 
 	if *filePath == "" || *targetDB == "" {
 		fmt.Println("Kullanım: sqlmapper --file=<dosya_yolu> --to=<hedef_db>")
 		fmt.Println("Örnek: sqlmapper --file=postgres.sql --to=mysql")
+		fmt.Println("Sıkıştırılmış dosyalar (.gz, .bz2, zlib) otomatik olarak açılır")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	content, err := os.ReadFile(*filePath)
+	if *compress && *outDir != "" {
+		fmt.Println("--gzip ve --out-dir birlikte kullanılamaz")
+		os.Exit(1)
+	}
+
+	metrics := monitoring.NewMetricsCollector()
+	if *metricsAddr != "" {
+		addr, err := serveMetrics(*metricsAddr, metrics)
+		if err != nil {
+			fmt.Printf("Metrik sunucusu başlatılamadı: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Metrikler http://%s/metrics adresinde sunuluyor\n", addr)
+
+		sampler := monitoring.NewSampler(metrics, monitoring.SamplerConfig{Interval: time.Second})
+		if err := sampler.Start(); err != nil {
+			fmt.Printf("Metrik örnekleyici başlatılamadı: %v\n", err)
+			os.Exit(1)
+		}
+		defer sampler.Stop()
+	}
+	start := time.Now()
+
+	content, err := readInput(*filePath)
 	if err != nil {
 		fmt.Printf("Dosya okuma hatası: %v\n", err)
 		os.Exit(1)
@@ -51,21 +82,48 @@ func main() {
 		os.Exit(1)
 	}
 
-	schema, err := sourceParser.Parse(string(content))
+	schema, err := sqlmapper.ParseSource(sourceParser, *filePath, string(content))
 	if err != nil {
-		fmt.Printf("Parse hatası: %v\n", err)
+		recordError(metrics, "parse")
+		reportParseError(os.Stderr, err)
 		os.Exit(1)
 	}
+	recordSchema(metrics, schema, time.Since(start))
+
+	// Diagnostics are only reported by default, since some of them come from
+	// statements the parsers cannot read yet rather than from the schema itself
+	if reportDiagnostics(os.Stderr, sqlmapper.Validate(schema)) && *strict {
+		recordError(metrics, "validation")
+		fmt.Println("Şema doğrulama hataları bulundu (--strict)")
+		os.Exit(1)
+	}
+
+	if *outDir != "" {
+		count, err := writeDirectory(*outDir, *targetDB, schema)
+		if err != nil {
+			recordError(metrics, "write")
+			fmt.Printf("Dizin yazma hatası: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Dönüşüm başarılı! %d nesne dosyası %s dizinine yazıldı (tümünü çalıştırmak için %s)\n",
+			count, *outDir, filepath.Join(*outDir, stream.IncludeFile))
+		return
+	}
 
 	result, err := targetParser.Generate(schema)
 	if err != nil {
+		recordError(metrics, "generate")
 		fmt.Printf("SQL oluşturma hatası: %v\n", err)
 		os.Exit(1)
 	}
 
 	outputPath := createOutputPath(*filePath, *targetDB)
-	err = os.WriteFile(outputPath, []byte(result), 0644)
+	if *compress {
+		outputPath += ".gz"
+	}
+	err = writeOutput(outputPath, result, *compress)
 	if err != nil {
+		recordError(metrics, "write")
 		fmt.Printf("Dosya yazma hatası: %v\n", err)
 		os.Exit(1)
 	}
@@ -84,33 +142,153 @@ func detectSourceType(content string) string {
 		return "sqlserver"
 	case strings.Contains(content, "SERIAL"):
 		return "postgres"
-	case strings.Contains(content, "NUMBER("):
+	case strings.Contains(content, "NUMBER("), strings.Contains(content, "VARCHAR2"):
 		return "oracle"
 	default:
 		return ""
 	}
 }
 
+// createParser returns the parser of a registered dialect, or nil if the
+// database type is unknown
 func createParser(dbType string) sqlmapper.Parser {
-	switch strings.ToLower(dbType) {
-	case "mysql":
-		return mysql.NewMySQL()
-	case "postgres":
-		return postgres.NewPostgreSQL()
-	case "sqlite":
-		return sqlite.NewSQLite()
-	case "oracle":
-		return oracle.NewOracle()
-	case "sqlserver":
-		return sqlserver.NewSQLServer()
-	default:
+	parser, err := sqlmapper.NewParser(sqlmapper.DatabaseType(dbType))
+	if err != nil {
 		return nil
 	}
+	return parser
+}
+
+// reportDiagnostics writes the validation diagnostics, one per line, and
+// reports whether any of them is an error
+func reportDiagnostics(w io.Writer, diagnostics []sqlmapper.Diagnostic) bool {
+	for _, d := range diagnostics {
+		fmt.Fprintln(w, d.String())
+	}
+	return sqlmapper.HasErrors(diagnostics)
+}
+
+// reportParseError writes a parse error in compiler style, "file:line:col: message",
+// followed by the offending statement
+func reportParseError(w io.Writer, err error) {
+	var parseErr *sqlmapper.ParseError
+	if !errors.As(err, &parseErr) || !parseErr.Pos.IsValid() {
+		fmt.Fprintf(w, "Parse hatası: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(w, "%s: hata: %v\n", parseErr.Pos, parseErr.Err)
+	if parseErr.Snippet != "" {
+		fmt.Fprintf(w, "\t%s\n", parseErr.Snippet)
+	}
+}
+
+// readInput reads a dump file, decompressing gzip, bzip2 and zlib content
+// detected from its magic bytes
+func readInput(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, _, err := stream.Decompress(file)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
 }
 
+// writeOutput writes the generated SQL to a file, compressed with gzip if requested
+func writeOutput(path, content string, compress bool) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	compression := stream.NoCompression
+	if compress {
+		compression = stream.Gzip
+	}
+	w, err := stream.Compress(file, compression)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if _, err := io.WriteString(w, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// serveMetrics serves the metrics in the Prometheus text format at /metrics on
+// addr until the program exits, and returns the address it listens on
+func serveMetrics(addr string, metrics *monitoring.MetricsCollector) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.PrometheusHandler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return listener.Addr(), nil
+}
+
+// recordSchema records the parsed objects of a schema and the time it took to
+// parse them
+func recordSchema(metrics *monitoring.MetricsCollector, schema *sqlmapper.Schema, elapsed time.Duration) {
+	objects := len(schema.Tables) + len(schema.Views) + len(schema.Functions) + len(schema.Procedures) +
+		len(schema.Triggers) + len(schema.Sequences) + len(schema.Types)
+	for i := 0; i < objects; i++ {
+		metrics.IncrementProcessedObjects()
+	}
+	metrics.RecordProcessingTime(elapsed)
+}
+
+// recordError counts a failed step of the conversion
+func recordError(metrics *monitoring.MetricsCollector, errorType string) {
+	metrics.IncrementFailedOperations()
+	metrics.IncrementErrorCount(errorType)
+}
+
+// writeDirectory writes the schema into a directory with one file per object
+// and an include script, and returns the number of object files
+func writeDirectory(dir, targetDB string, schema *sqlmapper.Schema) (int, error) {
+	parser, err := stream.NewStreamParser(sqlmapper.DatabaseType(targetDB))
+	if err != nil {
+		return 0, err
+	}
+	files, err := stream.GenerateDirectory(parser, schema, dir)
+	if err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// compressedExts are the file extensions of compressed dumps
+var compressedExts = []string{".gz", ".bz2", ".zz", ".zlib"}
+
+// createOutputPath names the output after the input and the target database,
+// dropping the extension of a compressed input: dump.sql.gz becomes dump_mysql.sql
 func createOutputPath(inputPath, targetDB string) string {
 	dir := filepath.Dir(inputPath)
 	filename := filepath.Base(inputPath)
+	for _, ext := range compressedExts {
+		if trimmed := strings.TrimSuffix(filename, ext); trimmed != filename && trimmed != "" {
+			filename = trimmed
+			break
+		}
+	}
 	ext := filepath.Ext(filename)
 	name := strings.TrimSuffix(filename, ext)
 	return filepath.Join(dir, fmt.Sprintf("%s_%s%s", name, targetDB, ext))
@@ -1,9 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/monitoring"
 )
 
 func TestDetectSourceType(t *testing.T) {
@@ -37,6 +47,11 @@ func TestDetectSourceType(t *testing.T) {
 			content: "CREATE TABLE test (id NUMBER(10));",
 			want:    "oracle",
 		},
+		{
+			name:    "Oracle VARCHAR2 tespiti",
+			content: "CREATE TABLE test (name VARCHAR2(50));",
+			want:    "oracle",
+		},
 		{
 			name:    "Bilinmeyen veritabanı",
 			content: "CREATE TABLE test (id INT);",
@@ -78,6 +93,18 @@ func TestCreateOutputPath(t *testing.T) {
 			targetDB:  "sqlite",
 			want:      "dump_sqlite.txt",
 		},
+		{
+			name:      "Gzip dosyası",
+			inputPath: "dump.sql.gz",
+			targetDB:  "mysql",
+			want:      "dump_mysql.sql",
+		},
+		{
+			name:      "Bzip2 dosyası",
+			inputPath: "dump.sql.bz2",
+			targetDB:  "oracle",
+			want:      "dump_oracle.sql",
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,3 +245,244 @@ CREATE TABLE users (
 		})
 	}
 }
+
+// exampleFiles are the dumps shipped in examples/files that the CLI converts
+var exampleFiles = []string{"mysql.sql", "postgres.sql", "sqlite.sql", "sqlserver.sql", "oracle.sql"}
+
+func TestExampleFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("süreç başlatır")
+	}
+	targets := []string{"mysql", "postgres", "sqlite", "oracle", "sqlserver"}
+
+	for _, file := range exampleFiles {
+		content, err := os.ReadFile(filepath.Join("..", "..", "examples", "files", file))
+		if err != nil {
+			t.Fatalf("Örnek dosya okunamadı: %v", err)
+		}
+		inputPath := filepath.Join(t.TempDir(), file)
+		if err := os.WriteFile(inputPath, content, 0644); err != nil {
+			t.Fatalf("Test dosyası oluşturulamadı: %v", err)
+		}
+
+		for _, target := range targets {
+			t.Run(file+"->"+target, func(t *testing.T) {
+				// main çağıran yardımcı testi ayrı bir süreçte çalıştır
+				cmd := exec.Command(os.Args[0], "-test.run=^TestExampleFilesHelper$")
+				cmd.Env = append(os.Environ(),
+					"SQLMAPPER_CLI_FILE="+inputPath,
+					"SQLMAPPER_CLI_TO="+target,
+				)
+				if output, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("Dönüşüm başarısız: %v\n%s", err, output)
+				}
+
+				outputPath := createOutputPath(inputPath, target)
+				result, err := os.ReadFile(outputPath)
+				if err != nil {
+					t.Fatalf("Çıktı dosyası oluşturulmadı: %v", err)
+				}
+				if !strings.Contains(strings.ToUpper(string(result)), "CREATE TABLE") {
+					t.Errorf("Çıktıda tablo bulunamadı: %s", outputPath)
+				}
+			})
+		}
+	}
+}
+
+// TestExampleFilesHelper runs the CLI in the process started by TestExampleFiles
+func TestExampleFilesHelper(t *testing.T) {
+	file := os.Getenv("SQLMAPPER_CLI_FILE")
+	if file == "" {
+		t.Skip("TestExampleFiles tarafından çalıştırılır")
+	}
+	os.Args = []string{"sqlmapper", "--file=" + file, "--to=" + os.Getenv("SQLMAPPER_CLI_TO")}
+	main()
+}
+
+func TestCompressedInputOutput(t *testing.T) {
+	testSQL := "CREATE TABLE users (id SERIAL PRIMARY KEY);\n"
+	tmpDir := t.TempDir()
+
+	// Gzip ile sıkıştırılmış girdi dosyası
+	inputPath := filepath.Join(tmpDir, "test.sql.gz")
+	if err := writeOutput(inputPath, testSQL, true); err != nil {
+		t.Fatalf("Test dosyası oluşturulamadı: %v", err)
+	}
+
+	raw, err := os.ReadFile(inputPath)
+	if err != nil {
+		t.Fatalf("Test dosyası okunamadı: %v", err)
+	}
+	if !bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		t.Errorf("Dosya gzip ile sıkıştırılmamış: %q", raw)
+	}
+
+	content, err := readInput(inputPath)
+	if err != nil {
+		t.Fatalf("Sıkıştırılmış dosya okunamadı: %v", err)
+	}
+	if string(content) != testSQL {
+		t.Errorf("readInput() = %q, beklenilen %q", content, testSQL)
+	}
+
+	// Sıkıştırılmamış dosyalar olduğu gibi okunur
+	plainPath := filepath.Join(tmpDir, "test.sql")
+	if err := writeOutput(plainPath, testSQL, false); err != nil {
+		t.Fatalf("Test dosyası oluşturulamadı: %v", err)
+	}
+	content, err = readInput(plainPath)
+	if err != nil {
+		t.Fatalf("Dosya okunamadı: %v", err)
+	}
+	if string(content) != testSQL {
+		t.Errorf("readInput() = %q, beklenilen %q", content, testSQL)
+	}
+
+	if _, err := readInput(filepath.Join(tmpDir, "yok.sql")); err == nil {
+		t.Errorf("Olmayan dosya için hata bekleniyordu")
+	}
+}
+
+func TestWriteDirectory(t *testing.T) {
+	parser := createParser("postgres")
+	schema, err := parser.Parse("CREATE TABLE users (id SERIAL PRIMARY KEY, name VARCHAR(100));\nCREATE VIEW names AS SELECT name FROM users;\n")
+	if err != nil {
+		t.Fatalf("Parse işlemi başarısız: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "cikti")
+	count, err := writeDirectory(dir, "mysql", schema)
+	if err != nil {
+		t.Fatalf("Dizin yazılamadı: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("writeDirectory() = %d, beklenilen 2", count)
+	}
+
+	include, err := os.ReadFile(filepath.Join(dir, "00_all.sql"))
+	if err != nil {
+		t.Fatalf("Betik okunamadı: %v", err)
+	}
+	if !strings.Contains(string(include), "source tables/users.sql\nsource views/names.sql\n") {
+		t.Errorf("Beklenmeyen betik içeriği: %q", include)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manifest.txt")); err != nil {
+		t.Errorf("Manifest dosyası oluşturulmadı: %v", err)
+	}
+
+	if _, err := writeDirectory(dir, "invalid", schema); err == nil {
+		t.Errorf("Geçersiz hedef için hata bekleniyordu")
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	metrics := monitoring.NewMetricsCollector()
+	addr, err := serveMetrics("127.0.0.1:0", metrics)
+	if err != nil {
+		t.Fatalf("Metrik sunucusu başlatılamadı: %v", err)
+	}
+
+	parser := createParser("postgres")
+	schema, err := parser.Parse("CREATE TABLE users (id SERIAL PRIMARY KEY);\n")
+	if err != nil {
+		t.Fatalf("Parse işlemi başarısız: %v", err)
+	}
+	recordSchema(metrics, schema, time.Millisecond)
+	recordError(metrics, "generate")
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics")
+	if err != nil {
+		t.Fatalf("Metrikler alınamadı: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, want := range []string{
+		"sqlmapper_objects_processed_total 1\n",
+		"sqlmapper_failed_operations_total 1\n",
+		"sqlmapper_error_count{type=\"generate\"} 1\n",
+		"sqlmapper_processing_seconds_count 1\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Metrik çıktısında %q bulunamadı:\n%s", want, body)
+		}
+	}
+
+	if _, err := serveMetrics(addr.String(), metrics); err == nil {
+		t.Errorf("Kullanımdaki adres için hata bekleniyordu")
+	}
+}
+
+func TestReportDiagnostics(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics []sqlmapper.Diagnostic
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "Sorun yok",
+		},
+		{
+			name: "Yalnızca uyarı",
+			diagnostics: []sqlmapper.Diagnostic{
+				{Severity: sqlmapper.SeverityWarning, Code: sqlmapper.CodeDuplicateIndex, Path: "tables.b.indexes.idx", Message: "index name idx is also used on table a"},
+			},
+			want: "warning [DUPLICATE_INDEX] tables.b.indexes.idx: index name idx is also used on table a\n",
+		},
+		{
+			name: "Hata",
+			diagnostics: []sqlmapper.Diagnostic{
+				{Severity: sqlmapper.SeverityError, Code: sqlmapper.CodeUnknownRefTable, Path: "tables.a.constraints.fk", Message: "foreign key references unknown table b"},
+			},
+			want:    "error [UNKNOWN_REF_TABLE] tables.a.constraints.fk: foreign key references unknown table b\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if got := reportDiagnostics(&buf, tt.diagnostics); got != tt.wantErr {
+				t.Errorf("reportDiagnostics() = %v, beklenilen %v", got, tt.wantErr)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("reportDiagnostics() çıktısı = %q, beklenilen %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReportParseError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Konumlu hata",
+			err: sqlmapper.NewParseError(
+				sqlmapper.Position{File: "dump.sql", Line: 42, Column: 5, Offset: 1200},
+				"CREATE TABLE broken",
+				errors.New("error parsing CREATE TABLE: invalid CREATE TABLE statement"),
+			),
+			want: "dump.sql:42:5: hata: error parsing CREATE TABLE: invalid CREATE TABLE statement\n\tCREATE TABLE broken\n",
+		},
+		{
+			name: "Konumsuz hata",
+			err:  errors.New("empty content"),
+			want: "Parse hatası: empty content\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reportParseError(&buf, tt.err)
+			if buf.String() != tt.want {
+				t.Errorf("reportParseError() çıktısı = %q, beklenilen %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
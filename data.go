@@ -0,0 +1,282 @@
+package sqlmapper
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a data value kept as a SQL expression, such as CURRENT_TIMESTAMP or
+// a function call, and written to the generated SQL unchanged.
+//
+// The other value types of Row.Values are nil for NULL, bool, int64, float64,
+// string, []byte for binary data and time.Time for dates and timestamps.
+type Expr string
+
+// timeLayouts are the date and timestamp formats recognized in literals
+var timeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999 Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z07:00",
+}
+
+// ParseTimeLiteral parses the text of a date or timestamp literal in ISO 8601
+// form, such as "2024-03-01" or "2024-03-01 10:30:00.5+02:00". Values without
+// a time zone are returned in UTC.
+func ParseTimeLiteral(text string) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FormatTimeLiteral formats a time as "2006-01-02" when it has no time of day,
+// and as "2006-01-02 15:04:05" with fractional seconds otherwise. The offset is
+// appended for times outside UTC.
+func FormatTimeLiteral(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 && t.Location() == time.UTC {
+		return t.Format("2006-01-02")
+	}
+	text := t.Format("2006-01-02 15:04:05.999999999")
+	if t.Location() != time.UTC {
+		text += t.Format("-07:00")
+	}
+	return text
+}
+
+// QuoteString quotes a string as a standard SQL literal, doubling single quotes
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// FormatLiteral encodes a data value as a standard SQL literal: NULL, numbers,
+// TRUE and FALSE, quoted strings, X'...' binary strings and quoted ISO dates.
+// Dialects override the encodings their syntax differs in.
+func FormatLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		return v.String()
+	case string:
+		return QuoteString(v)
+	case []byte:
+		return "X'" + strings.ToUpper(hex.EncodeToString(v)) + "'"
+	case time.Time:
+		return QuoteString(FormatTimeLiteral(v))
+	case Expr:
+		return string(v)
+	}
+	return QuoteString(fmt.Sprint(value))
+}
+
+// AppendRows adds the rows of an INSERT statement to the data of a table. The
+// table is looked up case-insensitively; when the schema does not define it, a
+// table without columns is added to hold the data.
+//
+// Values are stored under the table's column names. Without a column list the
+// values are taken in the order of the table's columns, or stored under their
+// 1-based position when the table's columns are unknown. String values of date
+// and timestamp columns are converted to time.Time.
+//
+// Parameters:
+//   - schema: The schema to add the data to
+//   - tableSchema: The schema qualifier of the table, or empty
+//   - tableName: The table name
+//   - columns: The column list of the INSERT statement, or nil
+//   - rows: The decoded values, one slice per row
+//
+// Returns:
+//   - error: An error if a row does not match the column count
+func AppendRows(schema *Schema, tableSchema, tableName string, columns []string, rows [][]interface{}) error {
+	table := FindTable(schema, tableSchema, tableName)
+	if table == nil {
+		schema.Tables = append(schema.Tables, Table{Name: tableName, Schema: tableSchema})
+		table = &schema.Tables[len(schema.Tables)-1]
+	}
+
+	keys := make([]string, len(columns))
+	types := make([]string, len(columns))
+	for i, name := range columns {
+		keys[i] = name
+		for _, column := range table.Columns {
+			if normalizeIdentifier(column.Name) == normalizeIdentifier(name) {
+				keys[i], types[i] = column.Name, column.DataType
+				break
+			}
+		}
+	}
+	if len(columns) == 0 {
+		for _, column := range table.Columns {
+			keys = append(keys, column.Name)
+			types = append(types, column.DataType)
+		}
+	}
+
+	for _, values := range rows {
+		if len(keys) > 0 && len(values) != len(keys) {
+			return fmt.Errorf("INSERT into %s has %d columns but %d values", tableName, len(keys), len(values))
+		}
+
+		row := Row{Values: make(map[string]interface{}, len(values))}
+		for i, value := range values {
+			if len(keys) == 0 {
+				row.Values[strconv.Itoa(i+1)] = value
+				continue
+			}
+			if text, ok := value.(string); ok && isTemporalType(types[i]) {
+				if t, ok := ParseTimeLiteral(text); ok {
+					value = t
+				}
+			}
+			row.Values[keys[i]] = value
+		}
+		table.Data = append(table.Data, row)
+	}
+	return nil
+}
+
+// InsertStatements renders the data rows of a table as INSERT statements
+// without terminators. Consecutive rows with the same columns are combined
+// into multi-row statements of at most batchSize rows; format encodes a value
+// as a literal of the target dialect.
+//
+// Parameters:
+//   - table: The table whose data is rendered
+//   - batchSize: The maximum number of rows per statement
+//   - format: The literal encoder of the target dialect
+//
+// Returns:
+//   - []string: The INSERT statements
+func InsertStatements(table Table, batchSize int, format func(interface{}) string) []string {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	var statements []string
+	var columns []string
+	var values []string
+	flush := func() {
+		if len(values) == 0 {
+			return
+		}
+		stmt := "INSERT INTO " + table.Name
+		if !isPositional(columns) {
+			stmt += " (" + strings.Join(columns, ", ") + ")"
+		}
+		statements = append(statements, stmt+" VALUES\n"+strings.Join(values, ",\n"))
+		values = values[:0]
+	}
+
+	for _, row := range table.Data {
+		rowColumns := rowColumns(table, row)
+		if len(values) >= batchSize || strings.Join(rowColumns, "\x00") != strings.Join(columns, "\x00") {
+			flush()
+		}
+		columns = rowColumns
+
+		literals := make([]string, len(columns))
+		for i, name := range columns {
+			literals[i] = format(row.Values[name])
+		}
+		values = append(values, "("+strings.Join(literals, ", ")+")")
+	}
+	flush()
+
+	return statements
+}
+
+// FindTable returns the table with the given name, or nil. Names are compared
+// case-insensitively and without quotes; a schema qualifier only has to match
+// when both the table and the lookup have one.
+//
+// Parameters:
+//   - schema: The schema holding the table
+//   - tableSchema: The schema qualifier of the lookup, or empty
+//   - name: The table name
+//
+// Returns:
+//   - *Table: The table in schema.Tables, or nil if there is none
+func FindTable(schema *Schema, tableSchema, name string) *Table {
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		if tableSchema != "" && normalizeIdentifier(table.Name) == normalizeIdentifier(tableSchema+"."+name) {
+			return table // Parsers that keep the qualifier in the name
+		}
+		if normalizeIdentifier(table.Name) != normalizeIdentifier(name) {
+			continue
+		}
+		if table.Schema == "" || tableSchema == "" || normalizeIdentifier(table.Schema) == normalizeIdentifier(tableSchema) {
+			return table
+		}
+	}
+	return nil
+}
+
+// rowColumns returns the columns of a row in table order, followed by values
+// of unknown columns in sorted order
+func rowColumns(table Table, row Row) []string {
+	columns := make([]string, 0, len(row.Values))
+	known := make(map[string]bool, len(table.Columns))
+	for _, column := range table.Columns {
+		known[column.Name] = true
+		if _, ok := row.Values[column.Name]; ok {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	var extra []string
+	for name := range row.Values {
+		if !known[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool {
+		a, errA := strconv.Atoi(extra[i])
+		b, errB := strconv.Atoi(extra[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return extra[i] < extra[j]
+	})
+	return append(columns, extra...)
+}
+
+// isPositional reports whether the columns are the 1-based positions used for
+// rows of tables with unknown columns
+func isPositional(columns []string) bool {
+	for i, name := range columns {
+		if name != strconv.Itoa(i+1) {
+			return false
+		}
+	}
+	return len(columns) > 0
+}
+
+// isTemporalType reports whether a data type holds dates or timestamps
+func isTemporalType(dataType string) bool {
+	upper := strings.ToUpper(dataType)
+	return strings.Contains(upper, "DATE") || strings.HasPrefix(upper, "TIMESTAMP")
+}
@@ -0,0 +1,88 @@
+package sqlmapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatLiteral(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "NULL"},
+		{true, "TRUE"},
+		{int64(-7), "-7"},
+		{1.25, "1.25"},
+		{"it's", "'it''s'"},
+		{[]byte{0xCA, 0xFE}, "X'CAFE'"},
+		{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "'2024-03-01'"},
+		{time.Date(2024, 3, 1, 10, 30, 0, 500000000, time.UTC), "'2024-03-01 10:30:00.5'"},
+		{time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("", 2*3600)), "'2024-03-01 10:30:00+02:00'"},
+		{Expr("CURRENT_TIMESTAMP"), "CURRENT_TIMESTAMP"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FormatLiteral(tt.value))
+	}
+}
+
+func TestParseTimeLiteral(t *testing.T) {
+	value, ok := ParseTimeLiteral("2024-03-01 10:30:00.5+02:00")
+	assert.True(t, ok)
+	assert.True(t, value.Equal(time.Date(2024, 3, 1, 8, 30, 0, 500000000, time.UTC)))
+
+	_, ok = ParseTimeLiteral("yesterday")
+	assert.False(t, ok)
+}
+
+func TestAppendRows(t *testing.T) {
+	schema := &Schema{Tables: []Table{{
+		Name: "Users",
+		Columns: []Column{
+			{Name: "id", DataType: "INT"},
+			{Name: "created", DataType: "DATETIME"},
+		},
+	}}}
+
+	err := AppendRows(schema, "", "users", []string{"ID", "created"}, [][]interface{}{{int64(1), "2024-03-01 10:30:00"}})
+	assert.NoError(t, err)
+	assert.NoError(t, AppendRows(schema, "", "users", nil, [][]interface{}{{int64(2), nil}}))
+	assert.Error(t, AppendRows(schema, "", "users", nil, [][]interface{}{{int64(3)}}))
+
+	users := schema.Tables[0]
+	assert.Equal(t, []Row{
+		{Values: map[string]interface{}{"id": int64(1), "created": time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)}},
+		{Values: map[string]interface{}{"id": int64(2), "created": nil}},
+	}, users.Data)
+
+	// Tables the schema does not define are added to hold the data
+	assert.NoError(t, AppendRows(schema, "audit", "log", nil, [][]interface{}{{"a", int64(1)}}))
+	assert.Len(t, schema.Tables, 2)
+	assert.Equal(t, "audit", schema.Tables[1].Schema)
+	assert.Equal(t, map[string]interface{}{"1": "a", "2": int64(1)}, schema.Tables[1].Data[0].Values)
+}
+
+func TestInsertStatements(t *testing.T) {
+	table := Table{
+		Name:    "users",
+		Columns: []Column{{Name: "id"}, {Name: "name"}},
+		Data: []Row{
+			{Values: map[string]interface{}{"id": int64(1), "name": "a"}},
+			{Values: map[string]interface{}{"id": int64(2), "name": "b"}},
+			{Values: map[string]interface{}{"id": int64(3), "name": "c"}},
+			{Values: map[string]interface{}{"id": int64(4)}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"INSERT INTO users (id, name) VALUES\n(1, 'a'),\n(2, 'b')",
+		"INSERT INTO users (id, name) VALUES\n(3, 'c')",
+		"INSERT INTO users (id) VALUES\n(4)",
+	}, InsertStatements(table, 2, FormatLiteral))
+
+	positional := Table{Name: "log", Data: []Row{{Values: map[string]interface{}{"1": "a", "2": nil}}}}
+	assert.Equal(t, []string{"INSERT INTO log VALUES\n('a', NULL)"}, InsertStatements(positional, 100, FormatLiteral))
+}
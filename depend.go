@@ -0,0 +1,375 @@
+package sqlmapper
+
+import (
+	"container/heap"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DeferredConstraint is a foreign key that takes part in a reference cycle.
+// It is left out of its CREATE TABLE statement and has to be added with
+// ALTER TABLE ... ADD CONSTRAINT once all tables exist.
+type DeferredConstraint struct {
+	Table      string
+	Constraint Constraint
+}
+
+// nodeKind identifies the kind of schema object a dependency node represents
+type nodeKind int
+
+const (
+	sequenceNode nodeKind = iota
+	tableNode
+	viewNode
+	triggerNode
+)
+
+// dependencyNode is a schema object in the dependency graph
+type dependencyNode struct {
+	kind nodeKind
+	pos  int           // Position of the object in its schema slice
+	deps map[int]bool  // Nodes this object depends on
+	fks  map[int][]int // Referenced table node -> positions of the foreign keys
+}
+
+var identifierRe = regexp.MustCompile(`[A-Za-z_][\w$#]*(?:\s*\.\s*[A-Za-z_][\w$#]*)?`)
+
+// SortSchema returns a copy of the schema whose sequences, tables, views and
+// triggers are ordered so that every object comes after the objects it depends on.
+// Dependencies are taken from foreign keys (Constraint.RefTable), names
+// referenced in view definitions, trigger tables, and sequences referenced in
+// column defaults and trigger bodies. Objects without dependencies between
+// them keep their original order.
+//
+// When foreign keys form a cycle, the constraints that close the cycle are
+// removed from their tables and returned as deferred constraints, to be added
+// after all tables are created. Self-references are not deferred.
+func SortSchema(schema *Schema) (*Schema, []DeferredConstraint) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	result := *schema
+	var nodes []dependencyNode
+	lookup := make(map[string]int)    // Lower-cased table and view names
+	sequences := make(map[string]int) // Lower-cased sequence names
+
+	register := func(names map[string]int, name, schemaName string, index int) {
+		names[strings.ToLower(name)] = index
+		if schemaName != "" {
+			names[strings.ToLower(schemaName+"."+name)] = index
+		}
+	}
+
+	for i, seq := range schema.Sequences {
+		nodes = append(nodes, dependencyNode{kind: sequenceNode, pos: i})
+		register(sequences, seq.Name, seq.Schema, len(nodes)-1)
+	}
+	for i, table := range schema.Tables {
+		nodes = append(nodes, dependencyNode{kind: tableNode, pos: i})
+		register(lookup, table.Name, table.Schema, len(nodes)-1)
+	}
+	for i, view := range schema.Views {
+		nodes = append(nodes, dependencyNode{kind: viewNode, pos: i})
+		register(lookup, view.Name, view.Schema, len(nodes)-1)
+	}
+	for i := range schema.Triggers {
+		nodes = append(nodes, dependencyNode{kind: triggerNode, pos: i})
+	}
+
+	resolve := func(names map[string]int, name string) (int, bool) {
+		name = strings.ToLower(strings.Join(strings.Fields(name), ""))
+		name = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(name)
+		if index, ok := names[name]; ok {
+			return index, true
+		}
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			index, ok := names[name[dot+1:]]
+			return index, ok
+		}
+		return 0, false
+	}
+
+	addDep := func(from, to int) {
+		if from == to {
+			return
+		}
+		if nodes[from].deps == nil {
+			nodes[from].deps = make(map[int]bool)
+		}
+		nodes[from].deps[to] = true
+	}
+
+	// Sequence references in free text, such as nextval('seq') or seq.NEXTVAL
+	addSequenceDeps := func(from int, text string) {
+		if text == "" || len(sequences) == 0 {
+			return
+		}
+		for _, ident := range identifierRe.FindAllString(text, -1) {
+			if index, ok := resolve(sequences, ident); ok {
+				addDep(from, index)
+				continue
+			}
+			// seq.NEXTVAL style references name the sequence before the dot
+			if dot := strings.Index(ident, "."); dot >= 0 {
+				if index, ok := resolve(sequences, ident[:dot]); ok {
+					addDep(from, index)
+				}
+			}
+		}
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+		switch node.kind {
+		case tableNode:
+			table := schema.Tables[node.pos]
+			for c, constraint := range table.Constraints {
+				if !isForeignKey(constraint) || constraint.RefTable == "" {
+					continue
+				}
+				ref, ok := resolve(lookup, constraint.RefTable)
+				if !ok || ref == i {
+					continue
+				}
+				addDep(i, ref)
+				if node.fks == nil {
+					node.fks = make(map[int][]int)
+				}
+				node.fks[ref] = append(node.fks[ref], c)
+			}
+			for _, column := range table.Columns {
+				addSequenceDeps(i, column.DefaultValue)
+			}
+		case viewNode:
+			for _, ident := range identifierRe.FindAllString(schema.Views[node.pos].Definition, -1) {
+				if ref, ok := resolve(lookup, ident); ok {
+					addDep(i, ref)
+				}
+			}
+		case triggerNode:
+			trigger := schema.Triggers[node.pos]
+			if ref, ok := resolve(lookup, trigger.Table); ok {
+				addDep(i, ref)
+			}
+			addSequenceDeps(i, trigger.Body)
+		}
+	}
+
+	// Kahn's algorithm, always emitting the ready node that came first in the input
+	dependents := make([][]int, len(nodes))
+	pending := make([]int, len(nodes))
+	for i, node := range nodes {
+		pending[i] = len(node.deps)
+		for dep := range node.deps {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	ready := &indexHeap{}
+	for i := range nodes {
+		if pending[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	emitted := make([]bool, len(nodes))
+	order := make([]int, 0, len(nodes))
+	deferred := make(map[int]map[int]bool) // Table node -> deferred constraint positions
+	var deferredList []DeferredConstraint
+	next := 0 // Lowest node that may not have been emitted yet
+
+	for len(order) < len(nodes) {
+		if ready.Len() == 0 {
+			// Every remaining node waits on another: break a foreign key cycle
+			for emitted[next] {
+				next++
+			}
+			broken := -1
+			cycle := findCycle(nodes, emitted)
+			for _, i := range cycle.members() {
+				if nodes[i].kind != tableNode {
+					continue
+				}
+				for ref := range nodes[i].fks {
+					if nodes[i].deps[ref] && cycle[ref] {
+						broken = i
+						break
+					}
+				}
+				if broken >= 0 {
+					break
+				}
+			}
+			if broken < 0 {
+				// Not caused by foreign keys; fall back to the input order
+				broken = next
+				pending[broken] = 0
+			} else {
+				table := schema.Tables[nodes[broken].pos]
+				if deferred[broken] == nil {
+					deferred[broken] = make(map[int]bool)
+				}
+				broke := make(map[int]bool)
+				for ref, positions := range nodes[broken].fks {
+					if !nodes[broken].deps[ref] || !cycle[ref] {
+						continue
+					}
+					delete(nodes[broken].deps, ref)
+					pending[broken]--
+					for _, c := range positions {
+						broke[c] = true
+						deferred[broken][c] = true
+					}
+				}
+				for c, constraint := range table.Constraints {
+					if broke[c] {
+						deferredList = append(deferredList, DeferredConstraint{Table: table.Name, Constraint: constraint})
+					}
+				}
+			}
+			if pending[broken] == 0 {
+				heap.Push(ready, broken)
+			}
+			continue
+		}
+
+		current := heap.Pop(ready).(int)
+		if emitted[current] {
+			continue
+		}
+		emitted[current] = true
+		order = append(order, current)
+
+		for _, dependent := range dependents[current] {
+			if emitted[dependent] || !nodes[dependent].deps[current] {
+				continue
+			}
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				heap.Push(ready, dependent)
+			}
+		}
+	}
+
+	result.Sequences = nil
+	result.Tables = nil
+	result.Views = nil
+	result.Triggers = nil
+	for _, i := range order {
+		node := nodes[i]
+		switch node.kind {
+		case sequenceNode:
+			result.Sequences = append(result.Sequences, schema.Sequences[node.pos])
+		case tableNode:
+			table := schema.Tables[node.pos]
+			if skip := deferred[i]; len(skip) > 0 {
+				constraints := make([]Constraint, 0, len(table.Constraints)-len(skip))
+				for c, constraint := range table.Constraints {
+					if !skip[c] {
+						constraints = append(constraints, constraint)
+					}
+				}
+				table.Constraints = constraints
+			}
+			result.Tables = append(result.Tables, table)
+		case viewNode:
+			result.Views = append(result.Views, schema.Views[node.pos])
+		case triggerNode:
+			result.Triggers = append(result.Triggers, schema.Triggers[node.pos])
+		}
+	}
+
+	return &result, deferredList
+}
+
+// nodeSet is a set of node indexes
+type nodeSet map[int]bool
+
+// members returns the indexes in the set in ascending order
+func (s nodeSet) members() []int {
+	result := make([]int, 0, len(s))
+	for i := range s {
+		result = append(result, i)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// findCycle returns the strongly connected component of the nodes not yet
+// emitted that contains a cycle and holds the lowest node index.
+func findCycle(nodes []dependencyNode, emitted []bool) nodeSet {
+	index := make(map[int]int)
+	lowLink := make(map[int]int)
+	onStack := make(map[int]bool)
+	var stack []int
+	var best nodeSet
+	bestMin := len(nodes)
+	counter := 0
+
+	var connect func(v int)
+	connect = func(v int) {
+		index[v] = counter
+		lowLink[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for w := range nodes[v].deps {
+			if emitted[w] {
+				continue
+			}
+			if _, seen := index[w]; !seen {
+				connect(w)
+				if lowLink[w] < lowLink[v] {
+					lowLink[v] = lowLink[w]
+				}
+			} else if onStack[w] && index[w] < lowLink[v] {
+				lowLink[v] = index[w]
+			}
+		}
+
+		if lowLink[v] == index[v] {
+			component := nodeSet{}
+			lowest := len(nodes)
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component[w] = true
+				if w < lowest {
+					lowest = w
+				}
+				if w == v {
+					break
+				}
+			}
+			if len(component) > 1 && lowest < bestMin {
+				best, bestMin = component, lowest
+			}
+		}
+	}
+
+	for v := range nodes {
+		if _, seen := index[v]; !seen && !emitted[v] {
+			connect(v)
+		}
+	}
+	return best
+}
+
+// indexHeap is a min-heap of node indexes
+type indexHeap []int
+
+func (h indexHeap) Len() int            { return len(h) }
+func (h indexHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,139 @@
+package sqlmapper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Dialect describes a database implementation registered with RegisterDialect.
+// The dialect packages register themselves when they are imported; the
+// sqlmapper/dialects package imports all built-in dialects at once.
+type Dialect struct {
+	// NewParser creates a parser for the dialect
+	NewParser func() Parser
+
+	// NewStreamParser creates a stream parser for the dialect. It returns a
+	// stream.StreamParser and may be nil for dialects without stream support.
+	NewStreamParser func() interface{}
+
+	// Aliases are alternative names the dialect can be looked up by,
+	// such as "postgres" for PostgreSQL
+	Aliases []string
+
+	// IncludeFormat is the command of the dialect's command-line client that
+	// runs another script, with %s for its path, such as `\i %s` for psql.
+	// It may be empty for dialects without one.
+	IncludeFormat string
+}
+
+// dialects holds the registered dialects indexed by database type, and
+// dialectNames maps lower-cased names and aliases to their database type
+var (
+	dialects     = map[DatabaseType]Dialect{}
+	dialectNames = map[string]DatabaseType{}
+	dialectsMu   sync.RWMutex
+)
+
+// RegisterDialect makes a dialect available under the given database type and
+// its aliases. Third-party dialects register themselves the same way as the
+// built-in ones, usually from an init function. It panics if NewParser is nil
+// or if the name or one of the aliases is already registered.
+func RegisterDialect(dbType DatabaseType, dialect Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+
+	if dialect.NewParser == nil {
+		panic("sqlmapper: RegisterDialect parser factory is nil for " + string(dbType))
+	}
+
+	names := append([]string{string(dbType)}, dialect.Aliases...)
+	for _, name := range names {
+		if _, dup := dialectNames[strings.ToLower(name)]; dup {
+			panic("sqlmapper: RegisterDialect called twice for " + name)
+		}
+	}
+
+	dialects[dbType] = dialect
+	for _, name := range names {
+		dialectNames[strings.ToLower(name)] = dbType
+	}
+}
+
+// LookupDialect returns the dialect registered under a database type or alias.
+// Names are matched case-insensitively.
+func LookupDialect(name string) (DatabaseType, Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	dbType, ok := dialectNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", Dialect{}, false
+	}
+	return dbType, dialects[dbType], true
+}
+
+// Dialects returns the registered database types in sorted order
+func Dialects() []DatabaseType {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	result := make([]DatabaseType, 0, len(dialects))
+	for dbType := range dialects {
+		result = append(result, dbType)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// NewParser creates a parser for a registered database type or alias
+//
+// Parameters:
+//   - dbType: The database type, e.g. sqlmapper.MySQL
+//
+// Returns:
+//   - Parser: A new parser instance for the dialect
+//   - error: An error if no dialect is registered under the name
+func NewParser(dbType DatabaseType) (Parser, error) {
+	_, dialect, ok := LookupDialect(string(dbType))
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type: %s (forgotten import?)", dbType)
+	}
+	return dialect.NewParser(), nil
+}
+
+// Convert parses SQL written for the src dialect and generates the equivalent
+// SQL for the dst dialect.
+//
+// Parameters:
+//   - src: The database type of the input SQL
+//   - dst: The database type to generate SQL for
+//   - sql: The SQL content to convert
+//
+// Returns:
+//   - string: The generated SQL
+//   - error: An error if a dialect is not registered, or parsing or generation fails
+func Convert(src, dst DatabaseType, sql string) (string, error) {
+	sourceParser, err := NewParser(src)
+	if err != nil {
+		return "", fmt.Errorf("source parser error: %v", err)
+	}
+
+	targetParser, err := NewParser(dst)
+	if err != nil {
+		return "", fmt.Errorf("target parser error: %v", err)
+	}
+
+	schema, err := sourceParser.Parse(sql)
+	if err != nil {
+		return "", fmt.Errorf("parse error: %v", err)
+	}
+
+	result, err := targetParser.Generate(schema)
+	if err != nil {
+		return "", fmt.Errorf("generate error: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package sqlmapper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// upperDialect is a minimal third-party dialect that reads one table name per
+// line and writes the names back in upper case
+type upperDialect struct{}
+
+func (upperDialect) Parse(content string) (*Schema, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, errors.New("empty content")
+	}
+	schema := &Schema{}
+	for _, name := range strings.Fields(content) {
+		schema.Tables = append(schema.Tables, Table{Name: name})
+	}
+	return schema, nil
+}
+
+func (upperDialect) Generate(schema *Schema) (string, error) {
+	var names []string
+	for _, table := range schema.Tables {
+		names = append(names, strings.ToUpper(table.Name))
+	}
+	return strings.Join(names, "\n"), nil
+}
+
+func TestRegisterDialect(t *testing.T) {
+	const upper DatabaseType = "upper"
+	RegisterDialect(upper, Dialect{
+		NewParser: func() Parser { return upperDialect{} },
+		Aliases:   []string{"UP"},
+	})
+	t.Cleanup(func() {
+		dialectsMu.Lock()
+		defer dialectsMu.Unlock()
+		delete(dialects, upper)
+		delete(dialectNames, "upper")
+		delete(dialectNames, "up")
+	})
+
+	dbType, dialect, ok := LookupDialect("Up")
+	assert.True(t, ok)
+	assert.Equal(t, upper, dbType)
+	assert.Nil(t, dialect.NewStreamParser)
+	assert.Contains(t, Dialects(), upper)
+
+	parser, err := NewParser("UPPER")
+	assert.NoError(t, err)
+	assert.IsType(t, upperDialect{}, parser)
+
+	result, err := Convert("up", upper, "users orders")
+	assert.NoError(t, err)
+	assert.Equal(t, "USERS\nORDERS", result)
+
+	_, err = Convert(upper, upper, "")
+	assert.EqualError(t, err, "parse error: empty content")
+
+	_, err = Convert(upper, "unknown", "users")
+	assert.EqualError(t, err, "target parser error: unsupported database type: unknown (forgotten import?)")
+
+	_, err = NewParser("unknown")
+	assert.Error(t, err)
+
+	// Names and aliases can only be registered once
+	assert.Panics(t, func() {
+		RegisterDialect("other", Dialect{NewParser: func() Parser { return upperDialect{} }, Aliases: []string{"upper"}})
+	})
+	assert.Panics(t, func() { RegisterDialect("other", Dialect{}) })
+}
@@ -0,0 +1,14 @@
+// Package dialects registers all built-in sqlmapper dialects. Import it for its
+// side effects to make MySQL, PostgreSQL, SQLite, SQL Server and Oracle available
+// through sqlmapper.NewParser, sqlmapper.Convert and stream.NewStreamParser:
+//
+//	import _ "github.com/mstgnz/sqlmapper/dialects"
+package dialects
+
+import (
+	_ "github.com/mstgnz/sqlmapper/mysql"
+	_ "github.com/mstgnz/sqlmapper/oracle"
+	_ "github.com/mstgnz/sqlmapper/postgres"
+	_ "github.com/mstgnz/sqlmapper/sqlite"
+	_ "github.com/mstgnz/sqlmapper/sqlserver"
+)
@@ -0,0 +1,664 @@
+package dialects
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinDialects(t *testing.T) {
+	assert.Equal(t, []sqlmapper.DatabaseType{
+		sqlmapper.MySQL, sqlmapper.Oracle, sqlmapper.PostgreSQL, sqlmapper.SQLite, sqlmapper.SQLServer,
+	}, sqlmapper.Dialects())
+
+	for _, name := range []string{"mysql", "postgresql", "postgres", "sqlite", "sqlite3", "sqlserver", "mssql", "oracle"} {
+		parser, err := sqlmapper.NewParser(sqlmapper.DatabaseType(name))
+		assert.NoError(t, err, name)
+		assert.NotNil(t, parser, name)
+
+		streamParser, err := stream.NewStreamParser(sqlmapper.DatabaseType(name))
+		assert.NoError(t, err, name)
+		assert.NotNil(t, streamParser, name)
+	}
+
+	_, err := stream.NewStreamParser("db2")
+	assert.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	result, err := sqlmapper.Convert(sqlmapper.MySQL, "postgres", `
+CREATE TABLE users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL
+);`)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "CREATE TABLE users")
+	assert.Contains(t, result, "name varchar(100) NOT NULL")
+}
+
+func TestParsePositions(t *testing.T) {
+	tests := []struct {
+		dialect sqlmapper.DatabaseType
+		content string
+	}{
+		{sqlmapper.MySQL, "-- users\nCREATE TABLE a (id INT);\n\n  CREATE TABLE b (id INT);"},
+		{sqlmapper.PostgreSQL, "-- users\nCREATE TABLE a (id integer);\n\n  CREATE TABLE b (id integer);"},
+		{sqlmapper.SQLite, "-- users\nCREATE TABLE a (id INTEGER);\n\n  CREATE TABLE b (id INTEGER);"},
+		{sqlmapper.SQLServer, "-- users\nCREATE TABLE a (id INT)\nGO\n  CREATE TABLE b (id INT)"},
+		{sqlmapper.Oracle, "-- users\nCREATE TABLE a (id NUMBER);\n\n  CREATE TABLE b (id NUMBER);"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			parser, err := sqlmapper.NewParser(tt.dialect)
+			assert.NoError(t, err)
+
+			schema, err := sqlmapper.ParseSource(parser, "dump.sql", tt.content)
+			assert.NoError(t, err)
+			if assert.Len(t, schema.Tables, 2) {
+				assert.Equal(t, "dump.sql:2:1", schema.Tables[0].Pos.String())
+				assert.Equal(t, 9, schema.Tables[0].Pos.Offset)
+				assert.Equal(t, "dump.sql:4:3", schema.Tables[1].Pos.String())
+			}
+		})
+	}
+}
+
+func TestParseErrorPositions(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqlmapper.DatabaseType
+		content string
+		want    string
+	}{
+		{
+			name:    "Invalid statement",
+			dialect: sqlmapper.SQLite,
+			content: "CREATE TABLE a (id INTEGER);\n\n  CREATE TABLE broken;",
+			want:    "dump.sql:3:3",
+		},
+		{
+			name:    "Unterminated string",
+			dialect: sqlmapper.MySQL,
+			content: "CREATE TABLE a (id INT);\nINSERT INTO a VALUES ('x);",
+			want:    "dump.sql:2:23",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := sqlmapper.NewParser(tt.dialect)
+			assert.NoError(t, err)
+
+			_, err = sqlmapper.ParseSource(parser, "dump.sql", tt.content)
+			var parseErr *sqlmapper.ParseError
+			if assert.ErrorAs(t, err, &parseErr) {
+				assert.Equal(t, tt.want, parseErr.Pos.String())
+			}
+		})
+	}
+}
+
+func TestParseStreamPositions(t *testing.T) {
+	parser, err := stream.NewStreamParser(sqlmapper.SQLite)
+	assert.NoError(t, err)
+
+	var positions []string
+	err = parser.ParseStream(strings.NewReader("CREATE TABLE a (id INTEGER);\n\n  CREATE TABLE b (id INTEGER);"), func(obj stream.SchemaObject) error {
+		table := obj.Data.(*sqlmapper.Table)
+		assert.Equal(t, obj.Pos, *table.Pos)
+		positions = append(positions, obj.Pos.String())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1:1", "3:3"}, positions)
+}
+
+func TestWorkerPoolPositions(t *testing.T) {
+	parser, err := stream.NewStreamParser(sqlmapper.SQLite)
+	assert.NoError(t, err)
+
+	content := "CREATE TABLE a (id INTEGER);\n\n  CREATE TABLE b (id INTEGER);\nCREATE INDEX idx_b ON b (id);"
+	var sequential, pooled []string
+	assert.NoError(t, parser.ParseStream(strings.NewReader(content), func(obj stream.SchemaObject) error {
+		sequential = append(sequential, fmt.Sprintf("%v %s", obj.Type, obj.Pos))
+		return nil
+	}))
+	assert.NoError(t, stream.NewWorkerPool(2, parser).Process(strings.NewReader(content), func(obj stream.SchemaObject) error {
+		if table, ok := obj.Data.(*sqlmapper.Table); ok {
+			assert.Equal(t, obj.Pos, *table.Pos)
+		}
+		pooled = append(pooled, fmt.Sprintf("%v %s", obj.Type, obj.Pos))
+		return nil
+	}))
+	assert.Equal(t, []string{"table 1:1", "table 3:3", "index 4:1"}, pooled)
+	assert.Equal(t, sequential, pooled)
+}
+
+func TestParseStreamParallelOrder(t *testing.T) {
+	dumps := map[sqlmapper.DatabaseType]string{
+		sqlmapper.MySQL:      "CREATE TABLE t%d (id INT, n INT DEFAULT %d);\n",
+		sqlmapper.PostgreSQL: "CREATE TABLE t%d (id integer, n integer DEFAULT %d);\n",
+		sqlmapper.SQLite:     "CREATE TABLE t%d (id INTEGER, n INTEGER DEFAULT %d);\n",
+		sqlmapper.SQLServer:  "CREATE TABLE t%d (id INT, n INT DEFAULT %d)\nGO\n",
+		sqlmapper.Oracle:     "CREATE TABLE t%d (id NUMBER, n NUMBER DEFAULT %d);\n",
+	}
+
+	for dialect, format := range dumps {
+		t.Run(string(dialect), func(t *testing.T) {
+			var dump strings.Builder
+			for i := 0; i < 200; i++ {
+				fmt.Fprintf(&dump, format, i, i)
+			}
+
+			parser, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+
+			var sequential, parallel []string
+			collect := func(names *[]string) func(stream.SchemaObject) error {
+				return func(obj stream.SchemaObject) error {
+					*names = append(*names, fmt.Sprintf("%v %s", obj.Type, obj.Pos))
+					return nil
+				}
+			}
+			assert.NoError(t, parser.ParseStream(strings.NewReader(dump.String()), collect(&sequential)))
+			assert.NoError(t, parser.ParseStreamParallel(strings.NewReader(dump.String()), collect(&parallel), 8))
+			assert.Len(t, sequential, 200)
+			assert.Equal(t, sequential, parallel)
+		})
+	}
+}
+
+func TestStreamContext(t *testing.T) {
+	schema := &sqlmapper.Schema{Tables: []sqlmapper.Table{{Name: "a", Columns: []sqlmapper.Column{{Name: "id", DataType: "INT"}}}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, dialect := range sqlmapper.Dialects() {
+		t.Run(string(dialect), func(t *testing.T) {
+			parser, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+
+			err = parser.ParseStreamContext(ctx, strings.NewReader("CREATE TABLE a (id INT);"), func(stream.SchemaObject) error {
+				t.Error("unexpected object")
+				return nil
+			})
+			assert.Equal(t, context.Canceled, err)
+
+			var out strings.Builder
+			assert.Equal(t, context.Canceled, parser.GenerateStreamContext(ctx, schema, &out))
+			assert.Empty(t, out.String())
+
+			assert.NoError(t, parser.GenerateStreamContext(context.Background(), schema, &out))
+			assert.Contains(t, out.String(), "CREATE TABLE a")
+		})
+	}
+}
+
+func TestDataRoundTrip(t *testing.T) {
+	source := `
+CREATE TABLE items (
+    id INT NOT NULL,
+    name VARCHAR(100),
+    data BLOB,
+    created DATETIME
+);
+
+INSERT INTO items (id, name, data, created) VALUES
+    (1, 'O\'Brien \\ Çay', X'00FF', '2024-03-01 10:30:00'),
+    (2, NULL, NULL, NULL);`
+
+	mysqlParser, err := sqlmapper.NewParser(sqlmapper.MySQL)
+	assert.NoError(t, err)
+	schema, err := mysqlParser.Parse(source)
+	assert.NoError(t, err)
+
+	created := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	want := []sqlmapper.Row{
+		{Values: map[string]interface{}{"id": int64(1), "name": `O'Brien \ Çay`, "data": []byte{0x00, 0xFF}, "created": created}},
+		{Values: map[string]interface{}{"id": int64(2), "name": nil, "data": nil, "created": nil}},
+	}
+	if assert.Len(t, schema.Tables, 1) {
+		assert.Equal(t, want, schema.Tables[0].Data)
+	}
+
+	for _, target := range sqlmapper.Dialects() {
+		t.Run(string(target), func(t *testing.T) {
+			parser, err := sqlmapper.NewParser(target)
+			assert.NoError(t, err)
+
+			generated, err := parser.Generate(schema)
+			assert.NoError(t, err)
+			assert.Contains(t, generated, "INSERT INTO items")
+
+			expected := want
+			if target == sqlmapper.SQLite {
+				// SQLite stores dates as TEXT, so they are read back as strings
+				expected = []sqlmapper.Row{{Values: map[string]interface{}{}}, want[1]}
+				for name, value := range want[0].Values {
+					expected[0].Values[name] = value
+				}
+				expected[0].Values["created"] = "2024-03-01 10:30:00"
+			}
+
+			parsed, err := parser.Parse(generated)
+			if assert.NoError(t, err, generated) && assert.Len(t, parsed.Tables, 1) {
+				assert.Equal(t, expected, parsed.Tables[0].Data, generated)
+			}
+		})
+	}
+}
+
+func TestStreamConvert(t *testing.T) {
+	source := `
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL, score REAL);
+CREATE VIEW user_ids AS SELECT id FROM users;
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+INSERT INTO users (id, email, score) VALUES (1, 'ada@example.com', 1.5);`
+
+	src, err := stream.NewStreamParser(sqlmapper.SQLite)
+	assert.NoError(t, err)
+
+	for _, dialect := range sqlmapper.Dialects() {
+		t.Run(string(dialect), func(t *testing.T) {
+			dst, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+
+			var out strings.Builder
+			assert.NoError(t, stream.Convert(src, dst, strings.NewReader(source), &out))
+
+			result := out.String()
+			table := strings.Index(result, "CREATE TABLE users")
+			view := strings.Index(result, "CREATE VIEW user_ids")
+			index := strings.Index(result, "idx_users_email ON users")
+			data := strings.Index(result, "INSERT INTO users (")
+			assert.True(t, table >= 0 && table < view && view < index && index < data, result)
+			assert.NotContains(t, result, ";;")
+		})
+	}
+}
+
+func TestStreamConvertAlterTable(t *testing.T) {
+	sources := map[sqlmapper.DatabaseType]string{
+		sqlmapper.MySQL: "CREATE TABLE users (id INT AUTO_INCREMENT PRIMARY KEY);\n" +
+			"CREATE TABLE orders (id INT AUTO_INCREMENT PRIMARY KEY, user_id INT);\n" +
+			"ALTER TABLE `orders`\n  ADD KEY `idx_orders_user` (`user_id`),\n" +
+			"  ADD CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;",
+		sqlmapper.PostgreSQL: "CREATE TABLE users (id integer NOT NULL);\n" +
+			"CREATE TABLE orders (id integer NOT NULL, user_id integer);\n" +
+			"ALTER TABLE ONLY public.orders\n    ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;",
+		sqlmapper.SQLServer: "CREATE TABLE [dbo].[users] ([id] INT IDENTITY(1,1) PRIMARY KEY)\nGO\n" +
+			"CREATE TABLE [dbo].[orders] ([id] INT IDENTITY(1,1) PRIMARY KEY, [user_id] INT)\nGO\n" +
+			"ALTER TABLE [dbo].[orders] ADD CONSTRAINT [fk_orders_user] FOREIGN KEY ([user_id]) REFERENCES [dbo].[users] ([id]) ON DELETE CASCADE\nGO",
+		sqlmapper.Oracle: "CREATE TABLE users (id NUMBER(10) PRIMARY KEY);\n" +
+			"CREATE TABLE orders (id NUMBER(10) PRIMARY KEY, user_id NUMBER(10));\n" +
+			"ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;",
+	}
+
+	dst, err := stream.NewStreamParser(sqlmapper.PostgreSQL)
+	assert.NoError(t, err)
+
+	for dialect, source := range sources {
+		t.Run(string(dialect), func(t *testing.T) {
+			src, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+
+			var out strings.Builder
+			assert.NoError(t, stream.Convert(src, dst, strings.NewReader(source), &out))
+			assert.Regexp(t, `ALTER TABLE (public\.|dbo\.)?orders ADD CONSTRAINT fk_orders_user FOREIGN KEY \(user_id\) REFERENCES (public\.|dbo\.)?users ?\(id\) ON DELETE CASCADE;`, out.String())
+			if dialect == sqlmapper.MySQL {
+				assert.Contains(t, out.String(), "CREATE INDEX idx_orders_user ON orders (user_id);")
+			}
+		})
+	}
+
+	// SQLite has no ALTER TABLE ... ADD CONSTRAINT: the statement fails instead of
+	// being dropped, and a lenient parse passes it through
+	src, err := stream.NewStreamParser(sqlmapper.SQLite)
+	assert.NoError(t, err)
+	source := "CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER);\n" +
+		"ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id);"
+
+	var parseErr *sqlmapper.ParseError
+	if assert.ErrorAs(t, stream.Convert(src, dst, strings.NewReader(source), io.Discard), &parseErr) {
+		assert.Equal(t, 2, parseErr.Pos.Line)
+	}
+
+	var unparsed []string
+	err = src.(stream.LenientStreamParser).ParseStreamLenient(strings.NewReader(source), func(obj stream.SchemaObject) error {
+		if obj.Type == stream.UnparsedObject {
+			unparsed = append(unparsed, obj.Data.(*stream.Unparsed).SQL)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, unparsed, 1)
+}
+
+// streamSchema assembles the objects of a stream parse into a schema
+func streamSchema(t *testing.T, dialect sqlmapper.DatabaseType, content string) *sqlmapper.Schema {
+	parser, err := stream.NewStreamParser(dialect)
+	assert.NoError(t, err)
+
+	schema, diagnostics, err := stream.Assemble(parser, strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Empty(t, diagnostics)
+	return schema
+}
+
+func TestStreamParity(t *testing.T) {
+	dumps := map[sqlmapper.DatabaseType]string{
+		sqlmapper.MySQL: `
+CREATE DATABASE shop;
+CREATE TABLE customers (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100) NOT NULL);
+CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT, total DECIMAL(10,2),
+    CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers(id));
+CREATE INDEX idx_orders_customer ON orders (customer_id);
+CREATE UNIQUE INDEX idx_customers_name ON customers (name);
+INSERT INTO customers (id, name) VALUES (1, 'Ada'), (2, 'Linus');
+INSERT INTO orders (id, customer_id, total) VALUES (10, 1, 9.5);
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100;
+DELIMITER //
+CREATE FUNCTION order_count(customer INT)
+RETURNS INT
+BEGIN
+    RETURN (SELECT COUNT(*) FROM orders WHERE customer_id = customer);
+END //
+CREATE PROCEDURE archive_orders(IN before_id INT)
+BEGIN
+    DELETE FROM orders WHERE id < before_id;
+END //
+CREATE TRIGGER orders_check
+BEFORE INSERT ON orders
+FOR EACH ROW
+BEGIN
+    SET NEW.total = ABS(NEW.total);
+END //
+DELIMITER ;
+GRANT SELECT, INSERT ON orders TO 'app'@'localhost';
+REVOKE INSERT ON orders FROM 'app'@'localhost';`,
+
+		sqlmapper.PostgreSQL: `
+CREATE SCHEMA shop;
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE TYPE status AS ENUM ('new', 'paid');
+CREATE SEQUENCE order_seq INCREMENT BY 1 START WITH 100;
+CREATE TABLE customers (id integer PRIMARY KEY, name varchar(100) NOT NULL);
+CREATE TABLE orders (id integer PRIMARY KEY, customer_id integer REFERENCES customers(id), total numeric(10,2));
+CREATE INDEX idx_orders_customer ON orders (customer_id);
+INSERT INTO customers (id, name) VALUES (1, 'Ada'), (2, 'Linus');
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100;
+CREATE FUNCTION touch() RETURNS TRIGGER AS $$
+BEGIN
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER orders_touch BEFORE UPDATE ON orders FOR EACH ROW EXECUTE FUNCTION touch();
+GRANT SELECT, INSERT ON orders TO app;`,
+
+		sqlmapper.SQLite: `
+CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES customers(id), total REAL);
+CREATE INDEX idx_orders_customer ON orders (customer_id);
+CREATE UNIQUE INDEX idx_customers_name ON customers (name);
+INSERT INTO customers (id, name) VALUES (1, 'Ada'), (2, 'Linus');
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100;
+CREATE TRIGGER orders_check AFTER INSERT ON orders BEGIN UPDATE orders SET total = abs(total); END;`,
+
+		sqlmapper.SQLServer: `
+CREATE TABLE customers (id INT PRIMARY KEY, name NVARCHAR(100) NOT NULL)
+GO
+CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT, total DECIMAL(10,2))
+GO
+ALTER TABLE orders ADD CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers(id)
+GO
+CREATE INDEX idx_orders_customer ON orders (customer_id)
+GO
+INSERT INTO customers (id, name) VALUES (1, N'Ada'), (2, N'Linus')
+GO
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100
+GO
+CREATE TRIGGER orders_check ON orders AFTER INSERT AS BEGIN SET NOCOUNT ON END
+GO`,
+
+		sqlmapper.Oracle: `
+CREATE SEQUENCE order_seq START WITH 100 INCREMENT BY 1;
+CREATE TABLE customers (id NUMBER PRIMARY KEY, name VARCHAR2(100) NOT NULL);
+CREATE TABLE orders (id NUMBER PRIMARY KEY, customer_id NUMBER, total NUMBER(10,2));
+INSERT INTO customers (id, name) VALUES (1, 'Ada');
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100;
+CREATE TRIGGER orders_check BEFORE INSERT ON orders FOR EACH ROW BEGIN :NEW.total := ABS(:NEW.total); END;`,
+	}
+
+	for dialect, dump := range dumps {
+		t.Run(string(dialect), func(t *testing.T) {
+			parser, err := sqlmapper.NewParser(dialect)
+			assert.NoError(t, err)
+			want, err := parser.Parse(dump)
+			assert.NoError(t, err)
+			assert.Len(t, want.Tables, 2)
+
+			assert.Equal(t, want, streamSchema(t, dialect, dump))
+
+			// Parallel workers deliver the same schema
+			streamParser, _ := stream.NewStreamParser(dialect)
+			assembler := stream.NewAssembler(dialect)
+			assert.NoError(t, streamParser.ParseStreamParallel(strings.NewReader(dump), assembler.Add, 4))
+			got, diagnostics := assembler.Schema()
+			assert.Empty(t, diagnostics)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+// failingSeeker fails reads once n bytes have been read
+type failingSeeker struct {
+	*strings.Reader
+	n int
+}
+
+func (r *failingSeeker) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errors.New("read failed")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n, err := r.Reader.Read(p)
+	r.n -= n
+	return n, err
+}
+
+func TestConvertResumable(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&sb, "CREATE TABLE t%d (id INTEGER PRIMARY KEY, name TEXT NOT NULL);\n", i)
+		fmt.Fprintf(&sb, "CREATE INDEX idx_t%d_name ON t%d (name);\n", i, i)
+	}
+	source := sb.String()
+
+	src, err := stream.NewStreamParser(sqlmapper.SQLite)
+	assert.NoError(t, err)
+
+	for _, dialect := range sqlmapper.Dialects() {
+		t.Run(string(dialect), func(t *testing.T) {
+			dst, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+			assert.Implements(t, (*stream.OptionsStreamParser)(nil), dst)
+			dir := t.TempDir()
+			checkpointPath := filepath.Join(dir, "checkpoint.json")
+
+			var want strings.Builder
+			assert.NoError(t, stream.Convert(src, dst, strings.NewReader(source), &want))
+
+			output, err := os.Create(filepath.Join(dir, "out.sql"))
+			assert.NoError(t, err)
+			defer output.Close()
+
+			opts := stream.CheckpointOptions{Path: checkpointPath, Every: 10, Resume: true}
+			err = stream.ConvertResumable(context.Background(), src, dst, &failingSeeker{Reader: strings.NewReader(source), n: len(source) / 2}, output, opts)
+			assert.EqualError(t, err, "read failed")
+			assert.FileExists(t, checkpointPath)
+
+			err = stream.ConvertResumable(context.Background(), src, dst, strings.NewReader(source), output, opts)
+			assert.NoError(t, err)
+
+			got, _ := os.ReadFile(output.Name())
+			assert.Equal(t, want.String(), string(got))
+		})
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	content := "CREATE TABLE a (id INT);\nINSERT INTO a (id) VALUES (1, 2);\nCREATE TABLE b (id INT);\nINSERT INTO b VALUES ('x"
+
+	for _, dialect := range sqlmapper.Dialects() {
+		t.Run(string(dialect), func(t *testing.T) {
+			parser, err := sqlmapper.NewParser(dialect)
+			assert.NoError(t, err)
+
+			schema, diagnostics, err := sqlmapper.ParseLenient(parser, content)
+			assert.NoError(t, err)
+
+			if assert.Len(t, schema.Tables, 2) {
+				assert.Equal(t, "b", schema.Tables[1].Name)
+				assert.Equal(t, 3, schema.Tables[1].Pos.Line)
+				assert.Empty(t, schema.Tables[0].Data)
+			}
+			if assert.Len(t, diagnostics, 2) {
+				assert.Equal(t, sqlmapper.CodeUnparsedStatement, diagnostics[0].Code)
+				assert.Equal(t, "INSERT INTO a (id) VALUES (1, 2)", diagnostics[0].Statement)
+				assert.Equal(t, 2, diagnostics[0].Pos.Line)
+				assert.Contains(t, diagnostics[0].Message, "1 columns but 2 values")
+				assert.Equal(t, "unterminated string", diagnostics[1].Message)
+			}
+
+			// Parse still stops at the first failure
+			parser, _ = sqlmapper.NewParser(dialect)
+			_, err = parser.Parse(content)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseStreamLenient(t *testing.T) {
+	content := "CREATE VIEW v AS SELECT 1;\nSET search_path = app;\nCREATE VIEW w AS SELECT 2;"
+
+	for _, dialect := range sqlmapper.Dialects() {
+		t.Run(string(dialect), func(t *testing.T) {
+			parser, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+
+			lenient, ok := parser.(stream.LenientStreamParser)
+			if !assert.True(t, ok) {
+				return
+			}
+
+			// Every statement is delivered once, and unparsed ones are written back verbatim
+			var lines []int
+			var out strings.Builder
+			err = lenient.ParseStreamLenient(strings.NewReader(content), func(obj stream.SchemaObject) error {
+				lines = append(lines, obj.Pos.Line)
+				if obj.Type == stream.UnparsedObject {
+					return parser.GenerateObject(obj, &out)
+				}
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1, 2, 3}, lines)
+			assert.Contains(t, out.String(), "SET search_path = app;")
+		})
+	}
+}
+
+func TestStreamComments(t *testing.T) {
+	content := `CREATE TABLE users (id integer NOT NULL, email varchar(100));
+COMMENT ON TABLE users IS 'Accounts';
+COMMENT ON COLUMN users.email IS 'User''s address';`
+
+	schema := streamSchema(t, sqlmapper.PostgreSQL, content)
+	if assert.Len(t, schema.Tables, 1) {
+		assert.Equal(t, "Accounts", schema.Tables[0].Comment)
+		assert.Equal(t, "User's address", schema.Tables[0].Columns[1].Comment)
+	}
+
+	src, _ := stream.NewStreamParser(sqlmapper.PostgreSQL)
+	for _, dialect := range []sqlmapper.DatabaseType{sqlmapper.PostgreSQL, sqlmapper.Oracle} {
+		dst, _ := stream.NewStreamParser(dialect)
+		var out strings.Builder
+		assert.NoError(t, stream.Convert(src, dst, strings.NewReader(content), &out))
+		assert.Contains(t, out.String(), "COMMENT ON TABLE users IS 'Accounts';")
+		assert.Contains(t, out.String(), "COMMENT ON COLUMN users.email IS 'User''s address';")
+	}
+
+	// Other dialects set comments their own way, or keep them as SQL comments
+	expected := map[sqlmapper.DatabaseType][]string{
+		sqlmapper.MySQL: {
+			"ALTER TABLE users COMMENT = 'Accounts';",
+			"-- MySQL sets column comments in the column definition: COMMENT ON COLUMN users.email IS 'User''s address';",
+		},
+		sqlmapper.SQLServer: {
+			"EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'Accounts', @level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'TABLE', @level1name = N'users';",
+			"@value = N'User''s address', @level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'TABLE', @level1name = N'users', @level2type = N'COLUMN', @level2name = N'email';",
+		},
+		sqlmapper.SQLite: {
+			"-- Comments are not supported by SQLite: COMMENT ON TABLE users IS 'Accounts';",
+			"-- Comments are not supported by SQLite: COMMENT ON COLUMN users.email IS 'User''s address';",
+		},
+	}
+	for dialect, statements := range expected {
+		dst, _ := stream.NewStreamParser(dialect)
+		var out strings.Builder
+		assert.NoError(t, stream.Convert(src, dst, strings.NewReader(content), &out))
+		for _, stmt := range statements {
+			assert.Contains(t, out.String(), stmt, dialect)
+		}
+	}
+}
+
+func TestGenerateDirectory(t *testing.T) {
+	parser, _ := sqlmapper.NewParser(sqlmapper.PostgreSQL)
+	schema, err := parser.Parse(`
+CREATE TABLE orders (id integer NOT NULL, customer_id integer,
+    CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers(id));
+CREATE TABLE customers (id integer NOT NULL, name varchar(100));
+CREATE INDEX idx_orders_customer ON orders (customer_id);
+CREATE VIEW big_orders AS SELECT * FROM orders;`)
+	assert.NoError(t, err)
+
+	for _, dialect := range sqlmapper.Dialects() {
+		t.Run(string(dialect), func(t *testing.T) {
+			dst, err := stream.NewStreamParser(dialect)
+			assert.NoError(t, err)
+			dir := t.TempDir()
+
+			files, err := stream.GenerateDirectory(dst, schema, dir)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"tables/customers.sql", "tables/orders.sql", "views/big_orders.sql"}, files)
+
+			orders, _ := os.ReadFile(filepath.Join(dir, "tables", "orders.sql"))
+			assert.Contains(t, string(orders), "CREATE TABLE orders")
+			assert.Contains(t, string(orders), "idx_orders_customer")
+			assert.NotContains(t, string(orders), "CREATE TABLE customers")
+
+			manifest, _ := os.ReadFile(filepath.Join(dir, stream.ManifestFile))
+			assert.Equal(t, strings.Join(files, "\n")+"\n", string(manifest))
+
+			_, registered, _ := sqlmapper.LookupDialect(string(dialect))
+			include, _ := os.ReadFile(filepath.Join(dir, stream.IncludeFile))
+			assert.Contains(t, string(include), fmt.Sprintf(registered.IncludeFormat, "tables/customers.sql")+"\n"+
+				fmt.Sprintf(registered.IncludeFormat, "tables/orders.sql")+"\n")
+		})
+	}
+}
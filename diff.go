@@ -0,0 +1,546 @@
+package sqlmapper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ChangeType represents the kind of a schema change
+type ChangeType string
+
+const (
+	CreateTable    ChangeType = "CREATE_TABLE"
+	DropTable      ChangeType = "DROP_TABLE"
+	AddColumn      ChangeType = "ADD_COLUMN"
+	DropColumn     ChangeType = "DROP_COLUMN"
+	ModifyColumn   ChangeType = "MODIFY_COLUMN"
+	AddIndex       ChangeType = "ADD_INDEX"
+	DropIndex      ChangeType = "DROP_INDEX"
+	AddConstraint  ChangeType = "ADD_CONSTRAINT"
+	DropConstraint ChangeType = "DROP_CONSTRAINT"
+	CreateView     ChangeType = "CREATE_VIEW"
+	DropView       ChangeType = "DROP_VIEW"
+	ModifyView     ChangeType = "MODIFY_VIEW"
+	CreateSequence ChangeType = "CREATE_SEQUENCE"
+	DropSequence   ChangeType = "DROP_SEQUENCE"
+	ModifySequence ChangeType = "MODIFY_SEQUENCE"
+	CreateTrigger  ChangeType = "CREATE_TRIGGER"
+	DropTrigger    ChangeType = "DROP_TRIGGER"
+	ModifyTrigger  ChangeType = "MODIFY_TRIGGER"
+	CreateFunction ChangeType = "CREATE_FUNCTION"
+	DropFunction   ChangeType = "DROP_FUNCTION"
+	ModifyFunction ChangeType = "MODIFY_FUNCTION"
+)
+
+// Change represents a single difference between two schemas.
+// Old holds the previous definition and is nil for additions; New holds the
+// new definition and is nil for removals. Both hold values of the matching
+// schema struct (Table, Column, Index, Constraint, View, Sequence, Trigger
+// or Function). Foreign keys of a created table are part of its definition;
+// its other constraints, and foreign keys that close a reference cycle, are
+// reported as separate AddConstraint changes.
+type Change struct {
+	Type    ChangeType
+	Table   string       // Owning table for column, index and constraint changes
+	Dialect DatabaseType // Dialect of the data types in Old and New
+	Name    string
+	Old     interface{}
+	New     interface{}
+}
+
+// Migrator represents a dialect that can render schema changes as a migration script
+type Migrator interface {
+	GenerateMigration(changes []Change) (string, error)
+}
+
+// Diff compares two schemas and returns the changes required to turn old into new.
+// The changes are ordered so that they can be applied one after another:
+// dependent objects are dropped before the objects they depend on, and
+// created after them. Objects are matched by name, case-insensitively.
+// Procedures are compared together with functions.
+//
+// Changed views, triggers and routines are reported as a drop of the old
+// definition, before any column or table is dropped, and a create of the new
+// one; Diff does not report ModifyView, ModifyTrigger or ModifyFunction.
+func Diff(old, new *Schema) []Change {
+	if old == nil {
+		old = &Schema{}
+	}
+	if new == nil {
+		new = &Schema{}
+	}
+
+	var drops, creates []Change
+
+	// Triggers, views and routines depend on tables, so they are dropped first
+	// and created last.
+	triggerDrops, triggerCreates := diffTriggers(old.Triggers, new.Triggers)
+	viewDrops, viewCreates := diffViews(sortedViews(old, true), sortedViews(new, false))
+	funcDrops, funcCreates := diffFunctions(routines(old), routines(new))
+	tableDrops, tableCreates := diffTables(old.Tables, new.Tables)
+	seqDrops, seqCreates := diffSequences(old.Sequences, new.Sequences)
+
+	drops = append(drops, triggerDrops...)
+	drops = append(drops, viewDrops...)
+	drops = append(drops, funcDrops...)
+	drops = append(drops, tableDrops...)
+	drops = append(drops, seqDrops...)
+
+	creates = append(creates, seqCreates...)
+	creates = append(creates, tableCreates...)
+	creates = append(creates, funcCreates...)
+	creates = append(creates, viewCreates...)
+	creates = append(creates, triggerCreates...)
+
+	dialect := new.Dialect
+	if dialect == "" {
+		dialect = old.Dialect
+	}
+	changes := append(drops, creates...)
+	for i := range changes {
+		changes[i].Dialect = dialect
+	}
+	return changes
+}
+
+// ConvertChanges returns a copy of the changes whose new table, column and
+// routine definitions have been translated to the dst dialect, as ConvertTypes
+// translates a schema. Migrators call it so that a migration between schemas of
+// another dialect uses data types of their own. Changes without a dialect, or
+// already in dst, are returned as is.
+//
+// Parameters:
+//   - changes: The changes to convert, as produced by Diff
+//   - dst: The target dialect
+//
+// Returns:
+//   - []Change: The converted changes
+func ConvertChanges(changes []Change, dst DatabaseType) []Change {
+	converted := make([]Change, len(changes))
+	for i, change := range changes {
+		if change.Dialect != "" && change.Dialect != dst {
+			schema := &Schema{Dialect: change.Dialect}
+			switch def := change.New.(type) {
+			case Table:
+				schema.Tables = []Table{def}
+				change.New = ConvertTypes(schema, dst).Tables[0]
+			case Column:
+				schema.Tables = []Table{{Columns: []Column{def}}}
+				change.New = ConvertTypes(schema, dst).Tables[0].Columns[0]
+			case Function:
+				schema.Functions = []Function{def}
+				change.New = ConvertTypes(schema, dst).Functions[0]
+			}
+			change.Dialect = dst
+		}
+		converted[i] = change
+	}
+	return converted
+}
+
+// diffTables compares table lists and returns the drop and create changes.
+// Drops are ordered constraints, indexes, columns, tables; creates are ordered
+// tables, columns, column modifications, constraints, indexes.
+func diffTables(oldTables, newTables []Table) ([]Change, []Change) {
+	var dropConstraints, dropIndexes, dropColumns, dropTables []Change
+	var createTables, addColumns, modifyColumns, addConstraints, addIndexes []Change
+
+	oldByName := make(map[string]Table, len(oldTables))
+	for _, table := range oldTables {
+		oldByName[qualifiedKey(table.Schema, table.Name)] = table
+	}
+	newByName := make(map[string]Table, len(newTables))
+	for _, table := range newTables {
+		newByName[qualifiedKey(table.Schema, table.Name)] = table
+	}
+
+	for _, oldTable := range oldTables {
+		newTable, ok := newByName[qualifiedKey(oldTable.Schema, oldTable.Name)]
+		if !ok {
+			// Foreign keys of dropped tables go first so referenced tables can be dropped
+			for _, constraint := range oldTable.Constraints {
+				if isForeignKey(constraint) && constraint.Name != "" {
+					dropConstraints = append(dropConstraints, Change{Type: DropConstraint, Table: oldTable.Name, Name: constraint.Name, Old: constraint})
+				}
+			}
+			dropTables = append(dropTables, Change{Type: DropTable, Table: oldTable.Name, Name: oldTable.Name, Old: oldTable})
+			continue
+		}
+
+		// Constraints
+		newConstraints := make(map[string]Constraint, len(newTable.Constraints))
+		for _, constraint := range newTable.Constraints {
+			newConstraints[constraintKey(constraint)] = constraint
+		}
+		oldConstraints := make(map[string]Constraint, len(oldTable.Constraints))
+		for _, constraint := range oldTable.Constraints {
+			key := constraintKey(constraint)
+			oldConstraints[key] = constraint
+			if nc, ok := newConstraints[key]; !ok || !equalConstraint(constraint, nc) {
+				dropConstraints = append(dropConstraints, Change{Type: DropConstraint, Table: newTable.Name, Name: constraint.Name, Old: constraint})
+			}
+		}
+		for _, constraint := range newTable.Constraints {
+			if oc, ok := oldConstraints[constraintKey(constraint)]; !ok || !equalConstraint(oc, constraint) {
+				addConstraints = append(addConstraints, Change{Type: AddConstraint, Table: newTable.Name, Name: constraint.Name, New: constraint})
+			}
+		}
+
+		// Indexes
+		newIndexes := make(map[string]Index, len(newTable.Indexes))
+		for _, index := range newTable.Indexes {
+			newIndexes[indexKey(index)] = index
+		}
+		oldIndexes := make(map[string]Index, len(oldTable.Indexes))
+		for _, index := range oldTable.Indexes {
+			key := indexKey(index)
+			oldIndexes[key] = index
+			if ni, ok := newIndexes[key]; !ok || !equalIndex(index, ni) {
+				dropIndexes = append(dropIndexes, Change{Type: DropIndex, Table: newTable.Name, Name: index.Name, Old: index})
+			}
+		}
+		for _, index := range newTable.Indexes {
+			if oi, ok := oldIndexes[indexKey(index)]; !ok || !equalIndex(oi, index) {
+				addIndexes = append(addIndexes, Change{Type: AddIndex, Table: newTable.Name, Name: index.Name, New: index})
+			}
+		}
+
+		// Columns
+		newColumns := make(map[string]Column, len(newTable.Columns))
+		for _, column := range newTable.Columns {
+			newColumns[strings.ToLower(column.Name)] = column
+		}
+		oldColumns := make(map[string]Column, len(oldTable.Columns))
+		for _, column := range oldTable.Columns {
+			oldColumns[strings.ToLower(column.Name)] = column
+			if _, ok := newColumns[strings.ToLower(column.Name)]; !ok {
+				dropColumns = append(dropColumns, Change{Type: DropColumn, Table: newTable.Name, Name: column.Name, Old: column})
+			}
+		}
+		for _, column := range newTable.Columns {
+			oc, ok := oldColumns[strings.ToLower(column.Name)]
+			if !ok {
+				addColumns = append(addColumns, Change{Type: AddColumn, Table: newTable.Name, Name: column.Name, New: column})
+			} else if !equalColumn(oc, column) {
+				modifyColumns = append(modifyColumns, Change{Type: ModifyColumn, Table: newTable.Name, Name: column.Name, Old: oc, New: column})
+			}
+		}
+	}
+
+	// New tables are created in dependency order. Their foreign keys are part
+	// of the table definition, unless they form a cycle and have to be added
+	// once all tables exist.
+	var created []Table
+	for _, newTable := range newTables {
+		if _, ok := oldByName[qualifiedKey(newTable.Schema, newTable.Name)]; !ok {
+			created = append(created, newTable)
+		}
+	}
+	sorted, deferred := SortSchema(&Schema{Tables: created})
+	for _, newTable := range sorted.Tables {
+		createTables = append(createTables, Change{Type: CreateTable, Table: newTable.Name, Name: newTable.Name, New: newTable})
+		for _, constraint := range newTable.Constraints {
+			if isForeignKey(constraint) || isInlinePrimaryKey(newTable, constraint) {
+				continue
+			}
+			addConstraints = append(addConstraints, Change{Type: AddConstraint, Table: newTable.Name, Name: constraint.Name, New: constraint})
+		}
+	}
+	for _, fk := range deferred {
+		addConstraints = append(addConstraints, Change{Type: AddConstraint, Table: fk.Table, Name: fk.Constraint.Name, New: fk.Constraint})
+	}
+
+	var drops, creates []Change
+	drops = append(drops, dropConstraints...)
+	drops = append(drops, dropIndexes...)
+	drops = append(drops, dropColumns...)
+	drops = append(drops, dropTables...)
+	creates = append(creates, createTables...)
+	creates = append(creates, addColumns...)
+	creates = append(creates, modifyColumns...)
+	creates = append(creates, addConstraints...)
+	creates = append(creates, addIndexes...)
+	return drops, creates
+}
+
+// diffViews compares view lists and returns the drop and create changes
+func diffViews(oldViews, newViews []View) ([]Change, []Change) {
+	var drops, creates []Change
+
+	newByName := make(map[string]View, len(newViews))
+	for _, view := range newViews {
+		newByName[qualifiedKey(view.Schema, view.Name)] = view
+	}
+	oldByName := make(map[string]View, len(oldViews))
+	for _, view := range oldViews {
+		oldByName[qualifiedKey(view.Schema, view.Name)] = view
+		if nv, ok := newByName[qualifiedKey(view.Schema, view.Name)]; !ok || !equalView(view, nv) {
+			drops = append(drops, Change{Type: DropView, Name: view.Name, Old: view})
+		}
+	}
+
+	for _, view := range newViews {
+		if ov, ok := oldByName[qualifiedKey(view.Schema, view.Name)]; !ok || !equalView(ov, view) {
+			creates = append(creates, Change{Type: CreateView, Name: view.Name, New: view})
+		}
+	}
+
+	return drops, creates
+}
+
+// diffSequences compares sequence lists and returns the drop and create changes
+func diffSequences(oldSeqs, newSeqs []Sequence) ([]Change, []Change) {
+	var drops, creates []Change
+
+	newByName := make(map[string]Sequence, len(newSeqs))
+	for _, seq := range newSeqs {
+		newByName[qualifiedKey(seq.Schema, seq.Name)] = seq
+	}
+	oldByName := make(map[string]Sequence, len(oldSeqs))
+	for _, seq := range oldSeqs {
+		oldByName[qualifiedKey(seq.Schema, seq.Name)] = seq
+		if _, ok := newByName[qualifiedKey(seq.Schema, seq.Name)]; !ok {
+			drops = append(drops, Change{Type: DropSequence, Name: seq.Name, Old: seq})
+		}
+	}
+
+	for _, seq := range newSeqs {
+		oldSeq, ok := oldByName[qualifiedKey(seq.Schema, seq.Name)]
+		switch {
+		case !ok:
+			creates = append(creates, Change{Type: CreateSequence, Name: seq.Name, New: seq})
+		case !equalSequence(oldSeq, seq):
+			creates = append(creates, Change{Type: ModifySequence, Name: seq.Name, Old: oldSeq, New: seq})
+		}
+	}
+
+	return drops, creates
+}
+
+// diffTriggers compares trigger lists and returns the drop and create changes
+func diffTriggers(oldTriggers, newTriggers []Trigger) ([]Change, []Change) {
+	var drops, creates []Change
+
+	newByName := make(map[string]Trigger, len(newTriggers))
+	for _, trigger := range newTriggers {
+		newByName[qualifiedKey(trigger.Schema, trigger.Name)] = trigger
+	}
+	oldByName := make(map[string]Trigger, len(oldTriggers))
+	for _, trigger := range oldTriggers {
+		oldByName[qualifiedKey(trigger.Schema, trigger.Name)] = trigger
+		if nt, ok := newByName[qualifiedKey(trigger.Schema, trigger.Name)]; !ok || !equalTrigger(trigger, nt) {
+			drops = append(drops, Change{Type: DropTrigger, Table: trigger.Table, Name: trigger.Name, Old: trigger})
+		}
+	}
+
+	for _, trigger := range newTriggers {
+		if ot, ok := oldByName[qualifiedKey(trigger.Schema, trigger.Name)]; !ok || !equalTrigger(ot, trigger) {
+			creates = append(creates, Change{Type: CreateTrigger, Table: trigger.Table, Name: trigger.Name, New: trigger})
+		}
+	}
+
+	return drops, creates
+}
+
+// diffFunctions compares routine lists and returns the drop and create changes
+func diffFunctions(oldFuncs, newFuncs []Function) ([]Change, []Change) {
+	var drops, creates []Change
+
+	newByName := make(map[string]Function, len(newFuncs))
+	for _, fn := range newFuncs {
+		newByName[qualifiedKey(fn.Schema, fn.Name)] = fn
+	}
+	oldByName := make(map[string]Function, len(oldFuncs))
+	for _, fn := range oldFuncs {
+		oldByName[qualifiedKey(fn.Schema, fn.Name)] = fn
+		if nf, ok := newByName[qualifiedKey(fn.Schema, fn.Name)]; !ok || !equalFunction(fn, nf) {
+			drops = append(drops, Change{Type: DropFunction, Name: fn.Name, Old: fn})
+		}
+	}
+
+	for _, fn := range newFuncs {
+		if of, ok := oldByName[qualifiedKey(fn.Schema, fn.Name)]; !ok || !equalFunction(of, fn) {
+			creates = append(creates, Change{Type: CreateFunction, Name: fn.Name, New: fn})
+		}
+	}
+
+	return drops, creates
+}
+
+// sortedViews returns the views of a schema in dependency order, or in
+// reverse dependency order for dropping
+func sortedViews(schema *Schema, reverse bool) []View {
+	sorted, _ := SortSchema(&Schema{Tables: schema.Tables, Views: schema.Views})
+	views := sorted.Views
+	if reverse {
+		for i, j := 0, len(views)-1; i < j; i, j = i+1, j-1 {
+			views[i], views[j] = views[j], views[i]
+		}
+	}
+	return views
+}
+
+// routines returns the functions of a schema together with its procedures
+// represented as functions with IsProc set.
+func routines(schema *Schema) []Function {
+	result := append([]Function(nil), schema.Functions...)
+	for _, proc := range schema.Procedures {
+		result = append(result, Function{
+			Name:       proc.Name,
+			Schema:     proc.Schema,
+			Parameters: proc.Parameters,
+			Body:       proc.Body,
+			Language:   proc.Language,
+			IsProc:     true,
+		})
+	}
+	return result
+}
+
+// qualifiedKey returns the case-insensitive lookup key of a schema object
+func qualifiedKey(schema, name string) string {
+	if schema == "" {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(schema + "." + name)
+}
+
+// constraintKey identifies a constraint by name, or by type and columns when unnamed
+func constraintKey(c Constraint) string {
+	if c.Name != "" {
+		return strings.ToLower(c.Name)
+	}
+	return strings.ToLower(c.Type + "(" + strings.Join(c.Columns, ",") + ")" + c.CheckExpression)
+}
+
+// indexKey identifies an index by name, or by uniqueness and columns when unnamed
+func indexKey(index Index) string {
+	if index.Name != "" {
+		return strings.ToLower(index.Name)
+	}
+	kind := "index"
+	if index.IsUnique {
+		kind = "unique"
+	}
+	return strings.ToLower(kind + "(" + strings.Join(index.Columns, ",") + ")" + index.Condition)
+}
+
+// isInlinePrimaryKey reports whether a primary key constraint is already
+// expressed by the IsPrimaryKey flag of its columns
+func isInlinePrimaryKey(table Table, c Constraint) bool {
+	if !strings.EqualFold(c.Type, "PRIMARY KEY") || len(c.Columns) == 0 {
+		return false
+	}
+	for _, name := range c.Columns {
+		found := false
+		for _, column := range table.Columns {
+			if strings.EqualFold(column.Name, strings.TrimSpace(name)) && column.IsPrimaryKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isForeignKey reports whether the constraint is a foreign key
+func isForeignKey(c Constraint) bool {
+	return strings.EqualFold(c.Type, "FOREIGN KEY")
+}
+
+var whitespaceRe = regexp.MustCompile(`\s+`)
+
+// normalizeSQL collapses whitespace so formatting-only differences are ignored
+func normalizeSQL(sql string) string {
+	return strings.TrimSpace(whitespaceRe.ReplaceAllString(sql, " "))
+}
+
+// equalColumn reports whether two column definitions are equivalent
+func equalColumn(a, b Column) bool {
+	return strings.EqualFold(a.DataType, b.DataType) &&
+		a.Length == b.Length &&
+		a.Scale == b.Scale &&
+		a.IsNullable == b.IsNullable &&
+		a.DefaultValue == b.DefaultValue &&
+		a.AutoIncrement == b.AutoIncrement
+}
+
+// equalIndex reports whether two index definitions are equivalent
+func equalIndex(a, b Index) bool {
+	return equalFold(a.Columns, b.Columns) &&
+		a.IsUnique == b.IsUnique &&
+		a.IsBitmap == b.IsBitmap &&
+		a.IsClustered == b.IsClustered &&
+		strings.EqualFold(a.Type, b.Type) &&
+		normalizeSQL(a.Condition) == normalizeSQL(b.Condition)
+}
+
+// equalConstraint reports whether two constraint definitions are equivalent
+func equalConstraint(a, b Constraint) bool {
+	return strings.EqualFold(a.Type, b.Type) &&
+		equalFold(a.Columns, b.Columns) &&
+		strings.EqualFold(a.RefTable, b.RefTable) &&
+		equalFold(a.RefColumns, b.RefColumns) &&
+		strings.EqualFold(a.UpdateRule, b.UpdateRule) &&
+		strings.EqualFold(a.DeleteRule, b.DeleteRule) &&
+		normalizeSQL(a.CheckExpression) == normalizeSQL(b.CheckExpression)
+}
+
+// equalView reports whether two view definitions are equivalent
+func equalView(a, b View) bool {
+	return normalizeSQL(a.Definition) == normalizeSQL(b.Definition) && a.IsMaterialized == b.IsMaterialized
+}
+
+// equalSequence reports whether two sequence definitions are equivalent
+func equalSequence(a, b Sequence) bool {
+	return a.IncrementBy == b.IncrementBy &&
+		a.MinValue == b.MinValue &&
+		a.MaxValue == b.MaxValue &&
+		a.StartValue == b.StartValue &&
+		a.Cache == b.Cache &&
+		a.Cycle == b.Cycle
+}
+
+// equalTrigger reports whether two trigger definitions are equivalent
+func equalTrigger(a, b Trigger) bool {
+	return strings.EqualFold(a.Table, b.Table) &&
+		strings.EqualFold(a.Timing, b.Timing) &&
+		strings.EqualFold(a.Event, b.Event) &&
+		a.ForEachRow == b.ForEachRow &&
+		normalizeSQL(a.Condition) == normalizeSQL(b.Condition) &&
+		normalizeSQL(a.Body) == normalizeSQL(b.Body)
+}
+
+// equalFunction reports whether two routine definitions are equivalent
+func equalFunction(a, b Function) bool {
+	if len(a.Parameters) != len(b.Parameters) {
+		return false
+	}
+	for i := range a.Parameters {
+		if !strings.EqualFold(a.Parameters[i].Name, b.Parameters[i].Name) ||
+			!strings.EqualFold(a.Parameters[i].DataType, b.Parameters[i].DataType) ||
+			!strings.EqualFold(a.Parameters[i].Direction, b.Parameters[i].Direction) {
+			return false
+		}
+	}
+	return a.IsProc == b.IsProc &&
+		strings.EqualFold(a.Returns, b.Returns) &&
+		strings.EqualFold(a.Language, b.Language) &&
+		normalizeSQL(a.Body) == normalizeSQL(b.Body)
+}
+
+// equalFold reports whether two string slices are equal, ignoring case
+func equalFold(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(strings.TrimSpace(a[i]), strings.TrimSpace(b[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -36,15 +36,28 @@ The package provides migration support through the migration package:
 
 Schema Comparison:
 
-Compare database schemas using the schema package:
+Compare two schemas and render the differences as a migration script:
 
-	import "github.com/mstgnz/sqlmapper/schema"
+	// Find differences
+	changes := sqlmapper.Diff(oldSchema, newSchema)
 
-	// Create schema comparer
-	comparer := schema.NewSchemaComparer(sourceTables, targetTables)
+	// Render ALTER statements for the target database
+	migration, err := postgres.NewPostgreSQL().(sqlmapper.Migrator).GenerateMigration(changes)
 
-	// Find differences
-	differences := comparer.Compare()
+Schema Serialization:
+
+Save a schema as a versioned JSON or YAML document and load it back:
+
+	data, err := sqlmapper.MarshalSchemaJSON(schema)
+	loaded, err := sqlmapper.UnmarshalSchemaJSON(data)
+
+Schema Validation:
+
+Check foreign keys, index columns, names and sequence references of a schema:
+
+	for _, d := range sqlmapper.Validate(schema) {
+		fmt.Println(d)
+	}
 
 Database Support:
 
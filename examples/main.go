@@ -6,97 +6,17 @@ import (
 	"os"
 
 	"github.com/mstgnz/sqlmapper"
-	"github.com/mstgnz/sqlmapper/mysql"
-	"github.com/mstgnz/sqlmapper/oracle"
-	"github.com/mstgnz/sqlmapper/postgres"
-	"github.com/mstgnz/sqlmapper/sqlite"
-	"github.com/mstgnz/sqlmapper/sqlserver"
+	_ "github.com/mstgnz/sqlmapper/dialects"
 	"github.com/mstgnz/sqlmapper/stream"
 )
 
-// DatabaseType represents supported database types
-type DatabaseType string
-
-const (
-	MySQL      DatabaseType = "mysql"
-	PostgreSQL DatabaseType = "postgresql"
-	SQLite     DatabaseType = "sqlite"
-	Oracle     DatabaseType = "oracle"
-	SQLServer  DatabaseType = "sqlserver"
-)
-
-// Parser interface for all database parsers
-type Parser interface {
-	Parse(content string) (*sqlmapper.Schema, error)
-	Generate(schema *sqlmapper.Schema) (string, error)
-}
-
-// ParserFactory creates parser instances
-type ParserFactory struct{}
-
-// NewParser creates a new parser instance based on database type
-func (f *ParserFactory) NewParser(dbType DatabaseType) (Parser, error) {
-	switch dbType {
-	case MySQL:
-		return mysql.NewMySQL(), nil
-	case PostgreSQL:
-		return postgres.NewPostgreSQL(), nil
-	case SQLite:
-		return sqlite.NewSQLite(), nil
-	case Oracle:
-		return oracle.NewOracle(), nil
-	case SQLServer:
-		return sqlserver.NewSQLServer(), nil
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", dbType)
-	}
-}
-
-// Converter handles database conversion operations
-type Converter struct {
-	factory *ParserFactory
-}
-
-// NewConverter creates a new converter instance
-func NewConverter() *Converter {
-	return &Converter{
-		factory: &ParserFactory{},
-	}
-}
-
-// Convert converts SQL from source database type to target database type
-func (c *Converter) Convert(sourceType, targetType DatabaseType, content string) (string, error) {
-	// Create source parser
-	sourceParser, err := c.factory.NewParser(sourceType)
-	if err != nil {
-		return "", fmt.Errorf("source parser error: %v", err)
-	}
-
-	// Parse source content
-	schema, err := sourceParser.Parse(content)
-	if err != nil {
-		return "", fmt.Errorf("parse error: %v", err)
-	}
-
-	// Create target parser
-	targetParser, err := c.factory.NewParser(targetType)
-	if err != nil {
-		return "", fmt.Errorf("target parser error: %v", err)
-	}
-
-	// Generate target content
-	result, err := targetParser.Generate(schema)
-	if err != nil {
-		return "", fmt.Errorf("generate error: %v", err)
-	}
-
-	return result, nil
-}
-
 // StreamExample demonstrates stream parsing functionality
 func StreamExample() {
 	// Create MySQL stream parser
-	parser := mysql.NewMySQLStreamParser()
+	parser, err := stream.NewStreamParser(sqlmapper.MySQL)
+	if err != nil {
+		log.Fatalf("Failed to create stream parser: %v", err)
+	}
 
 	// Open source file
 	file, err := os.Open("examples/files/mysql.sql")
@@ -142,20 +62,18 @@ func main() {
 	fmt.Println("\n=== Stream Parsing Example ===")
 	StreamExample()
 
-	converter := NewConverter()
-
 	// Example conversions
 	examples := []struct {
-		sourceType DatabaseType
-		targetType DatabaseType
+		sourceType sqlmapper.DatabaseType
+		targetType sqlmapper.DatabaseType
 		inputFile  string
 		outputFile string
 	}{
-		{PostgreSQL, MySQL, "examples/files/postgres.sql", "examples/files/output/postgres_to_mysql.sql"},
-		{MySQL, PostgreSQL, "examples/files/mysql.sql", "examples/files/output/mysql_to_postgres.sql"},
-		{Oracle, MySQL, "examples/files/oracle.sql", "examples/files/output/oracle_to_mysql.sql"},
-		{SQLServer, PostgreSQL, "examples/files/sqlserver.sql", "examples/files/output/sqlserver_to_postgres.sql"},
-		{SQLite, Oracle, "examples/files/sqlite.sql", "examples/files/output/sqlite_to_oracle.sql"},
+		{sqlmapper.PostgreSQL, sqlmapper.MySQL, "examples/files/postgres.sql", "examples/files/output/postgres_to_mysql.sql"},
+		{sqlmapper.MySQL, sqlmapper.PostgreSQL, "examples/files/mysql.sql", "examples/files/output/mysql_to_postgres.sql"},
+		{sqlmapper.Oracle, sqlmapper.MySQL, "examples/files/oracle.sql", "examples/files/output/oracle_to_mysql.sql"},
+		{sqlmapper.SQLServer, sqlmapper.PostgreSQL, "examples/files/sqlserver.sql", "examples/files/output/sqlserver_to_postgres.sql"},
+		{sqlmapper.SQLite, sqlmapper.Oracle, "examples/files/sqlite.sql", "examples/files/output/sqlite_to_oracle.sql"},
 	}
 
 	for _, example := range examples {
@@ -168,7 +86,7 @@ func main() {
 		}
 
 		// Convert
-		result, err := converter.Convert(example.sourceType, example.targetType, string(content))
+		result, err := sqlmapper.Convert(example.sourceType, example.targetType, string(content))
 		if err != nil {
 			log.Fatalf("Conversion error: %v", err)
 		}
@@ -1,7 +1,10 @@
 package monitoring
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -16,113 +19,337 @@ type AlertThreshold struct {
 type NotificationType string
 
 const (
-	EmailNotification NotificationType = "email"
-	SlackNotification NotificationType = "slack"
+	EmailNotification   NotificationType = "email"
+	SlackNotification   NotificationType = "slack"
+	WebhookNotification NotificationType = "webhook"
 )
 
 // NotificationChannel represents a channel for sending alerts
 type NotificationChannel struct {
 	Type   NotificationType
-	Target string
+	Target string // Recipients of an email, or the URL of a webhook
+
+	// Options are the type-specific settings of the channel, such as the SMTP
+	// server of email notifications
+	Options map[string]string
+
+	// Notifier delivers the alerts of the channel instead of the notifier
+	// registered for Type
+	Notifier Notifier
+
+	// Retry configures the retries and timeout of each notification
+	Retry RetryPolicy
 }
 
+// AlertState is the state of an alert rule
+type AlertState string
+
+const (
+	AlertInactive AlertState = "inactive" // The condition does not hold
+	AlertPending  AlertState = "pending"  // The condition holds, but not yet for the rule's For duration
+	AlertFiring   AlertState = "firing"   // The condition has held for the rule's For duration
+	AlertResolved AlertState = "resolved" // The condition stopped holding while the rule was firing
+)
+
+// AlertCondition reports whether an alert rule's condition holds for the
+// current metrics, with the values to include in its alerts
+type AlertCondition func(metrics *MetricsCollector) (bool, map[string]interface{})
+
+// AlertRule is a named condition that raises alerts
+type AlertRule struct {
+	Name      string         // Unique name of the rule
+	Message   string         // Summary of the alerts of the rule
+	Condition AlertCondition // Condition that makes the rule fire
+
+	// For is how long the condition must hold before the rule fires; until
+	// then the rule is pending. Zero fires on the first check.
+	For time.Duration
+
+	// RepeatInterval is how often a firing rule is notified again. Zero uses
+	// AlertConfig.RepeatInterval.
+	RepeatInterval time.Duration
+}
+
+// Built-in rule names, for the thresholds of AlertThreshold
+const (
+	ErrorRateRule      = "error_rate"
+	ProcessingTimeRule = "processing_time"
+	MemoryUsageRule    = "memory_usage"
+)
+
+// DefaultRepeatInterval is how often a firing rule is notified again by default
+const DefaultRepeatInterval = time.Minute
+
 // AlertConfig holds configuration for the alert manager
 type AlertConfig struct {
+	// Threshold sets up the built-in rules; zero thresholds are not checked.
+	// MemoryUsage is in MB.
 	Threshold     AlertThreshold
+	Rules         []AlertRule // Custom rules, checked after the built-in rules
 	Notifications []NotificationChannel
+
+	// Metrics is the collector the rules are checked against; nil creates a
+	// new one
+	Metrics *MetricsCollector
+
+	// RepeatInterval is how often firing rules are notified again; zero uses
+	// DefaultRepeatInterval
+	RepeatInterval time.Duration
 }
 
 // AlertManager handles monitoring and alerting
 type AlertManager struct {
 	config    AlertConfig
 	metrics   *MetricsCollector
-	lastAlert time.Time
+	notifiers []channelNotifier
+	now       func() time.Time
+
+	mu       sync.Mutex // Guards rules and errs
+	rules    []*ruleState
+	errs     []error    // Invalid rules of the configuration
+	notifyMu sync.Mutex // Keeps notifications in the order of the checks
 }
 
-// NewAlertManager creates a new alert manager
+// ruleState is an alert rule and its state
+type ruleState struct {
+	rule     AlertRule
+	state    AlertState
+	since    time.Time // When the condition started to hold
+	notified time.Time // When the last firing alert was sent
+}
+
+// channelNotifier is the notifier of a notification channel, or the error
+// creating it
+type channelNotifier struct {
+	channel  NotificationChannel
+	notifier Notifier
+	err      error
+}
+
+// NewAlertManager creates a new alert manager. The notifiers of the channels are
+// created up front; a misconfigured channel fails every alert sent through it,
+// and invalid rules are reported by every CheckThresholds.
 func NewAlertManager(config AlertConfig) *AlertManager {
-	return &AlertManager{
+	notifiers := make([]channelNotifier, len(config.Notifications))
+	for i, channel := range config.Notifications {
+		notifier, err := NewNotifier(channel)
+		notifiers[i] = channelNotifier{channel: channel, notifier: notifier, err: err}
+	}
+
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = NewMetricsCollector()
+	}
+
+	a := &AlertManager{
 		config:    config,
-		metrics:   NewMetricsCollector(),
-		lastAlert: time.Now(),
+		metrics:   metrics,
+		notifiers: notifiers,
+		now:       time.Now,
+	}
+	for _, rule := range append(thresholdRules(config.Threshold), config.Rules...) {
+		if err := a.AddRule(rule); err != nil {
+			a.errs = append(a.errs, err)
+		}
 	}
+	return a
 }
 
-// CheckThresholds checks if any metrics have exceeded their thresholds
-func (a *AlertManager) CheckThresholds() error {
-	// Check error rate
-	if a.metrics.ErrorRate() > a.config.Threshold.ErrorRate {
-		if err := a.sendAlert("Error rate threshold exceeded", map[string]interface{}{
-			"current_rate": a.metrics.ErrorRate(),
-			"threshold":    a.config.Threshold.ErrorRate,
-		}); err != nil {
-			return fmt.Errorf("failed to send error rate alert: %v", err)
-		}
+// thresholdRules returns the built-in rules of the non-zero thresholds
+func thresholdRules(threshold AlertThreshold) []AlertRule {
+	var rules []AlertRule
+	if threshold.ErrorRate > 0 {
+		rules = append(rules, AlertRule{
+			Name:    ErrorRateRule,
+			Message: "Error rate threshold exceeded",
+			Condition: func(m *MetricsCollector) (bool, map[string]interface{}) {
+				rate := m.ErrorRate()
+				return rate > threshold.ErrorRate, map[string]interface{}{
+					"current_rate": rate,
+					"threshold":    threshold.ErrorRate,
+				}
+			},
+		})
+	}
+	if threshold.ProcessingTime > 0 {
+		rules = append(rules, AlertRule{
+			Name:    ProcessingTimeRule,
+			Message: "Processing time threshold exceeded",
+			Condition: func(m *MetricsCollector) (bool, map[string]interface{}) {
+				avgTime := m.AverageProcessingTime()
+				return avgTime > threshold.ProcessingTime, map[string]interface{}{
+					"current_time": avgTime,
+					"threshold":    threshold.ProcessingTime,
+				}
+			},
+		})
 	}
+	if threshold.MemoryUsage > 0 {
+		rules = append(rules, AlertRule{
+			Name:    MemoryUsageRule,
+			Message: "Memory usage threshold exceeded",
+			Condition: func(m *MetricsCollector) (bool, map[string]interface{}) {
+				memUsage := float64(m.MemoryUsage()) / (1024 * 1024) // Convert to MB
+				return memUsage > threshold.MemoryUsage, map[string]interface{}{
+					"current_usage": memUsage,
+					"threshold":     threshold.MemoryUsage,
+				}
+			},
+		})
+	}
+	return rules
+}
 
-	// Check processing time
-	if avgTime := a.metrics.AverageProcessingTime(); avgTime > a.config.Threshold.ProcessingTime {
-		if err := a.sendAlert("Processing time threshold exceeded", map[string]interface{}{
-			"current_time": avgTime,
-			"threshold":    a.config.Threshold.ProcessingTime,
-		}); err != nil {
-			return fmt.Errorf("failed to send processing time alert: %v", err)
-		}
+// AddRule adds an alert rule, which starts out inactive
+//
+// Parameters:
+//   - rule: The rule to add
+//
+// Returns:
+//   - error: An error if the rule has no name or condition, or its name is taken
+func (a *AlertManager) AddRule(rule AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("alert rule needs a name")
+	}
+	if rule.Condition == nil {
+		return fmt.Errorf("alert rule %s needs a condition", rule.Name)
 	}
 
-	// Check memory usage
-	memUsage := float64(a.metrics.MemoryUsage()) / (1024 * 1024) // Convert to MB
-	if memUsage > a.config.Threshold.MemoryUsage {
-		if err := a.sendAlert("Memory usage threshold exceeded", map[string]interface{}{
-			"current_usage": memUsage,
-			"threshold":     a.config.Threshold.MemoryUsage,
-		}); err != nil {
-			return fmt.Errorf("failed to send memory usage alert: %v", err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, r := range a.rules {
+		if r.rule.Name == rule.Name {
+			return fmt.Errorf("duplicate alert rule: %s", rule.Name)
 		}
 	}
-
+	a.rules = append(a.rules, &ruleState{rule: rule, state: AlertInactive})
 	return nil
 }
 
-// sendAlert sends an alert through configured notification channels
-func (a *AlertManager) sendAlert(message string, data map[string]interface{}) error {
-	// Implement rate limiting
-	if time.Since(a.lastAlert) < time.Minute {
-		return nil // Skip if last alert was less than a minute ago
+// States returns the current state of every rule by rule name
+func (a *AlertManager) States() map[string]AlertState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	states := make(map[string]AlertState, len(a.rules))
+	for _, r := range a.rules {
+		states[r.rule.Name] = r.state
 	}
-	a.lastAlert = time.Now()
+	return states
+}
 
-	for _, channel := range a.config.Notifications {
-		if err := a.sendNotification(channel, message, data); err != nil {
-			return fmt.Errorf("failed to send notification via %s: %v", channel.Type, err)
+// CheckThresholds evaluates every rule against the current metrics and moves it
+// through its states:
+//
+//	inactive/resolved -> pending   the condition holds
+//	pending -> firing              the condition has held for the rule's For duration
+//	pending -> inactive            the condition stopped holding
+//	firing -> resolved             the condition stopped holding
+//
+// A firing alert is sent when a rule starts firing and again every repeat
+// interval while it keeps firing, and a resolved alert when it is resolved.
+// Rules are rate limited independently of each other. CheckThresholds is safe
+// for concurrent use; notifications of concurrent checks are sent one check at
+// a time.
+//
+// Returns:
+//   - error: The invalid rules of the configuration and the failed notifications
+func (a *AlertManager) CheckThresholds() error {
+	a.mu.Lock()
+	now := a.now()
+	var alerts []Alert
+	for _, r := range a.rules {
+		if alert, ok := a.evaluate(r, now); ok {
+			alerts = append(alerts, alert)
 		}
 	}
+	errs := append([]error(nil), a.errs...)
 
-	return nil
+	// Hand over to the notification lock before releasing the state, so that
+	// the alerts of consecutive checks are sent in order
+	a.notifyMu.Lock()
+	a.mu.Unlock()
+	defer a.notifyMu.Unlock()
+
+	for _, alert := range alerts {
+		if err := a.sendAlert(alert); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send %s alert: %v", alert.Rule, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// sendNotification sends a notification through a specific channel
-func (a *AlertManager) sendNotification(channel NotificationChannel, message string, data map[string]interface{}) error {
-	switch channel.Type {
-	case EmailNotification:
-		return a.sendEmailNotification(channel.Target, message, data)
-	case SlackNotification:
-		return a.sendSlackNotification(channel.Target, message, data)
-	default:
-		return fmt.Errorf("unsupported notification type: %s", channel.Type)
+// evaluate moves a rule to its next state and returns the alert to send, if any
+func (a *AlertManager) evaluate(r *ruleState, now time.Time) (Alert, bool) {
+	active, data := r.rule.Condition(a.metrics)
+	alert := Alert{Rule: r.rule.Name, Message: r.rule.Message, Data: data, Time: now}
+
+	if !active {
+		switch r.state {
+		case AlertPending:
+			r.state = AlertInactive
+		case AlertFiring:
+			r.state = AlertResolved
+			alert.State, alert.Since = AlertResolved, r.since
+			return alert, true
+		}
+		return Alert{}, false
+	}
+
+	switch r.state {
+	case AlertInactive, AlertResolved:
+		r.state, r.since = AlertPending, now
+	case AlertFiring:
+		repeat := r.rule.RepeatInterval
+		if repeat <= 0 {
+			repeat = a.config.RepeatInterval
+		}
+		if repeat <= 0 {
+			repeat = DefaultRepeatInterval
+		}
+		if now.Sub(r.notified) < repeat {
+			return Alert{}, false
+		}
+		r.notified = now
+		alert.State, alert.Since = AlertFiring, r.since
+		return alert, true
 	}
+
+	if r.state == AlertPending && now.Sub(r.since) >= r.rule.For {
+		r.state, r.notified = AlertFiring, now
+		alert.State, alert.Since = AlertFiring, r.since
+		return alert, true
+	}
+	return Alert{}, false
 }
 
-// sendEmailNotification sends an email notification
-func (a *AlertManager) sendEmailNotification(target, message string, data map[string]interface{}) error {
-	// TODO: Implement email sending logic
-	return nil
+// sendAlert sends an alert through configured notification channels. The
+// channels are notified concurrently; an error is returned for each channel
+// that failed.
+func (a *AlertManager) sendAlert(alert Alert) error {
+	errs := make([]error, len(a.notifiers))
+	var wg sync.WaitGroup
+	for i, n := range a.notifiers {
+		if n.err != nil {
+			errs[i] = fmt.Errorf("failed to send notification via %s: %v", n.channel.Type, n.err)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, n channelNotifier) {
+			defer wg.Done()
+			if err := n.notifier.Notify(context.Background(), alert); err != nil {
+				errs[i] = fmt.Errorf("failed to send notification via %s: %v", n.channel.Type, err)
+			}
+		}(i, n)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
 
-// sendSlackNotification sends a Slack notification
-func (a *AlertManager) sendSlackNotification(target, message string, data map[string]interface{}) error {
-	// TODO: Implement Slack notification logic
-	return nil
+// Metrics returns the collector the rules are checked against, to be fed by
+// the code being monitored or by a Sampler
+func (a *AlertManager) Metrics() *MetricsCollector {
+	return a.metrics
 }
 
 // GetMetrics returns the current metrics
@@ -130,7 +357,50 @@ func (a *AlertManager) GetMetrics() map[string]interface{} {
 	return a.metrics.GetMetrics()
 }
 
-// RecordMetric records a new metric value
-func (a *AlertManager) RecordMetric(name string, value interface{}) {
-	// TODO: Implement metric recording logic
+// RecordMetric records a metric value on the collector of the manager. The
+// gauges are named as in GetMetrics: memory_usage, cpu_utilization,
+// goroutine_count and channel_buffer_usage. A processing_time value is recorded
+// as the processing time of one object.
+//
+// Parameters:
+//   - name: The name of the metric
+//   - value: A number, or a time.Duration for processing_time
+//
+// Returns:
+//   - error: An error if the metric is unknown or the value is not a number
+func (a *AlertManager) RecordMetric(name string, value interface{}) error {
+	if name == "processing_time" {
+		duration, ok := value.(time.Duration)
+		if !ok {
+			return fmt.Errorf("invalid value for metric %s: %v", name, value)
+		}
+		a.metrics.RecordProcessingTime(duration)
+		return nil
+	}
+
+	var number float64
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case float64:
+		number = v
+	default:
+		return fmt.Errorf("invalid value for metric %s: %v", name, value)
+	}
+
+	switch name {
+	case "memory_usage":
+		a.metrics.SetMemoryUsage(int64(number))
+	case "cpu_utilization":
+		a.metrics.SetCPUUtilization(number)
+	case "goroutine_count":
+		a.metrics.SetGoroutineCount(int64(number))
+	case "channel_buffer_usage":
+		a.metrics.SetChannelBufferUsage(int64(number))
+	default:
+		return fmt.Errorf("unknown metric: %s", name)
+	}
+	return nil
 }
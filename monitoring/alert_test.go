@@ -0,0 +1,222 @@
+package monitoring
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// alertRecorder is a notifier that records the alerts it receives
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *alertRecorder) Notify(ctx context.Context, alert Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+// take returns the recorded alerts as "rule:state" and forgets them
+func (r *alertRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var got []string
+	for _, alert := range r.alerts {
+		got = append(got, alert.Rule+":"+string(alert.State))
+	}
+	r.alerts = nil
+	return got
+}
+
+// newTestAlertManager returns an alert manager that notifies a recorder and
+// whose clock is advanced by the returned function
+func newTestAlertManager(config AlertConfig) (*AlertManager, *alertRecorder, func(time.Duration)) {
+	recorder := &alertRecorder{}
+	config.Notifications = append(config.Notifications, NotificationChannel{Type: "recorder", Notifier: recorder})
+	manager := NewAlertManager(config)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return now }
+	return manager, recorder, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestAlertManager_Lifecycle(t *testing.T) {
+	var active atomic.Bool
+	manager, recorder, advance := newTestAlertManager(AlertConfig{
+		Rules: []AlertRule{{
+			Name:    "backlog",
+			Message: "Parse backlog is growing",
+			For:     5 * time.Minute,
+			Condition: func(m *MetricsCollector) (bool, map[string]interface{}) {
+				return active.Load(), map[string]interface{}{"active": active.Load()}
+			},
+			RepeatInterval: 10 * time.Minute,
+		}},
+	})
+
+	check := func(state AlertState, alerts ...string) {
+		t.Helper()
+		assert.NoError(t, manager.CheckThresholds())
+		assert.Equal(t, state, manager.States()["backlog"])
+		assert.Equal(t, alerts, recorder.take())
+	}
+
+	check(AlertInactive)
+
+	// Conditions that stop holding before the For duration never fire
+	active.Store(true)
+	check(AlertPending)
+	advance(4 * time.Minute)
+	active.Store(false)
+	check(AlertInactive)
+
+	active.Store(true)
+	check(AlertPending)
+	advance(4 * time.Minute)
+	check(AlertPending)
+	advance(time.Minute)
+	check(AlertFiring, "backlog:firing")
+
+	// Firing rules are notified again after the repeat interval
+	advance(9 * time.Minute)
+	check(AlertFiring)
+	advance(time.Minute)
+	check(AlertFiring, "backlog:firing")
+
+	active.Store(false)
+	advance(time.Minute)
+	check(AlertResolved, "backlog:resolved")
+	check(AlertResolved)
+
+	active.Store(true)
+	check(AlertPending)
+	advance(5 * time.Minute)
+	active.Store(false)
+	check(AlertInactive)
+}
+
+func TestAlertManager_ResolvedAlert(t *testing.T) {
+	manager, recorder, advance := newTestAlertManager(AlertConfig{
+		Threshold: AlertThreshold{MemoryUsage: 100},
+	})
+
+	manager.metrics.SetMemoryUsage(200 * 1024 * 1024)
+	assert.NoError(t, manager.CheckThresholds())
+	advance(3 * time.Minute)
+	manager.metrics.SetMemoryUsage(50 * 1024 * 1024)
+	assert.NoError(t, manager.CheckThresholds())
+
+	if assert.Len(t, recorder.alerts, 2) {
+		firing, resolved := recorder.alerts[0], recorder.alerts[1]
+		assert.Equal(t, AlertFiring, firing.State)
+		assert.Equal(t, float64(200), firing.Data["current_usage"])
+		assert.Equal(t, AlertResolved, resolved.State)
+		assert.Equal(t, float64(50), resolved.Data["current_usage"])
+		assert.Equal(t, firing.Time, resolved.Since)
+		assert.Equal(t, 3*time.Minute, resolved.Time.Sub(resolved.Since))
+
+		subject, err := render(nil, defaultSubject, resolved)
+		assert.NoError(t, err)
+		assert.Equal(t, "[sqlmapper] Resolved: Memory usage threshold exceeded", subject)
+		subject, err = render(nil, defaultSubject, firing)
+		assert.NoError(t, err)
+		assert.Equal(t, "[sqlmapper] Memory usage threshold exceeded", subject)
+	}
+}
+
+func TestAlertManager_RecordMetric(t *testing.T) {
+	manager, recorder, _ := newTestAlertManager(AlertConfig{
+		Threshold: AlertThreshold{MemoryUsage: 100},
+	})
+
+	assert.NoError(t, manager.RecordMetric("memory_usage", 200*1024*1024))
+	assert.NoError(t, manager.RecordMetric("cpu_utilization", 42.5))
+	assert.NoError(t, manager.RecordMetric("goroutine_count", int64(12)))
+	assert.NoError(t, manager.RecordMetric("processing_time", 2*time.Millisecond))
+	assert.Equal(t, int64(200*1024*1024), manager.Metrics().MemoryUsage())
+	assert.Equal(t, 42.5, manager.Metrics().CPUUtilization())
+	assert.Equal(t, int64(12), manager.Metrics().GoroutineCount())
+
+	assert.NoError(t, manager.CheckThresholds())
+	assert.Equal(t, []string{"memory_usage:firing"}, recorder.take())
+
+	assert.EqualError(t, manager.RecordMetric("disk_usage", 1), "unknown metric: disk_usage")
+	assert.Error(t, manager.RecordMetric("memory_usage", "a lot"))
+	assert.Error(t, manager.RecordMetric("processing_time", 5))
+}
+
+func TestAlertManager_RateLimitPerRule(t *testing.T) {
+	manager, recorder, advance := newTestAlertManager(AlertConfig{
+		Threshold:      AlertThreshold{ErrorRate: 10, MemoryUsage: 100},
+		RepeatInterval: 5 * time.Minute,
+	})
+	assert.Equal(t, map[string]AlertState{ErrorRateRule: AlertInactive, MemoryUsageRule: AlertInactive}, manager.States())
+
+	manager.metrics.IncrementProcessedObjects()
+	manager.metrics.IncrementFailedOperations()
+	assert.NoError(t, manager.CheckThresholds())
+	assert.Equal(t, []string{"error_rate:firing"}, recorder.take())
+
+	// A firing rule does not hold back the alerts of another
+	advance(time.Minute)
+	manager.metrics.SetMemoryUsage(200 * 1024 * 1024)
+	assert.NoError(t, manager.CheckThresholds())
+	assert.Equal(t, []string{"memory_usage:firing"}, recorder.take())
+
+	advance(4 * time.Minute)
+	assert.NoError(t, manager.CheckThresholds())
+	assert.Equal(t, []string{"error_rate:firing"}, recorder.take())
+	advance(time.Minute)
+	assert.NoError(t, manager.CheckThresholds())
+	assert.Equal(t, []string{"memory_usage:firing"}, recorder.take())
+}
+
+func TestAlertManager_InvalidRules(t *testing.T) {
+	always := func(*MetricsCollector) (bool, map[string]interface{}) { return true, nil }
+	manager, recorder, _ := newTestAlertManager(AlertConfig{
+		Rules: []AlertRule{
+			{Name: "always", Condition: always},
+			{Name: "always", Condition: always},
+			{Name: "broken"},
+			{Condition: always},
+		},
+	})
+
+	err := manager.CheckThresholds()
+	assert.ErrorContains(t, err, "duplicate alert rule: always")
+	assert.ErrorContains(t, err, "alert rule broken needs a condition")
+	assert.ErrorContains(t, err, "alert rule needs a name")
+	assert.Equal(t, []string{"always:firing"}, recorder.take())
+
+	assert.EqualError(t, manager.AddRule(AlertRule{Name: "always", Condition: always}), "duplicate alert rule: always")
+	assert.NoError(t, manager.AddRule(AlertRule{Name: "later", Condition: always}))
+	assert.Equal(t, AlertInactive, manager.States()["later"])
+}
+
+func TestAlertManager_Concurrent(t *testing.T) {
+	manager, recorder, _ := newTestAlertManager(AlertConfig{
+		Threshold: AlertThreshold{ErrorRate: 10},
+	})
+	manager.metrics.IncrementProcessedObjects()
+	manager.metrics.IncrementFailedOperations()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, manager.CheckThresholds())
+			_ = manager.States()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"error_rate:firing"}, recorder.take())
+}
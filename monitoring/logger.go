@@ -1,10 +1,15 @@
 package monitoring
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -28,88 +33,172 @@ const (
 	TextFormat LogFormat = "text"
 )
 
+// Special output paths that write to the standard streams instead of a file
+const (
+	StdoutPath = "stdout"
+	StderrPath = "stderr"
+)
+
 // LogConfig holds configuration for the logger
 type LogConfig struct {
-	Level      LogLevel
-	Format     LogFormat
+	Level  LogLevel
+	Format LogFormat
+
+	// OutputPath is the log file, or StdoutPath or StderrPath. Empty writes
+	// to stdout.
 	OutputPath string
-	ErrorPath  string
+
+	// ErrorPath is the file that error messages are written to instead of
+	// OutputPath, or StdoutPath or StderrPath. Empty writes errors to
+	// OutputPath as well.
+	ErrorPath string
+
 	MaxSize    int // megabytes
 	MaxBackups int
 	MaxAge     int // days
 	Compress   bool
 }
 
-// Logger handles logging operations
+// Logger handles logging operations. Loggers are safe for concurrent use; the
+// loggers returned by With share the outputs of their parent.
 type Logger struct {
 	config LogConfig
 	output io.Writer
 	error  io.Writer
+	fields map[string]interface{} // Fields added to every message
+	mu     *sync.Mutex            // Serializes writes to the outputs
+	files  []io.Closer
 }
 
-// NewLogger creates a new logger with the given configuration
+// NewLogger creates a new logger with the given configuration. Log files are
+// rotated by size and age; the standard streams are not rotated.
 func NewLogger(config LogConfig) (*Logger, error) {
+	l := &Logger{config: config, mu: &sync.Mutex{}}
+
+	output, err := l.open(config.OutputPath)
+	if err != nil {
+		return nil, err
+	}
+	l.output, l.error = output, output
+
+	// Errors share the main output unless they have a file of their own
+	if config.ErrorPath != "" && config.ErrorPath != config.OutputPath {
+		if l.error, err = l.open(config.ErrorPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// open returns the writer of an output path
+func (l *Logger) open(path string) (io.Writer, error) {
+	switch path {
+	case "", StdoutPath:
+		return os.Stdout, nil
+	case StderrPath:
+		return os.Stderr, nil
+	}
+
 	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(config.OutputPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %v", err)
 	}
 
-	// Configure main log output
-	output := &lumberjack.Logger{
-		Filename:   config.OutputPath,
-		MaxSize:    config.MaxSize,
-		MaxBackups: config.MaxBackups,
-		MaxAge:     config.MaxAge,
-		Compress:   config.Compress,
+	file := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    l.config.MaxSize,
+		MaxBackups: l.config.MaxBackups,
+		MaxAge:     l.config.MaxAge,
+		Compress:   l.config.Compress,
 	}
+	l.files = append(l.files, file)
+	return file, nil
+}
 
-	// Configure error log output
-	errorOutput := &lumberjack.Logger{
-		Filename:   config.ErrorPath,
-		MaxSize:    config.MaxSize,
-		MaxBackups: config.MaxBackups,
-		MaxAge:     config.MaxAge,
-		Compress:   config.Compress,
+// With returns a logger that adds fields to every message it logs, in addition
+// to the fields of l. Fields passed to a log call take precedence.
+//
+// Parameters:
+//   - fields: The fields to add
+//
+// Returns:
+//   - *Logger: The child logger, writing to the outputs of l
+func (l *Logger) With(fields map[string]interface{}) *Logger {
+	child := *l
+	child.fields = make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		child.fields[k] = v
 	}
+	for k, v := range fields {
+		child.fields[k] = v
+	}
+	return &child
+}
+
+// Close closes the log files of the logger, which are shared with the loggers
+// returned by With
+func (l *Logger) Close() error {
+	var errs []error
+	for _, file := range l.files {
+		if err := file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
 
-	return &Logger{
-		config: config,
-		output: output,
-		error:  errorOutput,
-	}, nil
+// Enabled reports whether messages of the level are logged
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level >= l.config.Level
 }
 
 // Debug logs a debug message
 func (l *Logger) Debug(msg string, fields map[string]interface{}) {
 	if l.config.Level <= DebugLevel {
-		l.log(DebugLevel, msg, fields)
+		l.log(time.Now(), DebugLevel, msg, fields)
 	}
 }
 
 // Info logs an info message
 func (l *Logger) Info(msg string, fields map[string]interface{}) {
 	if l.config.Level <= InfoLevel {
-		l.log(InfoLevel, msg, fields)
+		l.log(time.Now(), InfoLevel, msg, fields)
 	}
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(msg string, fields map[string]interface{}) {
 	if l.config.Level <= WarnLevel {
-		l.log(WarnLevel, msg, fields)
+		l.log(time.Now(), WarnLevel, msg, fields)
 	}
 }
 
 // Error logs an error message
 func (l *Logger) Error(msg string, fields map[string]interface{}) {
 	if l.config.Level <= ErrorLevel {
-		l.log(ErrorLevel, msg, fields)
+		l.log(time.Now(), ErrorLevel, msg, fields)
 	}
 }
 
-// log writes a log message with the specified level
-func (l *Logger) log(level LogLevel, msg string, fields map[string]interface{}) {
-	timestamp := time.Now().Format(time.RFC3339)
+// log writes a log message with the specified level. Messages with a zero
+// time have no timestamp.
+func (l *Logger) log(t time.Time, level LogLevel, msg string, fields map[string]interface{}) {
+	var timestamp string
+	if !t.IsZero() {
+		timestamp = t.Format(time.RFC3339)
+	}
+
+	if len(l.fields) > 0 {
+		merged := make(map[string]interface{}, len(l.fields)+len(fields))
+		for k, v := range l.fields {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		fields = merged
+	}
 
 	var output io.Writer
 	if level == ErrorLevel {
@@ -118,35 +207,88 @@ func (l *Logger) log(level LogLevel, msg string, fields map[string]interface{})
 		output = l.output
 	}
 
+	// Messages are formatted first and written at once, so that concurrent
+	// messages do not interleave
+	var buf bytes.Buffer
 	if l.config.Format == JSONFormat {
-		l.logJSON(output, level, timestamp, msg, fields)
+		l.logJSON(&buf, level, timestamp, msg, fields)
 	} else {
-		l.logText(output, level, timestamp, msg, fields)
+		l.logText(&buf, level, timestamp, msg, fields)
 	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = output.Write(buf.Bytes())
 }
 
-// logJSON writes a log message in JSON format
+// logJSON writes a log message in JSON format. The timestamp, level and message
+// come first, followed by the fields sorted by name; fields named like one of
+// the basic fields replace it.
 func (l *Logger) logJSON(w io.Writer, level LogLevel, timestamp, msg string, fields map[string]interface{}) {
 	// Add basic fields
+	keys := []string{"timestamp", "level", "message"}
 	logEntry := map[string]interface{}{
 		"timestamp": timestamp,
 		"level":     level.String(),
 		"message":   msg,
 	}
+	if timestamp == "" {
+		keys = keys[1:]
+		delete(logEntry, "timestamp")
+	}
 
-	// Add custom fields
+	// Add custom fields; those named like a basic field are written as fields.<name>
+	custom := make([]string, 0, len(fields))
 	for k, v := range fields {
+		switch k {
+		case "timestamp", "level", "message":
+			k = "fields." + k
+		}
+		custom = append(custom, k)
 		logEntry[k] = v
 	}
+	sort.Strings(custom)
+	keys = append(keys, custom...)
 
 	// Write JSON to output
-	fmt.Fprintf(w, "%v\n", logEntry)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(jsonValue(logEntry[k]))
+	}
+	buf.WriteString("}\n")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// jsonValue encodes a field value as JSON. Errors are written as their message,
+// and values that cannot be encoded as their fmt representation.
+func jsonValue(v interface{}) []byte {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		buf.Reset()
+		_ = enc.Encode(fmt.Sprintf("%+v", v))
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
 }
 
 // logText writes a log message in text format
 func (l *Logger) logText(w io.Writer, level LogLevel, timestamp, msg string, fields map[string]interface{}) {
 	// Write basic log entry
-	fmt.Fprintf(w, "%s [%s] %s", timestamp, level.String(), msg)
+	if timestamp != "" {
+		fmt.Fprintf(w, "%s ", timestamp)
+	}
+	fmt.Fprintf(w, "[%s] %s", level.String(), msg)
 
 	// Add fields if present
 	if len(fields) > 0 {
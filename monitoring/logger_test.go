@@ -0,0 +1,170 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLogger returns a logger that writes messages and errors to buffers
+func newTestLogger(config LogConfig) (*Logger, *bytes.Buffer, *bytes.Buffer) {
+	var output, errorOutput bytes.Buffer
+	return &Logger{config: config, output: &output, error: &errorOutput, mu: &sync.Mutex{}}, &output, &errorOutput
+}
+
+// decodeLines decodes every line of a buffer as a JSON object
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_JSON(t *testing.T) {
+	logger, output, errorOutput := newTestLogger(LogConfig{Level: InfoLevel, Format: JSONFormat})
+
+	logger.Debug("hidden", nil)
+	logger.Info("parsed <table>", map[string]interface{}{
+		"table":    "users",
+		"rows":     3,
+		"elapsed":  time.Second,
+		"err":      errors.New("bad row"),
+		"callback": func() {},
+	})
+	logger.Error("failed", map[string]interface{}{"level": "custom", "message": "hidden", "timestamp": 0})
+
+	line := output.String()
+	assert.True(t, strings.HasPrefix(line, `{"timestamp":"`), line)
+	assert.Contains(t, line, `"level":"INFO","message":"parsed <table>","callback":"0x`)
+	assert.Contains(t, line, `"elapsed":1000000000,"err":"bad row","rows":3,"table":"users"}`+"\n")
+
+	entries := decodeLines(t, output)
+	if assert.Len(t, entries, 1) {
+		_, err := time.Parse(time.RFC3339, entries[0]["timestamp"].(string))
+		assert.NoError(t, err)
+	}
+	entries = decodeLines(t, errorOutput)
+	if assert.Len(t, entries, 1) {
+		// Fields cannot replace the basic fields
+		assert.Equal(t, "ERROR", entries[0]["level"])
+		assert.Equal(t, "failed", entries[0]["message"])
+		assert.IsType(t, "", entries[0]["timestamp"])
+		assert.Equal(t, "custom", entries[0]["fields.level"])
+		assert.Equal(t, "hidden", entries[0]["fields.message"])
+		assert.Equal(t, float64(0), entries[0]["fields.timestamp"])
+	}
+}
+
+func TestLogger_With(t *testing.T) {
+	logger, output, _ := newTestLogger(LogConfig{Format: JSONFormat})
+
+	child := logger.With(map[string]interface{}{"component": "parser", "dialect": "mysql"})
+	child.With(map[string]interface{}{"file": "dump.sql"}).Info("started", map[string]interface{}{"dialect": "postgres"})
+	child.Info("done", nil)
+	logger.Info("plain", nil)
+
+	entries := decodeLines(t, output)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "parser", entries[0]["component"])
+		assert.Equal(t, "postgres", entries[0]["dialect"])
+		assert.Equal(t, "dump.sql", entries[0]["file"])
+		assert.Equal(t, "mysql", entries[1]["dialect"])
+		assert.NotContains(t, entries[1], "file")
+		assert.NotContains(t, entries[2], "component")
+	}
+}
+
+func TestLogger_Slog(t *testing.T) {
+	logger, output, errorOutput := newTestLogger(LogConfig{Level: InfoLevel, Format: JSONFormat})
+	log := slog.New(logger.Handler()).With("component", "stream")
+
+	log.Debug("hidden")
+	log.Info("parsed", "rows", 3, slog.Group("table", "name", "users", "schema", "public"))
+	log.WithGroup("request").With("id", 7).Warn("slow", "elapsed", time.Second, slog.Group("empty"))
+	log.Error("failed", "err", errors.New("bad row"))
+	log.Log(context.Background(), slog.LevelError+4, "fatal")
+
+	entries := decodeLines(t, output)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, map[string]interface{}{
+			"timestamp":    entries[0]["timestamp"],
+			"level":        "INFO",
+			"message":      "parsed",
+			"component":    "stream",
+			"rows":         float64(3),
+			"table.name":   "users",
+			"table.schema": "public",
+		}, entries[0])
+		assert.Equal(t, map[string]interface{}{
+			"timestamp":       entries[1]["timestamp"],
+			"level":           "WARN",
+			"message":         "slow",
+			"component":       "stream",
+			"request.id":      float64(7),
+			"request.elapsed": float64(time.Second),
+		}, entries[1])
+	}
+	entries = decodeLines(t, errorOutput)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "bad row", entries[0]["err"])
+		assert.Equal(t, "fatal", entries[1]["message"])
+	}
+
+	// Records without a time have no timestamp
+	output.Reset()
+	assert.NoError(t, logger.Handler().Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "untimed", 0)))
+	assert.Equal(t, `{"level":"INFO","message":"untimed"}`+"\n", output.String())
+}
+
+func TestNewLogger_Outputs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "sqlmapper.log")
+
+	// Without an error path, errors go to the main log
+	logger, err := NewLogger(LogConfig{Format: TextFormat, OutputPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("started", nil)
+	logger.Error("failed", map[string]interface{}{"table": "users"})
+	assert.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], " [INFO] started")
+		assert.Contains(t, lines[1], " [ERROR] failed fields=map[table:users]")
+	}
+
+	// Standard streams are not opened as files
+	logger, err = NewLogger(LogConfig{OutputPath: StdoutPath, ErrorPath: StderrPath})
+	assert.NoError(t, err)
+	assert.Equal(t, os.Stdout, logger.output)
+	assert.Equal(t, os.Stderr, logger.error)
+	assert.Empty(t, logger.files)
+
+	logger, err = NewLogger(LogConfig{ErrorPath: filepath.Join(dir, "error.log")})
+	assert.NoError(t, err)
+	assert.Equal(t, os.Stdout, logger.output)
+	assert.Len(t, logger.files, 1)
+	logger.Error("failed", nil)
+	assert.NoError(t, logger.Close())
+	assert.FileExists(t, filepath.Join(dir, "error.log"))
+}
@@ -1,19 +1,28 @@
 package monitoring
 
 import (
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// processingTimeBuckets are the upper bounds, in seconds, of the buckets of the
+// processing time histogram
+var processingTimeBuckets = [...]float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
 // MetricsCollector collects and manages performance metrics
 type MetricsCollector struct {
 	totalObjects        int64
 	totalProcessingTime int64
+	processingCount     int64                             // Number of recorded processing times
+	processingBuckets   [len(processingTimeBuckets)]int64 // Processing times per bucket
 	failedOperations    int64
 	memoryUsage         int64
-	cpuUtilization      float64
+	cpuUtilization      uint64 // float64 bits
 	goroutineCount      int64
+	gcCycles            int64
+	gcPauseTotal        int64 // nanoseconds
 	channelBufferUsage  int64
 	errorCount          map[string]int64
 	errorCountMutex     sync.RWMutex
@@ -33,9 +42,17 @@ func (m *MetricsCollector) IncrementProcessedObjects() {
 	atomic.AddInt64(&m.totalObjects, 1)
 }
 
-// RecordProcessingTime adds processing time to total
+// RecordProcessingTime adds processing time to total and to the processing
+// time histogram
 func (m *MetricsCollector) RecordProcessingTime(duration time.Duration) {
 	atomic.AddInt64(&m.totalProcessingTime, int64(duration))
+	atomic.AddInt64(&m.processingCount, 1)
+	for i, bound := range processingTimeBuckets {
+		if duration.Seconds() <= bound {
+			atomic.AddInt64(&m.processingBuckets[i], 1)
+			break
+		}
+	}
 }
 
 // IncrementFailedOperations increments the failed operations counter
@@ -50,7 +67,7 @@ func (m *MetricsCollector) SetMemoryUsage(bytes int64) {
 
 // SetCPUUtilization sets the current CPU utilization
 func (m *MetricsCollector) SetCPUUtilization(percentage float64) {
-	m.cpuUtilization = percentage
+	atomic.StoreUint64(&m.cpuUtilization, math.Float64bits(percentage))
 }
 
 // SetGoroutineCount sets the current number of goroutines
@@ -58,6 +75,13 @@ func (m *MetricsCollector) SetGoroutineCount(count int64) {
 	atomic.StoreInt64(&m.goroutineCount, count)
 }
 
+// SetGCStats sets the number of completed garbage collection cycles and the
+// total time the program was paused by them
+func (m *MetricsCollector) SetGCStats(cycles int64, pauseTotal time.Duration) {
+	atomic.StoreInt64(&m.gcCycles, cycles)
+	atomic.StoreInt64(&m.gcPauseTotal, int64(pauseTotal))
+}
+
 // SetChannelBufferUsage sets the current channel buffer usage
 func (m *MetricsCollector) SetChannelBufferUsage(usage int64) {
 	atomic.StoreInt64(&m.channelBufferUsage, usage)
@@ -82,15 +106,18 @@ func (m *MetricsCollector) IncrementRecoverySuccess() {
 
 // GetMetrics returns all current metrics
 func (m *MetricsCollector) GetMetrics() map[string]interface{} {
+	errorCount := m.ErrorCounts()
 	return map[string]interface{}{
 		"total_objects":         atomic.LoadInt64(&m.totalObjects),
 		"total_processing_time": atomic.LoadInt64(&m.totalProcessingTime),
 		"failed_operations":     atomic.LoadInt64(&m.failedOperations),
 		"memory_usage":          atomic.LoadInt64(&m.memoryUsage),
-		"cpu_utilization":       m.cpuUtilization,
+		"cpu_utilization":       m.CPUUtilization(),
 		"goroutine_count":       atomic.LoadInt64(&m.goroutineCount),
+		"gc_cycles":             m.GCCycles(),
+		"gc_pause_total":        int64(m.GCPauseTotal()),
 		"channel_buffer_usage":  atomic.LoadInt64(&m.channelBufferUsage),
-		"error_count":           m.errorCount,
+		"error_count":           errorCount,
 		"retry_attempts":        atomic.LoadInt64(&m.retryAttempts),
 		"recovery_success":      atomic.LoadInt64(&m.recoverySuccess),
 	}
@@ -110,6 +137,39 @@ func (m *MetricsCollector) AverageProcessingTime() time.Duration {
 	return time.Duration(atomic.LoadInt64(&m.totalProcessingTime) / total)
 }
 
+// CPUUtilization returns the current CPU utilization
+func (m *MetricsCollector) CPUUtilization() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&m.cpuUtilization))
+}
+
+// GoroutineCount returns the current number of goroutines
+func (m *MetricsCollector) GoroutineCount() int64 {
+	return atomic.LoadInt64(&m.goroutineCount)
+}
+
+// GCCycles returns the number of completed garbage collection cycles
+func (m *MetricsCollector) GCCycles() int64 {
+	return atomic.LoadInt64(&m.gcCycles)
+}
+
+// GCPauseTotal returns the total time the program was paused by garbage
+// collection
+func (m *MetricsCollector) GCPauseTotal() time.Duration {
+	return time.Duration(atomic.LoadInt64(&m.gcPauseTotal))
+}
+
+// ErrorCounts returns a copy of the error counts by error type
+func (m *MetricsCollector) ErrorCounts() map[string]int64 {
+	m.errorCountMutex.RLock()
+	defer m.errorCountMutex.RUnlock()
+
+	counts := make(map[string]int64, len(m.errorCount))
+	for errorType, count := range m.errorCount {
+		counts[errorType] = count
+	}
+	return counts
+}
+
 // MemoryUsage returns the current memory usage in bytes
 func (m *MetricsCollector) MemoryUsage() int64 {
 	return atomic.LoadInt64(&m.memoryUsage)
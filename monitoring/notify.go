@@ -0,0 +1,482 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Alert is a notification about an alert rule that started firing, keeps
+// firing or was resolved
+type Alert struct {
+	Rule    string                 // Name of the alert rule
+	State   AlertState             // AlertFiring or AlertResolved
+	Message string                 // Summary of the alert
+	Data    map[string]interface{} // Current values and thresholds
+	Since   time.Time              // When the rule's condition started to hold
+	Time    time.Time              // When the alert was raised
+}
+
+// Notifier delivers alerts to a notification channel. Implementations must be
+// safe for concurrent use and should give up once ctx is done.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify calls f(ctx, alert)
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// NotifierFactory creates the notifier of a notification channel from its
+// Target and Options
+type NotifierFactory func(channel NotificationChannel) (Notifier, error)
+
+var (
+	notifierFactories = map[NotificationType]NotifierFactory{}
+	notifierMu        sync.RWMutex
+)
+
+func init() {
+	RegisterNotifier(EmailNotification, newEmailNotifier)
+	RegisterNotifier(SlackNotification, newSlackNotifier)
+	RegisterNotifier(WebhookNotification, newWebhookNotifier)
+}
+
+// RegisterNotifier makes a notification type available to NotificationChannel.
+// Custom notifiers register themselves the same way as the built-in email,
+// slack and webhook notifiers. It panics if the factory is nil or the type is
+// already registered.
+func RegisterNotifier(typ NotificationType, factory NotifierFactory) {
+	notifierMu.Lock()
+	defer notifierMu.Unlock()
+
+	if factory == nil {
+		panic("monitoring: RegisterNotifier factory is nil for " + string(typ))
+	}
+	if _, dup := notifierFactories[typ]; dup {
+		panic("monitoring: RegisterNotifier called twice for " + string(typ))
+	}
+	notifierFactories[typ] = factory
+}
+
+// NewNotifier creates the notifier of a notification channel: its Notifier if
+// it has one, or one created by the factory registered for its Type. The
+// notifier retries failed notifications as the channel's Retry policy says.
+//
+// Parameters:
+//   - channel: The notification channel
+//
+// Returns:
+//   - Notifier: The notifier of the channel
+//   - error: An error if the type is not registered or the channel is misconfigured
+func NewNotifier(channel NotificationChannel) (Notifier, error) {
+	notifier := channel.Notifier
+	if notifier == nil {
+		notifierMu.RLock()
+		factory, ok := notifierFactories[channel.Type]
+		notifierMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unsupported notification type: %s", channel.Type)
+		}
+
+		var err error
+		if notifier, err = factory(channel); err != nil {
+			return nil, fmt.Errorf("invalid %s notification channel: %v", channel.Type, err)
+		}
+	}
+	return WithRetry(notifier, channel.Retry), nil
+}
+
+// PermanentError marks a notification error that retrying cannot fix, such as
+// a request the receiver rejected
+type PermanentError struct {
+	Err error
+}
+
+// Error returns the message of the underlying error
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks err as an error that is not retried
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// RetryPolicy configures how often and how fast a notification is retried
+type RetryPolicy struct {
+	// Attempts is the number of times a notification is tried.
+	// Values below 1 use 3.
+	Attempts int
+
+	// Backoff is the delay before the first retry; it doubles after every
+	// retry. Values below 1 use 500ms.
+	Backoff time.Duration
+
+	// MaxBackoff bounds the delay between two attempts.
+	// Values below 1 use 30 seconds.
+	MaxBackoff time.Duration
+
+	// Timeout bounds each attempt. Values below 1 use 10 seconds.
+	Timeout time.Duration
+}
+
+// WithRetry returns a notifier that gives each attempt of notifier the policy's
+// timeout and retries failed attempts with exponential backoff, until the
+// attempts are exhausted, the error is a PermanentError or ctx is done
+//
+// Parameters:
+//   - notifier: The notifier to retry
+//   - policy: The retry policy; the zero value uses the defaults
+//
+// Returns:
+//   - Notifier: The retrying notifier
+func WithRetry(notifier Notifier, policy RetryPolicy) Notifier {
+	if policy.Attempts < 1 {
+		policy.Attempts = 3
+	}
+	if policy.Backoff < 1 {
+		policy.Backoff = 500 * time.Millisecond
+	}
+	if policy.MaxBackoff < 1 {
+		policy.MaxBackoff = 30 * time.Second
+	}
+	if policy.Timeout < 1 {
+		policy.Timeout = 10 * time.Second
+	}
+	return &retryNotifier{notifier: notifier, policy: policy}
+}
+
+// retryNotifier is the Notifier returned by WithRetry
+type retryNotifier struct {
+	notifier Notifier
+	policy   RetryPolicy
+}
+
+func (r *retryNotifier) Notify(ctx context.Context, alert Alert) error {
+	backoff := r.policy.Backoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, r.policy.Timeout)
+		err := r.notifier.Notify(attemptCtx, alert)
+		cancel()
+
+		var permanent *PermanentError
+		if err == nil || errors.As(err, &permanent) || attempt >= r.policy.Attempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%v (giving up: %v)", err, ctx.Err())
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > r.policy.MaxBackoff {
+			backoff = r.policy.MaxBackoff
+		}
+	}
+}
+
+// Templates used when a notifier has none. They are executed with the Alert.
+const (
+	DefaultSubjectTemplate = `[sqlmapper] {{if eq .State "resolved"}}Resolved: {{end}}{{.Message}}`
+	DefaultMessageTemplate = `{{if eq .State "resolved"}}Resolved: {{end}}{{.Message}}{{range $key, $value := .Data}}
+{{$key}}: {{$value}}{{end}}`
+)
+
+var (
+	defaultSubject = template.Must(template.New("subject").Parse(DefaultSubjectTemplate))
+	defaultMessage = template.Must(template.New("message").Parse(DefaultMessageTemplate))
+)
+
+// render executes tmpl, or def if tmpl is nil, with the alert
+func render(tmpl, def *template.Template, alert Alert) (string, error) {
+	if tmpl == nil {
+		tmpl = def
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, alert); err != nil {
+		return "", Permanent(fmt.Errorf("error executing template: %v", err))
+	}
+	return buf.String(), nil
+}
+
+// parseTemplate parses a template option, or returns nil if it is empty
+func parseTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s template: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+// EmailNotifier sends alerts as plain text email through an SMTP server. The
+// connection is upgraded with STARTTLS when the server offers it.
+type EmailNotifier struct {
+	Addr      string             // SMTP server as host:port
+	From      string             // Sender address
+	To        []string           // Recipient addresses
+	Auth      smtp.Auth          // Authentication, or nil
+	TLSConfig *tls.Config        // STARTTLS configuration; nil verifies the server's host name
+	Subject   *template.Template // Subject template; nil uses DefaultSubjectTemplate
+	Body      *template.Template // Body template; nil uses DefaultMessageTemplate
+}
+
+// newEmailNotifier creates the notifier of an email channel. Target holds the
+// recipients, separated by commas; the options are "addr", "from", "username",
+// "password", "subject" and "body".
+func newEmailNotifier(channel NotificationChannel) (Notifier, error) {
+	n := &EmailNotifier{
+		Addr: channel.Options["addr"],
+		From: channel.Options["from"],
+	}
+	for _, to := range strings.Split(channel.Target, ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			n.To = append(n.To, to)
+		}
+	}
+	if n.Addr == "" || n.From == "" || len(n.To) == 0 {
+		return nil, errors.New("email notifications need the addr and from options and a target")
+	}
+
+	host, _, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address: %v", err)
+	}
+	if username := channel.Options["username"]; username != "" {
+		n.Auth = smtp.PlainAuth("", username, channel.Options["password"], host)
+	}
+
+	if n.Subject, err = parseTemplate("subject", channel.Options["subject"]); err != nil {
+		return nil, err
+	}
+	if n.Body, err = parseTemplate("body", channel.Options["body"]); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Notify implements the Notifier interface
+func (n *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	subject, err := render(n.Subject, defaultSubject, alert)
+	if err != nil {
+		return err
+	}
+	body, err := render(n.Body, defaultMessage, alert)
+	if err != nil {
+		return err
+	}
+	host, _, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid SMTP address: %v", err))
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	// Interrupt the SMTP conversation when ctx is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := n.send(client, host, subject, body, alert.Time); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// send runs the SMTP transaction of a message
+func (n *EmailNotifier) send(client *smtp.Client, host, subject, body string, date time.Time) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		config := n.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := client.StartTLS(config); err != nil {
+			return err
+		}
+	}
+	if n.Auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return Permanent(errors.New("SMTP server does not support AUTH"))
+		}
+		if err := client.Auth(n.Auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	header := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n",
+		n.From, strings.Join(n.To, ", "), mime.QEncoding.Encode("utf-8", oneLine(subject)), date.Format(time.RFC1123Z))
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := io.WriteString(w, header+body+"\r\n"); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// smtpError marks SMTP replies with a permanent failure code as permanent
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return Permanent(err)
+	}
+	return err
+}
+
+// oneLine joins the lines of a header value
+func oneLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// SlackNotifier posts alerts to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+	Template   *template.Template // Message template; nil uses DefaultMessageTemplate
+	Client     *http.Client       // nil uses http.DefaultClient
+}
+
+// newSlackNotifier creates the notifier of a slack channel. Target is the
+// incoming webhook URL; the "template" option sets the message template.
+func newSlackNotifier(channel NotificationChannel) (Notifier, error) {
+	if channel.Target == "" {
+		return nil, errors.New("slack notifications need a webhook URL as target")
+	}
+	tmpl, err := parseTemplate("template", channel.Options["template"])
+	if err != nil {
+		return nil, err
+	}
+	return &SlackNotifier{WebhookURL: channel.Target, Template: tmpl}, nil
+}
+
+// Notify implements the Notifier interface
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	text, err := render(n.Template, defaultMessage, alert)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, n.Client, n.WebhookURL, nil, map[string]string{"text": text})
+}
+
+// WebhookNotifier posts alerts as JSON objects with the fields "rule",
+// "state", "message", "data", "since" and "time" to a URL
+type WebhookNotifier struct {
+	URL     string
+	Headers map[string]string // Additional request headers, such as Authorization
+	Client  *http.Client      // nil uses http.DefaultClient
+}
+
+// newWebhookNotifier creates the notifier of a webhook channel. Target is the
+// URL; options named "header.<Name>" set request headers.
+func newWebhookNotifier(channel NotificationChannel) (Notifier, error) {
+	if channel.Target == "" {
+		return nil, errors.New("webhook notifications need a URL as target")
+	}
+	n := &WebhookNotifier{URL: channel.Target, Headers: map[string]string{}}
+	for key, value := range channel.Options {
+		if name, ok := strings.CutPrefix(key, "header."); ok {
+			n.Headers[name] = value
+		}
+	}
+	return n, nil
+}
+
+// Notify implements the Notifier interface
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.Client, n.URL, n.Headers, map[string]interface{}{
+		"rule":    alert.Rule,
+		"state":   alert.State,
+		"message": alert.Message,
+		"data":    alert.Data,
+		"since":   alert.Since,
+		"time":    alert.Time,
+	})
+}
+
+// postJSON posts a JSON payload. Responses other than 2xx are errors; client
+// errors other than 408 and 429 are permanent.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Permanent(fmt.Errorf("error encoding payload: %v", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(reply)))
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
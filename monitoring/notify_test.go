@@ -0,0 +1,267 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpMessage is a message received by the SMTP stub
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpStub starts an SMTP server that accepts one message per connection and
+// rejects recipients at reject.example
+func smtpStub(t *testing.T) (string, <-chan smtpMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan smtpMessage, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(textproto.NewConn(conn), messages)
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func serveSMTP(conn *textproto.Conn, messages chan<- smtpMessage) {
+	defer conn.Close()
+	var msg smtpMessage
+	_ = conn.PrintfLine("220 localhost ESMTP stub")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250 localhost")
+		case "MAIL":
+			msg.From = line[len("MAIL FROM:"):]
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			if strings.Contains(line, "reject.example") {
+				_ = conn.PrintfLine("550 No such user")
+				continue
+			}
+			msg.To = append(msg.To, line[len("RCPT TO:"):])
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 Go ahead")
+			data, err := conn.ReadDotLines()
+			if err != nil {
+				return
+			}
+			msg.Data = strings.Join(data, "\n")
+			messages <- msg
+			_ = conn.PrintfLine("250 Queued")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+var testAlert = Alert{
+	Rule:    ErrorRateRule,
+	State:   AlertFiring,
+	Message: "Error rate threshold exceeded",
+	Data:    map[string]interface{}{"current_rate": 12.5, "threshold": 1.0},
+	Since:   time.Date(2024, 5, 1, 11, 55, 0, 0, time.UTC),
+	Time:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestEmailNotifier(t *testing.T) {
+	addr, messages := smtpStub(t)
+
+	notifier, err := NewNotifier(NotificationChannel{
+		Type:    EmailNotification,
+		Target:  "ops@example.com, dba@example.com",
+		Options: map[string]string{"addr": addr, "from": "sqlmapper@example.com", "subject": "ALERT: {{.Message}}"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, notifier.Notify(context.Background(), testAlert))
+
+	msg := <-messages
+	assert.Equal(t, "<sqlmapper@example.com>", msg.From)
+	assert.Equal(t, []string{"<ops@example.com>", "<dba@example.com>"}, msg.To)
+	assert.Contains(t, msg.Data, "Subject: ALERT: Error rate threshold exceeded\n")
+	assert.Contains(t, msg.Data, "Date: Wed, 01 May 2024 12:00:00 +0000\n")
+	assert.True(t, strings.HasSuffix(msg.Data, "\n\nError rate threshold exceeded\ncurrent_rate: 12.5\nthreshold: 1"), msg.Data)
+
+	// Rejected recipients are not retried
+	var attempts int32
+	email := &EmailNotifier{Addr: addr, From: "sqlmapper@example.com", To: []string{"nobody@reject.example"}}
+	err = WithRetry(NotifierFunc(func(ctx context.Context, alert Alert) error {
+		atomic.AddInt32(&attempts, 1)
+		return email.Notify(ctx, alert)
+	}), RetryPolicy{Backoff: time.Millisecond}).Notify(context.Background(), testAlert)
+	var permanent *PermanentError
+	assert.True(t, errors.As(err, &permanent), "%v", err)
+	assert.Equal(t, int32(1), attempts)
+
+	// Channels must name the server and the sender
+	_, err = NewNotifier(NotificationChannel{Type: EmailNotification, Target: "ops@example.com"})
+	assert.Error(t, err)
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(NotificationChannel{
+		Type:    SlackNotification,
+		Target:  server.URL,
+		Options: map[string]string{"template": ":warning: {{.Message}} ({{index .Data \"current_rate\"}}%)"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, notifier.Notify(context.Background(), testAlert))
+	assert.Equal(t, map[string]string{"text": ":warning: Error rate threshold exceeded (12.5%)"}, payload)
+
+	_, err = NewNotifier(NotificationChannel{Type: SlackNotification, Target: server.URL, Options: map[string]string{"template": "{{"}})
+	assert.Error(t, err)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(NotificationChannel{
+		Type:    WebhookNotification,
+		Target:  server.URL,
+		Options: map[string]string{"header.Authorization": "Bearer token"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, notifier.Notify(context.Background(), testAlert))
+	assert.Equal(t, map[string]interface{}{
+		"rule":    "error_rate",
+		"state":   "firing",
+		"message": "Error rate threshold exceeded",
+		"data":    map[string]interface{}{"current_rate": 12.5, "threshold": 1.0},
+		"since":   "2024-05-01T11:55:00Z",
+		"time":    "2024-05-01T12:00:00Z",
+	}, payload)
+}
+
+func TestWithRetry(t *testing.T) {
+	var requests int32
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(status)
+		}
+	}))
+	defer server.Close()
+	webhook := &WebhookNotifier{URL: server.URL}
+
+	// Server errors are retried until an attempt succeeds
+	err := WithRetry(webhook, RetryPolicy{Attempts: 3, Backoff: time.Millisecond}).Notify(context.Background(), testAlert)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), requests)
+
+	// Rejected requests are not
+	atomic.StoreInt32(&requests, 0)
+	status = http.StatusBadRequest
+	err = WithRetry(webhook, RetryPolicy{Attempts: 3, Backoff: time.Millisecond}).Notify(context.Background(), testAlert)
+	assert.ErrorContains(t, err, "400 Bad Request")
+	assert.Equal(t, int32(1), requests)
+
+	// Each attempt is bounded by the timeout
+	var calls int32
+	slow := NotifierFunc(func(ctx context.Context, alert Alert) error {
+		atomic.AddInt32(&calls, 1)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	start := time.Now()
+	err = WithRetry(slow, RetryPolicy{Attempts: 2, Backoff: time.Millisecond, Timeout: 20 * time.Millisecond}).Notify(context.Background(), testAlert)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(2), calls)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Cancelling the context stops the backoff
+	ctx, cancel := context.WithCancel(context.Background())
+	failing := NotifierFunc(func(context.Context, Alert) error {
+		cancel()
+		return errors.New("unavailable")
+	})
+	err = WithRetry(failing, RetryPolicy{Attempts: 5, Backoff: time.Hour}).Notify(ctx, testAlert)
+	assert.ErrorContains(t, err, "unavailable")
+}
+
+func TestRegisterNotifier(t *testing.T) {
+	var received []Alert
+	var mu sync.Mutex
+	t.Cleanup(func() {
+		notifierMu.Lock()
+		defer notifierMu.Unlock()
+		delete(notifierFactories, "test-log")
+	})
+	RegisterNotifier("test-log", func(channel NotificationChannel) (Notifier, error) {
+		return NotifierFunc(func(ctx context.Context, alert Alert) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, alert)
+			return nil
+		}), nil
+	})
+	assert.Panics(t, func() { RegisterNotifier("test-log", newSlackNotifier) })
+	assert.Panics(t, func() { RegisterNotifier("test-nil", nil) })
+
+	_, err := NewNotifier(NotificationChannel{Type: "pager"})
+	assert.EqualError(t, err, "unsupported notification type: pager")
+
+	// Alerts go to every channel; failing channels are reported
+	manager := NewAlertManager(AlertConfig{
+		Threshold: AlertThreshold{ErrorRate: 10, ProcessingTime: time.Hour, MemoryUsage: 1024},
+		Notifications: []NotificationChannel{
+			{Type: "test-log"},
+			{Type: "custom", Notifier: NotifierFunc(func(context.Context, Alert) error { return Permanent(errors.New("down")) })},
+			{Type: "pager"},
+		},
+	})
+	manager.metrics.IncrementProcessedObjects()
+	manager.metrics.IncrementFailedOperations()
+
+	err = manager.CheckThresholds()
+	assert.ErrorContains(t, err, "failed to send error_rate alert: failed to send notification via custom: down")
+	assert.ErrorContains(t, err, "failed to send notification via pager: unsupported notification type: pager")
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "Error rate threshold exceeded", received[0].Message)
+		assert.Equal(t, float64(100), received[0].Data["current_rate"])
+	}
+}
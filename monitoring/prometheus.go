@@ -0,0 +1,115 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricPrefix namespaces the exported metric names
+const metricPrefix = "sqlmapper_"
+
+// WritePrometheus writes the current metrics in the Prometheus text exposition
+// format. Metric names are prefixed with "sqlmapper_"; errors are counted by
+// type in the error_count metric, and processing times are exported as the
+// processing_seconds histogram.
+//
+// Parameters:
+//   - w: The writer to write the metrics to
+//
+// Returns:
+//   - error: An error if writing fails
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	writeMetric(out, "objects_processed_total", "counter", "Total number of processed objects.", float64(m.TotalObjects()))
+	writeMetric(out, "failed_operations_total", "counter", "Total number of failed operations.", float64(atomic.LoadInt64(&m.failedOperations)))
+	writeMetric(out, "retry_attempts_total", "counter", "Total number of retry attempts.", float64(atomic.LoadInt64(&m.retryAttempts)))
+	writeMetric(out, "recovery_success_total", "counter", "Total number of successful recoveries.", float64(atomic.LoadInt64(&m.recoverySuccess)))
+	writeMetric(out, "memory_usage_bytes", "gauge", "Current memory usage in bytes.", float64(m.MemoryUsage()))
+	writeMetric(out, "cpu_utilization_percent", "gauge", "Current CPU utilization in percent.", m.CPUUtilization())
+	writeMetric(out, "goroutines", "gauge", "Current number of goroutines.", float64(m.GoroutineCount()))
+	writeMetric(out, "gc_cycles_total", "counter", "Total number of completed garbage collection cycles.", float64(m.GCCycles()))
+	writeMetric(out, "gc_pause_seconds_total", "counter", "Total time paused by garbage collection.", m.GCPauseTotal().Seconds())
+	writeMetric(out, "channel_buffer_usage", "gauge", "Current number of buffered items.", float64(atomic.LoadInt64(&m.channelBufferUsage)))
+
+	counts := m.ErrorCounts()
+	types := make([]string, 0, len(counts))
+	for errorType := range counts {
+		types = append(types, errorType)
+	}
+	sort.Strings(types)
+	writeHeader(out, "error_count", "counter", "Number of errors by type.")
+	for _, errorType := range types {
+		fmt.Fprintf(out, "%serror_count{type=\"%s\"} %d\n", metricPrefix, escapeLabel(errorType), counts[errorType])
+	}
+
+	// Buckets are stored per bound and exported cumulatively
+	writeHeader(out, "processing_seconds", "histogram", "Time taken to process an object.")
+	var cumulative int64
+	for i, bound := range processingTimeBuckets {
+		cumulative += atomic.LoadInt64(&m.processingBuckets[i])
+		fmt.Fprintf(out, "%sprocessing_seconds_bucket{le=\"%s\"} %d\n", metricPrefix, formatFloat(bound), cumulative)
+	}
+	count := atomic.LoadInt64(&m.processingCount)
+	fmt.Fprintf(out, "%sprocessing_seconds_bucket{le=\"+Inf\"} %d\n", metricPrefix, count)
+	fmt.Fprintf(out, "%sprocessing_seconds_sum %s\n", metricPrefix, formatFloat(float64(atomic.LoadInt64(&m.totalProcessingTime))/1e9))
+	fmt.Fprintf(out, "%sprocessing_seconds_count %d\n", metricPrefix, count)
+
+	return out.Flush()
+}
+
+// PrometheusHandler returns an http.Handler that serves the current metrics in
+// the Prometheus text exposition format, to be scraped by Prometheus
+//
+// Returns:
+//   - http.Handler: The handler, usually mounted at /metrics
+func (m *MetricsCollector) PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var buf strings.Builder
+		if err := m.WritePrometheus(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", PrometheusContentType)
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, buf.String())
+		}
+	})
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n", metricPrefix, name, help)
+	fmt.Fprintf(w, "# TYPE %s%s %s\n", metricPrefix, name, typ)
+}
+
+// writeMetric writes a metric with a single unlabelled sample
+func writeMetric(w io.Writer, name, typ, help string, value float64) {
+	writeHeader(w, name, typ, help)
+	fmt.Fprintf(w, "%s%s %s\n", metricPrefix, name, formatFloat(value))
+}
+
+// formatFloat formats a sample value as Prometheus expects it
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeLabel escapes a label value for the text exposition format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
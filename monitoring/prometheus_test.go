@@ -0,0 +1,100 @@
+package monitoring
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	metrics := NewMetricsCollector()
+	metrics.IncrementProcessedObjects()
+	metrics.IncrementProcessedObjects()
+	metrics.IncrementFailedOperations()
+	metrics.SetMemoryUsage(2048)
+	metrics.SetCPUUtilization(12.5)
+	metrics.SetGCStats(4, 1500*time.Microsecond)
+	metrics.IncrementErrorCount("parse")
+	metrics.IncrementErrorCount("parse")
+	metrics.IncrementErrorCount(`say "hi"`)
+	metrics.RecordProcessingTime(200 * time.Microsecond)
+	metrics.RecordProcessingTime(2 * time.Millisecond)
+	metrics.RecordProcessingTime(time.Minute)
+
+	server := httptest.NewServer(metrics.PrometheusHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, PrometheusContentType, resp.Header.Get("Content-Type"))
+	for _, line := range []string{
+		"# TYPE sqlmapper_objects_processed_total counter",
+		"sqlmapper_objects_processed_total 2",
+		"sqlmapper_failed_operations_total 1",
+		"# TYPE sqlmapper_memory_usage_bytes gauge",
+		"sqlmapper_memory_usage_bytes 2048",
+		"sqlmapper_cpu_utilization_percent 12.5",
+		"sqlmapper_gc_cycles_total 4",
+		"sqlmapper_gc_pause_seconds_total 0.0015",
+		`sqlmapper_error_count{type="parse"} 2`,
+		`sqlmapper_error_count{type="say \"hi\""} 1`,
+		"# TYPE sqlmapper_processing_seconds histogram",
+		`sqlmapper_processing_seconds_bucket{le="0.0001"} 0`,
+		`sqlmapper_processing_seconds_bucket{le="0.0005"} 1`,
+		`sqlmapper_processing_seconds_bucket{le="0.005"} 2`,
+		`sqlmapper_processing_seconds_bucket{le="10"} 2`,
+		`sqlmapper_processing_seconds_bucket{le="+Inf"} 3`,
+		"sqlmapper_processing_seconds_sum 60.0022",
+		"sqlmapper_processing_seconds_count 3",
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+
+	// Every metric has a HELP and a TYPE line
+	assert.Equal(t, strings.Count(text, "# HELP"), strings.Count(text, "# TYPE"))
+
+	// Only reads are allowed
+	resp, err = http.Post(server.URL, "text/plain", nil)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
+
+func TestPrometheusHandler_Concurrent(t *testing.T) {
+	metrics := NewMetricsCollector()
+	handler := metrics.PrometheusHandler()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			metrics.IncrementErrorCount("io")
+			metrics.SetCPUUtilization(float64(i))
+			metrics.RecordProcessingTime(time.Millisecond)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+	<-done
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), `sqlmapper_error_count{type="io"} 100`)
+	assert.Contains(t, recorder.Body.String(), "sqlmapper_processing_seconds_count 100")
+}
@@ -0,0 +1,283 @@
+package monitoring
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	runtimemetrics "runtime/metrics"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSampleInterval is how often a Sampler samples by default
+const DefaultSampleInterval = 10 * time.Second
+
+// clockTicks is the unit of the CPU times in /proc/self/stat. It is 100 per
+// second on all Linux platforms Go supports.
+const clockTicks = 100
+
+// Runtime metrics read by a Sampler
+const (
+	memoryTotalMetric    = "/memory/classes/total:bytes"
+	memoryReleasedMetric = "/memory/classes/heap/released:bytes"
+	goroutinesMetric     = "/sched/goroutines:goroutines"
+	gcCyclesMetric       = "/gc/cycles/total:gc-cycles"
+	gcPausesMetric       = "/sched/pauses/total/gc:seconds"
+	cpuTotalMetric       = "/cpu/classes/total:cpu-seconds"
+	cpuIdleMetric        = "/cpu/classes/idle:cpu-seconds"
+)
+
+// SamplerConfig holds configuration for a Sampler
+type SamplerConfig struct {
+	Interval time.Duration // Time between samples; zero uses DefaultSampleInterval
+	Alerts   *AlertManager // Checked after every sample, if set
+	OnError  func(error)   // Called with the errors of the alert checks, if set
+}
+
+// Sampler samples the runtime metrics of the process into a MetricsCollector
+// in the background: memory usage, goroutines, garbage collection and CPU
+// utilization. Memory usage is the memory mapped by the Go runtime, without the
+// heap memory returned to the operating system.
+//
+// The alert rules are checked on a goroutine of their own, so that slow
+// notifications do not delay the samples. A check is skipped while the previous
+// one is still running.
+//
+// CPU utilization is the CPU time of the process since the previous sample in
+// percent of the time GOMAXPROCS CPUs could have spent. On Linux it is read
+// from /proc/self/stat; elsewhere the Go runtime's estimate is used, which is
+// only updated by garbage collections.
+type Sampler struct {
+	metrics *MetricsCollector
+	config  SamplerConfig
+
+	checking atomic.Bool    // Set while an alert check is running
+	checks   sync.WaitGroup // Running alert checks
+
+	mu      sync.Mutex // Guards the fields below
+	stop    chan struct{}
+	done    chan struct{}
+	lastCPU time.Duration // CPU time at the previous sample
+	lastAt  time.Time     // Time of the previous sample
+	samples []runtimemetrics.Sample
+}
+
+// NewSampler creates a sampler that writes to metrics. Pass the collector of an
+// AlertManager, see AlertManager.Metrics, to check its rules against the
+// sampled values.
+//
+// Parameters:
+//   - metrics: The collector to write the samples to
+//   - config: The sampling configuration
+//
+// Returns:
+//   - *Sampler: The sampler, not yet started
+func NewSampler(metrics *MetricsCollector, config SamplerConfig) *Sampler {
+	if config.Interval <= 0 {
+		config.Interval = DefaultSampleInterval
+	}
+
+	samples := []runtimemetrics.Sample{
+		{Name: memoryTotalMetric},
+		{Name: memoryReleasedMetric},
+		{Name: goroutinesMetric},
+		{Name: gcCyclesMetric},
+		{Name: gcPausesMetric},
+		{Name: cpuTotalMetric},
+		{Name: cpuIdleMetric},
+	}
+	return &Sampler{metrics: metrics, config: config, samples: samples}
+}
+
+// Start takes a sample and keeps sampling every interval until Stop is called.
+// It does not wait for the alert check of the first sample.
+//
+// Returns:
+//   - error: An error if the sampler is already running
+func (s *Sampler) Start() error {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("sampler is already running")
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.stop, s.done = stop, done
+	s.mu.Unlock()
+
+	s.tick()
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.tick()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sampling and waits for the sample and the alert check in progress,
+// if any. Stopping a sampler that is not running does nothing.
+func (s *Sampler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+		s.checks.Wait()
+	}
+}
+
+// tick takes a sample and starts checking the alert rules, unless the previous
+// check is still running
+func (s *Sampler) tick() {
+	s.Sample()
+	if s.config.Alerts == nil || !s.checking.CompareAndSwap(false, true) {
+		return
+	}
+
+	s.checks.Add(1)
+	go func() {
+		defer s.checks.Done()
+		defer s.checking.Store(false)
+		if err := s.config.Alerts.CheckThresholds(); err != nil && s.config.OnError != nil {
+			s.config.OnError(err)
+		}
+	}()
+}
+
+// Sample samples the runtime metrics once. CPU utilization is set from the
+// second sample on.
+func (s *Sampler) Sample() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runtimemetrics.Read(s.samples)
+	values := make(map[string]runtimemetrics.Value, len(s.samples))
+	for _, sample := range s.samples {
+		values[sample.Name] = sample.Value
+	}
+
+	if total, ok := uint64Value(values[memoryTotalMetric]); ok {
+		released, _ := uint64Value(values[memoryReleasedMetric])
+		s.metrics.SetMemoryUsage(int64(total - released))
+	}
+	if goroutines, ok := uint64Value(values[goroutinesMetric]); ok {
+		s.metrics.SetGoroutineCount(int64(goroutines))
+	} else {
+		s.metrics.SetGoroutineCount(int64(runtime.NumGoroutine()))
+	}
+	if cycles, ok := uint64Value(values[gcCyclesMetric]); ok {
+		var pauses time.Duration
+		if v := values[gcPausesMetric]; v.Kind() == runtimemetrics.KindFloat64Histogram {
+			pauses = histogramTotal(v.Float64Histogram())
+		}
+		s.metrics.SetGCStats(int64(cycles), pauses)
+	}
+
+	now := time.Now()
+	cpu, ok := processCPUTime()
+	if !ok {
+		cpu, ok = runtimeCPUTime(values)
+	}
+	if !ok {
+		return
+	}
+	if !s.lastAt.IsZero() && cpu >= s.lastCPU {
+		if elapsed := now.Sub(s.lastAt); elapsed > 0 {
+			capacity := float64(elapsed) * float64(runtime.GOMAXPROCS(0))
+			s.metrics.SetCPUUtilization(float64(cpu-s.lastCPU) / capacity * 100)
+		}
+	}
+	s.lastCPU, s.lastAt = cpu, now
+}
+
+// uint64Value returns the value of a runtime metric of kind uint64
+func uint64Value(v runtimemetrics.Value) (uint64, bool) {
+	if v.Kind() != runtimemetrics.KindUint64 {
+		return 0, false
+	}
+	return v.Uint64(), true
+}
+
+// histogramTotal estimates the sum of the values of a histogram from the
+// midpoints of its buckets
+func histogramTotal(h *runtimemetrics.Float64Histogram) time.Duration {
+	var total float64
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		low, high := h.Buckets[i], h.Buckets[i+1]
+		mid := (low + high) / 2
+		switch {
+		case math.IsInf(low, -1):
+			mid = high
+		case math.IsInf(high, 1):
+			mid = low
+		}
+		total += mid * float64(count)
+	}
+	return time.Duration(total * float64(time.Second))
+}
+
+// runtimeCPUTime returns the CPU time of the process as estimated by the Go
+// runtime
+func runtimeCPUTime(values map[string]runtimemetrics.Value) (time.Duration, bool) {
+	total, idle := values[cpuTotalMetric], values[cpuIdleMetric]
+	if total.Kind() != runtimemetrics.KindFloat64 || idle.Kind() != runtimemetrics.KindFloat64 {
+		return 0, false
+	}
+	return time.Duration((total.Float64() - idle.Float64()) * float64(time.Second)), true
+}
+
+// processCPUTime returns the user and system CPU time of the process from
+// /proc/self/stat, which exists on Linux only
+func processCPUTime() (time.Duration, bool) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, false
+	}
+	cpu, err := parseProcStat(data)
+	if err != nil {
+		return 0, false
+	}
+	return cpu, true
+}
+
+// parseProcStat returns the sum of the utime and stime fields of a
+// /proc/<pid>/stat file
+func parseProcStat(data []byte) (time.Duration, error) {
+	// The command name is in parentheses and may itself contain spaces and
+	// parentheses; the fields after it start with the state, field 3
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("invalid stat: no command name")
+	}
+	fields := bytes.Fields(data[end+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("invalid stat: %d fields", len(fields)+2)
+	}
+
+	var ticks uint64
+	for _, field := range fields[11:13] { // utime and stime, fields 14 and 15
+		n, err := strconv.ParseUint(string(field), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid stat: %v", err)
+		}
+		ticks += n
+	}
+	return time.Duration(ticks) * time.Second / clockTicks, nil
+}
@@ -0,0 +1,118 @@
+package monitoring
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler_Sample(t *testing.T) {
+	metrics := NewMetricsCollector()
+	sampler := NewSampler(metrics, SamplerConfig{})
+	assert.Equal(t, DefaultSampleInterval, sampler.config.Interval)
+
+	runtime.GC()
+	sampler.Sample()
+	assert.Greater(t, metrics.MemoryUsage(), int64(0))
+	assert.Greater(t, metrics.GoroutineCount(), int64(0))
+	assert.GreaterOrEqual(t, metrics.GCCycles(), int64(1))
+	assert.Equal(t, float64(0), metrics.CPUUtilization())
+
+	// Burn some CPU so that the second sample sees it
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for n := 0; time.Now().Before(deadline); n++ {
+		_ = make([]byte, n%1024)
+	}
+	runtime.GC()
+	sampler.Sample()
+	assert.Greater(t, metrics.CPUUtilization(), float64(0))
+	assert.GreaterOrEqual(t, metrics.GCCycles(), int64(2))
+}
+
+func TestSampler_StartStop(t *testing.T) {
+	var checks int32
+	alerts := NewAlertManager(AlertConfig{
+		Rules: []AlertRule{{
+			Name: "goroutines",
+			Condition: func(m *MetricsCollector) (bool, map[string]interface{}) {
+				atomic.AddInt32(&checks, 1)
+				return m.GoroutineCount() > 0, nil
+			},
+		}},
+		Notifications: []NotificationChannel{{Type: "broken"}},
+	})
+	errs := make(chan error, 100)
+	sampler := NewSampler(alerts.Metrics(), SamplerConfig{
+		Interval: 5 * time.Millisecond,
+		Alerts:   alerts,
+		OnError:  func(err error) { errs <- err },
+	})
+
+	// The first sample is taken right away and checked in the background
+	assert.NoError(t, sampler.Start())
+	assert.Error(t, sampler.Start())
+	assert.Greater(t, alerts.Metrics().GoroutineCount(), int64(0))
+	assert.ErrorContains(t, <-errs, "unsupported notification type: broken")
+	assert.Equal(t, AlertFiring, alerts.States()["goroutines"])
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&checks) >= 3 }, time.Second, time.Millisecond)
+	sampler.Stop()
+	stopped := atomic.LoadInt32(&checks)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&checks))
+	sampler.Stop()
+
+	// Stopped samplers can be started again
+	assert.NoError(t, sampler.Start())
+	sampler.Stop()
+}
+
+func TestSampler_SlowAlerts(t *testing.T) {
+	var checks int32
+	release := make(chan struct{})
+	alerts := NewAlertManager(AlertConfig{
+		Rules: []AlertRule{{
+			Name: "always",
+			Condition: func(m *MetricsCollector) (bool, map[string]interface{}) {
+				atomic.AddInt32(&checks, 1)
+				return true, nil
+			},
+		}},
+		Notifications: []NotificationChannel{{Type: "slow", Notifier: NotifierFunc(func(ctx context.Context, alert Alert) error {
+			<-release
+			return nil
+		})}},
+	})
+	sampler := NewSampler(alerts.Metrics(), SamplerConfig{Interval: 5 * time.Millisecond, Alerts: alerts})
+
+	// Start does not wait for the notification
+	start := time.Now()
+	assert.NoError(t, sampler.Start())
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// Sampling goes on while the check is blocked, and no check is started meanwhile
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&checks) == 1 }, time.Second, time.Millisecond)
+	alerts.Metrics().SetGoroutineCount(-1)
+	assert.Eventually(t, func() bool { return alerts.Metrics().GoroutineCount() > 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&checks))
+
+	close(release)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&checks) >= 2 }, time.Second, time.Millisecond)
+	sampler.Stop()
+}
+
+func TestParseProcStat(t *testing.T) {
+	stat := "4242 (my (odd) proc) S 1 4242 4242 0 -1 4194560 1203 0 0 0 250 130 0 0 20 0 9 0 123 456 789\n"
+	cpu, err := parseProcStat([]byte(stat))
+	assert.NoError(t, err)
+	assert.Equal(t, 3800*time.Millisecond, cpu)
+
+	_, err = parseProcStat([]byte("4242 (proc) S 1 2"))
+	assert.Error(t, err)
+	_, err = parseProcStat([]byte("garbage"))
+	assert.Error(t, err)
+}
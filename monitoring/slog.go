@@ -0,0 +1,93 @@
+package monitoring
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that writes records through a Logger, so that
+// a Logger can back log/slog:
+//
+//	slog.SetDefault(slog.New(logger.Handler()))
+//
+// Record attributes become fields of the message; attributes of groups are
+// named "<group>.<key>". Levels below slog.LevelInfo are logged at DebugLevel,
+// and levels above slog.LevelError at ErrorLevel.
+type SlogHandler struct {
+	logger *Logger
+	prefix string // Group prefix of the attributes, ending in "."
+}
+
+// Handler returns a slog.Handler that logs through l
+//
+// Returns:
+//   - *SlogHandler: The handler, to be passed to slog.New
+func (l *Logger) Handler() *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// Enabled implements the slog.Handler interface
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(logLevel(level))
+}
+
+// Handle implements the slog.Handler interface
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(map[string]interface{}, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.prefix, attr)
+		return true
+	})
+	h.logger.log(record.Time, logLevel(record.Level), record.Message, fields)
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		addAttr(fields, h.prefix, attr)
+	}
+	return &SlogHandler{logger: h.logger.With(fields), prefix: h.prefix}
+}
+
+// WithGroup implements the slog.Handler interface
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{logger: h.logger, prefix: h.prefix + name + "."}
+}
+
+// logLevel maps a slog level to the log level it is logged at
+func logLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// addAttr adds an attribute to fields, flattening groups. Empty attributes are
+// ignored and groups without a key are inlined, as slog.Handler requires.
+func addAttr(fields map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			addAttr(fields, prefix, a)
+		}
+		return
+	}
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // MySQL represents a MySQL parser implementation that handles parsing and generating
@@ -26,6 +28,14 @@ func NewMySQL() sqlmapper.Database {
 	}
 }
 
+func init() {
+	sqlmapper.RegisterDialect(sqlmapper.MySQL, sqlmapper.Dialect{
+		NewParser:       func() sqlmapper.Parser { return NewMySQL() },
+		NewStreamParser: func() interface{} { return NewMySQLStreamParser() },
+		IncludeFormat:   "source %s",
+	})
+}
+
 // Parse takes a MySQL SQL dump content and parses it into a common schema structure.
 // It processes various MySQL objects including:
 // - Databases and schemas
@@ -47,39 +57,47 @@ func (m *MySQL) Parse(content string) (*sqlmapper.Schema, error) {
 		return nil, errors.New("empty content")
 	}
 
-	// Normalize content
-	content = m.normalizeContent(content)
-
-	// Parse schema objects
-	if err := m.parseSchemas(content); err != nil {
-		return nil, fmt.Errorf("error parsing schemas: %v", err)
+	if _, err := tokenizer.ParseStatements(content, tokenizer.MySQL, m.schema, m.parseTokenized, false); err != nil {
+		return nil, err
 	}
 
-	if err := m.parseTables(content); err != nil {
-		return nil, fmt.Errorf("error parsing tables: %v", err)
-	}
+	m.schema.Dialect = sqlmapper.MySQL
 
-	if err := m.parseIndexes(content); err != nil {
-		return nil, fmt.Errorf("error parsing indexes: %v", err)
-	}
+	return m.schema, nil
+}
 
-	if err := m.parseViews(content); err != nil {
-		return nil, fmt.Errorf("error parsing views: %v", err)
+// ParseLenient parses a MySQL SQL dump like Parse, but skips the statements
+// that fail to parse. Each skipped statement is reported as a diagnostic with
+// its position and the reason it failed.
+//
+// Parameters:
+//   - content: The MySQL SQL dump content to parse
+//
+// Returns:
+//   - *sqlmapper.Schema: The objects of the statements that could be parsed
+//   - []sqlmapper.Diagnostic: The skipped statements
+//   - error: An error if the content is empty
+func (m *MySQL) ParseLenient(content string) (*sqlmapper.Schema, []sqlmapper.Diagnostic, error) {
+	if content == "" {
+		return nil, nil, errors.New("empty content")
 	}
 
-	if err := m.parseFunctions(content); err != nil {
-		return nil, fmt.Errorf("error parsing functions: %v", err)
+	diagnostics, err := tokenizer.ParseStatements(content, tokenizer.MySQL, m.schema, m.parseTokenized, true)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := m.parseTriggers(content); err != nil {
-		return nil, fmt.Errorf("error parsing triggers: %v", err)
-	}
+	m.schema.Dialect = sqlmapper.MySQL
 
-	if err := m.parsePermissions(content); err != nil {
-		return nil, fmt.Errorf("error parsing permissions: %v", err)
-	}
+	return m.schema, diagnostics, nil
+}
 
-	return m.schema, nil
+// parseTokenized parses a single statement into the schema
+func (m *MySQL) parseTokenized(stmt tokenizer.Statement) error {
+	if tokenizer.IsInsert(stmt) {
+		return m.parseInsert(stmt)
+	}
+	return m.parseStatement(m.normalizeStatement(stmt))
 }
 
 // Generate creates a MySQL SQL dump from a schema structure.
@@ -101,14 +119,25 @@ func (m *MySQL) Generate(schema *sqlmapper.Schema) (string, error) {
 		return "", errors.New("empty schema")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.MySQL)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	var result strings.Builder
 
 	// Generate table creation
-	for i, table := range schema.Tables {
-		result.WriteString(m.generateTableSQL(table))
-		if i < len(schema.Tables)-1 {
+	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		if result.Len() > 0 {
 			result.WriteString("\n\n")
 		}
+		result.WriteString(m.generateTableSQL(table))
 
 		// Generate indexes for this table
 		if len(table.Indexes) > 0 {
@@ -122,30 +151,98 @@ func (m *MySQL) Generate(schema *sqlmapper.Schema) (string, error) {
 		}
 	}
 
+	// Add table data
+	for _, table := range schema.Tables {
+		for _, stmt := range m.generateInsertSQL(table) {
+			if result.Len() > 0 {
+				result.WriteString("\n\n")
+			}
+			result.WriteString(stmt + ";")
+		}
+	}
+
+	// Add foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		result.WriteString(fmt.Sprintf("\n\nALTER TABLE %s ADD %s;", fk.Table, m.generateConstraintSQL(fk.Constraint)))
+	}
+
 	return result.String(), nil
 }
 
-// normalizeContent preprocesses the SQL content by removing comments and normalizing whitespace.
-// It handles MySQL specific comment styles (-- and #) and DELIMITER statements.
+// normalizeStatement removes comments from a statement, collapses its whitespace
+// and restores the terminating semicolon expected by the statement patterns.
 //
 // Parameters:
-//   - content: The SQL content to normalize
+//   - stmt: The statement read by the tokenizer
 //
 // Returns:
-//   - string: The normalized SQL content
-func (m *MySQL) normalizeContent(content string) string {
-	// Remove comments
-	re := regexp.MustCompile(`--.*$|#.*$`)
-	content = re.ReplaceAllString(content, "")
+//   - string: The normalized statement
+func (m *MySQL) normalizeStatement(stmt tokenizer.Statement) string {
+	return strings.Join(strings.Fields(stmt.Text), " ") + ";"
+}
 
-	// Remove DELIMITER statements
-	content = regexp.MustCompile(`DELIMITER\s+[^\s]+`).ReplaceAllString(content, "")
+// parseInsert adds the rows of an INSERT ... VALUES statement to the data of its table.
+//
+// Parameters:
+//   - stmt: The INSERT statement read by the tokenizer
+//
+// Returns:
+//   - error: An error if the statement cannot be decoded
+func (m *MySQL) parseInsert(stmt tokenizer.Statement) error {
+	insert, err := tokenizer.ParseInsert(stmt, tokenizer.MySQL)
+	if err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	if insert == nil {
+		return nil
+	}
+	if err := sqlmapper.AppendRows(m.schema, insert.Schema, insert.Table, insert.Columns, insert.Rows); err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	return nil
+}
 
-	// Normalize whitespace
-	content = strings.TrimSpace(content)
-	content = regexp.MustCompile(`\s+`).ReplaceAllString(content, " ")
+// parseStatement extracts the schema objects defined by a single normalized statement.
+//
+// Parameters:
+//   - content: The normalized statement
+//
+// Returns:
+//   - error: An error if parsing fails
+func (m *MySQL) parseStatement(content string) error {
+	if err := m.parseSchemas(content); err != nil {
+		return fmt.Errorf("error parsing schemas: %v", err)
+	}
+
+	if err := m.parseTables(content); err != nil {
+		return fmt.Errorf("error parsing tables: %v", err)
+	}
+
+	if err := m.parseIndexes(content); err != nil {
+		return fmt.Errorf("error parsing indexes: %v", err)
+	}
+
+	if err := m.parseAlterTables(content); err != nil {
+		return fmt.Errorf("error parsing ALTER TABLE: %v", err)
+	}
+
+	if err := m.parseViews(content); err != nil {
+		return fmt.Errorf("error parsing views: %v", err)
+	}
 
-	return content
+	if err := m.parseFunctions(content); err != nil {
+		return fmt.Errorf("error parsing functions: %v", err)
+	}
+
+	if err := m.parseTriggers(content); err != nil {
+		return fmt.Errorf("error parsing triggers: %v", err)
+	}
+
+	if err := m.parsePermissions(content); err != nil {
+		return fmt.Errorf("error parsing permissions: %v", err)
+	}
+
+	return nil
 }
 
 // parseSchemas extracts database definitions from the SQL content.
@@ -274,6 +371,12 @@ func (m *MySQL) parseColumnsAndConstraints(columnDefs string, table *sqlmapper.T
 			continue
 		}
 
+		// Parse index clauses; KEY and INDEX are reserved, so they never start a column
+		if index, ok := m.parseInlineIndex(def); ok {
+			table.Indexes = append(table.Indexes, index)
+			continue
+		}
+
 		// Parse constraints
 		if strings.HasPrefix(strings.ToUpper(def), "CONSTRAINT") ||
 			(strings.Contains(strings.ToUpper(def), "PRIMARY KEY") && !strings.Contains(strings.ToUpper(def), "AUTO_INCREMENT")) ||
@@ -329,6 +432,45 @@ func (m *MySQL) parseColumnsAndConstraints(columnDefs string, table *sqlmapper.T
 	return nil
 }
 
+// parseInlineIndex parses an INDEX or KEY clause of CREATE TABLE, optionally
+// UNIQUE, FULLTEXT or SPATIAL. Unnamed indexes are named after their first
+// column, as MySQL does.
+//
+// Parameters:
+//   - def: The table element to parse
+//
+// Returns:
+//   - sqlmapper.Index: The parsed index
+//   - bool: Whether def is an index clause
+func (m *MySQL) parseInlineIndex(def string) (sqlmapper.Index, bool) {
+	re := regexp.MustCompile("(?is)^(UNIQUE\\s+|FULLTEXT\\s+|SPATIAL\\s+)?(?:INDEX|KEY)(?:\\s+(`[^`]+`|\\w+))?\\s*(?:USING\\s+(\\w+)\\s*)?\\((.*)\\)")
+	match := re.FindStringSubmatch(def)
+	if match == nil {
+		return sqlmapper.Index{}, false
+	}
+
+	index := sqlmapper.Index{
+		Name:     strings.Trim(match[2], "`"),
+		IsUnique: strings.EqualFold(strings.TrimSpace(match[1]), "UNIQUE"),
+		Type:     strings.ToUpper(match[3]),
+	}
+
+	// Key parts may have a prefix length and a direction: name(10) DESC
+	partRe := regexp.MustCompile("^`?(\\w+)`?")
+	for _, part := range strings.Split(match[4], ",") {
+		if name := partRe.FindStringSubmatch(strings.TrimSpace(part)); name != nil {
+			index.Columns = append(index.Columns, name[1])
+		}
+	}
+	if len(index.Columns) == 0 {
+		return sqlmapper.Index{}, false
+	}
+	if index.Name == "" {
+		index.Name = index.Columns[0]
+	}
+	return index, true
+}
+
 // parseColumn processes a single column definition.
 // It handles various column attributes including data type, length/precision,
 // nullability, defaults, auto increment, and inline constraints.
@@ -540,6 +682,59 @@ func (m *MySQL) parseIndexes(content string) error {
 	return nil
 }
 
+// parseAlterTables adds the indexes and constraints of ALTER TABLE ... ADD
+// statements to their tables. Other ALTER TABLE actions, and tables that are not
+// defined, are ignored as in parseIndexes.
+//
+// Parameters:
+//   - content: The SQL content to parse
+//
+// Returns:
+//   - error: An error if parsing fails
+func (m *MySQL) parseAlterTables(content string) error {
+	// Quoted names are unquoted, as the constraint patterns only match plain names
+	content = strings.ReplaceAll(content, "`", "")
+	re := regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+([.\w]+)\s+(.*?);?$`)
+	match := re.FindStringSubmatch(content)
+	if match == nil {
+		return nil
+	}
+	tableName := match[1]
+
+	// Every action after the first ADD starts with ADD; other actions are left
+	// at the end of the one before them, after the definition
+	constraintRe := regexp.MustCompile(`(?i)^(?:CONSTRAINT|PRIMARY\s+KEY|FOREIGN\s+KEY|UNIQUE|CHECK)\b`)
+	var indexes []sqlmapper.Index
+	var constraints []sqlmapper.Constraint
+	for _, action := range regexp.MustCompile(`(?i)(?:^|,)\s*ADD\s+`).Split(match[2], -1)[1:] {
+		def := strings.TrimSpace(action)
+		if index, ok := m.parseInlineIndex(def); ok {
+			indexes = append(indexes, index)
+			continue
+		}
+		if !constraintRe.MatchString(def) {
+			continue
+		}
+		constraint, err := m.parseConstraint(def)
+		if err != nil {
+			return err
+		}
+		constraints = append(constraints, constraint)
+	}
+	if len(indexes) == 0 && len(constraints) == 0 {
+		return nil
+	}
+
+	for i, table := range m.schema.Tables {
+		if table.Name == tableName || fmt.Sprintf("%s.%s", table.Schema, table.Name) == tableName {
+			m.schema.Tables[i].Indexes = append(m.schema.Tables[i].Indexes, indexes...)
+			m.schema.Tables[i].Constraints = append(m.schema.Tables[i].Constraints, constraints...)
+			break
+		}
+	}
+	return nil
+}
+
 // parseViews processes view definitions from the SQL content.
 // It handles both regular and updatable views with their definitions.
 //
@@ -808,10 +1003,19 @@ func (m *MySQL) generateTableSQL(table sqlmapper.Table) string {
 
 	result.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", table.Name))
 
-	// Columns
-	for i, column := range table.Columns {
-		result.WriteString("    " + m.generateColumnSQL(column))
-		if i < len(table.Columns)-1 {
+	// Columns and foreign keys
+	defs := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		defs = append(defs, m.generateColumnSQL(column))
+	}
+	for _, constraint := range table.Constraints {
+		if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+			defs = append(defs, m.generateConstraintSQL(constraint))
+		}
+	}
+	for i, def := range defs {
+		result.WriteString("    " + def)
+		if i < len(defs)-1 {
 			result.WriteString(",")
 		}
 		result.WriteString("\n")
@@ -896,3 +1100,40 @@ func (m *MySQL) generateIndexSQL(tableName string, index sqlmapper.Index) string
 
 	return result.String()
 }
+
+// insertBatchSize is the maximum number of rows per generated INSERT statement
+const insertBatchSize = 100
+
+// mysqlStringEscaper escapes the characters MySQL treats specially in string literals
+var mysqlStringEscaper = strings.NewReplacer(`\`, `\\`, "'", "''", "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// formatValue encodes a data value as a MySQL literal.
+// Strings are escaped with backslashes, as MySQL reads backslash escapes by default.
+//
+// Parameters:
+//   - value: The data value to encode
+//
+// Returns:
+//   - string: The SQL literal
+func (m *MySQL) formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + mysqlStringEscaper.Replace(v) + "'"
+	case time.Time:
+		// DATETIME values have no time zone
+		return "'" + sqlmapper.FormatTimeLiteral(v.UTC()) + "'"
+	}
+	return sqlmapper.FormatLiteral(value)
+}
+
+// generateInsertSQL creates the INSERT statements for the data of a table,
+// combining up to insertBatchSize rows per statement.
+//
+// Parameters:
+//   - table: The table whose data is generated
+//
+// Returns:
+//   - []string: The INSERT statements without terminators
+func (m *MySQL) generateInsertSQL(table sqlmapper.Table) []string {
+	return sqlmapper.InsertStatements(table, insertBatchSize, m.formatValue)
+}
@@ -0,0 +1,237 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// GenerateMigration renders a list of schema changes, as produced by sqlmapper.Diff,
+// into a MySQL migration script. Changes are rendered in the given order, and data types
+// of changes diffed from schemas of another dialect are translated to MySQL.
+// Objects MySQL does not support, such as sequences, are emitted as comments.
+// Triggers and routines are wrapped in DELIMITER $$ so the script can be run
+// with the mysql client.
+//
+// Parameters:
+//   - changes: The ordered schema changes to render
+//
+// Returns:
+//   - string: The generated MySQL migration statements
+//   - error: An error if a change carries an unexpected definition
+func (m *MySQL) GenerateMigration(changes []sqlmapper.Change) (string, error) {
+	changes = sqlmapper.ConvertChanges(changes, sqlmapper.MySQL)
+	var result strings.Builder
+
+	for _, change := range changes {
+		stmt, err := m.generateChangeSQL(change)
+		if err != nil {
+			return "", err
+		}
+		if stmt == "" {
+			continue
+		}
+		result.WriteString(stmt)
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// generateChangeSQL renders a single schema change
+func (m *MySQL) generateChangeSQL(change sqlmapper.Change) (string, error) {
+	switch change.Type {
+	case sqlmapper.CreateTable:
+		table, ok := change.New.(sqlmapper.Table)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		stmts := []string{m.generateTableSQL(table)}
+		for _, index := range table.Indexes {
+			stmts = append(stmts, m.generateIndexSQL(table.Name, index))
+		}
+		return strings.Join(stmts, "\n"), nil
+
+	case sqlmapper.DropTable:
+		return fmt.Sprintf("DROP TABLE %s;", change.Name), nil
+
+	case sqlmapper.AddColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", change.Table, m.generateColumnSQL(column)), nil
+
+	case sqlmapper.DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", change.Table, change.Name), nil
+
+	case sqlmapper.ModifyColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		column.IsPrimaryKey = false // The primary key is managed through constraints
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", change.Table, m.generateColumnSQL(column)), nil
+
+	case sqlmapper.AddIndex:
+		index, ok := change.New.(sqlmapper.Index)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return m.generateIndexSQL(change.Table, index), nil
+
+	case sqlmapper.DropIndex:
+		if change.Name == "" {
+			return fmt.Sprintf("-- Unnamed index on %s must be dropped manually", change.Table), nil
+		}
+		return fmt.Sprintf("DROP INDEX %s ON %s;", change.Name, change.Table), nil
+
+	case sqlmapper.AddConstraint:
+		constraint, ok := change.New.(sqlmapper.Constraint)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;", change.Table, m.generateConstraintSQL(constraint)), nil
+
+	case sqlmapper.DropConstraint:
+		constraint, _ := change.Old.(sqlmapper.Constraint)
+		switch strings.ToUpper(constraint.Type) {
+		case "PRIMARY KEY":
+			return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", change.Table), nil
+		case "FOREIGN KEY":
+			if change.Name == "" {
+				return fmt.Sprintf("-- Unnamed FOREIGN KEY on %s must be dropped manually", change.Table), nil
+			}
+			return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", change.Table, change.Name), nil
+		case "UNIQUE":
+			if change.Name == "" {
+				return fmt.Sprintf("-- Unnamed UNIQUE constraint on %s must be dropped manually", change.Table), nil
+			}
+			return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", change.Table, change.Name), nil
+		default:
+			if change.Name == "" {
+				return fmt.Sprintf("-- Unnamed %s constraint on %s must be dropped manually", constraint.Type, change.Table), nil
+			}
+			return fmt.Sprintf("ALTER TABLE %s DROP CHECK %s;", change.Table, change.Name), nil
+		}
+
+	case sqlmapper.CreateView, sqlmapper.ModifyView:
+		view, ok := change.New.(sqlmapper.View)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s;", view.Name, view.Definition), nil
+
+	case sqlmapper.DropView:
+		return fmt.Sprintf("DROP VIEW %s;", change.Name), nil
+
+	case sqlmapper.CreateSequence, sqlmapper.ModifySequence, sqlmapper.DropSequence:
+		return fmt.Sprintf("-- Sequences are not supported by MySQL: %s %s", change.Type, change.Name), nil
+
+	case sqlmapper.CreateTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return m.generateTriggerSQL(trigger), nil
+
+	case sqlmapper.ModifyTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("DROP TRIGGER %s;\n%s", change.Name, m.generateTriggerSQL(trigger)), nil
+
+	case sqlmapper.DropTrigger:
+		return fmt.Sprintf("DROP TRIGGER %s;", change.Name), nil
+
+	case sqlmapper.CreateFunction:
+		fn, ok := change.New.(sqlmapper.Function)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return m.generateFunctionSQL(fn), nil
+
+	case sqlmapper.ModifyFunction:
+		fn, ok := change.New.(sqlmapper.Function)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("DROP %s %s;\n%s", routineKind(fn), change.Name, m.generateFunctionSQL(fn)), nil
+
+	case sqlmapper.DropFunction:
+		fn, _ := change.Old.(sqlmapper.Function)
+		return fmt.Sprintf("DROP %s %s;", routineKind(fn), change.Name), nil
+	}
+
+	return "", errors.New("unsupported change type: " + string(change.Type))
+}
+
+// generateConstraintSQL creates a table constraint clause for the given constraint.
+//
+// Parameters:
+//   - constraint: The constraint structure to generate SQL for
+//
+// Returns:
+//   - string: The generated constraint clause
+func (m *MySQL) generateConstraintSQL(constraint sqlmapper.Constraint) string {
+	var result strings.Builder
+
+	if constraint.Name != "" {
+		result.WriteString(fmt.Sprintf("CONSTRAINT %s ", constraint.Name))
+	}
+
+	if strings.ToUpper(constraint.Type) == "CHECK" {
+		result.WriteString(fmt.Sprintf("CHECK (%s)", constraint.CheckExpression))
+		return result.String()
+	}
+
+	result.WriteString(fmt.Sprintf("%s (%s)", constraint.Type, strings.Join(constraint.Columns, ", ")))
+	if constraint.RefTable != "" {
+		result.WriteString(fmt.Sprintf(" REFERENCES %s(%s)", constraint.RefTable, strings.Join(constraint.RefColumns, ", ")))
+		if constraint.DeleteRule != "" {
+			result.WriteString(" ON DELETE " + constraint.DeleteRule)
+		}
+		if constraint.UpdateRule != "" {
+			result.WriteString(" ON UPDATE " + constraint.UpdateRule)
+		}
+	}
+
+	return result.String()
+}
+
+// generateTriggerSQL creates a CREATE TRIGGER statement for the given trigger
+func (m *MySQL) generateTriggerSQL(trigger sqlmapper.Trigger) string {
+	return withDelimiter(fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW BEGIN %s END",
+		trigger.Name, trigger.Timing, trigger.Event, trigger.Table, trigger.Body))
+}
+
+// generateFunctionSQL creates a CREATE FUNCTION or CREATE PROCEDURE statement for the given routine
+func (m *MySQL) generateFunctionSQL(fn sqlmapper.Function) string {
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = strings.TrimSpace(fmt.Sprintf("%s %s %s", param.Direction, param.Name, param.DataType))
+	}
+
+	if fn.IsProc {
+		return withDelimiter(fmt.Sprintf("CREATE PROCEDURE %s(%s) BEGIN %s END", fn.Name, strings.Join(params, ", "), fn.Body))
+	}
+	return withDelimiter(fmt.Sprintf("CREATE FUNCTION %s(%s) RETURNS %s BEGIN %s END", fn.Name, strings.Join(params, ", "), fn.Returns, fn.Body))
+}
+
+// withDelimiter terminates a compound statement with $$ under a DELIMITER
+// switch, so that the mysql client does not split it at the semicolons of its
+// body
+func withDelimiter(stmt string) string {
+	return "DELIMITER $$\n" + stmt + "$$\nDELIMITER ;"
+}
+
+// routineKind returns the object keyword for a routine
+func routineKind(fn sqlmapper.Function) string {
+	if fn.IsProc {
+		return "PROCEDURE"
+	}
+	return "FUNCTION"
+}
@@ -1,13 +1,14 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
-	"sync"
 
 	"github.com/mstgnz/sqlmapper"
 	"github.com/mstgnz/sqlmapper/stream"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // MySQLStreamParser implements the StreamParser interface for MySQL
@@ -24,227 +25,73 @@ func NewMySQLStreamParser() *MySQLStreamParser {
 
 // ParseStream implements the StreamParser interface
 func (p *MySQLStreamParser) ParseStream(reader io.Reader, callback func(stream.SchemaObject) error) error {
-	streamReader := stream.NewStreamReader(reader, ";")
-
-	for {
-		statement, err := streamReader.ReadStatement()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading statement: %v", err)
-		}
-
-		statement = strings.TrimSpace(statement)
-		if statement == "" {
-			continue
-		}
-
-		// Parse CREATE TABLE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TABLE") {
-			table, err := p.parseTableStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TableObject,
-				Data: table,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE VIEW statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE VIEW") {
-			view, err := p.parseViewStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ViewObject,
-				Data: view,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE FUNCTION statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE FUNCTION") {
-			function, err := p.parseFunctionStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.FunctionObject,
-				Data: function,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE PROCEDURE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE PROCEDURE") {
-			procedure, err := p.parseProcedureStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ProcedureObject,
-				Data: procedure,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE TRIGGER statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TRIGGER") {
-			trigger, err := p.parseTriggerStatement(statement)
-			if err != nil {
-				return err
-			}
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{})
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.TriggerObject,
-				Data: trigger,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-	}
+// ParseStreamLenient implements the stream.LenientStreamParser interface
+func (p *MySQLStreamParser) ParseStreamLenient(reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{Lenient: true})
+}
 
-	return nil
+// ParseStreamOptions implements the stream.OptionsStreamParser interface
+func (p *MySQLStreamParser) ParseStreamOptions(reader io.Reader, callback func(stream.SchemaObject) error, opts stream.ParseOptions) error {
+	return stream.ParseStatements(reader, tokenizer.MySQL, p.parseStatement, callback, opts)
 }
 
-// ParseStreamParallel implements parallel processing for MySQL stream parsing
+// ParseStreamParallel implements parallel processing for MySQL stream parsing.
+// Objects are delivered in input order; each worker parses with its own parser.
 func (p *MySQLStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
-	streamReader := stream.NewStreamReader(reader, ";")
-	statements := make(chan string, workers)
-	results := make(chan stream.SchemaObject, workers)
-	errors := make(chan error, workers)
-	var wg sync.WaitGroup
-
-	// Start worker goroutines
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for statement := range statements {
-				obj, err := p.parseStatement(statement)
-				if err != nil {
-					errors <- err
-					return
-				}
-				if obj != nil {
-					results <- *obj
-				}
-			}
-		}()
-	}
-
-	// Start a goroutine to close results channel after all workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	return stream.ParseStreamParallel(reader, tokenizer.MySQL, func() stream.ParseFunc {
+		return NewMySQLStreamParser().parseStatement
+	}, callback, stream.ParallelOptions{Workers: workers})
+}
 
-	// Start a goroutine to read statements and send them to workers
-	go func() {
-		for {
-			statement, err := streamReader.ReadStatement()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				errors <- fmt.Errorf("error reading statement: %v", err)
-				break
-			}
+// ParseStreamContext implements the StreamParser interface
+func (p *MySQLStreamParser) ParseStreamContext(ctx context.Context, reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return stream.ParseContext(ctx, reader, callback, p.ParseStream)
+}
 
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
-				continue
-			}
-			statements <- statement
-		}
-		close(statements)
-	}()
+// GenerateStreamContext implements the StreamParser interface
+func (p *MySQLStreamParser) GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error {
+	return stream.GenerateContext(ctx, schema, writer, p.GenerateStream)
+}
 
-	// Process results and handle errors
-	for obj := range results {
-		if err := callback(obj); err != nil {
-			return err
+// GenerateObject implements the StreamParser interface
+func (p *MySQLStreamParser) GenerateObject(obj stream.SchemaObject, writer io.Writer) error {
+	var stmt string
+	switch data := obj.Data.(type) {
+	case *sqlmapper.Index:
+		stmt = strings.TrimSuffix(p.mysql.generateIndexSQL(obj.Table, *data), ";")
+	case *sqlmapper.Constraint:
+		stmt = fmt.Sprintf("ALTER TABLE %s ADD %s", obj.Table, p.mysql.generateConstraintSQL(*data))
+	case *stream.Comment:
+		// Column comments are part of the column definition, which is not known here
+		if data.Column != "" {
+			stmt = data.LineComment(obj.Table, "MySQL sets column comments in the column definition")
+			break
 		}
-	}
-
-	// Check for any errors from workers
-	select {
-	case err := <-errors:
-		return err
+		stmt = fmt.Sprintf("ALTER TABLE %s COMMENT = %s", obj.Table, p.mysql.formatValue(data.Text))
+	case *stream.Unparsed:
+		stmt = data.SQL
 	default:
-		return nil
+		return p.GenerateStream(stream.ObjectSchema(obj), writer)
 	}
-}
 
-// parseStatement parses a single SQL statement and returns a SchemaObject
-func (p *MySQLStreamParser) parseStatement(statement string) (*stream.SchemaObject, error) {
-	upperStatement := strings.ToUpper(statement)
-
-	switch {
-	case strings.HasPrefix(upperStatement, "CREATE TABLE"):
-		table, err := p.parseTableStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TableObject,
-			Data: table,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE VIEW"):
-		view, err := p.parseViewStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ViewObject,
-			Data: view,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE FUNCTION"):
-		function, err := p.parseFunctionStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.FunctionObject,
-			Data: function,
-		}, nil
+	_, err := writer.Write([]byte(stmt + ";\n\n"))
+	return err
+}
 
-	case strings.HasPrefix(upperStatement, "CREATE PROCEDURE"):
-		procedure, err := p.parseProcedureStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ProcedureObject,
-			Data: procedure,
-		}, nil
-	}
+// Dialect implements the StreamParser interface
+func (p *MySQLStreamParser) Dialect() sqlmapper.DatabaseType {
+	return sqlmapper.MySQL
+}
 
-	return nil, nil
+// parseStatement parses a single SQL statement with the batch parser and
+// returns the objects it defines
+func (p *MySQLStreamParser) parseStatement(stmt tokenizer.Statement) ([]stream.SchemaObject, error) {
+	return stream.StatementObjects(stmt.Text, func(schema *sqlmapper.Schema) error {
+		return (&MySQL{schema: schema}).parseTokenized(stmt)
+	})
 }
 
 // GenerateStream implements the StreamParser interface
@@ -253,22 +100,51 @@ func (p *MySQLStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.W
 		return fmt.Errorf("schema cannot be nil")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.MySQL)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	// Write tables
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		// The MySQL generators terminate their statements themselves
 		stmt := p.mysql.generateTableSQL(table)
-		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+		if _, err := writer.Write([]byte(stmt + "\n\n")); err != nil {
 			return err
 		}
 
 		// Generate indexes for this table
 		for _, index := range table.Indexes {
 			stmt := p.mysql.generateIndexSQL(table.Name, index)
-			if _, err := writer.Write([]byte(stmt + ";\n")); err != nil {
+			if _, err := writer.Write([]byte(stmt + "\n")); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Write table data
+	for _, table := range schema.Tables {
+		for _, stmt := range p.mysql.generateInsertSQL(table) {
+			if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
 				return err
 			}
 		}
 	}
 
+	// Write foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD %s", fk.Table, p.mysql.generateConstraintSQL(fk.Constraint))
+		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+			return err
+		}
+	}
+
 	// Write views
 	for _, view := range schema.Views {
 		stmt := fmt.Sprintf("CREATE VIEW %s AS %s", view.Name, view.Definition)
@@ -322,115 +198,3 @@ func (p *MySQLStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.W
 
 	return nil
 }
-
-// parseTableStatement parses a CREATE TABLE statement
-func (p *MySQLStreamParser) parseTableStatement(statement string) (*sqlmapper.Table, error) {
-	// Create a temporary schema for parsing
-	tempSchema := &sqlmapper.Schema{}
-	p.mysql.schema = tempSchema
-
-	// Parse the table using the existing MySQL parser
-	if err := p.mysql.parseTables(statement); err != nil {
-		return nil, err
-	}
-
-	// Check if any table was parsed
-	if len(tempSchema.Tables) == 0 {
-		return nil, fmt.Errorf("no table found in statement")
-	}
-
-	// Return the first table
-	return &tempSchema.Tables[0], nil
-}
-
-// parseViewStatement parses a CREATE VIEW statement
-func (p *MySQLStreamParser) parseViewStatement(statement string) (*sqlmapper.View, error) {
-	// Create a temporary schema for parsing
-	tempSchema := &sqlmapper.Schema{}
-	p.mysql.schema = tempSchema
-
-	// Parse the view using the existing MySQL parser
-	if err := p.mysql.parseViews(statement); err != nil {
-		return nil, err
-	}
-
-	// Check if any view was parsed
-	if len(tempSchema.Views) == 0 {
-		return nil, fmt.Errorf("no view found in statement")
-	}
-
-	// Return the first view
-	return &tempSchema.Views[0], nil
-}
-
-// parseFunctionStatement parses a CREATE FUNCTION statement
-func (p *MySQLStreamParser) parseFunctionStatement(statement string) (*sqlmapper.Function, error) {
-	// Create a temporary schema for parsing
-	tempSchema := &sqlmapper.Schema{}
-	p.mysql.schema = tempSchema
-
-	// Parse the function using the existing MySQL parser
-	if err := p.mysql.parseFunctions(statement); err != nil {
-		return nil, err
-	}
-
-	// Check if any function was parsed
-	if len(tempSchema.Functions) == 0 {
-		return nil, fmt.Errorf("no function found in statement")
-	}
-
-	// Return the first function
-	return &tempSchema.Functions[0], nil
-}
-
-// parseProcedureStatement parses a CREATE PROCEDURE statement
-func (p *MySQLStreamParser) parseProcedureStatement(statement string) (*sqlmapper.Procedure, error) {
-	// Create a temporary schema for parsing
-	tempSchema := &sqlmapper.Schema{}
-	p.mysql.schema = tempSchema
-
-	// Parse the procedure using the existing MySQL parser
-	if err := p.mysql.parseFunctions(statement); err != nil {
-		return nil, err
-	}
-
-	// Check if any function was parsed
-	if len(tempSchema.Functions) == 0 {
-		return nil, fmt.Errorf("no procedure found in statement")
-	}
-
-	// Find the first procedure (function with IsProc=true)
-	for _, fn := range tempSchema.Functions {
-		if fn.IsProc {
-			proc := &sqlmapper.Procedure{
-				Name:       fn.Name,
-				Parameters: fn.Parameters,
-				Body:       fn.Body,
-				Schema:     fn.Schema,
-			}
-			return proc, nil
-		}
-	}
-
-	return nil, fmt.Errorf("no procedure found in statement")
-}
-
-// parseTriggerStatement parses a CREATE TRIGGER statement
-func (p *MySQLStreamParser) parseTriggerStatement(statement string) (*sqlmapper.Trigger, error) {
-	// Create a temporary schema for parsing
-	tempSchema := &sqlmapper.Schema{}
-	p.mysql.schema = tempSchema
-
-	// Parse the trigger using the existing MySQL parser
-	if err := p.mysql.parseTriggers(statement); err != nil {
-		return nil, err
-	}
-
-	// Check if any trigger was parsed
-	if len(tempSchema.Triggers) == 0 {
-		return nil, fmt.Errorf("no trigger found in statement")
-	}
-
-	// Return the first trigger
-	return &tempSchema.Triggers[0], nil
-}
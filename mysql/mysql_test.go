@@ -68,6 +68,37 @@ func TestMySQL_Parse(t *testing.T) {
 				assert.Len(t, table.Constraints, 4) // PK, FK, CHECK, UNIQUE
 			},
 		},
+		{
+			name: "CREATE TABLE with Index Clauses",
+			content: `
+				CREATE TABLE users (
+					id INT AUTO_INCREMENT PRIMARY KEY,
+					email VARCHAR(100) NOT NULL,
+					name VARCHAR(100),
+					bio TEXT,
+					INDEX idx_email (email),
+					KEY (name(20) DESC),
+					UNIQUE KEY uq_email_name USING BTREE (email, name),
+					FULLTEXT INDEX ft_bio (bio)
+				) ENGINE=InnoDB;`,
+			wantErr: false,
+			validate: func(t *testing.T, schema *sqlmapper.Schema) {
+				if assert.Len(t, schema.Tables, 1) {
+					table := schema.Tables[0]
+					assert.Len(t, table.Columns, 4)
+					for i := range table.Indexes {
+						table.Indexes[i].Pos = nil
+					}
+					assert.Equal(t, []sqlmapper.Index{
+						{Name: "idx_email", Columns: []string{"email"}},
+						{Name: "name", Columns: []string{"name"}},
+						{Name: "uq_email_name", Columns: []string{"email", "name"}, IsUnique: true, Type: "BTREE"},
+						{Name: "ft_bio", Columns: []string{"bio"}},
+					}, table.Indexes)
+				}
+				assert.Empty(t, sqlmapper.Validate(schema))
+			},
+		},
 		{
 			name: "ALTER TABLE Operations",
 			content: `
@@ -219,7 +250,15 @@ func TestMySQL_Parse(t *testing.T) {
 					(2, 'Bob Wilson');`,
 			wantErr: false,
 			validate: func(t *testing.T, schema *sqlmapper.Schema) {
-				// INSERT komutları şu an için parse edilmiyor
+				assert.Len(t, schema.Tables, 2)
+				assert.Equal(t, "departments", schema.Tables[0].Name)
+				assert.Len(t, schema.Tables[0].Data, 3)
+				assert.Equal(t, "Sales", schema.Tables[0].Data[2].Values["name"])
+
+				employees := schema.Tables[1]
+				assert.Len(t, employees.Data, 3)
+				assert.Equal(t, int64(1), employees.Data[1].Values["department_id"])
+				assert.Equal(t, "Jane Smith", employees.Data[1].Values["name"])
 			},
 		},
 	}
@@ -317,3 +356,217 @@ CREATE UNIQUE INDEX idx_price ON products(price);`),
 		})
 	}
 }
+
+func TestMySQL_GenerateMigration(t *testing.T) {
+	oldSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "name", DataType: "VARCHAR", Length: 100},
+					{Name: "legacy", DataType: "TEXT", IsNullable: true},
+				},
+				Indexes: []sqlmapper.Index{
+					{Name: "idx_name", Columns: []string{"name"}},
+				},
+			},
+			{
+				Name: "logs",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT"},
+				},
+			},
+		},
+		Views: []sqlmapper.View{
+			{Name: "active_users", Definition: "SELECT * FROM users"},
+		},
+	}
+
+	newSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "name", DataType: "VARCHAR", Length: 200},
+					{Name: "email", DataType: "VARCHAR", Length: 255, IsUnique: true},
+				},
+				Indexes: []sqlmapper.Index{
+					{Name: "idx_email", Columns: []string{"email"}, IsUnique: true},
+				},
+			},
+			{
+				Name: "orders",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "user_id", DataType: "INT"},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "fk_user", Type: "FOREIGN KEY", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+				},
+			},
+		},
+		Views: []sqlmapper.View{
+			{Name: "active_users", Definition: "SELECT id, name FROM users"},
+		},
+	}
+
+	m := &MySQL{}
+	got, err := m.GenerateMigration(sqlmapper.Diff(oldSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+DROP VIEW active_users;
+DROP INDEX idx_name ON users;
+ALTER TABLE users DROP COLUMN legacy;
+DROP TABLE logs;
+CREATE TABLE orders (
+    id INT PRIMARY KEY,
+    user_id INT NOT NULL,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+ALTER TABLE users ADD COLUMN email VARCHAR(255) NOT NULL UNIQUE;
+ALTER TABLE users MODIFY COLUMN name VARCHAR(200) NOT NULL;
+CREATE UNIQUE INDEX idx_email ON users(email);
+CREATE OR REPLACE VIEW active_users AS SELECT id, name FROM users;`), strings.TrimSpace(got))
+
+	// Identical schemas produce no migration
+	got, err = m.GenerateMigration(sqlmapper.Diff(newSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	// Malformed changes are rejected
+	_, err = m.GenerateMigration([]sqlmapper.Change{{Type: sqlmapper.AddColumn, Table: "users", Name: "x"}})
+	assert.Error(t, err)
+}
+
+func TestMySQL_GenerateMigration_Routines(t *testing.T) {
+	oldSchema := &sqlmapper.Schema{
+		Dialect: sqlmapper.PostgreSQL,
+		Tables: []sqlmapper.Table{{
+			Name: "users",
+			Columns: []sqlmapper.Column{
+				{Name: "id", DataType: "integer", IsPrimaryKey: true},
+				{Name: "name", DataType: "text"},
+			},
+			Indexes: []sqlmapper.Index{
+				{Columns: []string{"name"}},
+				{Columns: []string{"id", "name"}, IsUnique: true},
+			},
+		}},
+		Triggers: []sqlmapper.Trigger{
+			{Name: "trg_users", Table: "users", Timing: "BEFORE", Event: "INSERT", Body: "SET NEW.name = TRIM(NEW.name);"},
+		},
+	}
+	newSchema := &sqlmapper.Schema{
+		Dialect: sqlmapper.PostgreSQL,
+		Tables: []sqlmapper.Table{{
+			Name: "users",
+			Columns: []sqlmapper.Column{
+				{Name: "id", DataType: "integer", IsPrimaryKey: true},
+				{Name: "avatar", DataType: "bytea", IsNullable: true},
+			},
+			Indexes: []sqlmapper.Index{
+				{Columns: []string{"id", "name"}, IsUnique: true},
+			},
+		}},
+		Triggers: []sqlmapper.Trigger{
+			{Name: "trg_users", Table: "users", Timing: "BEFORE", Event: "INSERT", Body: "SET NEW.avatar = NULL;"},
+		},
+	}
+
+	// The changed trigger is dropped before the column it uses, the unchanged
+	// unnamed index is kept, and PostgreSQL types are translated
+	m := &MySQL{}
+	got, err := m.GenerateMigration(sqlmapper.Diff(oldSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+DROP TRIGGER trg_users;
+-- Unnamed index on users must be dropped manually
+ALTER TABLE users DROP COLUMN name;
+ALTER TABLE users ADD COLUMN avatar blob;
+DELIMITER $$
+CREATE TRIGGER trg_users BEFORE INSERT ON users FOR EACH ROW BEGIN SET NEW.avatar = NULL; END$$
+DELIMITER ;`), strings.TrimSpace(got))
+}
+
+func TestMySQL_Generate_DependencyOrder(t *testing.T) {
+	schema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "order_items",
+				Columns: []sqlmapper.Column{
+					{Name: "order_id", DataType: "INT"},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "fk_item_order", Type: "FOREIGN KEY", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}},
+				},
+			},
+			{
+				Name: "orders",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+				},
+			},
+		},
+	}
+
+	m := NewMySQL()
+	got, err := m.Generate(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+CREATE TABLE orders (
+    id INT PRIMARY KEY
+);
+
+CREATE TABLE order_items (
+    order_id INT NOT NULL,
+    CONSTRAINT fk_item_order FOREIGN KEY (order_id) REFERENCES orders(id)
+);`), strings.TrimSpace(got))
+
+	// A reference cycle is broken with a deferred ALTER TABLE
+	schema = &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "employees",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "department_id", DataType: "INT", IsNullable: true},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "fk_emp_dept", Type: "FOREIGN KEY", Columns: []string{"department_id"}, RefTable: "departments", RefColumns: []string{"id"}},
+				},
+			},
+			{
+				Name: "departments",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "manager_id", DataType: "INT", IsNullable: true},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "fk_dept_manager", Type: "FOREIGN KEY", Columns: []string{"manager_id"}, RefTable: "employees", RefColumns: []string{"id"}},
+				},
+			},
+		},
+	}
+
+	got, err = m.Generate(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+CREATE TABLE employees (
+    id INT PRIMARY KEY,
+    department_id INT
+);
+
+CREATE TABLE departments (
+    id INT PRIMARY KEY,
+    manager_id INT,
+    CONSTRAINT fk_dept_manager FOREIGN KEY (manager_id) REFERENCES employees(id)
+);
+
+ALTER TABLE employees ADD CONSTRAINT fk_emp_dept FOREIGN KEY (department_id) REFERENCES departments(id);`), strings.TrimSpace(got))
+
+	// The input schema is left untouched
+	assert.Len(t, schema.Tables[0].Constraints, 1)
+	assert.Equal(t, "employees", schema.Tables[0].Name)
+}
@@ -3,12 +3,15 @@
 package oracle
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // Oracle represents an Oracle parser implementation that handles parsing and generating
@@ -26,6 +29,14 @@ func NewOracle() sqlmapper.Database {
 	}
 }
 
+func init() {
+	sqlmapper.RegisterDialect(sqlmapper.Oracle, sqlmapper.Dialect{
+		NewParser:       func() sqlmapper.Parser { return NewOracle() },
+		NewStreamParser: func() interface{} { return NewOracleStreamParser() },
+		IncludeFormat:   "@%s",
+	})
+}
+
 // Parse takes an Oracle SQL dump content and parses it into a common schema structure.
 // It processes various Oracle objects including:
 // - Tables with columns and constraints
@@ -45,87 +56,116 @@ func (o *Oracle) Parse(content string) (*sqlmapper.Schema, error) {
 		return nil, errors.New("empty content")
 	}
 
-	// SQL ifadelerini ayır
-	var statements []string
-	var currentStmt strings.Builder
-	lines := strings.Split(content, "\n")
+	if _, err := tokenizer.ParseStatements(content, tokenizer.Oracle, o.schema, o.parseTokenized, false); err != nil {
+		return nil, err
+	}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	o.schema.Dialect = sqlmapper.Oracle
 
-		if line == "/" {
-			if currentStmt.Len() > 0 {
-				statements = append(statements, currentStmt.String())
-				currentStmt.Reset()
-			}
-			continue
-		}
+	return o.schema, nil
+}
 
-		if strings.HasSuffix(line, ";") {
-			currentStmt.WriteString(line[:len(line)-1])
-			if currentStmt.Len() > 0 {
-				statements = append(statements, currentStmt.String())
-				currentStmt.Reset()
-			}
-		} else {
-			if currentStmt.Len() > 0 {
-				currentStmt.WriteString(" ")
-			}
-			currentStmt.WriteString(line)
-		}
+// ParseLenient parses a Oracle SQL dump like Parse, but skips the statements
+// that fail to parse. Each skipped statement is reported as a diagnostic with
+// its position and the reason it failed.
+//
+// Parameters:
+//   - content: The Oracle SQL dump content to parse
+//
+// Returns:
+//   - *sqlmapper.Schema: The objects of the statements that could be parsed
+//   - []sqlmapper.Diagnostic: The skipped statements
+//   - error: An error if the content is empty
+func (o *Oracle) ParseLenient(content string) (*sqlmapper.Schema, []sqlmapper.Diagnostic, error) {
+	if content == "" {
+		return nil, nil, errors.New("empty content")
 	}
 
-	if currentStmt.Len() > 0 {
-		statements = append(statements, currentStmt.String())
+	diagnostics, err := tokenizer.ParseStatements(content, tokenizer.Oracle, o.schema, o.parseTokenized, true)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	for _, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
+	o.schema.Dialect = sqlmapper.Oracle
+
+	return o.schema, diagnostics, nil
+}
+
+// parseTokenized parses a single statement into the schema
+func (o *Oracle) parseTokenized(statement tokenizer.Statement) error {
+	stmt := statement.Compact()
+
+	// INSERT
+	if tokenizer.IsInsert(statement) {
+		if err := o.parseInsert(statement); err != nil {
+			return err
 		}
+	}
 
-		// CREATE TABLE
-		if strings.HasPrefix(strings.ToUpper(stmt), "CREATE TABLE") {
-			table, err := o.parseCreateTable(stmt)
-			if err != nil {
-				return nil, err
-			}
-			o.schema.Tables = append(o.schema.Tables, table)
+	// CREATE TABLE, as whole keywords so that CREATE TABLESPACE is not taken for a table
+	if regexp.MustCompile(`(?is)^CREATE\s+TABLE\s`).MatchString(stmt) {
+		table, err := o.parseCreateTable(stmt)
+		if err != nil {
+			return err
 		}
+		o.schema.Tables = append(o.schema.Tables, table)
+		return nil
+	}
 
-		// CREATE SEQUENCE
-		if strings.HasPrefix(strings.ToUpper(stmt), "CREATE SEQUENCE") {
-			seq, err := o.parseCreateSequence(stmt)
-			if err != nil {
-				return nil, err
-			}
-			o.schema.Sequences = append(o.schema.Sequences, seq)
+	// ALTER TABLE
+	if regexp.MustCompile(`(?is)^ALTER\s+TABLE\s`).MatchString(stmt) {
+		return o.parseAlterTable(stmt)
+	}
+
+	// CREATE SEQUENCE
+	if strings.HasPrefix(strings.ToUpper(stmt), "CREATE SEQUENCE") {
+		seq, err := o.parseCreateSequence(stmt)
+		if err != nil {
+			return err
 		}
+		o.schema.Sequences = append(o.schema.Sequences, seq)
+	}
 
-		// CREATE VIEW
-		if strings.HasPrefix(strings.ToUpper(stmt), "CREATE") && strings.Contains(strings.ToUpper(stmt), "VIEW") {
-			view, err := o.parseCreateView(stmt)
-			if err != nil {
-				return nil, err
-			}
-			o.schema.Views = append(o.schema.Views, view)
+	// CREATE VIEW
+	if strings.HasPrefix(strings.ToUpper(stmt), "CREATE") && strings.Contains(strings.ToUpper(stmt), "VIEW") {
+		view, err := o.parseCreateView(stmt)
+		if err != nil {
+			return err
 		}
+		o.schema.Views = append(o.schema.Views, view)
+	}
 
-		// CREATE TRIGGER
-		if strings.HasPrefix(strings.ToUpper(stmt), "CREATE") && strings.Contains(strings.ToUpper(stmt), "TRIGGER") {
-			trigger, err := o.parseCreateTrigger(stmt)
-			if err != nil {
-				return nil, err
-			}
-			o.schema.Triggers = append(o.schema.Triggers, trigger)
+	// CREATE TRIGGER
+	if strings.HasPrefix(strings.ToUpper(stmt), "CREATE") && strings.Contains(strings.ToUpper(stmt), "TRIGGER") {
+		trigger, err := o.parseCreateTrigger(stmt)
+		if err != nil {
+			return err
 		}
+		o.schema.Triggers = append(o.schema.Triggers, trigger)
 	}
 
-	return o.schema, nil
+	return nil
+}
+
+// parseInsert adds the rows of an INSERT ... VALUES statement to the data of its table.
+//
+// Parameters:
+//   - stmt: The INSERT statement read by the tokenizer
+//
+// Returns:
+//   - error: An error if the statement cannot be decoded
+func (o *Oracle) parseInsert(stmt tokenizer.Statement) error {
+	insert, err := tokenizer.ParseInsert(stmt, tokenizer.Oracle)
+	if err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	if insert == nil {
+		return nil
+	}
+	if err := sqlmapper.AppendRows(o.schema, insert.Schema, insert.Table, insert.Columns, insert.Rows); err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	return nil
 }
 
 // parseCreateTable processes a CREATE TABLE statement and extracts table structure.
@@ -144,92 +184,26 @@ func (o *Oracle) Parse(content string) (*sqlmapper.Schema, error) {
 func (o *Oracle) parseCreateTable(stmt string) (sqlmapper.Table, error) {
 	table := sqlmapper.Table{}
 
-	// Tablo adını al
-	tableNameRegex := regexp.MustCompile(`CREATE\s+TABLE\s+(\w+)`)
-	matches := tableNameRegex.FindStringSubmatch(stmt)
-	if len(matches) > 1 {
-		table.Name = matches[1]
+	// Tablo adını al; adın ardından parantez içinde kolon listesi gelmeli
+	tableNameRegex := regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(?:\w+\.)?(\w+)\s*\(`)
+	loc := tableNameRegex.FindStringSubmatchIndex(stmt)
+	if loc == nil {
+		return table, fmt.Errorf("invalid CREATE TABLE statement: no column list")
 	}
+	table.Name = stmt[loc[2]:loc[3]]
 
 	// Kolonları parse et
-	columnsStr := stmt[strings.Index(stmt, "(")+1 : strings.LastIndex(stmt, ")")]
+	closeIdx := strings.LastIndex(stmt, ")")
+	if closeIdx < loc[1] {
+		return table, fmt.Errorf("invalid CREATE TABLE statement: unterminated column list")
+	}
+	columnsStr := stmt[loc[1]:closeIdx]
 	columnDefs := strings.Split(columnsStr, ",")
 
 	for _, colDef := range columnDefs {
 		colDef = strings.TrimSpace(colDef)
 		if strings.HasPrefix(colDef, "CONSTRAINT") {
-			constraint := sqlmapper.Constraint{}
-
-			// Constraint adını al
-			nameRegex := regexp.MustCompile(`CONSTRAINT\s+(\w+)`)
-			matches := nameRegex.FindStringSubmatch(colDef)
-			if len(matches) > 1 {
-				constraint.Name = matches[1]
-			}
-
-			if strings.Contains(colDef, "PRIMARY KEY") {
-				constraint.Type = "PRIMARY KEY"
-				// Kolonları al
-				colsRegex := regexp.MustCompile(`PRIMARY\s+KEY\s*\(([^)]+)\)`)
-				matches = colsRegex.FindStringSubmatch(colDef)
-				if len(matches) > 1 {
-					cols := strings.Split(matches[1], ",")
-					for i, col := range cols {
-						cols[i] = strings.TrimSpace(col)
-					}
-					constraint.Columns = cols
-				}
-			} else if strings.Contains(colDef, "FOREIGN KEY") {
-				constraint.Type = "FOREIGN KEY"
-				// FK kolonlarını al
-				fkRegex := regexp.MustCompile(`FOREIGN\s+KEY\s*\(([^)]+)\)`)
-				matches = fkRegex.FindStringSubmatch(colDef)
-				if len(matches) > 1 {
-					cols := strings.Split(matches[1], ",")
-					for i, col := range cols {
-						cols[i] = strings.TrimSpace(col)
-					}
-					constraint.Columns = cols
-				}
-				// Referans tabloyu ve kolonları al
-				refRegex := regexp.MustCompile(`REFERENCES\s+(\w+)\s*\(([^)]+)\)`)
-				matches = refRegex.FindStringSubmatch(colDef)
-				if len(matches) > 2 {
-					constraint.RefTable = matches[1]
-					refCols := strings.Split(matches[2], ",")
-					for i, col := range refCols {
-						refCols[i] = strings.TrimSpace(col)
-					}
-					constraint.RefColumns = refCols
-				}
-				// ON DELETE kuralını al
-				if strings.Contains(colDef, "ON DELETE") {
-					if strings.Contains(colDef, "CASCADE") {
-						constraint.DeleteRule = "CASCADE"
-					}
-				}
-			} else if strings.Contains(colDef, "UNIQUE") {
-				constraint.Type = "UNIQUE"
-				// Kolonları al
-				colsRegex := regexp.MustCompile(`UNIQUE\s*\(([^)]+)\)`)
-				matches = colsRegex.FindStringSubmatch(colDef)
-				if len(matches) > 1 {
-					cols := strings.Split(matches[1], ",")
-					for i, col := range cols {
-						cols[i] = strings.TrimSpace(col)
-					}
-					constraint.Columns = cols
-				}
-			} else if strings.Contains(colDef, "CHECK") {
-				constraint.Type = "CHECK"
-				// Check ifadesini al
-				checkRegex := regexp.MustCompile(`CHECK\s*\(([^)]+)\)`)
-				matches = checkRegex.FindStringSubmatch(colDef)
-				if len(matches) > 1 {
-					constraint.CheckExpression = strings.TrimSpace(matches[1])
-				}
-			}
-			table.Constraints = append(table.Constraints, constraint)
+			table.Constraints = append(table.Constraints, o.parseConstraint(colDef))
 			continue
 		}
 
@@ -247,8 +221,7 @@ func (o *Oracle) parseCreateTable(stmt string) (sqlmapper.Table, error) {
 			col.IsNullable = false
 		}
 
-		if strings.Contains(colDef, "DEFAULT") {
-			defaultIdx := strings.Index(strings.ToUpper(colDef), "DEFAULT")
+		if defaultIdx := strings.Index(strings.ToUpper(colDef), "DEFAULT"); defaultIdx != -1 {
 			restStr := colDef[defaultIdx+7:]
 			defaultEnd := strings.Index(restStr, " ")
 			if defaultEnd == -1 {
@@ -293,6 +266,113 @@ func (o *Oracle) parseCreateTable(stmt string) (sqlmapper.Table, error) {
 	return table, nil
 }
 
+// parseConstraint parses a table constraint of CREATE TABLE or ALTER TABLE ... ADD,
+// named or not.
+//
+// Parameters:
+//   - colDef: The constraint definition
+//
+// Returns:
+//   - sqlmapper.Constraint: The parsed constraint
+func (o *Oracle) parseConstraint(colDef string) sqlmapper.Constraint {
+	constraint := sqlmapper.Constraint{}
+
+	// Constraint adını al
+	nameRegex := regexp.MustCompile(`CONSTRAINT\s+(\w+)`)
+	matches := nameRegex.FindStringSubmatch(colDef)
+	if len(matches) > 1 {
+		constraint.Name = matches[1]
+	}
+
+	if strings.Contains(colDef, "PRIMARY KEY") {
+		constraint.Type = "PRIMARY KEY"
+		// Kolonları al
+		colsRegex := regexp.MustCompile(`PRIMARY\s+KEY\s*\(([^)]+)\)`)
+		matches = colsRegex.FindStringSubmatch(colDef)
+		if len(matches) > 1 {
+			cols := strings.Split(matches[1], ",")
+			for i, col := range cols {
+				cols[i] = strings.TrimSpace(col)
+			}
+			constraint.Columns = cols
+		}
+	} else if strings.Contains(colDef, "FOREIGN KEY") {
+		constraint.Type = "FOREIGN KEY"
+		// FK kolonlarını al
+		fkRegex := regexp.MustCompile(`FOREIGN\s+KEY\s*\(([^)]+)\)`)
+		matches = fkRegex.FindStringSubmatch(colDef)
+		if len(matches) > 1 {
+			cols := strings.Split(matches[1], ",")
+			for i, col := range cols {
+				cols[i] = strings.TrimSpace(col)
+			}
+			constraint.Columns = cols
+		}
+		// Referans tabloyu ve kolonları al
+		refRegex := regexp.MustCompile(`REFERENCES\s+(?:\w+\.)?(\w+)\s*\(([^)]+)\)`)
+		matches = refRegex.FindStringSubmatch(colDef)
+		if len(matches) > 2 {
+			constraint.RefTable = matches[1]
+			refCols := strings.Split(matches[2], ",")
+			for i, col := range refCols {
+				refCols[i] = strings.TrimSpace(col)
+			}
+			constraint.RefColumns = refCols
+		}
+		// ON DELETE kuralını al
+		if strings.Contains(colDef, "ON DELETE") {
+			if strings.Contains(colDef, "CASCADE") {
+				constraint.DeleteRule = "CASCADE"
+			}
+		}
+	} else if strings.Contains(colDef, "UNIQUE") {
+		constraint.Type = "UNIQUE"
+		// Kolonları al
+		colsRegex := regexp.MustCompile(`UNIQUE\s*\(([^)]+)\)`)
+		matches = colsRegex.FindStringSubmatch(colDef)
+		if len(matches) > 1 {
+			cols := strings.Split(matches[1], ",")
+			for i, col := range cols {
+				cols[i] = strings.TrimSpace(col)
+			}
+			constraint.Columns = cols
+		}
+	} else if strings.Contains(colDef, "CHECK") {
+		constraint.Type = "CHECK"
+		// Check ifadesini al
+		checkRegex := regexp.MustCompile(`CHECK\s*\(([^)]+)\)`)
+		matches = checkRegex.FindStringSubmatch(colDef)
+		if len(matches) > 1 {
+			constraint.CheckExpression = strings.TrimSpace(matches[1])
+		}
+	}
+	return constraint
+}
+
+// parseAlterTable adds the constraint of an ALTER TABLE ... ADD statement to its
+// table. Other ALTER TABLE actions, and tables that are not defined, are ignored.
+//
+// Parameters:
+//   - stmt: The ALTER TABLE statement to parse
+//
+// Returns:
+//   - error: An error if parsing fails
+func (o *Oracle) parseAlterTable(stmt string) error {
+	alterRegex := regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:\w+\.)?(\w+)\s+ADD\s+((?:CONSTRAINT|PRIMARY\s+KEY|FOREIGN\s+KEY|UNIQUE|CHECK)\b.*)$`)
+	matches := alterRegex.FindStringSubmatch(stmt)
+	if matches == nil {
+		return nil
+	}
+
+	for i := range o.schema.Tables {
+		if o.schema.Tables[i].Name == matches[1] {
+			o.schema.Tables[i].Constraints = append(o.schema.Tables[i].Constraints, o.parseConstraint(matches[2]))
+			break
+		}
+	}
+	return nil
+}
+
 // parseCreateSequence processes a CREATE SEQUENCE statement.
 // It extracts sequence properties including:
 // - Sequence name and schema
@@ -417,7 +497,7 @@ func (o *Oracle) parseCreateTrigger(stmt string) (sqlmapper.Trigger, error) {
 	// Trigger gövdesini al
 	beginIndex := strings.Index(strings.ToUpper(stmt), "BEGIN")
 	endIndex := strings.LastIndex(strings.ToUpper(stmt), "END")
-	if beginIndex != -1 && endIndex != -1 {
+	if beginIndex != -1 && endIndex > beginIndex {
 		trigger.Body = strings.TrimSpace(stmt[beginIndex : endIndex+3])
 	}
 
@@ -443,6 +523,12 @@ func (o *Oracle) Generate(schema *sqlmapper.Schema) (string, error) {
 		return "", errors.New("empty schema")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.Oracle)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	var result strings.Builder
 
 	// Create sequences
@@ -453,6 +539,19 @@ func (o *Oracle) Generate(schema *sqlmapper.Schema) (string, error) {
 
 	// Create tables
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		// Unnamed constraints are handled with column definitions
+		var constraints []sqlmapper.Constraint
+		for _, constraint := range table.Constraints {
+			if constraint.Name != "" {
+				constraints = append(constraints, constraint)
+			}
+		}
+
 		result.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", table.Name))
 
 		// Add columns
@@ -481,17 +580,14 @@ func (o *Oracle) Generate(schema *sqlmapper.Schema) (string, error) {
 			if col.IsUnique && !col.IsPrimaryKey {
 				result.WriteString(" UNIQUE")
 			}
-			if i < len(table.Columns)-1 || len(table.Constraints) > 0 {
+			if i < len(table.Columns)-1 || len(constraints) > 0 {
 				result.WriteString(",")
 			}
 			result.WriteString("\n")
 		}
 
 		// Add Constraint
-		for i, constraint := range table.Constraints {
-			if constraint.Name == "" {
-				continue // Skip unnamed constraints as they are handled with column definitions
-			}
+		for i, constraint := range constraints {
 			result.WriteString(fmt.Sprintf("    CONSTRAINT %s %s", constraint.Name, constraint.Type))
 			if len(constraint.Columns) > 0 {
 				result.WriteString(fmt.Sprintf(" (%s)", strings.Join(constraint.Columns, ", ")))
@@ -505,7 +601,7 @@ func (o *Oracle) Generate(schema *sqlmapper.Schema) (string, error) {
 					result.WriteString(fmt.Sprintf(" ON DELETE %s", constraint.DeleteRule))
 				}
 			}
-			if i < len(table.Constraints)-1 {
+			if i < len(constraints)-1 {
 				result.WriteString(",")
 			}
 			result.WriteString("\n")
@@ -527,6 +623,18 @@ func (o *Oracle) Generate(schema *sqlmapper.Schema) (string, error) {
 		result.WriteString("\n")
 	}
 
+	// Add table data
+	for _, table := range schema.Tables {
+		for _, stmt := range o.generateInsertSQL(table) {
+			result.WriteString(stmt + ";\n")
+		}
+	}
+
+	// Add foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		result.WriteString(fmt.Sprintf("ALTER TABLE %s ADD %s;\n\n", fk.Table, o.generateConstraintSQL(fk.Constraint)))
+	}
+
 	// Create views
 	for _, view := range schema.Views {
 		result.WriteString(fmt.Sprintf("CREATE OR REPLACE VIEW %s AS\n%s;\n\n",
@@ -557,112 +665,6 @@ func (o *Oracle) Generate(schema *sqlmapper.Schema) (string, error) {
 	return result.String(), nil
 }
 
-func (o *Oracle) parseTables(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+TABLE\s+([.\w]+)\s*\((.*?)\)(?:\s+TABLESPACE\s+(\w+))?`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 2 {
-		tableName := matches[1]
-		columnDefs := matches[2]
-
-		table := sqlmapper.Table{}
-
-		// Parse schema if exists
-		parts := strings.Split(tableName, ".")
-		if len(parts) > 1 {
-			table.Schema = parts[0]
-			table.Name = parts[1]
-		} else {
-			table.Name = tableName
-		}
-
-		// Parse tablespace if exists
-		if len(matches) > 3 && matches[3] != "" {
-			table.TableSpace = matches[3]
-		}
-
-		// Parse columns and constraints
-		columns := strings.Split(columnDefs, ",")
-		for _, col := range columns {
-			col = strings.TrimSpace(col)
-			if strings.HasPrefix(strings.ToUpper(col), "CONSTRAINT") {
-				continue // Skip constraints for now
-			}
-
-			parts := strings.Fields(col)
-			if len(parts) < 2 {
-				continue
-			}
-
-			column := sqlmapper.Column{
-				Name:       parts[0],
-				DataType:   parts[1],
-				IsNullable: true,
-			}
-
-			// Parse length/precision
-			if strings.Contains(column.DataType, "(") {
-				re := regexp.MustCompile(`(\w+)\((\d+)(?:,(\d+))?\)`)
-				if matches := re.FindStringSubmatch(column.DataType); len(matches) > 2 {
-					column.DataType = matches[1]
-					fmt.Sscanf(matches[2], "%d", &column.Length)
-					if len(matches) > 3 && matches[3] != "" {
-						fmt.Sscanf(matches[3], "%d", &column.Scale)
-					}
-				}
-			}
-
-			// Parse constraints
-			if strings.Contains(strings.ToUpper(col), "NOT NULL") {
-				column.IsNullable = false
-			}
-			if strings.Contains(strings.ToUpper(col), "PRIMARY KEY") {
-				column.IsPrimaryKey = true
-			}
-			if strings.Contains(strings.ToUpper(col), "UNIQUE") {
-				column.IsUnique = true
-			}
-			if strings.Contains(strings.ToUpper(col), "DEFAULT") {
-				re := regexp.MustCompile(`DEFAULT\s+([^,\s]+)`)
-				if matches := re.FindStringSubmatch(col); len(matches) > 1 {
-					column.DefaultValue = matches[1]
-				}
-			}
-
-			table.Columns = append(table.Columns, column)
-		}
-
-		o.schema.Tables = append(o.schema.Tables, table)
-	}
-
-	return nil
-}
-
-func (o *Oracle) parseViews(statement string) error {
-	re := regexp.MustCompile(`CREATE(?:\s+OR\s+REPLACE)?\s+VIEW\s+([.\w]+)\s+AS\s+(.*?)(?:WITH\s+READ\s+ONLY)?$`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 2 {
-		viewName := matches[1]
-		view := sqlmapper.View{
-			Definition: matches[2],
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(viewName, ".")
-		if len(parts) > 1 {
-			view.Schema = parts[0]
-			view.Name = parts[1]
-		} else {
-			view.Name = viewName
-		}
-
-		o.schema.Views = append(o.schema.Views, view)
-	}
-
-	return nil
-}
-
 func (o *Oracle) parseFunctions(statement string) error {
 	re := regexp.MustCompile(`CREATE(?:\s+OR\s+REPLACE)?\s+(FUNCTION|PROCEDURE)\s+([.\w]+)\s*\((.*?)\)(?:\s+RETURN\s+(\w+))?\s+IS|AS\s+(.*?)(?:END\s+\w+)?$`)
 	matches := re.FindStringSubmatch(statement)
@@ -709,76 +711,6 @@ func (o *Oracle) parseFunctions(statement string) error {
 	return nil
 }
 
-func (o *Oracle) parseTriggers(statement string) error {
-	re := regexp.MustCompile(`CREATE(?:\s+OR\s+REPLACE)?\s+TRIGGER\s+([.\w]+)\s+(BEFORE|AFTER|INSTEAD\s+OF)\s+(INSERT|UPDATE|DELETE)\s+ON\s+([.\w]+)(?:\s+FOR\s+EACH\s+ROW)?\s+(.*?)(?:END\s+\w+)?$`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 5 {
-		triggerName := matches[1]
-		trigger := sqlmapper.Trigger{
-			Timing:     matches[2],
-			Event:      matches[3],
-			Table:      matches[4],
-			Body:       matches[5],
-			ForEachRow: strings.Contains(statement, "FOR EACH ROW"),
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(triggerName, ".")
-		if len(parts) > 1 {
-			trigger.Schema = parts[0]
-			trigger.Name = parts[1]
-		} else {
-			trigger.Name = triggerName
-		}
-
-		o.schema.Triggers = append(o.schema.Triggers, trigger)
-	}
-
-	return nil
-}
-
-func (o *Oracle) parseSequences(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+SEQUENCE\s+([.\w]+)(?:\s+START\s+WITH\s+(\d+))?(?:\s+INCREMENT\s+BY\s+(\d+))?(?:\s+MINVALUE\s+(\d+))?(?:\s+MAXVALUE\s+(\d+))?(?:\s+CACHE\s+(\d+))?(?:\s+CYCLE)?`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 1 {
-		seqName := matches[1]
-		seq := sqlmapper.Sequence{
-			Name: seqName,
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(seqName, ".")
-		if len(parts) > 1 {
-			seq.Schema = parts[0]
-			seq.Name = parts[1]
-		}
-
-		// Parse optional parameters
-		if len(matches) > 2 && matches[2] != "" {
-			fmt.Sscanf(matches[2], "%d", &seq.StartValue)
-		}
-		if len(matches) > 3 && matches[3] != "" {
-			fmt.Sscanf(matches[3], "%d", &seq.IncrementBy)
-		}
-		if len(matches) > 4 && matches[4] != "" {
-			fmt.Sscanf(matches[4], "%d", &seq.MinValue)
-		}
-		if len(matches) > 5 && matches[5] != "" {
-			fmt.Sscanf(matches[5], "%d", &seq.MaxValue)
-		}
-		if len(matches) > 6 && matches[6] != "" {
-			fmt.Sscanf(matches[6], "%d", &seq.Cache)
-		}
-		seq.Cycle = strings.Contains(statement, "CYCLE")
-
-		o.schema.Sequences = append(o.schema.Sequences, seq)
-	}
-
-	return nil
-}
-
 func (o *Oracle) parseTypes(statement string) error {
 	re := regexp.MustCompile(`CREATE(?:\s+OR\s+REPLACE)?\s+TYPE\s+([.\w]+)\s+(?:AS\s+|IS\s+|UNDER\s+)?(.+?)(?:NOT\s+FINAL)?$`)
 	matches := re.FindStringSubmatch(statement)
@@ -875,31 +807,19 @@ func (o *Oracle) generateTypeSQL(typ sqlmapper.Type) string {
 func (o *Oracle) generateTableSQL(table sqlmapper.Table) string {
 	sql := "CREATE TABLE " + table.Name + " (\n"
 
-	// Generate columns
-	for i, col := range table.Columns {
-		sql += "    " + col.Name + " " + col.DataType
-		if col.Length > 0 {
-			sql += fmt.Sprintf("(%d", col.Length)
-			if col.Scale > 0 {
-				sql += fmt.Sprintf(",%d", col.Scale)
-			}
-			sql += ")"
-		}
-
-		if !col.IsNullable {
-			sql += " NOT NULL"
-		}
-		if col.IsUnique {
-			sql += " UNIQUE"
-		}
-		if col.DefaultValue != "" {
-			sql += " DEFAULT " + col.DefaultValue
-		}
-
-		if i < len(table.Columns)-1 {
-			sql += ",\n"
+	// Generate columns and foreign keys
+	defs := make([]string, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		defs = append(defs, o.generateColumnSQL(col))
+	}
+	for _, constraint := range table.Constraints {
+		if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+			defs = append(defs, o.generateConstraintSQL(constraint))
 		}
 	}
+	if len(defs) > 0 {
+		sql += "    " + strings.Join(defs, ",\n    ")
+	}
 
 	sql += "\n)"
 
@@ -911,6 +831,30 @@ func (o *Oracle) generateTableSQL(table sqlmapper.Table) string {
 	return sql
 }
 
+// generateColumnSQL generates SQL for a column definition
+func (o *Oracle) generateColumnSQL(col sqlmapper.Column) string {
+	sql := col.Name + " " + col.DataType
+	if col.Length > 0 {
+		sql += fmt.Sprintf("(%d", col.Length)
+		if col.Scale > 0 {
+			sql += fmt.Sprintf(",%d", col.Scale)
+		}
+		sql += ")"
+	}
+
+	if !col.IsNullable {
+		sql += " NOT NULL"
+	}
+	if col.IsUnique {
+		sql += " UNIQUE"
+	}
+	if col.DefaultValue != "" {
+		sql += " DEFAULT " + col.DefaultValue
+	}
+
+	return sql
+}
+
 // generateIndexSQL generates SQL for an index
 func (o *Oracle) generateIndexSQL(tableName string, index sqlmapper.Index) string {
 	var sql string
@@ -931,3 +875,47 @@ func (o *Oracle) generateIndexSQL(tableName string, index sqlmapper.Index) strin
 
 	return sql
 }
+
+// formatValue encodes a data value as an Oracle literal. Booleans become 1 and 0,
+// binary data is converted with HEXTORAW and dates are written as DATE and
+// TIMESTAMP literals.
+//
+// Parameters:
+//   - value: The data value to encode
+//
+// Returns:
+//   - string: The SQL literal
+func (o *Oracle) formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		return "HEXTORAW('" + strings.ToUpper(hex.EncodeToString(v)) + "')"
+	case time.Time:
+		text := sqlmapper.FormatTimeLiteral(v)
+		if len(text) == len("2006-01-02") {
+			return "DATE '" + text + "'"
+		}
+		text = v.Format("2006-01-02 15:04:05.999999999")
+		if v.Location() != time.UTC {
+			text += v.Format(" -07:00")
+		}
+		return "TIMESTAMP '" + text + "'"
+	}
+	return sqlmapper.FormatLiteral(value)
+}
+
+// generateInsertSQL creates the INSERT statements for the data of a table.
+// Oracle does not accept multi-row VALUES lists, so each row is a statement.
+//
+// Parameters:
+//   - table: The table whose data is generated
+//
+// Returns:
+//   - []string: The INSERT statements without terminators
+func (o *Oracle) generateInsertSQL(table sqlmapper.Table) []string {
+	return sqlmapper.InsertStatements(table, 1, o.formatValue)
+}
@@ -0,0 +1,269 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// GenerateMigration renders a list of schema changes, as produced by sqlmapper.Diff,
+// into an Oracle migration script. Changes are rendered in the given order, and data types
+// of changes diffed from schemas of another dialect are translated to Oracle.
+// PL/SQL blocks such as triggers and routines are terminated with a slash so
+// the script can be run with SQL*Plus.
+//
+// Parameters:
+//   - changes: The ordered schema changes to render
+//
+// Returns:
+//   - string: The generated Oracle migration statements
+//   - error: An error if a change carries an unexpected definition
+func (o *Oracle) GenerateMigration(changes []sqlmapper.Change) (string, error) {
+	changes = sqlmapper.ConvertChanges(changes, sqlmapper.Oracle)
+	var result strings.Builder
+
+	for _, change := range changes {
+		stmt, err := o.generateChangeSQL(change)
+		if err != nil {
+			return "", err
+		}
+		if stmt == "" {
+			continue
+		}
+		result.WriteString(stmt)
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// generateChangeSQL renders a single schema change
+func (o *Oracle) generateChangeSQL(change sqlmapper.Change) (string, error) {
+	switch change.Type {
+	case sqlmapper.CreateTable:
+		table, ok := change.New.(sqlmapper.Table)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		stmts := []string{o.generateTableSQL(table) + ";"}
+		for _, index := range table.Indexes {
+			stmts = append(stmts, o.generateIndexSQL(table.Name, index)+";")
+		}
+		return strings.Join(stmts, "\n"), nil
+
+	case sqlmapper.DropTable:
+		return fmt.Sprintf("DROP TABLE %s;", change.Name), nil
+
+	case sqlmapper.AddColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD (%s);", change.Table, o.generateColumnSQL(column)), nil
+
+	case sqlmapper.DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", change.Table, change.Name), nil
+
+	case sqlmapper.ModifyColumn:
+		oldColumn, _ := change.Old.(sqlmapper.Column)
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s MODIFY (%s);", change.Table, o.generateModifyColumnSQL(oldColumn, column)), nil
+
+	case sqlmapper.AddIndex:
+		index, ok := change.New.(sqlmapper.Index)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return o.generateIndexSQL(change.Table, index) + ";", nil
+
+	case sqlmapper.DropIndex:
+		if change.Name == "" {
+			return fmt.Sprintf("-- Unnamed index on %s must be dropped manually", change.Table), nil
+		}
+		return fmt.Sprintf("DROP INDEX %s;", change.Name), nil
+
+	case sqlmapper.AddConstraint:
+		constraint, ok := change.New.(sqlmapper.Constraint)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;", change.Table, o.generateConstraintSQL(constraint)), nil
+
+	case sqlmapper.DropConstraint:
+		if change.Name == "" {
+			constraint, _ := change.Old.(sqlmapper.Constraint)
+			if strings.EqualFold(constraint.Type, "PRIMARY KEY") {
+				return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", change.Table), nil
+			}
+			return fmt.Sprintf("-- Unnamed %s constraint on %s must be dropped manually", constraint.Type, change.Table), nil
+		}
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", change.Table, change.Name), nil
+
+	case sqlmapper.CreateView, sqlmapper.ModifyView:
+		view, ok := change.New.(sqlmapper.View)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		if view.IsMaterialized {
+			stmt := fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s;", view.Name, view.Definition)
+			if change.Type == sqlmapper.ModifyView {
+				stmt = fmt.Sprintf("DROP MATERIALIZED VIEW %s;\n%s", view.Name, stmt)
+			}
+			return stmt, nil
+		}
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s;", view.Name, view.Definition), nil
+
+	case sqlmapper.DropView:
+		if view, ok := change.Old.(sqlmapper.View); ok && view.IsMaterialized {
+			return fmt.Sprintf("DROP MATERIALIZED VIEW %s;", change.Name), nil
+		}
+		return fmt.Sprintf("DROP VIEW %s;", change.Name), nil
+
+	case sqlmapper.CreateSequence:
+		seq, ok := change.New.(sqlmapper.Sequence)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return strings.TrimSuffix(o.generateSequenceSQL(seq), "\n") + ";", nil
+
+	case sqlmapper.ModifySequence:
+		seq, ok := change.New.(sqlmapper.Sequence)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("DROP SEQUENCE %s;\n%s;", change.Name, strings.TrimSuffix(o.generateSequenceSQL(seq), "\n")), nil
+
+	case sqlmapper.DropSequence:
+		return fmt.Sprintf("DROP SEQUENCE %s;", change.Name), nil
+
+	case sqlmapper.CreateTrigger, sqlmapper.ModifyTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return o.generateTriggerSQL(trigger), nil
+
+	case sqlmapper.DropTrigger:
+		return fmt.Sprintf("DROP TRIGGER %s;", change.Name), nil
+
+	case sqlmapper.CreateFunction, sqlmapper.ModifyFunction:
+		fn, ok := change.New.(sqlmapper.Function)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return o.generateFunctionSQL(fn), nil
+
+	case sqlmapper.DropFunction:
+		fn, _ := change.Old.(sqlmapper.Function)
+		if fn.IsProc {
+			return fmt.Sprintf("DROP PROCEDURE %s;", change.Name), nil
+		}
+		return fmt.Sprintf("DROP FUNCTION %s;", change.Name), nil
+	}
+
+	return "", errors.New("unsupported change type: " + string(change.Type))
+}
+
+// generateModifyColumnSQL generates a MODIFY column clause. Oracle rejects a
+// MODIFY that restates the current nullability, so it is only included when it changes.
+func (o *Oracle) generateModifyColumnSQL(oldColumn, column sqlmapper.Column) string {
+	sql := column.Name + " " + column.DataType
+	if column.Length > 0 {
+		sql += fmt.Sprintf("(%d", column.Length)
+		if column.Scale > 0 {
+			sql += fmt.Sprintf(",%d", column.Scale)
+		}
+		sql += ")"
+	}
+
+	if oldColumn.DefaultValue != column.DefaultValue {
+		if column.DefaultValue == "" {
+			sql += " DEFAULT NULL"
+		} else {
+			sql += " DEFAULT " + column.DefaultValue
+		}
+	}
+	if oldColumn.IsNullable != column.IsNullable {
+		if column.IsNullable {
+			sql += " NULL"
+		} else {
+			sql += " NOT NULL"
+		}
+	}
+
+	return sql
+}
+
+// generateConstraintSQL generates SQL for a table constraint clause
+func (o *Oracle) generateConstraintSQL(constraint sqlmapper.Constraint) string {
+	sql := ""
+	if constraint.Name != "" {
+		sql = "CONSTRAINT " + constraint.Name + " "
+	}
+
+	if strings.ToUpper(constraint.Type) == "CHECK" {
+		return sql + "CHECK (" + constraint.CheckExpression + ")"
+	}
+
+	sql += constraint.Type + " (" + strings.Join(constraint.Columns, ", ") + ")"
+	if constraint.RefTable != "" {
+		sql += " REFERENCES " + constraint.RefTable + " (" + strings.Join(constraint.RefColumns, ", ") + ")"
+		if constraint.DeleteRule != "" {
+			sql += " ON DELETE " + constraint.DeleteRule
+		}
+	}
+	if constraint.Deferrable {
+		sql += " DEFERRABLE"
+		if constraint.Initially != "" {
+			sql += " INITIALLY " + constraint.Initially
+		}
+	}
+
+	return sql
+}
+
+// generateTriggerSQL generates SQL for a trigger
+func (o *Oracle) generateTriggerSQL(trigger sqlmapper.Trigger) string {
+	sql := fmt.Sprintf("CREATE OR REPLACE TRIGGER %s %s %s ON %s", trigger.Name, trigger.Timing, trigger.Event, trigger.Table)
+	if trigger.ForEachRow {
+		sql += " FOR EACH ROW"
+	}
+	if trigger.Condition != "" {
+		sql += " WHEN (" + trigger.Condition + ")"
+	}
+
+	body := strings.TrimSpace(trigger.Body)
+	if !strings.HasSuffix(body, ";") {
+		body += ";"
+	}
+	return sql + "\nBEGIN\n" + body + "\nEND;\n/"
+}
+
+// generateFunctionSQL generates SQL for a function or procedure
+func (o *Oracle) generateFunctionSQL(fn sqlmapper.Function) string {
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = strings.TrimSpace(fmt.Sprintf("%s %s %s", param.Name, param.Direction, param.DataType))
+		params[i] = strings.Join(strings.Fields(params[i]), " ")
+	}
+
+	signature := fn.Name
+	if len(params) > 0 {
+		signature += "(" + strings.Join(params, ", ") + ")"
+	}
+
+	body := strings.TrimSpace(fn.Body)
+	if !strings.HasSuffix(body, ";") {
+		body += ";"
+	}
+
+	if fn.IsProc {
+		return fmt.Sprintf("CREATE OR REPLACE PROCEDURE %s AS\nBEGIN\n%s\nEND;\n/", signature, body)
+	}
+	return fmt.Sprintf("CREATE OR REPLACE FUNCTION %s RETURN %s AS\nBEGIN\n%s\nEND;\n/", signature, fn.Returns, body)
+}
@@ -1,13 +1,14 @@
 package oracle
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
-	"sync"
 
 	"github.com/mstgnz/sqlmapper"
 	"github.com/mstgnz/sqlmapper/stream"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // OracleStreamParser implements the StreamParser interface for Oracle
@@ -24,260 +25,86 @@ func NewOracleStreamParser() *OracleStreamParser {
 
 // ParseStream implements the StreamParser interface
 func (p *OracleStreamParser) ParseStream(reader io.Reader, callback func(stream.SchemaObject) error) error {
-	streamReader := stream.NewStreamReader(reader, "/")
-
-	for {
-		statement, err := streamReader.ReadStatement()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading statement: %v", err)
-		}
-
-		statement = strings.TrimSpace(statement)
-		if statement == "" {
-			continue
-		}
-
-		// Parse CREATE TABLE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TABLE") {
-			table, err := p.parseTableStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TableObject,
-				Data: table,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE VIEW statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE VIEW") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE MATERIALIZED VIEW") {
-			view, err := p.parseViewStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ViewObject,
-				Data: view,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE FUNCTION statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE FUNCTION") {
-			function, err := p.parseFunctionStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.FunctionObject,
-				Data: function,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE PROCEDURE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE PROCEDURE") {
-			procedure, err := p.parseProcedureStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ProcedureObject,
-				Data: procedure,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE TRIGGER statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TRIGGER") {
-			trigger, err := p.parseTriggerStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TriggerObject,
-				Data: trigger,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE SEQUENCE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE SEQUENCE") {
-			sequence, err := p.parseSequenceStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.SequenceObject,
-				Data: sequence,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{})
+}
 
-		// Parse CREATE TYPE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TYPE") {
-			typ, err := p.parseTypeStatement(statement)
-			if err != nil {
-				return err
-			}
+// ParseStreamLenient implements the stream.LenientStreamParser interface
+func (p *OracleStreamParser) ParseStreamLenient(reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{Lenient: true})
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.TypeObject,
-				Data: typ,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
+// ParseStreamOptions implements the stream.OptionsStreamParser interface
+func (p *OracleStreamParser) ParseStreamOptions(reader io.Reader, callback func(stream.SchemaObject) error, opts stream.ParseOptions) error {
+	return stream.ParseStatements(reader, tokenizer.Oracle, p.parseStatement, callback, opts)
+}
 
-		// Parse CREATE INDEX statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE UNIQUE INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE BITMAP INDEX") {
-			index, err := p.parseIndexStatement(statement)
-			if err != nil {
-				return err
-			}
+// ParseStreamParallel implements parallel processing for Oracle stream parsing.
+// Objects are delivered in input order; each worker parses with its own parser.
+func (p *OracleStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
+	return stream.ParseStreamParallel(reader, tokenizer.Oracle, func() stream.ParseFunc {
+		return NewOracleStreamParser().parseStatement
+	}, callback, stream.ParallelOptions{Workers: workers})
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.IndexObject,
-				Data: index,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-	}
+// ParseStreamContext implements the StreamParser interface
+func (p *OracleStreamParser) ParseStreamContext(ctx context.Context, reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return stream.ParseContext(ctx, reader, callback, p.ParseStream)
+}
 
-	return nil
+// GenerateStreamContext implements the StreamParser interface
+func (p *OracleStreamParser) GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error {
+	return stream.GenerateContext(ctx, schema, writer, p.GenerateStream)
 }
 
-// ParseStreamParallel implements parallel processing for Oracle stream parsing
-func (p *OracleStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
-	streamReader := stream.NewStreamReader(reader, "/")
-	statements := make(chan string, workers)
-	results := make(chan stream.SchemaObject, workers)
-	errors := make(chan error, workers)
-	var wg sync.WaitGroup
-
-	// Start worker goroutines
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for statement := range statements {
-				obj, err := p.parseStatement(statement)
-				if err != nil {
-					errors <- err
-					return
-				}
-				if obj != nil {
-					results <- *obj
-				}
-			}
-		}()
+// GenerateObject implements the StreamParser interface
+func (p *OracleStreamParser) GenerateObject(obj stream.SchemaObject, writer io.Writer) error {
+	var stmt string
+	switch data := obj.Data.(type) {
+	case *sqlmapper.Index:
+		stmt = p.oracle.generateIndexSQL(obj.Table, *data)
+	case *sqlmapper.Constraint:
+		stmt = fmt.Sprintf("ALTER TABLE %s ADD %s", obj.Table, p.oracle.generateConstraintSQL(*data))
+	case *stream.Comment:
+		stmt = data.Statement(obj.Table)
+	case *stream.Unparsed:
+		stmt = data.SQL
+	default:
+		return p.GenerateStream(stream.ObjectSchema(obj), writer)
 	}
 
-	// Start a goroutine to close results channel after all workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Start a goroutine to read statements and send them to workers
-	go func() {
-		for {
-			statement, err := streamReader.ReadStatement()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				errors <- fmt.Errorf("error reading statement: %v", err)
-				break
-			}
+	_, err := writer.Write([]byte(stmt + ";\n\n"))
+	return err
+}
 
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
-				continue
-			}
-			statements <- statement
-		}
-		close(statements)
-	}()
+// Dialect implements the StreamParser interface
+func (p *OracleStreamParser) Dialect() sqlmapper.DatabaseType {
+	return sqlmapper.Oracle
+}
 
-	// Process results and handle errors
-	for obj := range results {
-		if err := callback(obj); err != nil {
-			return err
-		}
+// parseStatement parses a single SQL statement with the batch parser and
+// returns the objects it defines. Routines, types and indexes, which the batch
+// parser skips, are parsed by parseDefinition.
+func (p *OracleStreamParser) parseStatement(stmt tokenizer.Statement) ([]stream.SchemaObject, error) {
+	objects, err := stream.StatementObjects(stmt.Text, func(schema *sqlmapper.Schema) error {
+		return (&Oracle{schema: schema}).parseTokenized(stmt)
+	})
+	if err != nil || len(objects) > 0 {
+		return objects, err
 	}
 
-	// Check for any errors from workers
-	select {
-	case err := <-errors:
-		return err
-	default:
-		return nil
+	obj, err := p.parseDefinition(stmt.Text)
+	if err != nil || obj == nil {
+		return nil, err
 	}
+	return []stream.SchemaObject{*obj}, nil
 }
 
-// parseStatement parses a single SQL statement and returns a SchemaObject
-func (p *OracleStreamParser) parseStatement(statement string) (*stream.SchemaObject, error) {
+// parseDefinition parses the routine, type and index statements the batch
+// parser skips
+func (p *OracleStreamParser) parseDefinition(statement string) (*stream.SchemaObject, error) {
 	upperStatement := strings.ToUpper(statement)
 
 	switch {
-	case strings.HasPrefix(upperStatement, "CREATE TABLE"):
-		table, err := p.parseTableStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TableObject,
-			Data: table,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE VIEW"),
-		strings.HasPrefix(upperStatement, "CREATE MATERIALIZED VIEW"):
-		view, err := p.parseViewStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ViewObject,
-			Data: view,
-		}, nil
-
 	case strings.HasPrefix(upperStatement, "CREATE FUNCTION"):
 		function, err := p.parseFunctionStatement(statement)
 		if err != nil {
@@ -298,26 +125,6 @@ func (p *OracleStreamParser) parseStatement(statement string) (*stream.SchemaObj
 			Data: procedure,
 		}, nil
 
-	case strings.HasPrefix(upperStatement, "CREATE TRIGGER"):
-		trigger, err := p.parseTriggerStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TriggerObject,
-			Data: trigger,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE SEQUENCE"):
-		sequence, err := p.parseSequenceStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.SequenceObject,
-			Data: sequence,
-		}, nil
-
 	case strings.HasPrefix(upperStatement, "CREATE TYPE"):
 		typ, err := p.parseTypeStatement(statement)
 		if err != nil {
@@ -331,57 +138,26 @@ func (p *OracleStreamParser) parseStatement(statement string) (*stream.SchemaObj
 	case strings.HasPrefix(upperStatement, "CREATE INDEX"),
 		strings.HasPrefix(upperStatement, "CREATE UNIQUE INDEX"),
 		strings.HasPrefix(upperStatement, "CREATE BITMAP INDEX"):
-		index, err := p.parseIndexStatement(statement)
+		index, table, err := p.parseIndexStatement(statement)
 		if err != nil {
 			return nil, err
 		}
 		return &stream.SchemaObject{
-			Type: stream.IndexObject,
-			Data: index,
+			Type:  stream.IndexObject,
+			Table: table,
+			Data:  index,
 		}, nil
 	}
 
 	return nil, nil
 }
 
-// parseTableStatement parses a CREATE TABLE statement
-func (p *OracleStreamParser) parseTableStatement(statement string) (*sqlmapper.Table, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
-
-	if err := p.oracle.parseTables(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Tables) == 0 {
-		return nil, fmt.Errorf("no table found in statement")
-	}
-
-	return &tempSchema.Tables[0], nil
-}
-
-// parseViewStatement parses a CREATE VIEW statement
-func (p *OracleStreamParser) parseViewStatement(statement string) (*sqlmapper.View, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
-
-	if err := p.oracle.parseViews(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Views) == 0 {
-		return nil, fmt.Errorf("no view found in statement")
-	}
-
-	return &tempSchema.Views[0], nil
-}
-
 // parseFunctionStatement parses a CREATE FUNCTION statement
 func (p *OracleStreamParser) parseFunctionStatement(statement string) (*sqlmapper.Function, error) {
 	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
+	parser := &Oracle{schema: tempSchema}
 
-	if err := p.oracle.parseFunctions(statement); err != nil {
+	if err := parser.parseFunctions(statement); err != nil {
 		return nil, err
 	}
 
@@ -401,9 +177,9 @@ func (p *OracleStreamParser) parseFunctionStatement(statement string) (*sqlmappe
 // parseProcedureStatement parses a CREATE PROCEDURE statement
 func (p *OracleStreamParser) parseProcedureStatement(statement string) (*sqlmapper.Procedure, error) {
 	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
+	parser := &Oracle{schema: tempSchema}
 
-	if err := p.oracle.parseFunctions(statement); err != nil {
+	if err := parser.parseFunctions(statement); err != nil {
 		return nil, err
 	}
 
@@ -426,44 +202,12 @@ func (p *OracleStreamParser) parseProcedureStatement(statement string) (*sqlmapp
 	return nil, fmt.Errorf("no procedure found in statement")
 }
 
-// parseTriggerStatement parses a CREATE TRIGGER statement
-func (p *OracleStreamParser) parseTriggerStatement(statement string) (*sqlmapper.Trigger, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
-
-	if err := p.oracle.parseTriggers(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Triggers) == 0 {
-		return nil, fmt.Errorf("no trigger found in statement")
-	}
-
-	return &tempSchema.Triggers[0], nil
-}
-
-// parseSequenceStatement parses a CREATE SEQUENCE statement
-func (p *OracleStreamParser) parseSequenceStatement(statement string) (*sqlmapper.Sequence, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
-
-	if err := p.oracle.parseSequences(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Sequences) == 0 {
-		return nil, fmt.Errorf("no sequence found in statement")
-	}
-
-	return &tempSchema.Sequences[0], nil
-}
-
 // parseTypeStatement parses a CREATE TYPE statement
 func (p *OracleStreamParser) parseTypeStatement(statement string) (*sqlmapper.Type, error) {
 	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
+	parser := &Oracle{schema: tempSchema}
 
-	if err := p.oracle.parseTypes(statement); err != nil {
+	if err := parser.parseTypes(statement); err != nil {
 		return nil, err
 	}
 
@@ -474,20 +218,20 @@ func (p *OracleStreamParser) parseTypeStatement(statement string) (*sqlmapper.Ty
 	return &tempSchema.Types[0], nil
 }
 
-// parseIndexStatement parses a CREATE INDEX statement
-func (p *OracleStreamParser) parseIndexStatement(statement string) (*sqlmapper.Index, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.oracle.schema = tempSchema
+// parseIndexStatement parses a CREATE INDEX statement and returns the index and its table
+func (p *OracleStreamParser) parseIndexStatement(statement string) (*sqlmapper.Index, string, error) {
+	tempSchema := &sqlmapper.Schema{Tables: []sqlmapper.Table{{Name: stream.IndexTable(statement)}}}
+	parser := &Oracle{schema: tempSchema}
 
-	if err := p.oracle.parseIndexes(statement); err != nil {
-		return nil, err
+	if err := parser.parseIndexes(statement); err != nil {
+		return nil, "", err
 	}
 
 	if len(tempSchema.Tables) == 0 || len(tempSchema.Tables[0].Indexes) == 0 {
-		return nil, fmt.Errorf("no index found in statement")
+		return nil, "", fmt.Errorf("no index found in statement")
 	}
 
-	return &tempSchema.Tables[0].Indexes[0], nil
+	return &tempSchema.Tables[0].Indexes[0], tempSchema.Tables[0].Name, nil
 }
 
 // GenerateStream implements the StreamParser interface
@@ -496,6 +240,12 @@ func (p *OracleStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.
 		return fmt.Errorf("schema cannot be nil")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.Oracle)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	// Write sequences
 	for _, sequence := range schema.Sequences {
 		stmt := p.oracle.generateSequenceSQL(sequence)
@@ -514,6 +264,11 @@ func (p *OracleStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.
 
 	// Write tables
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
 		stmt := p.oracle.generateTableSQL(table)
 		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
 			return err
@@ -528,6 +283,23 @@ func (p *OracleStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.
 		}
 	}
 
+	// Write table data
+	for _, table := range schema.Tables {
+		for _, stmt := range p.oracle.generateInsertSQL(table) {
+			if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Write foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD %s", fk.Table, p.oracle.generateConstraintSQL(fk.Constraint))
+		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+			return err
+		}
+	}
+
 	// Write views
 	for _, view := range schema.Views {
 		stmt := fmt.Sprintf("CREATE VIEW %s AS %s", view.Name, view.Definition)
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/mysql"
+	"github.com/mstgnz/sqlmapper/stream"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -141,6 +143,22 @@ func TestOracle_Parse(t *testing.T) {
 				assert.True(t, postsTrigger.ForEachRow)
 			},
 		},
+		{
+			name: "CREATE TABLESPACE is not a table",
+			content: `CREATE TABLESPACE ts DATAFILE 'x.dbf' SIZE 10M;
+				CREATE TABLE reviews (id NUMBER(10));`,
+			wantErr: false,
+			validate: func(t *testing.T, schema *sqlmapper.Schema) {
+				assert.Len(t, schema.Tables, 1)
+				assert.Equal(t, "reviews", schema.Tables[0].Name)
+				assert.Empty(t, schema.Views)
+			},
+		},
+		{
+			name:    "CREATE TABLE AS SELECT",
+			content: "CREATE TABLE t AS SELECT COUNT(*) AS total FROM users;",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -159,6 +177,35 @@ func TestOracle_Parse(t *testing.T) {
 	}
 }
 
+func TestOracle_ParseLenient(t *testing.T) {
+	content := `CREATE TABLESPACE ts DATAFILE 'x.dbf' SIZE 10M;
+CREATE TABLE users (id NUMBER(10), name VARCHAR2(50));
+CREATE TABLE user_names AS SELECT name FROM users;`
+
+	schema, diagnostics, err := sqlmapper.ParseLenient(NewOracle(), content)
+	assert.NoError(t, err)
+	if assert.Len(t, schema.Tables, 1) {
+		assert.Equal(t, "users", schema.Tables[0].Name)
+		assert.Equal(t, 2, schema.Tables[0].Pos.Line)
+	}
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, sqlmapper.CodeUnparsedStatement, diagnostics[0].Code)
+		assert.Equal(t, 3, diagnostics[0].Pos.Line)
+		assert.Equal(t, "CREATE TABLE user_names AS SELECT name FROM users", diagnostics[0].Statement)
+	}
+
+	// The stream parser passes both statements through, as they define no table
+	var unparsed []int
+	err = NewOracleStreamParser().ParseStreamLenient(strings.NewReader(content), func(obj stream.SchemaObject) error {
+		if obj.Type == stream.UnparsedObject {
+			unparsed = append(unparsed, obj.Pos.Line)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, unparsed)
+}
+
 func TestOracle_Generate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -269,3 +316,146 @@ SELECT u.*, COUNT(p.id) as post_count FROM users u LEFT JOIN posts p ON u.id = p
 		})
 	}
 }
+
+func TestOracle_Generate_TypeMapping(t *testing.T) {
+	content := `CREATE TABLE notes (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		body LONGTEXT,
+		title VARCHAR(100) NOT NULL,
+		price DECIMAL(10,2),
+		active BOOLEAN
+	);`
+
+	schema, err := mysql.NewMySQL().Parse(content)
+	assert.NoError(t, err)
+	assert.Equal(t, sqlmapper.MySQL, schema.Dialect)
+
+	result, err := NewOracle().Generate(schema)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "id NUMBER(10) PRIMARY KEY")
+	assert.Contains(t, result, "body CLOB")
+	assert.Contains(t, result, "title VARCHAR2(100) NOT NULL")
+	assert.Contains(t, result, "price NUMBER(10,2)")
+	assert.Contains(t, result, "active NUMBER(1)")
+	assert.NotContains(t, result, "LONGTEXT")
+
+	// The source schema must not be modified
+	assert.Equal(t, "LONGTEXT", schema.Tables[0].Columns[1].DataType)
+
+	// User overrides take precedence over the built-in table
+	original := sqlmapper.TypeMapping(sqlmapper.MySQL, sqlmapper.Oracle)["longtext"]
+	sqlmapper.RegisterTypeMapping(sqlmapper.MySQL, sqlmapper.Oracle, map[string]string{"LONGTEXT": "NCLOB"})
+	t.Cleanup(func() {
+		sqlmapper.RegisterTypeMapping(sqlmapper.MySQL, sqlmapper.Oracle, map[string]string{"longtext": original})
+	})
+
+	result, err = NewOracle().Generate(schema)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "body NCLOB")
+}
+
+func TestOracle_GenerateMigration(t *testing.T) {
+	oldSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "employees",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "NUMBER", Length: 10, IsPrimaryKey: true},
+					{Name: "salary", DataType: "NUMBER", Length: 8, Scale: 2, IsNullable: true},
+				},
+			},
+		},
+		Sequences: []sqlmapper.Sequence{
+			{Name: "emp_seq", StartValue: 1, IncrementBy: 1},
+		},
+	}
+
+	newSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "employees",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "NUMBER", Length: 10, IsPrimaryKey: true},
+					{Name: "salary", DataType: "NUMBER", Length: 10, Scale: 2},
+					{Name: "hired_at", DataType: "DATE", IsNullable: true},
+				},
+			},
+		},
+		Triggers: []sqlmapper.Trigger{
+			{Name: "emp_bi", Table: "employees", Timing: "BEFORE", Event: "INSERT", ForEachRow: true, Body: ":NEW.id := emp_seq.NEXTVAL"},
+		},
+	}
+
+	o := &Oracle{}
+	got, err := o.GenerateMigration(sqlmapper.Diff(oldSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+DROP SEQUENCE emp_seq;
+ALTER TABLE employees ADD (hired_at DATE);
+ALTER TABLE employees MODIFY (salary NUMBER(10,2) NOT NULL);
+CREATE OR REPLACE TRIGGER emp_bi BEFORE INSERT ON employees FOR EACH ROW
+BEGIN
+:NEW.id := emp_seq.NEXTVAL;
+END;
+/`), strings.TrimSpace(got))
+}
+
+func TestOracle_Generate_DependencyOrder(t *testing.T) {
+	schema := &sqlmapper.Schema{
+		Views: []sqlmapper.View{
+			{Name: "top_customers", Definition: "SELECT * FROM customer_totals WHERE total > 1000"},
+			{Name: "customer_totals", Definition: "SELECT customer_id, SUM(amount) total FROM orders GROUP BY customer_id"},
+		},
+		Triggers: []sqlmapper.Trigger{
+			{Name: "orders_bi", Table: "orders", Timing: "BEFORE", Event: "INSERT", ForEachRow: true, Body: "BEGIN :NEW.id := order_seq.NEXTVAL; END;"},
+		},
+		Tables: []sqlmapper.Table{
+			{
+				Name: "orders",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "NUMBER", IsPrimaryKey: true},
+					{Name: "customer_id", DataType: "NUMBER"},
+					{Name: "amount", DataType: "NUMBER", IsNullable: true},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "fk_order_customer", Type: "FOREIGN KEY", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}},
+				},
+			},
+			{
+				Name: "customers",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "NUMBER", IsPrimaryKey: true},
+				},
+			},
+		},
+		Sequences: []sqlmapper.Sequence{
+			{Name: "order_seq", StartValue: 1, IncrementBy: 1},
+		},
+	}
+
+	o := NewOracle()
+	got, err := o.Generate(schema)
+	assert.NoError(t, err)
+
+	positions := make([]int, 0)
+	for _, stmt := range []string{
+		"CREATE SEQUENCE order_seq",
+		"CREATE TABLE customers",
+		"CREATE TABLE orders",
+		"CREATE OR REPLACE VIEW customer_totals",
+		"CREATE OR REPLACE VIEW top_customers",
+		"CREATE OR REPLACE TRIGGER orders_bi",
+	} {
+		pos := strings.Index(got, stmt)
+		assert.GreaterOrEqual(t, pos, 0, stmt)
+		positions = append(positions, pos)
+	}
+	assert.IsIncreasing(t, positions)
+	assert.NotContains(t, got, "ALTER TABLE")
+
+	var buf strings.Builder
+	err = NewOracleStreamParser().GenerateStream(schema, &buf)
+	assert.NoError(t, err)
+	assert.Less(t, strings.Index(buf.String(), "CREATE TABLE customers"), strings.Index(buf.String(), "CREATE TABLE orders"))
+	assert.Less(t, strings.Index(buf.String(), "CREATE VIEW customer_totals"), strings.Index(buf.String(), "CREATE VIEW top_customers"))
+}
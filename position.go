@@ -0,0 +1,318 @@
+package sqlmapper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Position is the source location of the statement an object was parsed from.
+// Line and Column are 1-based; Offset is the 0-based byte offset in the input.
+type Position struct {
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
+	Line   int    `json:"line" yaml:"line"`
+	Column int    `json:"column" yaml:"column"`
+	Offset int    `json:"offset" yaml:"offset"`
+}
+
+// IsValid reports whether the position holds a line number
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats the position as "file:line:col", or "line:col" without a file name
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// maxSnippetLength bounds the statement excerpt kept in a ParseError
+const maxSnippetLength = 80
+
+// ParseError is returned by parsers when a statement cannot be parsed.
+// It records where the statement starts and an excerpt of it.
+type ParseError struct {
+	Pos     Position
+	Snippet string // First line of the offending statement, truncated
+	Err     error
+}
+
+// NewParseError creates a ParseError for the statement at pos
+//
+// Parameters:
+//   - pos: The position of the offending statement
+//   - statement: The statement text; an excerpt of it is kept as the snippet
+//   - err: The underlying error
+//
+// Returns:
+//   - *ParseError: The parse error
+func NewParseError(pos Position, statement string, err error) *ParseError {
+	return &ParseError{
+		Pos:     pos,
+		Snippet: snippet(statement),
+		Err:     err,
+	}
+}
+
+// Error formats the error as "file:line:col: message"
+func (e *ParseError) Error() string {
+	msg := e.Err.Error()
+	if e.Pos.IsValid() {
+		msg = e.Pos.String() + ": " + msg
+	}
+	if e.Snippet != "" {
+		msg += fmt.Sprintf(" (near %q)", e.Snippet)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// snippet returns the first line of a statement, shortened to maxSnippetLength runes
+func snippet(statement string) string {
+	statement = strings.TrimSpace(statement)
+	if i := strings.IndexAny(statement, "\r\n"); i >= 0 {
+		statement = strings.TrimSpace(statement[:i]) + " ..."
+	}
+	runes := []rune(statement)
+	if len(runes) > maxSnippetLength {
+		statement = string(runes[:maxSnippetLength]) + "..."
+	}
+	return statement
+}
+
+// ParseFile reads a file and parses it with ParseSource
+//
+// Parameters:
+//   - parser: The parser of the file's dialect
+//   - path: The path of the SQL file
+//
+// Returns:
+//   - *Schema: The parsed schema
+//   - error: An error if the file cannot be read or parsed
+func ParseFile(parser Parser, path string) (*Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSource(parser, path, string(content))
+}
+
+// ParseSource parses content read from the named source, setting the name as
+// the file of the parsed object positions and of a returned ParseError
+//
+// Parameters:
+//   - parser: The parser of the content's dialect
+//   - name: The file name reported in positions
+//   - content: The SQL content to parse
+//
+// Returns:
+//   - *Schema: The parsed schema
+//   - error: An error if parsing fails
+func ParseSource(parser Parser, name, content string) (*Schema, error) {
+	schema, err := parser.Parse(content)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.Pos.File = name
+		}
+		return nil, err
+	}
+
+	SetPositionFile(schema, name)
+	return schema, nil
+}
+
+// SetPositionFile sets the file name of every object position in the schema
+func SetPositionFile(schema *Schema, file string) {
+	if schema == nil {
+		return
+	}
+	walkPositions(schema, func(pos **Position) {
+		if *pos != nil {
+			(*pos).File = file
+		}
+	})
+}
+
+// PositionMark records how many objects a schema holds, so that the objects a
+// parser adds for the next statement can be given the statement's position
+type PositionMark struct {
+	views, functions, procedures, triggers, sequences int
+	types, userTypes, extensions, permissions         int
+	indexes                                           []int // Index counts of the existing tables
+}
+
+// MarkPositions records the current object counts of the schema
+func MarkPositions(schema *Schema) PositionMark {
+	mark := PositionMark{
+		views:       len(schema.Views),
+		functions:   len(schema.Functions),
+		procedures:  len(schema.Procedures),
+		triggers:    len(schema.Triggers),
+		sequences:   len(schema.Sequences),
+		types:       len(schema.Types),
+		userTypes:   len(schema.UserDefinedTypes),
+		extensions:  len(schema.Extensions),
+		permissions: len(schema.Permissions),
+		indexes:     make([]int, len(schema.Tables)),
+	}
+	for i, table := range schema.Tables {
+		mark.indexes[i] = len(table.Indexes)
+	}
+	return mark
+}
+
+// Apply sets pos on the objects added to the schema since the mark was taken
+func (m PositionMark) Apply(schema *Schema, pos Position) {
+	set := func(p **Position) {
+		if *p == nil {
+			value := pos
+			*p = &value
+		}
+	}
+
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		first := 0
+		if i < len(m.indexes) {
+			first = m.indexes[i]
+		} else {
+			set(&table.Pos)
+		}
+		for j := first; j < len(table.Indexes); j++ {
+			set(&table.Indexes[j].Pos)
+		}
+	}
+	for i := m.views; i < len(schema.Views); i++ {
+		set(&schema.Views[i].Pos)
+	}
+	for i := m.functions; i < len(schema.Functions); i++ {
+		set(&schema.Functions[i].Pos)
+	}
+	for i := m.procedures; i < len(schema.Procedures); i++ {
+		set(&schema.Procedures[i].Pos)
+	}
+	for i := m.triggers; i < len(schema.Triggers); i++ {
+		set(&schema.Triggers[i].Pos)
+	}
+	for i := m.sequences; i < len(schema.Sequences); i++ {
+		set(&schema.Sequences[i].Pos)
+	}
+	for i := m.types; i < len(schema.Types); i++ {
+		set(&schema.Types[i].Pos)
+	}
+	for i := m.userTypes; i < len(schema.UserDefinedTypes); i++ {
+		set(&schema.UserDefinedTypes[i].Pos)
+	}
+	for i := m.extensions; i < len(schema.Extensions); i++ {
+		set(&schema.Extensions[i].Pos)
+	}
+	for i := m.permissions; i < len(schema.Permissions); i++ {
+		set(&schema.Permissions[i].Pos)
+	}
+}
+
+// Rollback removes the objects added to the schema since the mark was taken.
+// Lenient parsers use it to drop what a statement that failed had added.
+func (m PositionMark) Rollback(schema *Schema) {
+	schema.Tables = truncate(schema.Tables, len(m.indexes))
+	for i := range schema.Tables {
+		schema.Tables[i].Indexes = truncate(schema.Tables[i].Indexes, m.indexes[i])
+	}
+	schema.Views = truncate(schema.Views, m.views)
+	schema.Functions = truncate(schema.Functions, m.functions)
+	schema.Procedures = truncate(schema.Procedures, m.procedures)
+	schema.Triggers = truncate(schema.Triggers, m.triggers)
+	schema.Sequences = truncate(schema.Sequences, m.sequences)
+	schema.Types = truncate(schema.Types, m.types)
+	schema.UserDefinedTypes = truncate(schema.UserDefinedTypes, m.userTypes)
+	schema.Extensions = truncate(schema.Extensions, m.extensions)
+	schema.Permissions = truncate(schema.Permissions, m.permissions)
+}
+
+// truncate shortens a slice to at most n elements; no elements leave it nil
+func truncate[T any](s []T, n int) []T {
+	if n == 0 {
+		return nil
+	}
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// UnparsedStatement returns the diagnostic of a statement that a lenient parse
+// skipped because of err
+func UnparsedStatement(pos Position, statement string, err error) Diagnostic {
+	return Diagnostic{
+		Severity:  SeverityError,
+		Code:      CodeUnparsedStatement,
+		Message:   err.Error(),
+		Pos:       &pos,
+		Statement: statement,
+	}
+}
+
+// ParseLenient parses content with a parser that skips the statements it fails
+// to parse. Parsers without a lenient mode parse content with Parse, so the
+// first failure is returned as an error.
+//
+// Parameters:
+//   - parser: The parser of the content's dialect
+//   - content: The SQL content to parse
+//
+// Returns:
+//   - *Schema: The objects of the statements that could be parsed
+//   - []Diagnostic: The statements that were skipped, in input order
+//   - error: An error if the content is empty or cannot be parsed at all
+func ParseLenient(parser Parser, content string) (*Schema, []Diagnostic, error) {
+	if lenient, ok := parser.(LenientParser); ok {
+		return lenient.ParseLenient(content)
+	}
+	schema, err := parser.Parse(content)
+	return schema, nil, err
+}
+
+// walkPositions calls fn with the position field of every positioned object
+func walkPositions(schema *Schema, fn func(**Position)) {
+	for i := range schema.Tables {
+		fn(&schema.Tables[i].Pos)
+		for j := range schema.Tables[i].Indexes {
+			fn(&schema.Tables[i].Indexes[j].Pos)
+		}
+	}
+	for i := range schema.Views {
+		fn(&schema.Views[i].Pos)
+	}
+	for i := range schema.Functions {
+		fn(&schema.Functions[i].Pos)
+	}
+	for i := range schema.Procedures {
+		fn(&schema.Procedures[i].Pos)
+	}
+	for i := range schema.Triggers {
+		fn(&schema.Triggers[i].Pos)
+	}
+	for i := range schema.Sequences {
+		fn(&schema.Sequences[i].Pos)
+	}
+	for i := range schema.Types {
+		fn(&schema.Types[i].Pos)
+	}
+	for i := range schema.UserDefinedTypes {
+		fn(&schema.UserDefinedTypes[i].Pos)
+	}
+	for i := range schema.Extensions {
+		fn(&schema.Extensions[i].Pos)
+	}
+	for i := range schema.Permissions {
+		fn(&schema.Permissions[i].Pos)
+	}
+}
@@ -0,0 +1,124 @@
+package sqlmapper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lineParser parses one table per line and fails on lines starting with "!",
+// recording positions the way the dialect parsers do
+type lineParser struct{}
+
+func (lineParser) Parse(content string) (*Schema, error) {
+	schema := &Schema{}
+	offset := 0
+	for i, line := range strings.Split(content, "\n") {
+		pos := Position{Line: i + 1, Column: 1, Offset: offset}
+		offset += len(line) + 1
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "!") {
+			return nil, NewParseError(pos, line, errors.New("invalid table"))
+		}
+
+		mark := MarkPositions(schema)
+		schema.Tables = append(schema.Tables, Table{Name: line})
+		mark.Apply(schema, pos)
+	}
+	return schema, nil
+}
+
+func (lineParser) Generate(schema *Schema) (string, error) {
+	return "", nil
+}
+
+func TestPosition_String(t *testing.T) {
+	assert.Equal(t, "3:7", Position{Line: 3, Column: 7}.String())
+	assert.Equal(t, "dump.sql:3:7", Position{File: "dump.sql", Line: 3, Column: 7}.String())
+	assert.False(t, Position{}.IsValid())
+}
+
+func TestParseError(t *testing.T) {
+	cause := errors.New("error parsing CREATE TABLE: invalid CREATE TABLE statement")
+	err := NewParseError(Position{Line: 12, Column: 3, Offset: 410}, "CREATE TABLE users\n  id INT\n)", cause)
+
+	assert.Equal(t, `12:3: error parsing CREATE TABLE: invalid CREATE TABLE statement (near "CREATE TABLE users ...")`, err.Error())
+	assert.True(t, errors.Is(err, cause))
+
+	var wrapped error = err
+	var parseErr *ParseError
+	assert.True(t, errors.As(wrapped, &parseErr))
+	assert.Equal(t, 410, parseErr.Pos.Offset)
+
+	long := NewParseError(Position{}, strings.Repeat("x", 100), cause)
+	assert.Equal(t, strings.Repeat("x", maxSnippetLength)+"...", long.Snippet)
+}
+
+func TestPositionMark(t *testing.T) {
+	schema := &Schema{Tables: []Table{{Name: "users", Indexes: []Index{{Name: "idx_a"}}}}}
+	mark := MarkPositions(schema)
+
+	schema.Tables[0].Indexes = append(schema.Tables[0].Indexes, Index{Name: "idx_b"})
+	schema.Tables = append(schema.Tables, Table{Name: "orders"})
+	schema.Views = append(schema.Views, View{Name: "active_users"})
+	mark.Apply(schema, Position{Line: 4, Column: 1, Offset: 30})
+
+	assert.Nil(t, schema.Tables[0].Pos)
+	assert.Nil(t, schema.Tables[0].Indexes[0].Pos)
+	assert.Equal(t, &Position{Line: 4, Column: 1, Offset: 30}, schema.Tables[0].Indexes[1].Pos)
+	assert.Equal(t, &Position{Line: 4, Column: 1, Offset: 30}, schema.Tables[1].Pos)
+	assert.Equal(t, &Position{Line: 4, Column: 1, Offset: 30}, schema.Views[0].Pos)
+}
+
+func TestPositionMark_Rollback(t *testing.T) {
+	schema := &Schema{Tables: []Table{{Name: "users", Indexes: []Index{{Name: "idx_a"}}}}}
+	mark := MarkPositions(schema)
+
+	schema.Tables[0].Indexes = append(schema.Tables[0].Indexes, Index{Name: "idx_b"})
+	schema.Tables = append(schema.Tables, Table{Name: "orders"})
+	schema.Sequences = append(schema.Sequences, Sequence{Name: "seq"})
+	mark.Rollback(schema)
+
+	assert.Equal(t, &Schema{Tables: []Table{{Name: "users", Indexes: []Index{{Name: "idx_a"}}}}}, schema)
+}
+
+func TestParseLenient(t *testing.T) {
+	// Parsers without a lenient mode stop at the first failure
+	_, diagnostics, err := ParseLenient(lineParser{}, "users\n!broken\norders")
+	assert.Error(t, err)
+	assert.Nil(t, diagnostics)
+
+	diagnostic := UnparsedStatement(Position{Line: 2, Column: 1}, "!broken", errors.New("invalid table"))
+	assert.True(t, HasErrors([]Diagnostic{diagnostic}))
+	assert.Equal(t, "2:1: error [UNPARSED_STATEMENT] invalid table", diagnostic.String())
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "tables.sql")
+	assert.NoError(t, os.WriteFile(path, []byte("users\n\norders\n"), 0644))
+
+	schema, err := ParseFile(lineParser{}, path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Position{File: path, Line: 1, Column: 1, Offset: 0}, schema.Tables[0].Pos)
+	assert.Equal(t, &Position{File: path, Line: 3, Column: 1, Offset: 7}, schema.Tables[1].Pos)
+
+	broken := filepath.Join(dir, "broken.sql")
+	assert.NoError(t, os.WriteFile(broken, []byte("users\n!orders\n"), 0644))
+
+	_, err = ParseFile(lineParser{}, broken)
+	var parseErr *ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, broken+":2:1", parseErr.Pos.String())
+	assert.Equal(t, broken+`:2:1: invalid table (near "!orders")`, err.Error())
+
+	_, err = ParseFile(lineParser{}, filepath.Join(dir, "missing.sql"))
+	assert.Error(t, err)
+}
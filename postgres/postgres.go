@@ -3,12 +3,14 @@
 package postgres
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // PostgreSQL represents a PostgreSQL parser implementation that handles parsing and generating
@@ -26,6 +28,15 @@ func NewPostgreSQL() sqlmapper.Database {
 	}
 }
 
+func init() {
+	sqlmapper.RegisterDialect(sqlmapper.PostgreSQL, sqlmapper.Dialect{
+		NewParser:       func() sqlmapper.Parser { return NewPostgreSQL() },
+		NewStreamParser: func() interface{} { return NewPostgreSQLStreamParser() },
+		Aliases:         []string{"postgres", "pg"},
+		IncludeFormat:   `\i %s`,
+	})
+}
+
 // Parse takes a PostgreSQL SQL dump content and parses it into a common schema structure.
 // It processes various PostgreSQL objects including:
 // - Schemas and databases
@@ -50,51 +61,47 @@ func (p *PostgreSQL) Parse(content string) (*sqlmapper.Schema, error) {
 		return nil, errors.New("empty content")
 	}
 
-	// Normalize content
-	content = p.normalizeContent(content)
-
-	// Parse schema objects
-	if err := p.parseSchemas(content); err != nil {
-		return nil, fmt.Errorf("error parsing schemas: %v", err)
+	if _, err := tokenizer.ParseStatements(content, tokenizer.PostgreSQL, p.schema, p.parseTokenized, false); err != nil {
+		return nil, err
 	}
 
-	if err := p.parseTypes(content); err != nil {
-		return nil, fmt.Errorf("error parsing types: %v", err)
-	}
+	p.schema.Dialect = sqlmapper.PostgreSQL
 
-	if err := p.parseExtensions(content); err != nil {
-		return nil, fmt.Errorf("error parsing extensions: %v", err)
-	}
-
-	if err := p.parseSequences(content); err != nil {
-		return nil, fmt.Errorf("error parsing sequences: %v", err)
-	}
-
-	if err := p.parseTables(content); err != nil {
-		return nil, fmt.Errorf("error parsing tables: %v", err)
-	}
+	return p.schema, nil
+}
 
-	if err := p.parseIndexes(content); err != nil {
-		return nil, fmt.Errorf("error parsing indexes: %v", err)
+// ParseLenient parses a PostgreSQL SQL dump like Parse, but skips the statements
+// that fail to parse. Each skipped statement is reported as a diagnostic with
+// its position and the reason it failed.
+//
+// Parameters:
+//   - content: The PostgreSQL SQL dump content to parse
+//
+// Returns:
+//   - *sqlmapper.Schema: The objects of the statements that could be parsed
+//   - []sqlmapper.Diagnostic: The skipped statements
+//   - error: An error if the content is empty
+func (p *PostgreSQL) ParseLenient(content string) (*sqlmapper.Schema, []sqlmapper.Diagnostic, error) {
+	if content == "" {
+		return nil, nil, errors.New("empty content")
 	}
 
-	if err := p.parseViews(content); err != nil {
-		return nil, fmt.Errorf("error parsing views: %v", err)
+	diagnostics, err := tokenizer.ParseStatements(content, tokenizer.PostgreSQL, p.schema, p.parseTokenized, true)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := p.parseFunctions(content); err != nil {
-		return nil, fmt.Errorf("error parsing functions: %v", err)
-	}
+	p.schema.Dialect = sqlmapper.PostgreSQL
 
-	if err := p.parseTriggers(content); err != nil {
-		return nil, fmt.Errorf("error parsing triggers: %v", err)
-	}
+	return p.schema, diagnostics, nil
+}
 
-	if err := p.parsePermissions(content); err != nil {
-		return nil, fmt.Errorf("error parsing permissions: %v", err)
+// parseTokenized parses a single statement into the schema
+func (p *PostgreSQL) parseTokenized(stmt tokenizer.Statement) error {
+	if tokenizer.IsInsert(stmt) {
+		return p.parseInsert(stmt)
 	}
-
-	return p.schema, nil
+	return p.parseStatement(p.normalizeStatement(stmt))
 }
 
 // Generate creates a PostgreSQL SQL dump from a schema structure.
@@ -117,9 +124,32 @@ func (p *PostgreSQL) Generate(schema *sqlmapper.Schema) (string, error) {
 		return "", errors.New("empty schema")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.PostgreSQL)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	var result strings.Builder
 
+	// Sequences come first so column defaults can use them
+	for _, seq := range schema.Sequences {
+		result.WriteString("CREATE SEQUENCE " + seq.Name + p.generateSequenceOptions(seq) + ";\n")
+	}
+
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		var foreignKeys []string
+		for _, constraint := range table.Constraints {
+			if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+				foreignKeys = append(foreignKeys, p.generateConstraintSQL(constraint))
+			}
+		}
+
 		result.WriteString("CREATE TABLE ")
 		result.WriteString(table.Name)
 		result.WriteString(" (\n")
@@ -150,7 +180,16 @@ func (p *PostgreSQL) Generate(schema *sqlmapper.Schema) (string, error) {
 				}
 			}
 
-			if i < len(table.Columns)-1 {
+			if i < len(table.Columns)-1 || len(foreignKeys) > 0 {
+				result.WriteString(",")
+			}
+			result.WriteString("\n")
+		}
+
+		for i, fk := range foreignKeys {
+			result.WriteString("    ")
+			result.WriteString(fk)
+			if i < len(foreignKeys)-1 {
 				result.WriteString(",")
 			}
 			result.WriteString("\n")
@@ -174,27 +213,107 @@ func (p *PostgreSQL) Generate(schema *sqlmapper.Schema) (string, error) {
 		}
 	}
 
+	// Add table data
+	for _, table := range schema.Tables {
+		for _, stmt := range p.generateInsertSQL(table) {
+			result.WriteString(stmt + ";\n")
+		}
+	}
+
+	// Add foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		result.WriteString(fmt.Sprintf("ALTER TABLE %s ADD %s;\n", fk.Table, p.generateConstraintSQL(fk.Constraint)))
+	}
+
 	return result.String(), nil
 }
 
-// normalizeContent preprocesses the SQL content by removing comments and normalizing whitespace.
-// This helps ensure consistent parsing of the SQL statements.
+// normalizeStatement removes comments from a statement, collapses its whitespace
+// and restores the terminating semicolon expected by the statement patterns.
+//
+// Parameters:
+//   - stmt: The statement read by the tokenizer
+//
+// Returns:
+//   - string: The normalized statement
+func (p *PostgreSQL) normalizeStatement(stmt tokenizer.Statement) string {
+	return strings.Join(strings.Fields(stmt.Text), " ") + ";"
+}
+
+// parseInsert adds the rows of an INSERT ... VALUES statement to the data of its table.
+//
+// Parameters:
+//   - stmt: The INSERT statement read by the tokenizer
+//
+// Returns:
+//   - error: An error if the statement cannot be decoded
+func (p *PostgreSQL) parseInsert(stmt tokenizer.Statement) error {
+	insert, err := tokenizer.ParseInsert(stmt, tokenizer.PostgreSQL)
+	if err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	if insert == nil {
+		return nil
+	}
+	if err := sqlmapper.AppendRows(p.schema, insert.Schema, insert.Table, insert.Columns, insert.Rows); err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	return nil
+}
+
+// parseStatement extracts the schema objects defined by a single normalized statement.
 //
 // Parameters:
-//   - content: The SQL content to normalize
+//   - content: The normalized statement
 //
 // Returns:
-//   - string: The normalized SQL content
-func (p *PostgreSQL) normalizeContent(content string) string {
-	// Remove comments
-	re := regexp.MustCompile(`--.*$`)
-	content = re.ReplaceAllString(content, "")
+//   - error: An error if parsing fails
+func (p *PostgreSQL) parseStatement(content string) error {
+	if err := p.parseSchemas(content); err != nil {
+		return fmt.Errorf("error parsing schemas: %v", err)
+	}
+
+	if err := p.parseTypes(content); err != nil {
+		return fmt.Errorf("error parsing types: %v", err)
+	}
+
+	if err := p.parseExtensions(content); err != nil {
+		return fmt.Errorf("error parsing extensions: %v", err)
+	}
+
+	if err := p.parseSequences(content); err != nil {
+		return fmt.Errorf("error parsing sequences: %v", err)
+	}
+
+	if err := p.parseTables(content); err != nil {
+		return fmt.Errorf("error parsing tables: %v", err)
+	}
 
-	// Normalize whitespace
-	content = strings.TrimSpace(content)
-	content = regexp.MustCompile(`\s+`).ReplaceAllString(content, " ")
+	if err := p.parseIndexes(content); err != nil {
+		return fmt.Errorf("error parsing indexes: %v", err)
+	}
+
+	if err := p.parseAlterTables(content); err != nil {
+		return fmt.Errorf("error parsing ALTER TABLE: %v", err)
+	}
+
+	if err := p.parseViews(content); err != nil {
+		return fmt.Errorf("error parsing views: %v", err)
+	}
+
+	if err := p.parseFunctions(content); err != nil {
+		return fmt.Errorf("error parsing functions: %v", err)
+	}
+
+	if err := p.parseTriggers(content); err != nil {
+		return fmt.Errorf("error parsing triggers: %v", err)
+	}
+
+	if err := p.parsePermissions(content); err != nil {
+		return fmt.Errorf("error parsing permissions: %v", err)
+	}
 
-	return content
+	return nil
 }
 
 // parseSchemas extracts database and schema definitions from the SQL content.
@@ -737,6 +856,53 @@ func (p *PostgreSQL) parseIndexes(content string) error {
 	return nil
 }
 
+// parseAlterTables adds the constraints of ALTER TABLE ... ADD statements to
+// their tables. Other ALTER TABLE actions, and tables that are not defined, are
+// ignored as in parseIndexes.
+//
+// Parameters:
+//   - content: The SQL content to parse
+//
+// Returns:
+//   - error: An error if parsing fails
+func (p *PostgreSQL) parseAlterTables(content string) error {
+	// Quoted names are unquoted, as the constraint patterns only match plain names
+	content = strings.ReplaceAll(content, `"`, "")
+	re := regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:ONLY\s+)?(?:IF\s+EXISTS\s+)?([.\w]+)\s+(.*?);?$`)
+	match := re.FindStringSubmatch(content)
+	if match == nil {
+		return nil
+	}
+	tableName := match[1]
+
+	// Every action after the first ADD starts with ADD; other actions are left
+	// at the end of the one before them, after the definition
+	constraintRe := regexp.MustCompile(`(?i)^(?:CONSTRAINT|PRIMARY\s+KEY|FOREIGN\s+KEY|UNIQUE|CHECK)\b`)
+	var constraints []sqlmapper.Constraint
+	for _, action := range regexp.MustCompile(`(?i)(?:^|,)\s*ADD\s+`).Split(match[2], -1)[1:] {
+		def := strings.TrimSpace(action)
+		if !constraintRe.MatchString(def) {
+			continue
+		}
+		constraint, err := p.parseConstraint(def)
+		if err != nil {
+			return err
+		}
+		constraints = append(constraints, constraint)
+	}
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	for i, table := range p.schema.Tables {
+		if table.Name == tableName || fmt.Sprintf("%s.%s", table.Schema, table.Name) == tableName {
+			p.schema.Tables[i].Constraints = append(p.schema.Tables[i].Constraints, constraints...)
+			break
+		}
+	}
+	return nil
+}
+
 // parseViews processes view definitions from the SQL content.
 // It handles both regular and materialized views with their definitions.
 //
@@ -1058,37 +1224,19 @@ func (p *PostgreSQL) generateTypeSQL(typ sqlmapper.Type) string {
 func (p *PostgreSQL) generateTableSQL(table sqlmapper.Table) string {
 	sql := "CREATE TABLE " + table.Name + " (\n"
 
-	// Generate columns
-	for i, col := range table.Columns {
-		sql += "    " + col.Name + " "
-
-		if col.IsPrimaryKey && strings.ToUpper(col.DataType) == "SERIAL" {
-			sql += "SERIAL PRIMARY KEY"
-		} else {
-			sql += col.DataType
-			if col.Length > 0 {
-				sql += fmt.Sprintf("(%d", col.Length)
-				if col.Scale > 0 {
-					sql += fmt.Sprintf(",%d", col.Scale)
-				}
-				sql += ")"
-			}
-
-			if !col.IsNullable {
-				sql += " NOT NULL"
-			}
-			if col.IsUnique {
-				sql += " UNIQUE"
-			}
-			if col.DefaultValue != "" {
-				sql += " DEFAULT " + col.DefaultValue
-			}
-		}
-
-		if i < len(table.Columns)-1 {
-			sql += ",\n"
+	// Generate columns and foreign keys
+	defs := make([]string, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		defs = append(defs, p.generateColumnSQL(col))
+	}
+	for _, constraint := range table.Constraints {
+		if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+			defs = append(defs, p.generateConstraintSQL(constraint))
 		}
 	}
+	if len(defs) > 0 {
+		sql += "    " + strings.Join(defs, ",\n    ")
+	}
 
 	sql += "\n)"
 
@@ -1100,6 +1248,36 @@ func (p *PostgreSQL) generateTableSQL(table sqlmapper.Table) string {
 	return sql
 }
 
+// generateColumnSQL generates SQL for a column definition
+func (p *PostgreSQL) generateColumnSQL(col sqlmapper.Column) string {
+	sql := col.Name + " "
+
+	if col.IsPrimaryKey && strings.ToUpper(col.DataType) == "SERIAL" {
+		return sql + "SERIAL PRIMARY KEY"
+	}
+
+	sql += col.DataType
+	if col.Length > 0 {
+		sql += fmt.Sprintf("(%d", col.Length)
+		if col.Scale > 0 {
+			sql += fmt.Sprintf(",%d", col.Scale)
+		}
+		sql += ")"
+	}
+
+	if !col.IsNullable {
+		sql += " NOT NULL"
+	}
+	if col.IsUnique {
+		sql += " UNIQUE"
+	}
+	if col.DefaultValue != "" {
+		sql += " DEFAULT " + col.DefaultValue
+	}
+
+	return sql
+}
+
 // generateIndexSQL generates SQL for an index
 func (p *PostgreSQL) generateIndexSQL(tableName string, index sqlmapper.Index) string {
 	var sql string
@@ -1122,3 +1300,33 @@ func (p *PostgreSQL) generateIndexSQL(tableName string, index sqlmapper.Index) s
 
 	return sql
 }
+
+// insertBatchSize is the maximum number of rows per generated INSERT statement
+const insertBatchSize = 100
+
+// formatValue encodes a data value as a PostgreSQL literal.
+// Binary data is written in the hex format of bytea.
+//
+// Parameters:
+//   - value: The data value to encode
+//
+// Returns:
+//   - string: The SQL literal
+func (p *PostgreSQL) formatValue(value interface{}) string {
+	if data, ok := value.([]byte); ok {
+		return "'\\x" + hex.EncodeToString(data) + "'::bytea"
+	}
+	return sqlmapper.FormatLiteral(value)
+}
+
+// generateInsertSQL creates the INSERT statements for the data of a table,
+// combining up to insertBatchSize rows per statement.
+//
+// Parameters:
+//   - table: The table whose data is generated
+//
+// Returns:
+//   - []string: The INSERT statements without terminators
+func (p *PostgreSQL) generateInsertSQL(table sqlmapper.Table) []string {
+	return sqlmapper.InsertStatements(table, insertBatchSize, p.formatValue)
+}
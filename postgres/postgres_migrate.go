@@ -0,0 +1,304 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// GenerateMigration renders a list of schema changes, as produced by sqlmapper.Diff,
+// into a PostgreSQL migration script. Changes are rendered in the given order, and data types
+// of changes diffed from schemas of another dialect are translated to PostgreSQL.
+//
+// Parameters:
+//   - changes: The ordered schema changes to render
+//
+// Returns:
+//   - string: The generated PostgreSQL migration statements
+//   - error: An error if a change carries an unexpected definition
+func (p *PostgreSQL) GenerateMigration(changes []sqlmapper.Change) (string, error) {
+	changes = sqlmapper.ConvertChanges(changes, sqlmapper.PostgreSQL)
+	var result strings.Builder
+
+	for _, change := range changes {
+		stmt, err := p.generateChangeSQL(change)
+		if err != nil {
+			return "", err
+		}
+		if stmt == "" {
+			continue
+		}
+		result.WriteString(stmt)
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// generateChangeSQL renders a single schema change
+func (p *PostgreSQL) generateChangeSQL(change sqlmapper.Change) (string, error) {
+	switch change.Type {
+	case sqlmapper.CreateTable:
+		table, ok := change.New.(sqlmapper.Table)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		stmts := []string{p.generateTableSQL(table) + ";"}
+		for _, index := range table.Indexes {
+			stmts = append(stmts, p.generateIndexSQL(table.Name, index)+";")
+		}
+		return strings.Join(stmts, "\n"), nil
+
+	case sqlmapper.DropTable:
+		return fmt.Sprintf("DROP TABLE %s;", change.Name), nil
+
+	case sqlmapper.AddColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", change.Table, p.generateColumnSQL(column)), nil
+
+	case sqlmapper.DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", change.Table, change.Name), nil
+
+	case sqlmapper.ModifyColumn:
+		oldColumn, _ := change.Old.(sqlmapper.Column)
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return p.generateAlterColumnSQL(change.Table, oldColumn, column), nil
+
+	case sqlmapper.AddIndex:
+		index, ok := change.New.(sqlmapper.Index)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return p.generateIndexSQL(change.Table, index) + ";", nil
+
+	case sqlmapper.DropIndex:
+		if change.Name == "" {
+			return fmt.Sprintf("-- Unnamed index on %s must be dropped manually", change.Table), nil
+		}
+		return fmt.Sprintf("DROP INDEX %s;", change.Name), nil
+
+	case sqlmapper.AddConstraint:
+		constraint, ok := change.New.(sqlmapper.Constraint)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;", change.Table, p.generateConstraintSQL(constraint)), nil
+
+	case sqlmapper.DropConstraint:
+		if change.Name == "" {
+			constraint, _ := change.Old.(sqlmapper.Constraint)
+			return fmt.Sprintf("-- Unnamed %s constraint on %s must be dropped manually", constraint.Type, change.Table), nil
+		}
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", change.Table, change.Name), nil
+
+	case sqlmapper.CreateView, sqlmapper.ModifyView:
+		view, ok := change.New.(sqlmapper.View)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		if view.IsMaterialized {
+			stmt := fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s;", view.Name, view.Definition)
+			if change.Type == sqlmapper.ModifyView {
+				stmt = fmt.Sprintf("DROP MATERIALIZED VIEW %s;\n%s", view.Name, stmt)
+			}
+			return stmt, nil
+		}
+		if oldView, ok := change.Old.(sqlmapper.View); ok && oldView.IsMaterialized {
+			return fmt.Sprintf("DROP MATERIALIZED VIEW %s;\nCREATE VIEW %s AS %s;", view.Name, view.Name, view.Definition), nil
+		}
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s;", view.Name, view.Definition), nil
+
+	case sqlmapper.DropView:
+		if view, ok := change.Old.(sqlmapper.View); ok && view.IsMaterialized {
+			return fmt.Sprintf("DROP MATERIALIZED VIEW %s;", change.Name), nil
+		}
+		return fmt.Sprintf("DROP VIEW %s;", change.Name), nil
+
+	case sqlmapper.CreateSequence:
+		seq, ok := change.New.(sqlmapper.Sequence)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return "CREATE SEQUENCE " + seq.Name + p.generateSequenceOptions(seq) + ";", nil
+
+	case sqlmapper.ModifySequence:
+		seq, ok := change.New.(sqlmapper.Sequence)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		options := p.generateSequenceOptions(seq)
+		if !seq.Cycle {
+			options += " NO CYCLE"
+		}
+		return "ALTER SEQUENCE " + seq.Name + options + ";", nil
+
+	case sqlmapper.DropSequence:
+		return fmt.Sprintf("DROP SEQUENCE %s;", change.Name), nil
+
+	case sqlmapper.CreateTrigger, sqlmapper.ModifyTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		stmt := p.generateTriggerSQL(trigger)
+		if change.Type == sqlmapper.ModifyTrigger {
+			stmt = fmt.Sprintf("DROP TRIGGER %s ON %s;\n%s", change.Name, change.Table, stmt)
+		}
+		return stmt, nil
+
+	case sqlmapper.DropTrigger:
+		return fmt.Sprintf("DROP TRIGGER %s ON %s;", change.Name, change.Table), nil
+
+	case sqlmapper.CreateFunction, sqlmapper.ModifyFunction:
+		fn, ok := change.New.(sqlmapper.Function)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return p.generateFunctionSQL(fn), nil
+
+	case sqlmapper.DropFunction:
+		fn, _ := change.Old.(sqlmapper.Function)
+		if fn.IsProc {
+			return fmt.Sprintf("DROP PROCEDURE %s;", change.Name), nil
+		}
+		return fmt.Sprintf("DROP FUNCTION %s;", change.Name), nil
+	}
+
+	return "", errors.New("unsupported change type: " + string(change.Type))
+}
+
+// generateAlterColumnSQL generates the ALTER COLUMN statements needed to turn oldColumn into column
+func (p *PostgreSQL) generateAlterColumnSQL(tableName string, oldColumn, column sqlmapper.Column) string {
+	var stmts []string
+	prefix := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", tableName, column.Name)
+
+	if !strings.EqualFold(oldColumn.DataType, column.DataType) || oldColumn.Length != column.Length || oldColumn.Scale != column.Scale {
+		dataType := column.DataType
+		if column.Length > 0 {
+			if column.Scale > 0 {
+				dataType += fmt.Sprintf("(%d,%d)", column.Length, column.Scale)
+			} else {
+				dataType += fmt.Sprintf("(%d)", column.Length)
+			}
+		}
+		stmts = append(stmts, fmt.Sprintf("%s TYPE %s;", prefix, dataType))
+	}
+
+	if oldColumn.IsNullable != column.IsNullable {
+		if column.IsNullable {
+			stmts = append(stmts, prefix+" DROP NOT NULL;")
+		} else {
+			stmts = append(stmts, prefix+" SET NOT NULL;")
+		}
+	}
+
+	if oldColumn.DefaultValue != column.DefaultValue {
+		if column.DefaultValue == "" {
+			stmts = append(stmts, prefix+" DROP DEFAULT;")
+		} else {
+			stmts = append(stmts, fmt.Sprintf("%s SET DEFAULT %s;", prefix, column.DefaultValue))
+		}
+	}
+
+	return strings.Join(stmts, "\n")
+}
+
+// generateConstraintSQL generates SQL for a table constraint clause
+func (p *PostgreSQL) generateConstraintSQL(constraint sqlmapper.Constraint) string {
+	sql := ""
+	if constraint.Name != "" {
+		sql = "CONSTRAINT " + constraint.Name + " "
+	}
+
+	if strings.ToUpper(constraint.Type) == "CHECK" {
+		return sql + "CHECK (" + constraint.CheckExpression + ")"
+	}
+
+	sql += constraint.Type + " (" + strings.Join(constraint.Columns, ", ") + ")"
+	if constraint.RefTable != "" {
+		sql += " REFERENCES " + constraint.RefTable + " (" + strings.Join(constraint.RefColumns, ", ") + ")"
+		if constraint.DeleteRule != "" {
+			sql += " ON DELETE " + constraint.DeleteRule
+		}
+		if constraint.UpdateRule != "" {
+			sql += " ON UPDATE " + constraint.UpdateRule
+		}
+	}
+	if constraint.Deferrable {
+		sql += " DEFERRABLE"
+		if constraint.Initially != "" {
+			sql += " INITIALLY " + constraint.Initially
+		}
+	}
+
+	return sql
+}
+
+// generateSequenceOptions generates the option list of a CREATE or ALTER SEQUENCE statement
+func (p *PostgreSQL) generateSequenceOptions(seq sqlmapper.Sequence) string {
+	sql := ""
+	if seq.IncrementBy != 0 {
+		sql += fmt.Sprintf(" INCREMENT BY %d", seq.IncrementBy)
+	}
+	if seq.MinValue != 0 {
+		sql += fmt.Sprintf(" MINVALUE %d", seq.MinValue)
+	}
+	if seq.MaxValue != 0 {
+		sql += fmt.Sprintf(" MAXVALUE %d", seq.MaxValue)
+	}
+	if seq.StartValue != 0 {
+		sql += fmt.Sprintf(" START WITH %d", seq.StartValue)
+	}
+	if seq.Cache != 0 {
+		sql += fmt.Sprintf(" CACHE %d", seq.Cache)
+	}
+	if seq.Cycle {
+		sql += " CYCLE"
+	}
+	return sql
+}
+
+// generateTriggerSQL generates SQL for a trigger. The trigger body holds the
+// name of the trigger function, as produced by the parser.
+func (p *PostgreSQL) generateTriggerSQL(trigger sqlmapper.Trigger) string {
+	sql := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s", trigger.Name, trigger.Timing, trigger.Event, trigger.Table)
+	if trigger.ForEachRow {
+		sql += " FOR EACH ROW"
+	}
+	if trigger.Condition != "" {
+		sql += " WHEN (" + trigger.Condition + ")"
+	}
+	body := strings.TrimSpace(trigger.Body)
+	if !strings.HasSuffix(body, ")") {
+		body += "()"
+	}
+	return sql + " EXECUTE FUNCTION " + body + ";"
+}
+
+// generateFunctionSQL generates SQL for a function or procedure
+func (p *PostgreSQL) generateFunctionSQL(fn sqlmapper.Function) string {
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = strings.TrimSpace(fmt.Sprintf("%s %s %s", param.Direction, param.Name, param.DataType))
+	}
+
+	language := fn.Language
+	if language == "" {
+		language = "plpgsql"
+	}
+
+	if fn.IsProc {
+		return fmt.Sprintf("CREATE OR REPLACE PROCEDURE %s(%s) LANGUAGE %s AS $$%s$$;",
+			fn.Name, strings.Join(params, ", "), language, fn.Body)
+	}
+	return fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(%s) RETURNS %s AS $$%s$$ LANGUAGE %s;",
+		fn.Name, strings.Join(params, ", "), fn.Returns, fn.Body, language)
+}
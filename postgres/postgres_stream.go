@@ -1,13 +1,13 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"strings"
-	"sync"
 
 	"github.com/mstgnz/sqlmapper"
 	"github.com/mstgnz/sqlmapper/stream"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // PostgreSQLStreamParser implements the StreamParser interface for PostgreSQL
@@ -24,470 +24,68 @@ func NewPostgreSQLStreamParser() *PostgreSQLStreamParser {
 
 // ParseStream implements the StreamParser interface
 func (p *PostgreSQLStreamParser) ParseStream(reader io.Reader, callback func(stream.SchemaObject) error) error {
-	streamReader := stream.NewStreamReader(reader, ";")
-
-	for {
-		statement, err := streamReader.ReadStatement()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading statement: %v", err)
-		}
-
-		statement = strings.TrimSpace(statement)
-		if statement == "" {
-			continue
-		}
-
-		// Parse CREATE TYPE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TYPE") {
-			typ, err := p.parseTypeStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TypeObject,
-				Data: typ,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE TABLE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TABLE") {
-			table, err := p.parseTableStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TableObject,
-				Data: table,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE VIEW statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE VIEW") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE MATERIALIZED VIEW") {
-			view, err := p.parseViewStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ViewObject,
-				Data: view,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE FUNCTION statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE FUNCTION") {
-			function, err := p.parseFunctionStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.FunctionObject,
-				Data: function,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE PROCEDURE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE PROCEDURE") {
-			procedure, err := p.parseProcedureStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ProcedureObject,
-				Data: procedure,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE TRIGGER statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TRIGGER") {
-			trigger, err := p.parseTriggerStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TriggerObject,
-				Data: trigger,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE INDEX statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE UNIQUE INDEX") {
-			index, err := p.parseIndexStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.IndexObject,
-				Data: index,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse GRANT/REVOKE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "GRANT") ||
-			strings.HasPrefix(strings.ToUpper(statement), "REVOKE") {
-			permission, err := p.parsePermissionStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.PermissionObject,
-				Data: permission,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-	}
-
-	return nil
-}
-
-// ParseStreamParallel implements parallel processing for PostgreSQL stream parsing
-func (p *PostgreSQLStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
-	streamReader := stream.NewStreamReader(reader, ";")
-	statements := make(chan string, workers)
-	results := make(chan stream.SchemaObject, workers)
-	errors := make(chan error, workers)
-	var wg sync.WaitGroup
-
-	// Start worker goroutines
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for statement := range statements {
-				obj, err := p.parseStatement(statement)
-				if err != nil {
-					errors <- err
-					return
-				}
-				if obj != nil {
-					results <- *obj
-				}
-			}
-		}()
-	}
-
-	// Start a goroutine to close results channel after all workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Start a goroutine to read statements and send them to workers
-	go func() {
-		for {
-			statement, err := streamReader.ReadStatement()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				errors <- fmt.Errorf("error reading statement: %v", err)
-				break
-			}
-
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
-				continue
-			}
-			statements <- statement
-		}
-		close(statements)
-	}()
-
-	// Process results and handle errors
-	for obj := range results {
-		if err := callback(obj); err != nil {
-			return err
-		}
-	}
-
-	// Check for any errors from workers
-	select {
-	case err := <-errors:
-		return err
-	default:
-		return nil
-	}
-}
-
-// parseStatement parses a single SQL statement and returns a SchemaObject
-func (p *PostgreSQLStreamParser) parseStatement(statement string) (*stream.SchemaObject, error) {
-	upperStatement := strings.ToUpper(statement)
-
-	switch {
-	case strings.HasPrefix(upperStatement, "CREATE TYPE"):
-		typ, err := p.parseTypeStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TypeObject,
-			Data: typ,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE TABLE"):
-		table, err := p.parseTableStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TableObject,
-			Data: table,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE VIEW"),
-		strings.HasPrefix(upperStatement, "CREATE MATERIALIZED VIEW"):
-		view, err := p.parseViewStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ViewObject,
-			Data: view,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE FUNCTION"):
-		function, err := p.parseFunctionStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.FunctionObject,
-			Data: function,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE PROCEDURE"):
-		procedure, err := p.parseProcedureStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ProcedureObject,
-			Data: procedure,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE TRIGGER"):
-		trigger, err := p.parseTriggerStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TriggerObject,
-			Data: trigger,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE INDEX"),
-		strings.HasPrefix(upperStatement, "CREATE UNIQUE INDEX"):
-		index, err := p.parseIndexStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.IndexObject,
-			Data: index,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "GRANT"),
-		strings.HasPrefix(upperStatement, "REVOKE"):
-		permission, err := p.parsePermissionStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.PermissionObject,
-			Data: permission,
-		}, nil
-	}
-
-	return nil, nil
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{})
 }
 
-// parseTypeStatement parses a CREATE TYPE statement
-func (p *PostgreSQLStreamParser) parseTypeStatement(statement string) (*sqlmapper.Type, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseTypes(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Types) == 0 {
-		return nil, fmt.Errorf("no type found in statement")
-	}
-
-	return &tempSchema.Types[0], nil
+// ParseStreamLenient implements the stream.LenientStreamParser interface
+func (p *PostgreSQLStreamParser) ParseStreamLenient(reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{Lenient: true})
 }
 
-// parseTableStatement parses a CREATE TABLE statement
-func (p *PostgreSQLStreamParser) parseTableStatement(statement string) (*sqlmapper.Table, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseTables(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Tables) == 0 {
-		return nil, fmt.Errorf("no table found in statement")
-	}
-
-	return &tempSchema.Tables[0], nil
+// ParseStreamOptions implements the stream.OptionsStreamParser interface
+func (p *PostgreSQLStreamParser) ParseStreamOptions(reader io.Reader, callback func(stream.SchemaObject) error, opts stream.ParseOptions) error {
+	return stream.ParseStatements(reader, tokenizer.PostgreSQL, p.parseStatement, callback, opts)
 }
 
-// parseViewStatement parses a CREATE VIEW statement
-func (p *PostgreSQLStreamParser) parseViewStatement(statement string) (*sqlmapper.View, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseViews(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Views) == 0 {
-		return nil, fmt.Errorf("no view found in statement")
-	}
-
-	return &tempSchema.Views[0], nil
+// ParseStreamParallel implements parallel processing for PostgreSQL stream parsing.
+// Objects are delivered in input order; each worker parses with its own parser.
+func (p *PostgreSQLStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
+	return stream.ParseStreamParallel(reader, tokenizer.PostgreSQL, func() stream.ParseFunc {
+		return NewPostgreSQLStreamParser().parseStatement
+	}, callback, stream.ParallelOptions{Workers: workers})
 }
 
-// parseFunctionStatement parses a CREATE FUNCTION statement
-func (p *PostgreSQLStreamParser) parseFunctionStatement(statement string) (*sqlmapper.Function, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseFunctions(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Functions) == 0 {
-		return nil, fmt.Errorf("no function found in statement")
-	}
-
-	for _, fn := range tempSchema.Functions {
-		if !fn.IsProc {
-			return &fn, nil
-		}
-	}
-
-	return nil, fmt.Errorf("no function found in statement")
+// ParseStreamContext implements the StreamParser interface
+func (p *PostgreSQLStreamParser) ParseStreamContext(ctx context.Context, reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return stream.ParseContext(ctx, reader, callback, p.ParseStream)
 }
 
-// parseProcedureStatement parses a CREATE PROCEDURE statement
-func (p *PostgreSQLStreamParser) parseProcedureStatement(statement string) (*sqlmapper.Procedure, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseFunctions(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Functions) == 0 {
-		return nil, fmt.Errorf("no procedure found in statement")
-	}
-
-	for _, fn := range tempSchema.Functions {
-		if fn.IsProc {
-			proc := &sqlmapper.Procedure{
-				Name:       fn.Name,
-				Parameters: fn.Parameters,
-				Body:       fn.Body,
-				Schema:     fn.Schema,
-			}
-			return proc, nil
-		}
-	}
-
-	return nil, fmt.Errorf("no procedure found in statement")
+// GenerateStreamContext implements the StreamParser interface
+func (p *PostgreSQLStreamParser) GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error {
+	return stream.GenerateContext(ctx, schema, writer, p.GenerateStream)
 }
 
-// parseTriggerStatement parses a CREATE TRIGGER statement
-func (p *PostgreSQLStreamParser) parseTriggerStatement(statement string) (*sqlmapper.Trigger, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseTriggers(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Triggers) == 0 {
-		return nil, fmt.Errorf("no trigger found in statement")
+// GenerateObject implements the StreamParser interface
+func (p *PostgreSQLStreamParser) GenerateObject(obj stream.SchemaObject, writer io.Writer) error {
+	var stmt string
+	switch data := obj.Data.(type) {
+	case *sqlmapper.Index:
+		stmt = p.postgres.generateIndexSQL(obj.Table, *data)
+	case *sqlmapper.Constraint:
+		stmt = fmt.Sprintf("ALTER TABLE %s ADD %s", obj.Table, p.postgres.generateConstraintSQL(*data))
+	case *stream.Comment:
+		stmt = data.Statement(obj.Table)
+	case *stream.Unparsed:
+		stmt = data.SQL
+	default:
+		return p.GenerateStream(stream.ObjectSchema(obj), writer)
 	}
 
-	return &tempSchema.Triggers[0], nil
+	_, err := writer.Write([]byte(stmt + ";\n\n"))
+	return err
 }
 
-// parseIndexStatement parses a CREATE INDEX statement
-func (p *PostgreSQLStreamParser) parseIndexStatement(statement string) (*sqlmapper.Index, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parseIndexes(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Tables) == 0 || len(tempSchema.Tables[0].Indexes) == 0 {
-		return nil, fmt.Errorf("no index found in statement")
-	}
-
-	return &tempSchema.Tables[0].Indexes[0], nil
+// Dialect implements the StreamParser interface
+func (p *PostgreSQLStreamParser) Dialect() sqlmapper.DatabaseType {
+	return sqlmapper.PostgreSQL
 }
 
-// parsePermissionStatement parses a GRANT/REVOKE statement
-func (p *PostgreSQLStreamParser) parsePermissionStatement(statement string) (*sqlmapper.Permission, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.postgres.schema = tempSchema
-
-	if err := p.postgres.parsePermissions(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Permissions) == 0 {
-		return nil, fmt.Errorf("no permission found in statement")
-	}
-
-	return &tempSchema.Permissions[0], nil
+// parseStatement parses a single SQL statement with the batch parser and
+// returns the objects it defines
+func (p *PostgreSQLStreamParser) parseStatement(stmt tokenizer.Statement) ([]stream.SchemaObject, error) {
+	return stream.StatementObjects(stmt.Text, func(schema *sqlmapper.Schema) error {
+		return (&PostgreSQL{schema: schema}).parseTokenized(stmt)
+	})
 }
 
 // GenerateStream implements the StreamParser interface
@@ -496,6 +94,12 @@ func (p *PostgreSQLStreamParser) GenerateStream(schema *sqlmapper.Schema, writer
 		return fmt.Errorf("schema cannot be nil")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.PostgreSQL)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	// Write types
 	for _, typ := range schema.Types {
 		stmt := p.postgres.generateTypeSQL(typ)
@@ -504,8 +108,21 @@ func (p *PostgreSQLStreamParser) GenerateStream(schema *sqlmapper.Schema, writer
 		}
 	}
 
+	// Write sequences
+	for _, seq := range schema.Sequences {
+		stmt := "CREATE SEQUENCE " + seq.Name + p.postgres.generateSequenceOptions(seq)
+		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+			return err
+		}
+	}
+
 	// Write tables
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
 		stmt := p.postgres.generateTableSQL(table)
 		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
 			return err
@@ -520,6 +137,23 @@ func (p *PostgreSQLStreamParser) GenerateStream(schema *sqlmapper.Schema, writer
 		}
 	}
 
+	// Write table data
+	for _, table := range schema.Tables {
+		for _, stmt := range p.postgres.generateInsertSQL(table) {
+			if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Write foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD %s", fk.Table, p.postgres.generateConstraintSQL(fk.Constraint))
+		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+			return err
+		}
+	}
+
 	// Write views
 	for _, view := range schema.Views {
 		if view.IsMaterialized {
@@ -447,3 +447,136 @@ CREATE UNIQUE INDEX idx_price ON products(price);`),
 		})
 	}
 }
+
+func TestPostgreSQL_GenerateMigration(t *testing.T) {
+	oldSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "name", DataType: "VARCHAR", Length: 100, IsNullable: true},
+					{Name: "status", DataType: "TEXT", DefaultValue: "'new'"},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "chk_status", Type: "CHECK", CheckExpression: "status <> ''"},
+				},
+			},
+		},
+		Sequences: []sqlmapper.Sequence{
+			{Name: "user_seq", StartValue: 1, IncrementBy: 1},
+		},
+		Triggers: []sqlmapper.Trigger{
+			{Name: "audit_users", Table: "users", Timing: "AFTER", Event: "INSERT", ForEachRow: true, Body: "audit()"},
+		},
+	}
+
+	newSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "name", DataType: "VARCHAR", Length: 200},
+					{Name: "status", DataType: "TEXT"},
+				},
+			},
+		},
+		Sequences: []sqlmapper.Sequence{
+			{Name: "user_seq", StartValue: 1, IncrementBy: 10},
+		},
+		Views: []sqlmapper.View{
+			{Name: "user_stats", Definition: "SELECT count(*) FROM users", IsMaterialized: true},
+		},
+		Functions: []sqlmapper.Function{
+			{Name: "user_count", Returns: "INTEGER", Body: " SELECT count(*) FROM users ", Language: "sql"},
+		},
+	}
+
+	p := &PostgreSQL{}
+	got, err := p.GenerateMigration(sqlmapper.Diff(oldSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+DROP TRIGGER audit_users ON users;
+ALTER TABLE users DROP CONSTRAINT chk_status;
+ALTER SEQUENCE user_seq INCREMENT BY 10 START WITH 1 NO CYCLE;
+ALTER TABLE users ALTER COLUMN name TYPE VARCHAR(200);
+ALTER TABLE users ALTER COLUMN name SET NOT NULL;
+ALTER TABLE users ALTER COLUMN status DROP DEFAULT;
+CREATE OR REPLACE FUNCTION user_count() RETURNS INTEGER AS $$ SELECT count(*) FROM users $$ LANGUAGE sql;
+CREATE MATERIALIZED VIEW user_stats AS SELECT count(*) FROM users;`), strings.TrimSpace(got))
+
+	// Dropping everything reverses the dependency order
+	got, err = p.GenerateMigration(sqlmapper.Diff(oldSchema, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+DROP TRIGGER audit_users ON users;
+DROP TABLE users;
+DROP SEQUENCE user_seq;`), strings.TrimSpace(got))
+}
+
+func TestPostgreSQL_Diff_SameInput(t *testing.T) {
+	content := `
+CREATE SEQUENCE order_seq INCREMENT BY 1 START WITH 1;
+CREATE TABLE orders (
+    id INTEGER DEFAULT nextval('order_seq') PRIMARY KEY,
+    total NUMERIC(10,2) NOT NULL
+);
+CREATE INDEX idx_orders_total ON orders (total);
+CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100;`
+
+	oldSchema, err := NewPostgreSQL().Parse(content)
+	assert.NoError(t, err)
+	newSchema, err := NewPostgreSQL().Parse(content)
+	assert.NoError(t, err)
+
+	assert.Len(t, newSchema.Sequences, 1)
+	// Each parse records its own positions, which must not count as changes
+	assert.Empty(t, sqlmapper.Diff(oldSchema, newSchema))
+}
+
+func TestPostgreSQL_SchemaSnapshot(t *testing.T) {
+	content := `
+CREATE SEQUENCE order_seq START WITH 1 INCREMENT BY 1;
+CREATE TABLE customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL
+);
+CREATE TABLE orders (
+    id INTEGER DEFAULT nextval('order_seq'),
+    customer_id INTEGER,
+    total NUMERIC(10,2),
+    CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+CREATE VIEW order_totals AS SELECT customer_id, SUM(total) FROM orders GROUP BY customer_id;`
+
+	p := NewPostgreSQL()
+	schema, err := p.Parse(content)
+	assert.NoError(t, err)
+
+	expected, err := p.Generate(schema)
+	assert.NoError(t, err)
+
+	jsonData, err := sqlmapper.MarshalSchemaJSON(schema)
+	assert.NoError(t, err)
+	fromJSON, err := sqlmapper.UnmarshalSchemaJSON(jsonData)
+	assert.NoError(t, err)
+	againJSON, err := sqlmapper.MarshalSchemaJSON(fromJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, string(jsonData), string(againJSON))
+
+	// Source positions are not part of the snapshot
+	assert.NotNil(t, schema.Tables[0].Pos)
+	assert.Nil(t, fromJSON.Tables[0].Pos)
+
+	yamlData, err := sqlmapper.MarshalSchemaYAML(schema)
+	assert.NoError(t, err)
+	fromYAML, err := sqlmapper.UnmarshalSchemaYAML(yamlData)
+	assert.NoError(t, err)
+	assert.Equal(t, fromJSON, fromYAML)
+
+	// A loaded snapshot generates the same SQL as the parsed schema
+	result, err := NewPostgreSQL().Generate(fromJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
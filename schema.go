@@ -19,278 +19,289 @@ const (
 
 // Schema represents a database schema
 type Schema struct {
-	Name             string
-	Tables           []Table
-	Procedures       []Procedure
-	Functions        []Function
-	Triggers         []Trigger
-	Views            []View
-	Sequences        []Sequence
-	Extensions       []Extension
-	Permissions      []Permission
-	UserDefinedTypes []UserDefinedType
-	Partitions       map[string][]Partition // table_name -> partitions
-	DatabaseLinks    []DatabaseLink
-	Tablespaces      []Tablespace
-	Roles            []Role
-	Users            []User
-	Clusters         []Cluster
-	MaterializedLogs []MaterializedViewLog
-	Types            []Type
+	Name             string                 `json:"name" yaml:"name"`
+	Dialect          DatabaseType           `json:"dialect,omitempty" yaml:"dialect,omitempty"` // Source dialect the schema was parsed from
+	Tables           []Table                `json:"tables,omitempty" yaml:"tables,omitempty"`
+	Procedures       []Procedure            `json:"procedures,omitempty" yaml:"procedures,omitempty"`
+	Functions        []Function             `json:"functions,omitempty" yaml:"functions,omitempty"`
+	Triggers         []Trigger              `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	Views            []View                 `json:"views,omitempty" yaml:"views,omitempty"`
+	Sequences        []Sequence             `json:"sequences,omitempty" yaml:"sequences,omitempty"`
+	Extensions       []Extension            `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Permissions      []Permission           `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	UserDefinedTypes []UserDefinedType      `json:"user_defined_types,omitempty" yaml:"user_defined_types,omitempty"`
+	Partitions       map[string][]Partition `json:"partitions,omitempty" yaml:"partitions,omitempty"` // table_name -> partitions
+	DatabaseLinks    []DatabaseLink         `json:"database_links,omitempty" yaml:"database_links,omitempty"`
+	Tablespaces      []Tablespace           `json:"tablespaces,omitempty" yaml:"tablespaces,omitempty"`
+	Roles            []Role                 `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Users            []User                 `json:"users,omitempty" yaml:"users,omitempty"`
+	Clusters         []Cluster              `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	MaterializedLogs []MaterializedViewLog  `json:"materialized_logs,omitempty" yaml:"materialized_logs,omitempty"`
+	Types            []Type                 `json:"types,omitempty" yaml:"types,omitempty"`
 }
 
 // Table represents a database table
 type Table struct {
-	Name        string
-	Schema      string
-	Columns     []Column
-	Indexes     []Index
-	Constraints []Constraint
-	Data        []Row
-	TableSpace  string
-	Storage     *StorageClause
-	Temporary   bool
-	Comment     string
-	Options     string // Storage engine options (e.g., ENGINE=InnoDB, CHARSET=utf8mb4)
+	Name        string         `json:"name" yaml:"name"`
+	Schema      string         `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Columns     []Column       `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Indexes     []Index        `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+	Constraints []Constraint   `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	Data        []Row          `json:"data,omitempty" yaml:"data,omitempty"`
+	TableSpace  string         `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	Storage     *StorageClause `json:"storage,omitempty" yaml:"storage,omitempty"`
+	Temporary   bool           `json:"temporary,omitempty" yaml:"temporary,omitempty"`
+	Comment     string         `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Options     string         `json:"options,omitempty" yaml:"options,omitempty"` // Storage engine options (e.g., ENGINE=InnoDB, CHARSET=utf8mb4)
+	Pos         *Position      `json:"-" yaml:"-"`                                 // Source location of the defining statement
 }
 
 // Column represents a table column
 type Column struct {
-	Name            string
-	DataType        string
-	Length          int
-	Scale           int
-	Precision       int
-	IsNullable      bool `default:"true"`
-	DefaultValue    string
-	AutoIncrement   bool
-	IsPrimaryKey    bool
-	IsUnique        bool
-	Comment         string
-	Order           int
-	CheckExpression string
+	Name            string `json:"name" yaml:"name"`
+	DataType        string `json:"data_type,omitempty" yaml:"data_type,omitempty"`
+	Length          int    `json:"length,omitempty" yaml:"length,omitempty"`
+	Scale           int    `json:"scale,omitempty" yaml:"scale,omitempty"`
+	Precision       int    `json:"precision,omitempty" yaml:"precision,omitempty"`
+	IsNullable      bool   `json:"is_nullable,omitempty" yaml:"is_nullable,omitempty" default:"true"`
+	DefaultValue    string `json:"default_value,omitempty" yaml:"default_value,omitempty"`
+	AutoIncrement   bool   `json:"auto_increment,omitempty" yaml:"auto_increment,omitempty"`
+	IsPrimaryKey    bool   `json:"is_primary_key,omitempty" yaml:"is_primary_key,omitempty"`
+	IsUnique        bool   `json:"is_unique,omitempty" yaml:"is_unique,omitempty"`
+	Comment         string `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Order           int    `json:"order,omitempty" yaml:"order,omitempty"`
+	CheckExpression string `json:"check_expression,omitempty" yaml:"check_expression,omitempty"`
 }
 
 // Index represents a table index
 type Index struct {
-	Name        string
-	Columns     []string
-	IsUnique    bool
-	IsBitmap    bool   // Oracle için bitmap indeks desteği
-	IsClustered bool   // SQL Server için clustered indeks desteği
-	Type        string // BTREE, HASH etc.
-	Condition   string // WHERE clause
-	TableSpace  string
-	Storage     *StorageClause
-	Compression bool
+	Name        string         `json:"name" yaml:"name"`
+	Columns     []string       `json:"columns,omitempty" yaml:"columns,omitempty"`
+	IsUnique    bool           `json:"is_unique,omitempty" yaml:"is_unique,omitempty"`
+	IsBitmap    bool           `json:"is_bitmap,omitempty" yaml:"is_bitmap,omitempty"`       // Oracle için bitmap indeks desteği
+	IsClustered bool           `json:"is_clustered,omitempty" yaml:"is_clustered,omitempty"` // SQL Server için clustered indeks desteği
+	Type        string         `json:"type,omitempty" yaml:"type,omitempty"`                 // BTREE, HASH etc.
+	Condition   string         `json:"condition,omitempty" yaml:"condition,omitempty"`       // WHERE clause
+	TableSpace  string         `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	Storage     *StorageClause `json:"storage,omitempty" yaml:"storage,omitempty"`
+	Compression bool           `json:"compression,omitempty" yaml:"compression,omitempty"`
+	Pos         *Position      `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Constraint represents a table constraint
 type Constraint struct {
-	Name            string
-	Type            string // PRIMARY KEY, FOREIGN KEY, UNIQUE, CHECK
-	Columns         []string
-	RefTable        string
-	RefColumns      []string
-	UpdateRule      string
-	DeleteRule      string
-	CheckExpression string
-	Deferrable      bool
-	Initially       string // IMMEDIATE, DEFERRED
+	Name            string   `json:"name" yaml:"name"`
+	Type            string   `json:"type,omitempty" yaml:"type,omitempty"` // PRIMARY KEY, FOREIGN KEY, UNIQUE, CHECK
+	Columns         []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	RefTable        string   `json:"ref_table,omitempty" yaml:"ref_table,omitempty"`
+	RefColumns      []string `json:"ref_columns,omitempty" yaml:"ref_columns,omitempty"`
+	UpdateRule      string   `json:"update_rule,omitempty" yaml:"update_rule,omitempty"`
+	DeleteRule      string   `json:"delete_rule,omitempty" yaml:"delete_rule,omitempty"`
+	CheckExpression string   `json:"check_expression,omitempty" yaml:"check_expression,omitempty"`
+	Deferrable      bool     `json:"deferrable,omitempty" yaml:"deferrable,omitempty"`
+	Initially       string   `json:"initially,omitempty" yaml:"initially,omitempty"` // IMMEDIATE, DEFERRED
 }
 
 // Row represents table data
 type Row struct {
-	Values map[string]interface{}
+	Values map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`
 }
 
 // Procedure represents a stored procedure
 type Procedure struct {
-	Name          string
-	Schema        string
-	Parameters    []Parameter
-	Body          string
-	Language      string
-	Security      string // DEFINER, INVOKER
-	SQLSecurity   string
-	Deterministic bool
-	Comment       string
+	Name          string      `json:"name" yaml:"name"`
+	Schema        string      `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Parameters    []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Body          string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Language      string      `json:"language,omitempty" yaml:"language,omitempty"`
+	Security      string      `json:"security,omitempty" yaml:"security,omitempty"` // DEFINER, INVOKER
+	SQLSecurity   string      `json:"sql_security,omitempty" yaml:"sql_security,omitempty"`
+	Deterministic bool        `json:"deterministic,omitempty" yaml:"deterministic,omitempty"`
+	Comment       string      `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Pos           *Position   `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Function represents a database function
 type Function struct {
-	Name       string
-	Schema     string
-	Parameters []Parameter
-	Returns    string
-	Body       string
-	Language   string
-	IsProc     bool
+	Name       string      `json:"name" yaml:"name"`
+	Schema     string      `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Returns    string      `json:"returns,omitempty" yaml:"returns,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Language   string      `json:"language,omitempty" yaml:"language,omitempty"`
+	IsProc     bool        `json:"is_proc,omitempty" yaml:"is_proc,omitempty"`
+	Pos        *Position   `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Parameter represents a procedure or function parameter
 type Parameter struct {
-	Name      string
-	DataType  string
-	Direction string // IN, OUT, INOUT
-	Default   string
+	Name      string `json:"name" yaml:"name"`
+	DataType  string `json:"data_type,omitempty" yaml:"data_type,omitempty"`
+	Direction string `json:"direction,omitempty" yaml:"direction,omitempty"` // IN, OUT, INOUT
+	Default   string `json:"default,omitempty" yaml:"default,omitempty"`
 }
 
 // Trigger represents a database trigger
 type Trigger struct {
-	Name       string
-	Schema     string
-	Table      string
-	Timing     string
-	Event      string
-	Body       string
-	Condition  string
-	ForEachRow bool
+	Name       string    `json:"name" yaml:"name"`
+	Schema     string    `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Table      string    `json:"table,omitempty" yaml:"table,omitempty"`
+	Timing     string    `json:"timing,omitempty" yaml:"timing,omitempty"`
+	Event      string    `json:"event,omitempty" yaml:"event,omitempty"`
+	Body       string    `json:"body,omitempty" yaml:"body,omitempty"`
+	Condition  string    `json:"condition,omitempty" yaml:"condition,omitempty"`
+	ForEachRow bool      `json:"for_each_row,omitempty" yaml:"for_each_row,omitempty"`
+	Pos        *Position `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // View represents a database view
 type View struct {
-	Name           string
-	Schema         string
-	Definition     string
-	IsMaterialized bool
+	Name           string    `json:"name" yaml:"name"`
+	Schema         string    `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Definition     string    `json:"definition,omitempty" yaml:"definition,omitempty"`
+	IsMaterialized bool      `json:"is_materialized,omitempty" yaml:"is_materialized,omitempty"`
+	Pos            *Position `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Sequence represents a database sequence
 type Sequence struct {
-	Name        string
-	Schema      string
-	IncrementBy int
-	MinValue    int
-	MaxValue    int
-	StartValue  int
-	Cache       int
-	Cycle       bool
+	Name        string    `json:"name" yaml:"name"`
+	Schema      string    `json:"schema,omitempty" yaml:"schema,omitempty"`
+	IncrementBy int       `json:"increment_by,omitempty" yaml:"increment_by,omitempty"`
+	MinValue    int       `json:"min_value,omitempty" yaml:"min_value,omitempty"`
+	MaxValue    int       `json:"max_value,omitempty" yaml:"max_value,omitempty"`
+	StartValue  int       `json:"start_value,omitempty" yaml:"start_value,omitempty"`
+	Cache       int       `json:"cache,omitempty" yaml:"cache,omitempty"`
+	Cycle       bool      `json:"cycle,omitempty" yaml:"cycle,omitempty"`
+	Pos         *Position `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Extension represents a database extension
 type Extension struct {
-	Name    string
-	Version string
-	Schema  string
+	Name    string    `json:"name" yaml:"name"`
+	Version string    `json:"version,omitempty" yaml:"version,omitempty"`
+	Schema  string    `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Pos     *Position `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Permission represents a database permission
 type Permission struct {
-	Type       string // GRANT, REVOKE
-	Privileges []string
-	Object     string
-	Grantee    string
-	WithGrant  bool
+	Type       string    `json:"type,omitempty" yaml:"type,omitempty"` // GRANT, REVOKE
+	Privileges []string  `json:"privileges,omitempty" yaml:"privileges,omitempty"`
+	Object     string    `json:"object,omitempty" yaml:"object,omitempty"`
+	Grantee    string    `json:"grantee,omitempty" yaml:"grantee,omitempty"`
+	WithGrant  bool      `json:"with_grant,omitempty" yaml:"with_grant,omitempty"`
+	Pos        *Position `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // UserDefinedType represents custom data types
 type UserDefinedType struct {
-	Name       string
-	Schema     string
-	BaseType   string
-	Properties map[string]interface{}
+	Name       string                 `json:"name" yaml:"name"`
+	Schema     string                 `json:"schema,omitempty" yaml:"schema,omitempty"`
+	BaseType   string                 `json:"base_type,omitempty" yaml:"base_type,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Pos        *Position              `json:"-" yaml:"-"` // Source location of the defining statement
 }
 
 // Partition represents table partition information
 type Partition struct {
-	Name          string
-	Type          string // RANGE, LIST, HASH
-	SubPartitions []SubPartition
-	Expression    string
-	Values        []string
-	TableSpace    string
-	Storage       *StorageClause
+	Name          string         `json:"name" yaml:"name"`
+	Type          string         `json:"type,omitempty" yaml:"type,omitempty"` // RANGE, LIST, HASH
+	SubPartitions []SubPartition `json:"sub_partitions,omitempty" yaml:"sub_partitions,omitempty"`
+	Expression    string         `json:"expression,omitempty" yaml:"expression,omitempty"`
+	Values        []string       `json:"values,omitempty" yaml:"values,omitempty"`
+	TableSpace    string         `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	Storage       *StorageClause `json:"storage,omitempty" yaml:"storage,omitempty"`
 }
 
 // SubPartition represents table sub-partition information
 type SubPartition struct {
-	Name       string
-	Type       string
-	Expression string
-	Values     []string
-	TableSpace string
-	Storage    *StorageClause
+	Name       string         `json:"name" yaml:"name"`
+	Type       string         `json:"type,omitempty" yaml:"type,omitempty"`
+	Expression string         `json:"expression,omitempty" yaml:"expression,omitempty"`
+	Values     []string       `json:"values,omitempty" yaml:"values,omitempty"`
+	TableSpace string         `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	Storage    *StorageClause `json:"storage,omitempty" yaml:"storage,omitempty"`
 }
 
 // MaterializedViewLog represents materialized view log information
 type MaterializedViewLog struct {
-	Name           string
-	Schema         string
-	TableName      string
-	Columns        []string
-	RowID          bool
-	PrimaryKey     bool
-	SequenceNumber bool
-	CommitSCN      bool
-	Storage        *StorageClause
+	Name           string         `json:"name" yaml:"name"`
+	Schema         string         `json:"schema,omitempty" yaml:"schema,omitempty"`
+	TableName      string         `json:"table_name,omitempty" yaml:"table_name,omitempty"`
+	Columns        []string       `json:"columns,omitempty" yaml:"columns,omitempty"`
+	RowID          bool           `json:"row_id,omitempty" yaml:"row_id,omitempty"`
+	PrimaryKey     bool           `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	SequenceNumber bool           `json:"sequence_number,omitempty" yaml:"sequence_number,omitempty"`
+	CommitSCN      bool           `json:"commit_scn,omitempty" yaml:"commit_scn,omitempty"`
+	Storage        *StorageClause `json:"storage,omitempty" yaml:"storage,omitempty"`
 }
 
 // DatabaseLink represents database link information
 type DatabaseLink struct {
-	Name        string
-	Owner       string
-	ConnectInfo string
-	Public      bool
+	Name        string `json:"name" yaml:"name"`
+	Owner       string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	ConnectInfo string `json:"connect_info,omitempty" yaml:"connect_info,omitempty"`
+	Public      bool   `json:"public,omitempty" yaml:"public,omitempty"`
 }
 
 // Tablespace represents tablespace information
 type Tablespace struct {
-	Name        string
-	Type        string // PERMANENT, TEMPORARY
-	Status      string
-	Autoextend  bool
-	MaxSize     int64
-	InitialSize int64
-	DataFile    string
-	BlockSize   int
-	Logging     bool
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"` // PERMANENT, TEMPORARY
+	Status      string `json:"status,omitempty" yaml:"status,omitempty"`
+	Autoextend  bool   `json:"autoextend,omitempty" yaml:"autoextend,omitempty"`
+	MaxSize     int64  `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+	InitialSize int64  `json:"initial_size,omitempty" yaml:"initial_size,omitempty"`
+	DataFile    string `json:"data_file,omitempty" yaml:"data_file,omitempty"`
+	BlockSize   int    `json:"block_size,omitempty" yaml:"block_size,omitempty"`
+	Logging     bool   `json:"logging,omitempty" yaml:"logging,omitempty"`
 }
 
 // Role represents database role information
 type Role struct {
-	Name        string
-	Password    string
-	Permissions []Permission
-	Members     []string
-	System      bool
+	Name        string       `json:"name" yaml:"name"`
+	Password    string       `json:"password,omitempty" yaml:"password,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Members     []string     `json:"members,omitempty" yaml:"members,omitempty"`
+	System      bool         `json:"system,omitempty" yaml:"system,omitempty"`
 }
 
 // User represents database user information
 type User struct {
-	Name        string
-	Password    string
-	DefaultRole string
-	Roles       []string
-	Permissions []Permission
-	Profile     string
-	Status      string
-	TableSpace  string
-	TempSpace   string
+	Name        string       `json:"name" yaml:"name"`
+	Password    string       `json:"password,omitempty" yaml:"password,omitempty"`
+	DefaultRole string       `json:"default_role,omitempty" yaml:"default_role,omitempty"`
+	Roles       []string     `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Profile     string       `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Status      string       `json:"status,omitempty" yaml:"status,omitempty"`
+	TableSpace  string       `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	TempSpace   string       `json:"temp_space,omitempty" yaml:"temp_space,omitempty"`
 }
 
 // Cluster represents Oracle cluster information
 type Cluster struct {
-	Name       string
-	Schema     string
-	TableSpace string
-	Key        []string
-	Tables     []string
-	Size       int
-	HashKeys   int
-	Storage    *StorageClause
+	Name       string         `json:"name" yaml:"name"`
+	Schema     string         `json:"schema,omitempty" yaml:"schema,omitempty"`
+	TableSpace string         `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	Key        []string       `json:"key,omitempty" yaml:"key,omitempty"`
+	Tables     []string       `json:"tables,omitempty" yaml:"tables,omitempty"`
+	Size       int            `json:"size,omitempty" yaml:"size,omitempty"`
+	HashKeys   int            `json:"hash_keys,omitempty" yaml:"hash_keys,omitempty"`
+	Storage    *StorageClause `json:"storage,omitempty" yaml:"storage,omitempty"`
 }
 
 // StorageClause represents storage properties
 type StorageClause struct {
-	Initial     int64
-	Next        int64
-	MinExtents  int
-	MaxExtents  int
-	Pctincrease int
-	Buffer      int
-	TableSpace  string
-	Logging     bool
+	Initial     int64  `json:"initial,omitempty" yaml:"initial,omitempty"`
+	Next        int64  `json:"next,omitempty" yaml:"next,omitempty"`
+	MinExtents  int    `json:"min_extents,omitempty" yaml:"min_extents,omitempty"`
+	MaxExtents  int    `json:"max_extents,omitempty" yaml:"max_extents,omitempty"`
+	Pctincrease int    `json:"pctincrease,omitempty" yaml:"pctincrease,omitempty"`
+	Buffer      int    `json:"buffer,omitempty" yaml:"buffer,omitempty"`
+	TableSpace  string `json:"table_space,omitempty" yaml:"table_space,omitempty"`
+	Logging     bool   `json:"logging,omitempty" yaml:"logging,omitempty"`
 }
 
 // Parser represents an interface for database dump operations
@@ -299,10 +310,19 @@ type Parser interface {
 	Generate(schema *Schema) (string, error)
 }
 
+// LenientParser is implemented by parsers that can skip the statements they
+// fail to parse instead of stopping at the first one
+type LenientParser interface {
+	// ParseLenient parses content like Parse, but records each statement that
+	// fails to parse as a diagnostic and goes on with the next one
+	ParseLenient(content string) (*Schema, []Diagnostic, error)
+}
+
 // Type represents a database type
 type Type struct {
-	Name       string
-	Schema     string
-	Kind       string // ENUM, COMPOSITE, DOMAIN, etc.
-	Definition string
+	Name       string    `json:"name" yaml:"name"`
+	Schema     string    `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Kind       string    `json:"kind,omitempty" yaml:"kind,omitempty"` // ENUM, COMPOSITE, DOMAIN, etc.
+	Definition string    `json:"definition,omitempty" yaml:"definition,omitempty"`
+	Pos        *Position `json:"-" yaml:"-"` // Source location of the defining statement
 }
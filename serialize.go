@@ -0,0 +1,265 @@
+package sqlmapper
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaFormatVersion is the version of the serialized schema document
+// written by MarshalSchemaJSON and MarshalSchemaYAML.
+const SchemaFormatVersion = 1
+
+// SchemaDocument is the versioned envelope a schema is serialized in
+type SchemaDocument struct {
+	Version int     `json:"version" yaml:"version"`
+	Schema  *Schema `json:"schema" yaml:"schema"`
+}
+
+// Tags of the row values JSON and YAML have no type for. They are written as
+// single-key objects such as {"$bytes": "cafe"}.
+const (
+	bytesTag = "$bytes" // []byte as hex
+	timeTag  = "$time"  // time.Time in RFC 3339 with nanoseconds
+	exprTag  = "$expr"  // Expr as its SQL text
+)
+
+// FormatMigration upgrades a decoded document of one format version to the
+// next one. It receives the document as generic JSON values and edits it in place.
+type FormatMigration func(doc map[string]interface{}) error
+
+// formatMigrations holds the migrations indexed by the version they upgrade from.
+// When the document format changes, SchemaFormatVersion is increased and a
+// migration from the previous version is registered here, so documents
+// written by older releases keep loading.
+var formatMigrations = map[int]FormatMigration{}
+
+// MarshalSchemaJSON serializes a schema to an indented, versioned JSON document.
+// Struct fields are written in declaration order and map keys are sorted,
+// so the same schema always produces the same bytes.
+func MarshalSchemaJSON(schema *Schema) ([]byte, error) {
+	if schema == nil {
+		return nil, errors.New("empty schema")
+	}
+
+	data, err := json.MarshalIndent(SchemaDocument{Version: SchemaFormatVersion, Schema: schema}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// UnmarshalSchemaJSON loads a schema from a versioned JSON document.
+// Documents of older format versions are migrated to the current version.
+func UnmarshalSchemaJSON(data []byte) (*Schema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema: %v", err)
+	}
+	return decodeSchemaDocument(doc)
+}
+
+// MarshalSchemaYAML serializes a schema to a versioned YAML document.
+// It uses the same field names and ordering as MarshalSchemaJSON.
+func MarshalSchemaYAML(schema *Schema) ([]byte, error) {
+	if schema == nil {
+		return nil, errors.New("empty schema")
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(SchemaDocument{Version: SchemaFormatVersion, Schema: schema}); err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalSchemaYAML loads a schema from a versioned YAML document.
+// Documents of older format versions are migrated to the current version.
+func UnmarshalSchemaYAML(data []byte) (*Schema, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema: %v", err)
+	}
+	return decodeSchemaDocument(doc)
+}
+
+// decodeSchemaDocument migrates a generic document to the current format
+// version and decodes it into a schema
+func decodeSchemaDocument(doc map[string]interface{}) (*Schema, error) {
+	if doc == nil {
+		return nil, errors.New("empty schema document")
+	}
+
+	version, err := documentVersion(doc["version"])
+	if err != nil {
+		return nil, err
+	}
+	if version > SchemaFormatVersion {
+		return nil, fmt.Errorf("unsupported schema format version %d (latest supported is %d)", version, SchemaFormatVersion)
+	}
+
+	for ; version < SchemaFormatVersion; version++ {
+		migrate, ok := formatMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from schema format version %d", version)
+		}
+		if err := migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate schema format version %d: %v", version, err)
+		}
+	}
+	doc["version"] = SchemaFormatVersion
+
+	// Re-encode the migrated document so both formats share one decoder
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var result SchemaDocument
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema: %v", err)
+	}
+	if result.Schema == nil {
+		return nil, errors.New("schema document has no schema")
+	}
+
+	normalizeSchemaValues(result.Schema)
+	return result.Schema, nil
+}
+
+// documentVersion reads the format version of a decoded document
+func documentVersion(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, errors.New("schema document has no format version")
+	case int:
+		return v, nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid schema format version %q", v.String())
+		}
+		return int(n), nil
+	}
+	return 0, fmt.Errorf("invalid schema format version %v", value)
+}
+
+// normalizeSchemaValues converts the numbers of free-form values, which
+// decode as json.Number, into int64 when they are integral and float64 otherwise
+func normalizeSchemaValues(schema *Schema) {
+	for i := range schema.Tables {
+		for j := range schema.Tables[i].Data {
+			for key, value := range schema.Tables[i].Data[j].Values {
+				schema.Tables[i].Data[j].Values[key] = normalizeValue(value)
+			}
+		}
+	}
+	for i := range schema.UserDefinedTypes {
+		for key, value := range schema.UserDefinedTypes[i].Properties {
+			schema.UserDefinedTypes[i].Properties[key] = normalizeValue(value)
+		}
+	}
+}
+
+// MarshalJSON writes the row values, tagging binary, time and expression values
+func (r Row) MarshalJSON() ([]byte, error) {
+	type row Row
+	return json.Marshal(row{Values: encodeValues(r.Values)})
+}
+
+// MarshalYAML writes the row values, tagging binary, time and expression values
+func (r Row) MarshalYAML() (interface{}, error) {
+	type row Row
+	return row{Values: encodeValues(r.Values)}, nil
+}
+
+// encodeValues returns a copy of row values with the tagged form of the values
+// JSON and YAML have no type for
+func encodeValues(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	encoded := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case []byte:
+			encoded[key] = map[string]string{bytesTag: hex.EncodeToString(v)}
+		case time.Time:
+			encoded[key] = map[string]string{timeTag: v.Format(time.RFC3339Nano)}
+		case Expr:
+			encoded[key] = map[string]string{exprTag: string(v)}
+		default:
+			encoded[key] = value
+		}
+	}
+	return encoded
+}
+
+// decodeTagged decodes a value written by encodeValues
+func decodeTagged(value map[string]interface{}) (interface{}, bool) {
+	if len(value) != 1 {
+		return nil, false
+	}
+	for tag, item := range value {
+		text, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		switch tag {
+		case bytesTag:
+			if data, err := hex.DecodeString(text); err == nil {
+				return data, true
+			}
+		case timeTag:
+			if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+				return t, true
+			}
+		case exprTag:
+			return Expr(text), true
+		}
+	}
+	return nil, false
+}
+
+// normalizeValue converts json.Number values, including nested ones, and
+// decodes tagged values
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil && !math.IsInf(f, 0) {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		if decoded, ok := decodeTagged(v); ok {
+			return decoded
+		}
+		for key, item := range v {
+			v[key] = normalizeValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeValue(item)
+		}
+	}
+	return value
+}
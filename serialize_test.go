@@ -0,0 +1,160 @@
+package sqlmapper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fullSchema() *Schema {
+	storage := &StorageClause{Initial: 65536, Next: 1048576, MinExtents: 1, MaxExtents: 100, Pctincrease: 10, Buffer: 2, TableSpace: "users", Logging: true}
+
+	return &Schema{
+		Name:    "shop",
+		Dialect: Oracle,
+		Tables: []Table{
+			{
+				Name:   "orders",
+				Schema: "sales",
+				Columns: []Column{
+					{Name: "id", DataType: "NUMBER", Length: 10, IsPrimaryKey: true, AutoIncrement: true, Order: 1},
+					{Name: "total", DataType: "NUMBER", Length: 10, Scale: 2, Precision: 10, IsNullable: true, DefaultValue: "0", Comment: "Order total", CheckExpression: "total >= 0", Order: 2},
+				},
+				Indexes: []Index{
+					{Name: "idx_total", Columns: []string{"total"}, IsBitmap: true, Type: "BTREE", Condition: "total > 0", TableSpace: "idx", Storage: storage, Compression: true},
+				},
+				Constraints: []Constraint{
+					{Name: "fk_customer", Type: "FOREIGN KEY", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}, UpdateRule: "CASCADE", DeleteRule: "SET NULL", Deferrable: true, Initially: "DEFERRED"},
+				},
+				Data: []Row{
+					{Values: map[string]interface{}{"id": int64(1), "total": 12.5, "note": "2024-01-01", "flag": true, "missing": nil}},
+					{Values: map[string]interface{}{
+						"id":         int64(2),
+						"receipt":    []byte{0x00, 0xca, 0xfe},
+						"ordered_on": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+						"shipped_at": time.Date(2024, 3, 2, 10, 30, 0, 500, time.UTC),
+						"created_at": Expr("CURRENT_TIMESTAMP"),
+						"raw":        map[string]interface{}{"$expr": "not a string", "other": "x"},
+					}},
+				},
+				TableSpace: "users",
+				Storage:    storage,
+				Temporary:  true,
+				Comment:    "Orders",
+				Options:    "ENGINE=InnoDB",
+			},
+		},
+		Procedures: []Procedure{
+			{Name: "archive", Schema: "sales", Parameters: []Parameter{{Name: "days", DataType: "NUMBER", Direction: "IN", Default: "30"}}, Body: "NULL;", Language: "PLSQL", Security: "DEFINER", SQLSecurity: "INVOKER", Deterministic: true, Comment: "Archive orders"},
+		},
+		Functions: []Function{
+			{Name: "total_of", Parameters: []Parameter{{Name: "id", DataType: "NUMBER"}}, Returns: "NUMBER", Body: "RETURN 1;", Language: "PLSQL"},
+		},
+		Triggers:    []Trigger{{Name: "orders_bi", Table: "orders", Timing: "BEFORE", Event: "INSERT", Body: "NULL;", Condition: "NEW.id IS NULL", ForEachRow: true}},
+		Views:       []View{{Name: "big_orders", Definition: "SELECT * FROM orders WHERE total > 100", IsMaterialized: true}},
+		Sequences:   []Sequence{{Name: "order_seq", IncrementBy: 1, MinValue: 1, MaxValue: 999999, StartValue: 1, Cache: 20, Cycle: true}},
+		Extensions:  []Extension{{Name: "uuid-ossp", Version: "1.1", Schema: "public"}},
+		Permissions: []Permission{{Type: "GRANT", Privileges: []string{"SELECT", "INSERT"}, Object: "orders", Grantee: "app", WithGrant: true}},
+		UserDefinedTypes: []UserDefinedType{
+			{Name: "money_t", BaseType: "NUMBER", Properties: map[string]interface{}{"precision": int64(12), "labels": []interface{}{"a", "b"}}},
+		},
+		Partitions: map[string][]Partition{
+			"orders": {
+				{
+					Name:       "p2024",
+					Type:       "RANGE",
+					Expression: "created_at",
+					Values:     []string{"2025-01-01"},
+					TableSpace: "users",
+					Storage:    storage,
+					SubPartitions: []SubPartition{
+						{Name: "p2024_a", Type: "HASH", Expression: "id", Values: []string{"1"}, TableSpace: "users", Storage: storage},
+					},
+				},
+			},
+		},
+		DatabaseLinks:    []DatabaseLink{{Name: "remote", Owner: "sys", ConnectInfo: "remote_db", Public: true}},
+		Tablespaces:      []Tablespace{{Name: "users", Type: "PERMANENT", Status: "ONLINE", Autoextend: true, MaxSize: 1 << 40, InitialSize: 1 << 20, DataFile: "users01.dbf", BlockSize: 8192, Logging: true}},
+		Roles:            []Role{{Name: "reporting", Password: "secret", Permissions: []Permission{{Type: "GRANT", Privileges: []string{"SELECT"}, Object: "orders", Grantee: "reporting"}}, Members: []string{"alice"}, System: true}},
+		Users:            []User{{Name: "alice", Password: "pw", DefaultRole: "reporting", Roles: []string{"reporting"}, Profile: "default", Status: "OPEN", TableSpace: "users", TempSpace: "temp"}},
+		Clusters:         []Cluster{{Name: "emp_dept", Schema: "hr", TableSpace: "users", Key: []string{"dept_id"}, Tables: []string{"emp", "dept"}, Size: 512, HashKeys: 100, Storage: storage}},
+		MaterializedLogs: []MaterializedViewLog{{Name: "mlog_orders", TableName: "orders", Columns: []string{"total"}, RowID: true, PrimaryKey: true, SequenceNumber: true, CommitSCN: true, Storage: storage}},
+		Types:            []Type{{Name: "status_t", Kind: "ENUM", Definition: "('new', 'done')"}},
+	}
+}
+
+func TestSchema_JSONRoundTrip(t *testing.T) {
+	schema := fullSchema()
+
+	data, err := MarshalSchemaJSON(schema)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "{\n  \"version\": 1,\n  \"schema\": {\n    \"name\": \"shop\""))
+	assert.Contains(t, string(data), `"receipt": {
+                "$bytes": "00cafe"
+              }`)
+	assert.Contains(t, string(data), `"$time": "2024-03-02T10:30:00.0000005Z"`)
+	assert.Contains(t, string(data), `"$expr": "CURRENT_TIMESTAMP"`)
+
+	loaded, err := UnmarshalSchemaJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, schema, loaded)
+
+	// Serialization is deterministic
+	again, err := MarshalSchemaJSON(loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+
+	// Source positions do not end up in snapshots
+	schema.Tables[0].Pos = &Position{Line: 3, Column: 1, Offset: 42}
+	positioned, err := MarshalSchemaJSON(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(positioned))
+}
+
+func TestSchema_YAMLRoundTrip(t *testing.T) {
+	schema := fullSchema()
+
+	data, err := MarshalSchemaYAML(schema)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "version: 1\nschema:\n  name: shop\n"))
+	assert.Contains(t, string(data), "receipt:\n              $bytes: 00cafe\n")
+
+	loaded, err := UnmarshalSchemaYAML(data)
+	assert.NoError(t, err)
+	assert.Equal(t, schema, loaded)
+
+	again, err := MarshalSchemaYAML(loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+
+	schema.Views[0].Pos = &Position{Line: 7, Column: 1, Offset: 120}
+	positioned, err := MarshalSchemaYAML(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(positioned))
+}
+
+func TestSchema_UnmarshalVersions(t *testing.T) {
+	_, err := UnmarshalSchemaJSON([]byte(`{"schema": {"name": "x"}}`))
+	assert.Error(t, err)
+
+	_, err = UnmarshalSchemaJSON([]byte(`{"version": 99, "schema": {"name": "x"}}`))
+	assert.ErrorContains(t, err, "unsupported schema format version 99")
+
+	_, err = UnmarshalSchemaYAML([]byte("version: 0\nschema:\n  name: x\n"))
+	assert.ErrorContains(t, err, "no migration from schema format version 0")
+
+	// Older documents are upgraded through the registered migrations
+	formatMigrations[0] = func(doc map[string]interface{}) error {
+		schema := doc["schema"].(map[string]interface{})
+		schema["name"] = schema["db_name"]
+		delete(schema, "db_name")
+		return nil
+	}
+	t.Cleanup(func() { delete(formatMigrations, 0) })
+
+	loaded, err := UnmarshalSchemaYAML([]byte("version: 0\nschema:\n  db_name: legacy\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy", loaded.Name)
+}
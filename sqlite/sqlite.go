@@ -5,10 +5,10 @@ package sqlite
 import (
 	"bytes"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // SQLite represents a SQLite parser implementation that handles parsing and generating
@@ -28,6 +28,15 @@ func NewSQLite() sqlmapper.Database {
 	}
 }
 
+func init() {
+	sqlmapper.RegisterDialect(sqlmapper.SQLite, sqlmapper.Dialect{
+		NewParser:       func() sqlmapper.Parser { return NewSQLite() },
+		NewStreamParser: func() interface{} { return NewSQLiteStreamParser() },
+		Aliases:         []string{"sqlite3"},
+		IncludeFormat:   ".read %s",
+	})
+}
+
 // Parse takes a SQLite SQL dump content and parses it into a common schema structure.
 // It processes various SQLite objects including:
 // - Tables with columns and constraints
@@ -49,52 +58,111 @@ func (s *SQLite) Parse(content string) (*sqlmapper.Schema, error) {
 	s.buf = bytes.NewBuffer([]byte(content))
 	s.schema = &sqlmapper.Schema{}
 
-	// Split content into statements
-	statements := bytes.Split([]byte(content), []byte(";"))
+	if _, err := tokenizer.ParseStatements(content, tokenizer.SQLite, s.schema, s.parseTokenized, false); err != nil {
+		return nil, err
+	}
 
-	for _, stmt := range statements {
-		stmt = bytes.TrimSpace(stmt)
-		if len(stmt) == 0 {
-			continue
+	s.schema.Dialect = sqlmapper.SQLite
+
+	return s.schema, nil
+}
+
+// ParseLenient parses a SQLite SQL dump like Parse, but skips the statements
+// that fail to parse. Each skipped statement is reported as a diagnostic with
+// its position and the reason it failed.
+//
+// Parameters:
+//   - content: The SQLite SQL dump content to parse
+//
+// Returns:
+//   - *sqlmapper.Schema: The objects of the statements that could be parsed
+//   - []sqlmapper.Diagnostic: The skipped statements
+//   - error: An error if the content is empty
+func (s *SQLite) ParseLenient(content string) (*sqlmapper.Schema, []sqlmapper.Diagnostic, error) {
+	if content == "" {
+		return nil, nil, fmt.Errorf("empty content")
+	}
+
+	s.buf = bytes.NewBuffer([]byte(content))
+	s.schema = &sqlmapper.Schema{}
+
+	diagnostics, err := tokenizer.ParseStatements(content, tokenizer.SQLite, s.schema, s.parseTokenized, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.schema.Dialect = sqlmapper.SQLite
+
+	return s.schema, diagnostics, nil
+}
+
+// parseTokenized parses a single statement into the schema
+func (s *SQLite) parseTokenized(statement tokenizer.Statement) error {
+	stmt := []byte(statement.Text)
+	upperStmt := bytes.ToUpper(stmt)
+
+	switch {
+	case tokenizer.IsInsert(statement):
+		if err := s.parseInsert(statement); err != nil {
+			return err
 		}
 
-		// Skip comments
-		if bytes.HasPrefix(stmt, []byte("--")) || bytes.HasPrefix(stmt, []byte("/*")) {
-			continue
+	case bytes.HasPrefix(upperStmt, []byte("CREATE TABLE")):
+		table, err := s.parseCreateTable(stmt)
+		if err != nil {
+			return fmt.Errorf("error parsing CREATE TABLE: %v", err)
 		}
+		s.schema.Tables = append(s.schema.Tables, table)
 
-		upperStmt := bytes.ToUpper(stmt)
+	case bytes.HasPrefix(upperStmt, []byte("CREATE INDEX")) || bytes.HasPrefix(upperStmt, []byte("CREATE UNIQUE INDEX")):
+		if err := s.parseCreateIndex(stmt); err != nil {
+			return fmt.Errorf("error parsing CREATE INDEX: %v", err)
+		}
 
-		switch {
-		case bytes.HasPrefix(upperStmt, []byte("CREATE TABLE")):
-			table, err := s.parseCreateTable(stmt)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing CREATE TABLE: %v", err)
+	case bytes.HasPrefix(upperStmt, []byte("ALTER TABLE")):
+		// SQLite can only add columns to a table; constraints are part of CREATE TABLE
+		words := bytes.Fields(upperStmt)
+		for i := 3; i+1 < len(words); i++ {
+			if string(words[i]) != "ADD" {
+				continue
 			}
-			s.schema.Tables = append(s.schema.Tables, table)
-
-		case bytes.HasPrefix(upperStmt, []byte("CREATE INDEX")) || bytes.HasPrefix(upperStmt, []byte("CREATE UNIQUE INDEX")):
-			if err := s.parseCreateIndex(stmt); err != nil {
-				return nil, fmt.Errorf("error parsing CREATE INDEX: %v", err)
+			switch string(bytes.TrimLeft(words[i+1], "(")) {
+			case "CONSTRAINT", "PRIMARY", "FOREIGN", "UNIQUE", "CHECK":
+				return fmt.Errorf("ALTER TABLE cannot add constraints in SQLite")
 			}
+		}
 
-		case bytes.HasPrefix(upperStmt, []byte("CREATE VIEW")):
-			view, err := s.parseCreateView(stmt)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing CREATE VIEW: %v", err)
-			}
-			s.schema.Views = append(s.schema.Views, view)
+	case bytes.HasPrefix(upperStmt, []byte("CREATE VIEW")):
+		view, err := s.parseCreateView(stmt)
+		if err != nil {
+			return fmt.Errorf("error parsing CREATE VIEW: %v", err)
+		}
+		s.schema.Views = append(s.schema.Views, view)
 
-		case bytes.HasPrefix(upperStmt, []byte("CREATE TRIGGER")):
-			trigger, err := s.parseCreateTrigger(stmt)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing CREATE TRIGGER: %v", err)
-			}
-			s.schema.Triggers = append(s.schema.Triggers, trigger)
+	case bytes.HasPrefix(upperStmt, []byte("CREATE TRIGGER")):
+		trigger, err := s.parseCreateTrigger(stmt)
+		if err != nil {
+			return fmt.Errorf("error parsing CREATE TRIGGER: %v", err)
 		}
+		s.schema.Triggers = append(s.schema.Triggers, trigger)
 	}
 
-	return s.schema, nil
+	return nil
+}
+
+// parseInsert adds the rows of an INSERT ... VALUES statement to the data of its table.
+func (s *SQLite) parseInsert(stmt tokenizer.Statement) error {
+	insert, err := tokenizer.ParseInsert(stmt, tokenizer.SQLite)
+	if err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	if insert == nil {
+		return nil
+	}
+	if err := sqlmapper.AppendRows(s.schema, insert.Schema, insert.Table, insert.Columns, insert.Rows); err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	return nil
 }
 
 // parseCreateTable parses a CREATE TABLE statement and returns a Table structure.
@@ -195,18 +263,24 @@ func (s *SQLite) parseCreateIndex(stmt []byte) error {
 		return fmt.Errorf("invalid CREATE INDEX statement: missing table name")
 	}
 
+	// The column list may follow the table name without a space: ON users(email)
+	tablePart := parts[tableNamePos]
+	if idx := bytes.IndexByte(tablePart, '('); idx != -1 {
+		tablePart = tablePart[:idx]
+	}
+
 	indexName := string(bytes.Trim(parts[indexNamePos], "`"))
-	tableName := string(bytes.Trim(parts[tableNamePos], "`"))
+	tableName := string(bytes.Trim(tablePart, "`"))
 
 	// Remove schema prefix if exists
-	if idx := bytes.LastIndex(parts[tableNamePos], []byte(".")); idx != -1 {
-		tableName = string(bytes.Trim(parts[tableNamePos][idx+1:], "`"))
+	if idx := bytes.LastIndex(tablePart, []byte(".")); idx != -1 {
+		tableName = string(bytes.Trim(tablePart[idx+1:], "`"))
 	}
 
 	// Extract columns
 	startIdx := bytes.LastIndex(stmt, []byte("("))
 	endIdx := bytes.LastIndex(stmt, []byte(")"))
-	if startIdx == -1 || endIdx == -1 {
+	if startIdx == -1 || endIdx < startIdx {
 		return fmt.Errorf("no columns found in CREATE INDEX statement")
 	}
 
@@ -285,16 +359,17 @@ func (s *SQLite) parseCreateTrigger(stmt []byte) (sqlmapper.Trigger, error) {
 		trigger.Event = "DELETE"
 	}
 
-	// Extract table name
-	if idx := bytes.Index(upperStmt, []byte(" ON ")); idx != -1 {
-		rest := stmt[idx+4:]
-		if spaceIdx := bytes.Index(rest, []byte(" ")); spaceIdx != -1 {
-			tableName := bytes.TrimSpace(rest[:spaceIdx])
+	// Extract table name from the first ON after the trigger name; it may be
+	// followed by a newline as well as a space
+	for i := 3; i+1 < len(parts); i++ {
+		if bytes.EqualFold(parts[i], []byte("ON")) {
+			tableName := parts[i+1]
 			// Remove schema prefix if exists
 			if dotIdx := bytes.LastIndex(tableName, []byte(".")); dotIdx != -1 {
 				tableName = tableName[dotIdx+1:]
 			}
 			trigger.Table = string(bytes.Trim(tableName, "`"))
+			break
 		}
 	}
 
@@ -325,10 +400,28 @@ func (s *SQLite) Generate(schema *sqlmapper.Schema) (string, error) {
 		return "", fmt.Errorf("empty schema")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.SQLite)
+
+	// Order objects so that referenced tables are created first
+	schema = sortSchema(schema)
+
 	s.buf.Reset()
 
 	// Generate tables
 	for i, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		var foreignKeys []string
+		for _, constraint := range table.Constraints {
+			if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+				foreignKeys = append(foreignKeys, s.generateConstraintSQL(constraint))
+			}
+		}
+
 		s.buf.WriteString("CREATE TABLE ")
 		s.buf.WriteString(table.Name)
 		s.buf.WriteString(" (\n")
@@ -360,7 +453,16 @@ func (s *SQLite) Generate(schema *sqlmapper.Schema) (string, error) {
 				}
 			}
 
-			if j < len(table.Columns)-1 {
+			if j < len(table.Columns)-1 || len(foreignKeys) > 0 {
+				s.buf.WriteByte(',')
+			}
+			s.buf.WriteByte('\n')
+		}
+
+		for j, fk := range foreignKeys {
+			s.buf.WriteString("    ")
+			s.buf.WriteString(fk)
+			if j < len(foreignKeys)-1 {
 				s.buf.WriteByte(',')
 			}
 			s.buf.WriteByte('\n')
@@ -388,212 +490,81 @@ func (s *SQLite) Generate(schema *sqlmapper.Schema) (string, error) {
 		}
 	}
 
-	return s.buf.String(), nil
-}
-
-func (s *SQLite) parseTables(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([.\w]+)\s*\((.*?)\)`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 2 {
-		tableName := matches[1]
-		columnDefs := matches[2]
-
-		table := sqlmapper.Table{}
-
-		// Parse schema if exists
-		parts := strings.Split(tableName, ".")
-		if len(parts) > 1 {
-			table.Schema = parts[0]
-			table.Name = parts[1]
-		} else {
-			table.Name = tableName
+	// Add table data
+	for _, table := range schema.Tables {
+		for _, stmt := range s.generateInsertSQL(table) {
+			s.buf.WriteString(stmt + ";\n")
 		}
-
-		// Parse columns and constraints
-		columns := strings.Split(columnDefs, ",")
-		for _, col := range columns {
-			col = strings.TrimSpace(col)
-			if strings.HasPrefix(strings.ToUpper(col), "CONSTRAINT") {
-				continue // Skip constraints for now
-			}
-
-			parts := strings.Fields(col)
-			if len(parts) < 2 {
-				continue
-			}
-
-			column := sqlmapper.Column{
-				Name:       parts[0],
-				DataType:   parts[1],
-				IsNullable: true,
-			}
-
-			// Parse length/precision
-			if strings.Contains(column.DataType, "(") {
-				re := regexp.MustCompile(`(\w+)\((\d+)(?:,(\d+))?\)`)
-				if matches := re.FindStringSubmatch(column.DataType); len(matches) > 2 {
-					column.DataType = matches[1]
-					fmt.Sscanf(matches[2], "%d", &column.Length)
-					if len(matches) > 3 && matches[3] != "" {
-						fmt.Sscanf(matches[3], "%d", &column.Scale)
-					}
-				}
-			}
-
-			// Parse constraints
-			if strings.Contains(strings.ToUpper(col), "NOT NULL") {
-				column.IsNullable = false
-			}
-			if strings.Contains(strings.ToUpper(col), "PRIMARY KEY") {
-				column.IsPrimaryKey = true
-				if strings.Contains(strings.ToUpper(col), "AUTOINCREMENT") {
-					column.AutoIncrement = true
-				}
-			}
-			if strings.Contains(strings.ToUpper(col), "UNIQUE") {
-				column.IsUnique = true
-			}
-			if strings.Contains(strings.ToUpper(col), "DEFAULT") {
-				re := regexp.MustCompile(`DEFAULT\s+([^,\s]+)`)
-				if matches := re.FindStringSubmatch(col); len(matches) > 1 {
-					column.DefaultValue = matches[1]
-				}
-			}
-
-			table.Columns = append(table.Columns, column)
-		}
-
-		s.schema.Tables = append(s.schema.Tables, table)
 	}
 
-	return nil
-}
-
-func (s *SQLite) parseViews(statement string) error {
-	re := regexp.MustCompile(`CREATE(?:\s+TEMP|\s+TEMPORARY)?\s+VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?([.\w]+)\s+AS\s+(.+)$`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 2 {
-		viewName := matches[1]
-		view := sqlmapper.View{
-			Definition: matches[2],
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(viewName, ".")
-		if len(parts) > 1 {
-			view.Schema = parts[0]
-			view.Name = parts[1]
-		} else {
-			view.Name = viewName
-		}
-
-		s.schema.Views = append(s.schema.Views, view)
-	}
-
-	return nil
-}
-
-func (s *SQLite) parseTriggers(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+TRIGGER\s+(?:IF\s+NOT\s+EXISTS\s+)?([.\w]+)\s+(BEFORE|AFTER|INSTEAD\s+OF)\s+(DELETE|INSERT|UPDATE(?:\s+OF\s+[^ON]+)?)\s+ON\s+([.\w]+)(?:\s+FOR\s+EACH\s+ROW)?(?:\s+WHEN\s+([^BEGIN]+))?\s+BEGIN\s+(.*?)\s+END`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 6 {
-		triggerName := matches[1]
-		trigger := sqlmapper.Trigger{
-			Timing:     matches[2],
-			Event:      matches[3],
-			Table:      matches[4],
-			Condition:  matches[5],
-			Body:       matches[6],
-			ForEachRow: strings.Contains(statement, "FOR EACH ROW"),
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(triggerName, ".")
-		if len(parts) > 1 {
-			trigger.Schema = parts[0]
-			trigger.Name = parts[1]
-		} else {
-			trigger.Name = triggerName
-		}
-
-		s.schema.Triggers = append(s.schema.Triggers, trigger)
-	}
-
-	return nil
+	return s.buf.String(), nil
 }
 
-func (s *SQLite) parseIndexes(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:IF\s+NOT\s+EXISTS\s+)?([.\w]+)\s+ON\s+([.\w]+)\s*\((.*?)\)`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 3 {
-		indexName := matches[1]
-		tableName := matches[2]
-		columns := strings.Split(matches[3], ",")
-
-		// Find the table
-		for i, table := range s.schema.Tables {
-			if table.Name == tableName || fmt.Sprintf("%s.%s", table.Schema, table.Name) == tableName {
-				index := sqlmapper.Index{
-					Name:     indexName,
-					Columns:  make([]string, len(columns)),
-					IsUnique: strings.Contains(statement, "UNIQUE"),
-				}
-
-				// Clean column names
-				for j, col := range columns {
-					index.Columns[j] = strings.TrimSpace(col)
-				}
-
-				s.schema.Tables[i].Indexes = append(s.schema.Tables[i].Indexes, index)
+// sortSchema orders the schema objects by their dependencies. SQLite cannot
+// add constraints to an existing table, but it resolves foreign keys lazily,
+// so foreign keys that close a reference cycle stay in their table definition.
+func sortSchema(schema *sqlmapper.Schema) *sqlmapper.Schema {
+	sorted, deferred := sqlmapper.SortSchema(schema)
+	for _, fk := range deferred {
+		for i := range sorted.Tables {
+			if sorted.Tables[i].Name == fk.Table {
+				sorted.Tables[i].Constraints = append(sorted.Tables[i].Constraints, fk.Constraint)
 				break
 			}
 		}
 	}
-
-	return nil
+	return sorted
 }
 
 // generateTableSQL generates SQL for a table
 func (s *SQLite) generateTableSQL(table sqlmapper.Table) string {
 	sql := "CREATE TABLE " + table.Name + " (\n"
 
-	// Generate columns
-	for i, col := range table.Columns {
-		sql += "    " + col.Name + " " + col.DataType
-		if col.Length > 0 {
-			sql += fmt.Sprintf("(%d", col.Length)
-			if col.Scale > 0 {
-				sql += fmt.Sprintf(",%d", col.Scale)
-			}
-			sql += ")"
+	// Generate columns and foreign keys
+	defs := make([]string, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		defs = append(defs, s.generateColumnSQL(col))
+	}
+	for _, constraint := range table.Constraints {
+		if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+			defs = append(defs, s.generateConstraintSQL(constraint))
 		}
+	}
+	if len(defs) > 0 {
+		sql += "    " + strings.Join(defs, ",\n    ")
+	}
 
-		if col.IsPrimaryKey {
-			sql += " PRIMARY KEY"
-			if col.AutoIncrement {
-				sql += " AUTOINCREMENT"
-			}
-		}
-		if !col.IsNullable {
-			sql += " NOT NULL"
-		}
-		if col.IsUnique {
-			sql += " UNIQUE"
-		}
-		if col.DefaultValue != "" {
-			sql += " DEFAULT " + col.DefaultValue
-		}
+	sql += "\n)"
+
+	return sql
+}
 
-		if i < len(table.Columns)-1 {
-			sql += ",\n"
+// generateColumnSQL generates SQL for a column definition
+func (s *SQLite) generateColumnSQL(col sqlmapper.Column) string {
+	sql := col.Name + " " + col.DataType
+	if col.Length > 0 {
+		sql += fmt.Sprintf("(%d", col.Length)
+		if col.Scale > 0 {
+			sql += fmt.Sprintf(",%d", col.Scale)
 		}
+		sql += ")"
 	}
 
-	sql += "\n)"
+	if col.IsPrimaryKey {
+		sql += " PRIMARY KEY"
+		if col.AutoIncrement {
+			sql += " AUTOINCREMENT"
+		}
+	}
+	if !col.IsNullable {
+		sql += " NOT NULL"
+	}
+	if col.IsUnique {
+		sql += " UNIQUE"
+	}
+	if col.DefaultValue != "" {
+		sql += " DEFAULT " + col.DefaultValue
+	}
 
 	return sql
 }
@@ -611,3 +582,22 @@ func (s *SQLite) generateIndexSQL(tableName string, index sqlmapper.Index) strin
 
 	return sql
 }
+
+// insertBatchSize is the maximum number of rows per generated INSERT statement
+const insertBatchSize = 100
+
+// formatValue encodes a data value as a SQLite literal. Booleans are stored as 1 and 0.
+func (s *SQLite) formatValue(value interface{}) string {
+	if b, ok := value.(bool); ok {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	return sqlmapper.FormatLiteral(value)
+}
+
+// generateInsertSQL creates the INSERT statements for the data of a table
+func (s *SQLite) generateInsertSQL(table sqlmapper.Table) []string {
+	return sqlmapper.InsertStatements(table, insertBatchSize, s.formatValue)
+}
@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// GenerateMigration renders a list of schema changes, as produced by sqlmapper.Diff,
+// into a SQLite migration script. Changes are rendered in the given order, and data types
+// of changes diffed from schemas of another dialect are translated to SQLite.
+// Changes SQLite cannot apply with ALTER TABLE, such as column modifications
+// and constraint changes, are emitted as comments since they require the
+// table to be rebuilt.
+//
+// Parameters:
+//   - changes: The ordered schema changes to render
+//
+// Returns:
+//   - string: The generated SQLite migration statements
+//   - error: An error if a change carries an unexpected definition
+func (s *SQLite) GenerateMigration(changes []sqlmapper.Change) (string, error) {
+	changes = sqlmapper.ConvertChanges(changes, sqlmapper.SQLite)
+	var result strings.Builder
+
+	// Constraints added to tables created by this migration are declared inline
+	created := make(map[string]bool)
+	inline := make(map[string][]sqlmapper.Constraint)
+	for _, change := range changes {
+		if change.Type == sqlmapper.CreateTable {
+			created[strings.ToLower(change.Name)] = true
+		}
+	}
+	for _, change := range changes {
+		if constraint, ok := change.New.(sqlmapper.Constraint); ok && change.Type == sqlmapper.AddConstraint && created[strings.ToLower(change.Table)] {
+			inline[strings.ToLower(change.Table)] = append(inline[strings.ToLower(change.Table)], constraint)
+		}
+	}
+
+	for _, change := range changes {
+		if change.Type == sqlmapper.AddConstraint && created[strings.ToLower(change.Table)] {
+			continue
+		}
+		stmt, err := s.generateChangeSQL(change, inline[strings.ToLower(change.Table)])
+		if err != nil {
+			return "", err
+		}
+		if stmt == "" {
+			continue
+		}
+		result.WriteString(stmt)
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// generateChangeSQL renders a single schema change. Constraints are declared
+// inline when the change creates a table.
+func (s *SQLite) generateChangeSQL(change sqlmapper.Change, constraints []sqlmapper.Constraint) (string, error) {
+	switch change.Type {
+	case sqlmapper.CreateTable:
+		table, ok := change.New.(sqlmapper.Table)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return s.generateCreateTableSQL(table, constraints), nil
+
+	case sqlmapper.DropTable:
+		return fmt.Sprintf("DROP TABLE %s;", change.Name), nil
+
+	case sqlmapper.AddColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", change.Table, s.generateColumnSQL(column)), nil
+
+	case sqlmapper.DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", change.Table, change.Name), nil
+
+	case sqlmapper.ModifyColumn:
+		return fmt.Sprintf("-- SQLite cannot modify column %s.%s; the table must be rebuilt", change.Table, change.Name), nil
+
+	case sqlmapper.AddIndex:
+		index, ok := change.New.(sqlmapper.Index)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return s.generateIndexSQL(change.Table, index) + ";", nil
+
+	case sqlmapper.DropIndex:
+		if change.Name == "" {
+			return fmt.Sprintf("-- Unnamed index on %s must be dropped manually", change.Table), nil
+		}
+		return fmt.Sprintf("DROP INDEX %s;", change.Name), nil
+
+	case sqlmapper.AddConstraint, sqlmapper.DropConstraint:
+		constraint, _ := change.New.(sqlmapper.Constraint)
+		if change.Type == sqlmapper.DropConstraint {
+			constraint, _ = change.Old.(sqlmapper.Constraint)
+		}
+		return fmt.Sprintf("-- SQLite cannot alter %s constraint %s on %s; the table must be rebuilt",
+			constraint.Type, change.Name, change.Table), nil
+
+	case sqlmapper.CreateView:
+		view, ok := change.New.(sqlmapper.View)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("CREATE VIEW %s AS %s;", view.Name, view.Definition), nil
+
+	case sqlmapper.ModifyView:
+		view, ok := change.New.(sqlmapper.View)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("DROP VIEW %s;\nCREATE VIEW %s AS %s;", change.Name, view.Name, view.Definition), nil
+
+	case sqlmapper.DropView:
+		return fmt.Sprintf("DROP VIEW %s;", change.Name), nil
+
+	case sqlmapper.CreateSequence, sqlmapper.ModifySequence, sqlmapper.DropSequence:
+		return fmt.Sprintf("-- Sequences are not supported by SQLite: %s %s", change.Type, change.Name), nil
+
+	case sqlmapper.CreateTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return s.generateTriggerSQL(trigger), nil
+
+	case sqlmapper.ModifyTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("DROP TRIGGER %s;\n%s", change.Name, s.generateTriggerSQL(trigger)), nil
+
+	case sqlmapper.DropTrigger:
+		return fmt.Sprintf("DROP TRIGGER %s;", change.Name), nil
+
+	case sqlmapper.CreateFunction, sqlmapper.ModifyFunction, sqlmapper.DropFunction:
+		return fmt.Sprintf("-- Stored routines are not supported by SQLite: %s %s", change.Type, change.Name), nil
+	}
+
+	return "", errors.New("unsupported change type: " + string(change.Type))
+}
+
+// generateCreateTableSQL generates SQL for a table and its indexes, declaring the
+// given constraints inline since SQLite cannot add constraints to an existing table
+func (s *SQLite) generateCreateTableSQL(table sqlmapper.Table, constraints []sqlmapper.Constraint) string {
+	sql := s.generateTableSQL(table)
+	if len(constraints) > 0 {
+		clauses := make([]string, len(constraints))
+		for i, constraint := range constraints {
+			clauses[i] = s.generateConstraintSQL(constraint)
+		}
+		sql = strings.TrimSuffix(sql, "\n)") + ",\n    " + strings.Join(clauses, ",\n    ") + "\n)"
+	}
+
+	stmts := []string{sql + ";"}
+	for _, index := range table.Indexes {
+		stmts = append(stmts, s.generateIndexSQL(table.Name, index)+";")
+	}
+	return strings.Join(stmts, "\n")
+}
+
+// generateConstraintSQL generates SQL for a table constraint clause
+func (s *SQLite) generateConstraintSQL(constraint sqlmapper.Constraint) string {
+	sql := ""
+	if constraint.Name != "" {
+		sql = "CONSTRAINT " + constraint.Name + " "
+	}
+
+	if strings.ToUpper(constraint.Type) == "CHECK" {
+		return sql + "CHECK (" + constraint.CheckExpression + ")"
+	}
+
+	sql += constraint.Type + " (" + strings.Join(constraint.Columns, ", ") + ")"
+	if constraint.RefTable != "" {
+		sql += " REFERENCES " + constraint.RefTable + " (" + strings.Join(constraint.RefColumns, ", ") + ")"
+		if constraint.DeleteRule != "" {
+			sql += " ON DELETE " + constraint.DeleteRule
+		}
+		if constraint.UpdateRule != "" {
+			sql += " ON UPDATE " + constraint.UpdateRule
+		}
+	}
+
+	return sql
+}
+
+// generateTriggerSQL generates SQL for a trigger
+func (s *SQLite) generateTriggerSQL(trigger sqlmapper.Trigger) string {
+	sql := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s", trigger.Name, trigger.Timing, trigger.Event, trigger.Table)
+	if trigger.ForEachRow {
+		sql += " FOR EACH ROW"
+	}
+	if trigger.Condition != "" {
+		sql += " WHEN " + trigger.Condition
+	}
+
+	body := strings.TrimSpace(trigger.Body)
+	if !strings.HasSuffix(body, ";") {
+		body += ";"
+	}
+	return sql + " BEGIN " + body + " END;"
+}
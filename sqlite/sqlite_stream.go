@@ -1,13 +1,13 @@
 package sqlite
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"strings"
-	"sync"
 
 	"github.com/mstgnz/sqlmapper"
 	"github.com/mstgnz/sqlmapper/stream"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // SQLiteStreamParser implements the StreamParser interface for SQLite
@@ -24,211 +24,68 @@ func NewSQLiteStreamParser() *SQLiteStreamParser {
 
 // ParseStream implements the StreamParser interface
 func (p *SQLiteStreamParser) ParseStream(reader io.Reader, callback func(stream.SchemaObject) error) error {
-	streamReader := stream.NewStreamReader(reader, ";")
-
-	for {
-		statement, err := streamReader.ReadStatement()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading statement: %v", err)
-		}
-
-		statement = strings.TrimSpace(statement)
-		if statement == "" {
-			continue
-		}
-
-		// Parse CREATE TABLE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TABLE") {
-			table, err := p.parseTableStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TableObject,
-				Data: table,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE VIEW statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE VIEW") {
-			view, err := p.parseViewStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ViewObject,
-				Data: view,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE INDEX statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE UNIQUE INDEX") {
-			index, err := p.parseIndexStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.IndexObject,
-				Data: index,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE TRIGGER statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TRIGGER") {
-			trigger, err := p.parseTriggerStatement(statement)
-			if err != nil {
-				return err
-			}
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{})
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.TriggerObject,
-				Data: trigger,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-	}
+// ParseStreamLenient implements the stream.LenientStreamParser interface
+func (p *SQLiteStreamParser) ParseStreamLenient(reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{Lenient: true})
+}
 
-	return nil
+// ParseStreamOptions implements the stream.OptionsStreamParser interface
+func (p *SQLiteStreamParser) ParseStreamOptions(reader io.Reader, callback func(stream.SchemaObject) error, opts stream.ParseOptions) error {
+	return stream.ParseStatements(reader, tokenizer.SQLite, p.parseStatement, callback, opts)
 }
 
-// ParseStreamParallel implements parallel processing for SQLite stream parsing
+// ParseStreamParallel implements parallel processing for SQLite stream parsing.
+// Objects are delivered in input order; each worker parses with its own parser.
 func (p *SQLiteStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
-	streamReader := stream.NewStreamReader(reader, ";")
-	statements := make(chan string, workers)
-	results := make(chan stream.SchemaObject, workers)
-	errors := make(chan error, workers)
-	var wg sync.WaitGroup
-
-	// Start worker goroutines
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for statement := range statements {
-				obj, err := p.parseStatement(statement)
-				if err != nil {
-					errors <- err
-					return
-				}
-				if obj != nil {
-					results <- *obj
-				}
-			}
-		}()
-	}
-
-	// Start a goroutine to close results channel after all workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Start a goroutine to read statements and send them to workers
-	go func() {
-		for {
-			statement, err := streamReader.ReadStatement()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				errors <- fmt.Errorf("error reading statement: %v", err)
-				break
-			}
+	return stream.ParseStreamParallel(reader, tokenizer.SQLite, func() stream.ParseFunc {
+		return NewSQLiteStreamParser().parseStatement
+	}, callback, stream.ParallelOptions{Workers: workers})
+}
 
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
-				continue
-			}
-			statements <- statement
-		}
-		close(statements)
-	}()
+// ParseStreamContext implements the StreamParser interface
+func (p *SQLiteStreamParser) ParseStreamContext(ctx context.Context, reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return stream.ParseContext(ctx, reader, callback, p.ParseStream)
+}
 
-	// Process results and handle errors
-	for obj := range results {
-		if err := callback(obj); err != nil {
-			return err
-		}
-	}
+// GenerateStreamContext implements the StreamParser interface
+func (p *SQLiteStreamParser) GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error {
+	return stream.GenerateContext(ctx, schema, writer, p.GenerateStream)
+}
 
-	// Check for any errors from workers
-	select {
-	case err := <-errors:
-		return err
+// GenerateObject implements the StreamParser interface
+func (p *SQLiteStreamParser) GenerateObject(obj stream.SchemaObject, writer io.Writer) error {
+	var stmt string
+	switch data := obj.Data.(type) {
+	case *sqlmapper.Index:
+		stmt = p.sqlite.generateIndexSQL(obj.Table, *data)
+	case *sqlmapper.Constraint:
+		stmt = fmt.Sprintf("ALTER TABLE %s ADD %s", obj.Table, p.sqlite.generateConstraintSQL(*data))
+	case *stream.Comment:
+		stmt = data.LineComment(obj.Table, "Comments are not supported by SQLite")
+	case *stream.Unparsed:
+		stmt = data.SQL
 	default:
-		return nil
+		return p.GenerateStream(stream.ObjectSchema(obj), writer)
 	}
-}
-
-// parseStatement parses a single SQL statement and returns a SchemaObject
-func (p *SQLiteStreamParser) parseStatement(statement string) (*stream.SchemaObject, error) {
-	upperStatement := strings.ToUpper(statement)
-
-	switch {
-	case strings.HasPrefix(upperStatement, "CREATE TABLE"):
-		table, err := p.parseTableStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TableObject,
-			Data: table,
-		}, nil
 
-	case strings.HasPrefix(upperStatement, "CREATE VIEW"):
-		view, err := p.parseViewStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ViewObject,
-			Data: view,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE INDEX"):
-		index, err := p.parseIndexStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.IndexObject,
-			Data: index,
-		}, nil
+	_, err := writer.Write([]byte(stmt + ";\n\n"))
+	return err
+}
 
-	case strings.HasPrefix(upperStatement, "CREATE TRIGGER"):
-		trigger, err := p.parseTriggerStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TriggerObject,
-			Data: trigger,
-		}, nil
-	}
+// Dialect implements the StreamParser interface
+func (p *SQLiteStreamParser) Dialect() sqlmapper.DatabaseType {
+	return sqlmapper.SQLite
+}
 
-	return nil, nil
+// parseStatement parses a single SQL statement with the batch parser and
+// returns the objects it defines
+func (p *SQLiteStreamParser) parseStatement(stmt tokenizer.Statement) ([]stream.SchemaObject, error) {
+	return stream.StatementObjects(stmt.Text, func(schema *sqlmapper.Schema) error {
+		return (&SQLite{schema: schema}).parseTokenized(stmt)
+	})
 }
 
 // GenerateStream implements the StreamParser interface
@@ -237,8 +94,19 @@ func (p *SQLiteStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.
 		return fmt.Errorf("schema cannot be nil")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.SQLite)
+
+	// Order objects so that referenced tables are created first
+	schema = sortSchema(schema)
+
 	// Write tables
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
 		stmt := p.sqlite.generateTableSQL(table)
 		if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
 			return err
@@ -253,6 +121,15 @@ func (p *SQLiteStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.
 		}
 	}
 
+	// Write table data
+	for _, table := range schema.Tables {
+		for _, stmt := range p.sqlite.generateInsertSQL(table) {
+			if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Write views
 	for _, view := range schema.Views {
 		stmt := fmt.Sprintf("CREATE VIEW %s AS %s", view.Name, view.Definition)
@@ -279,67 +156,3 @@ func (p *SQLiteStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.
 
 	return nil
 }
-
-// parseTableStatement parses a CREATE TABLE statement
-func (p *SQLiteStreamParser) parseTableStatement(statement string) (*sqlmapper.Table, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlite.schema = tempSchema
-
-	if err := p.sqlite.parseTables(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Tables) == 0 {
-		return nil, fmt.Errorf("no table found in statement")
-	}
-
-	return &tempSchema.Tables[0], nil
-}
-
-// parseViewStatement parses a CREATE VIEW statement
-func (p *SQLiteStreamParser) parseViewStatement(statement string) (*sqlmapper.View, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlite.schema = tempSchema
-
-	if err := p.sqlite.parseViews(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Views) == 0 {
-		return nil, fmt.Errorf("no view found in statement")
-	}
-
-	return &tempSchema.Views[0], nil
-}
-
-// parseIndexStatement parses a CREATE INDEX statement
-func (p *SQLiteStreamParser) parseIndexStatement(statement string) (*sqlmapper.Index, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlite.schema = tempSchema
-
-	if err := p.sqlite.parseIndexes(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Tables) == 0 || len(tempSchema.Tables[0].Indexes) == 0 {
-		return nil, fmt.Errorf("no index found in statement")
-	}
-
-	return &tempSchema.Tables[0].Indexes[0], nil
-}
-
-// parseTriggerStatement parses a CREATE TRIGGER statement
-func (p *SQLiteStreamParser) parseTriggerStatement(statement string) (*sqlmapper.Trigger, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlite.schema = tempSchema
-
-	if err := p.sqlite.parseTriggers(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Triggers) == 0 {
-		return nil, fmt.Errorf("no trigger found in statement")
-	}
-
-	return &tempSchema.Triggers[0], nil
-}
@@ -70,6 +70,20 @@ func TestSQLite_Parse(t *testing.T) {
 				// Additional validation logic for triggers can be added here
 			},
 		},
+		{
+			name: "Semicolons inside literals and comments",
+			content: `-- users; accounts
+CREATE TABLE test (id INTEGER PRIMARY KEY, [note;text] TEXT DEFAULT 'a;b');
+/* CREATE TABLE ignored (id INTEGER); */
+CREATE TRIGGER trg AFTER INSERT ON test BEGIN UPDATE test SET name = 'x'; DELETE FROM log; END;
+CREATE INDEX idx_note ON test (id);`,
+			wantErr: false,
+			validate: func(t *testing.T, schema *sqlmapper.Schema) {
+				assert.Len(t, schema.Tables, 1)
+				assert.Len(t, schema.Triggers, 1)
+				assert.Contains(t, schema.Triggers[0].Body, "DELETE FROM log")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -172,3 +186,105 @@ func TestSQLite_Generate_ComplexSchema(t *testing.T) {
 	_, err := s.Generate(schema)
 	assert.NoError(t, err)
 }
+
+func TestSQLite_GenerateMigration(t *testing.T) {
+	oldSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "name", DataType: "TEXT"},
+				},
+			},
+		},
+	}
+
+	newSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "name", DataType: "TEXT", IsNullable: true},
+					{Name: "email", DataType: "TEXT", IsNullable: true},
+				},
+			},
+			{
+				Name: "posts",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "user_id", DataType: "INTEGER"},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "fk_user", Type: "FOREIGN KEY", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, DeleteRule: "CASCADE"},
+				},
+				Indexes: []sqlmapper.Index{
+					{Name: "idx_posts_user", Columns: []string{"user_id"}},
+				},
+			},
+		},
+		Triggers: []sqlmapper.Trigger{
+			{Name: "trg_posts", Table: "posts", Timing: "AFTER", Event: "DELETE", Body: "DELETE FROM logs WHERE post_id = OLD.id"},
+		},
+	}
+
+	s := &SQLite{}
+	got, err := s.GenerateMigration(sqlmapper.Diff(oldSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+CREATE TABLE posts (
+    id INTEGER PRIMARY KEY NOT NULL,
+    user_id INTEGER NOT NULL,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_posts_user ON posts (user_id);
+ALTER TABLE users ADD COLUMN email TEXT;
+-- SQLite cannot modify column users.name; the table must be rebuilt
+CREATE TRIGGER trg_posts AFTER DELETE ON posts BEGIN DELETE FROM logs WHERE post_id = OLD.id; END;`), strings.TrimSpace(got))
+}
+
+func TestSQLite_Generate_ForeignKeyCycle(t *testing.T) {
+	schema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "a",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "b_id", DataType: "INTEGER", IsNullable: true},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Type: "FOREIGN KEY", Columns: []string{"b_id"}, RefTable: "b", RefColumns: []string{"id"}},
+				},
+			},
+			{
+				Name: "b",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+					{Name: "a_id", DataType: "INTEGER", IsNullable: true},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Type: "FOREIGN KEY", Columns: []string{"a_id"}, RefTable: "a", RefColumns: []string{"id"}},
+				},
+			},
+		},
+	}
+
+	// SQLite cannot add constraints later, so cyclic foreign keys stay inline
+	s := NewSQLite()
+	got, err := s.Generate(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+CREATE TABLE a (
+    id INTEGER PRIMARY KEY,
+    b_id INTEGER,
+    FOREIGN KEY (b_id) REFERENCES b (id)
+);
+
+CREATE TABLE b (
+    id INTEGER PRIMARY KEY,
+    a_id INTEGER,
+    FOREIGN KEY (a_id) REFERENCES a (id)
+);`), strings.TrimSpace(got))
+	assert.NotContains(t, got, "ALTER TABLE")
+}
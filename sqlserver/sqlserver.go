@@ -4,12 +4,16 @@ package sqlserver
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // SQLServer represents a SQL Server parser implementation that handles parsing and generating
@@ -29,6 +33,15 @@ func NewSQLServer() sqlmapper.Database {
 	}
 }
 
+func init() {
+	sqlmapper.RegisterDialect(sqlmapper.SQLServer, sqlmapper.Dialect{
+		NewParser:       func() sqlmapper.Parser { return NewSQLServer() },
+		NewStreamParser: func() interface{} { return NewSQLServerStreamParser() },
+		Aliases:         []string{"mssql"},
+		IncludeFormat:   ":r %s",
+	})
+}
+
 // Parse takes a SQL Server SQL dump content and parses it into a common schema structure.
 // It processes various SQL Server objects including:
 // - Tables with columns and constraints
@@ -48,84 +61,102 @@ func (s *SQLServer) Parse(content string) (*sqlmapper.Schema, error) {
 		return nil, errors.New("empty content")
 	}
 
-	// Convert content to bytes
-	contentBytes := []byte(content)
+	// GO batch separators and semicolons inside literals, comments and
+	// routine bodies are handled by the tokenizer
+	if _, err := tokenizer.ParseStatements(content, tokenizer.SQLServer, s.schema, s.parseTokenized, false); err != nil {
+		return nil, err
+	}
 
-	// Split content into statements
-	statements := s.splitStatements(contentBytes)
+	s.schema.Dialect = sqlmapper.SQLServer
 
-	for _, stmt := range statements {
-		stmt = bytes.TrimSpace(stmt)
-		if len(stmt) == 0 {
-			continue
-		}
+	return s.schema, nil
+}
 
-		upperStmt := bytes.ToUpper(stmt)
+// ParseLenient parses a SQLServer SQL dump like Parse, but skips the statements
+// that fail to parse. Each skipped statement is reported as a diagnostic with
+// its position and the reason it failed.
+//
+// Parameters:
+//   - content: The SQLServer SQL dump content to parse
+//
+// Returns:
+//   - *sqlmapper.Schema: The objects of the statements that could be parsed
+//   - []sqlmapper.Diagnostic: The skipped statements
+//   - error: An error if the content is empty
+func (s *SQLServer) ParseLenient(content string) (*sqlmapper.Schema, []sqlmapper.Diagnostic, error) {
+	if content == "" {
+		return nil, nil, errors.New("empty content")
+	}
 
-		switch {
-		case bytes.HasPrefix(upperStmt, []byte("CREATE TABLE")):
-			table, err := s.parseCreateTable(stmt)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing CREATE TABLE: %v", err)
-			}
-			s.schema.Tables = append(s.schema.Tables, table)
+	diagnostics, err := tokenizer.ParseStatements(content, tokenizer.SQLServer, s.schema, s.parseTokenized, true)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		case bytes.HasPrefix(upperStmt, []byte("CREATE INDEX")) || bytes.HasPrefix(upperStmt, []byte("CREATE UNIQUE INDEX")):
-			if err := s.parseCreateIndex(stmt); err != nil {
-				return nil, fmt.Errorf("error parsing CREATE INDEX: %v", err)
-			}
+	s.schema.Dialect = sqlmapper.SQLServer
 
-		case bytes.HasPrefix(upperStmt, []byte("ALTER TABLE")):
-			if err := s.parseAlterTable(stmt); err != nil {
-				return nil, fmt.Errorf("error parsing ALTER TABLE: %v", err)
-			}
+	return s.schema, diagnostics, nil
+}
 
-		case bytes.HasPrefix(upperStmt, []byte("CREATE VIEW")):
-			view, err := s.parseCreateView(stmt)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing CREATE VIEW: %v", err)
-			}
-			s.schema.Views = append(s.schema.Views, view)
+// parseTokenized parses a single statement into the schema
+func (s *SQLServer) parseTokenized(statement tokenizer.Statement) error {
+	stmt := []byte(statement.Text)
+	upperStmt := bytes.ToUpper(stmt)
 
-		case bytes.HasPrefix(upperStmt, []byte("CREATE TRIGGER")):
-			trigger, err := s.parseCreateTrigger(stmt)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing CREATE TRIGGER: %v", err)
-			}
-			s.schema.Triggers = append(s.schema.Triggers, trigger)
+	switch {
+	case tokenizer.IsInsert(statement):
+		if err := s.parseInsert(statement); err != nil {
+			return err
 		}
-	}
 
-	return s.schema, nil
-}
+	case bytes.HasPrefix(upperStmt, []byte("CREATE TABLE")):
+		table, err := s.parseCreateTable(stmt)
+		if err != nil {
+			return fmt.Errorf("error parsing CREATE TABLE: %v", err)
+		}
+		s.schema.Tables = append(s.schema.Tables, table)
 
-// splitStatements splits the SQL content into individual statements.
-// It handles both semicolon and GO statement terminators.
-func (s *SQLServer) splitStatements(content []byte) [][]byte {
-	var statements [][]byte
-	s.buf.Reset()
+	case bytes.HasPrefix(upperStmt, []byte("CREATE INDEX")) || bytes.HasPrefix(upperStmt, []byte("CREATE UNIQUE INDEX")):
+		if err := s.parseCreateIndex(stmt); err != nil {
+			return fmt.Errorf("error parsing CREATE INDEX: %v", err)
+		}
 
-	// First split by GO statements
-	goBlocks := bytes.Split(content, []byte("GO"))
+	case bytes.HasPrefix(upperStmt, []byte("ALTER TABLE")):
+		if err := s.parseAlterTable(stmt); err != nil {
+			return fmt.Errorf("error parsing ALTER TABLE: %v", err)
+		}
 
-	for _, block := range goBlocks {
-		block = bytes.TrimSpace(block)
-		if len(block) == 0 {
-			continue
+	case bytes.HasPrefix(upperStmt, []byte("CREATE VIEW")):
+		view, err := s.parseCreateView(stmt)
+		if err != nil {
+			return fmt.Errorf("error parsing CREATE VIEW: %v", err)
 		}
+		s.schema.Views = append(s.schema.Views, view)
 
-		// Then split each GO block by semicolons
-		stmts := bytes.Split(block, []byte(";"))
-		for _, stmt := range stmts {
-			stmt = bytes.TrimSpace(stmt)
-			if len(stmt) == 0 || bytes.HasPrefix(stmt, []byte("--")) {
-				continue
-			}
-			statements = append(statements, stmt)
+	case bytes.HasPrefix(upperStmt, []byte("CREATE TRIGGER")):
+		trigger, err := s.parseCreateTrigger(stmt)
+		if err != nil {
+			return fmt.Errorf("error parsing CREATE TRIGGER: %v", err)
 		}
+		s.schema.Triggers = append(s.schema.Triggers, trigger)
 	}
 
-	return statements
+	return nil
+}
+
+// parseInsert adds the rows of an INSERT ... VALUES statement to the data of its table.
+func (s *SQLServer) parseInsert(stmt tokenizer.Statement) error {
+	insert, err := tokenizer.ParseInsert(stmt, tokenizer.SQLServer)
+	if err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	if insert == nil {
+		return nil
+	}
+	if err := sqlmapper.AppendRows(s.schema, insert.Schema, insert.Table, insert.Columns, insert.Rows); err != nil {
+		return fmt.Errorf("error parsing INSERT: %v", err)
+	}
+	return nil
 }
 
 // parseCreateTable parses a CREATE TABLE statement and returns a Table structure.
@@ -149,8 +180,11 @@ func (s *SQLServer) parseCreateTable(stmt []byte) (sqlmapper.Table, error) {
 	table.Name = string(tableName)
 
 	// Extract column definitions
-	columnsBytes := stmt[startIdx+endIdx+1 : bytes.LastIndex(stmt, []byte(")"))]
-	columnDefs := bytes.Split(columnsBytes, []byte(","))
+	closeIdx := bytes.LastIndex(stmt, []byte(")"))
+	if closeIdx < startIdx+endIdx {
+		return table, fmt.Errorf("invalid CREATE TABLE statement: no column list")
+	}
+	columnDefs := s.splitDefinitions(stmt[startIdx+endIdx+1 : closeIdx])
 
 	for _, colDef := range columnDefs {
 		colDef = bytes.TrimSpace(colDef)
@@ -273,7 +307,7 @@ func (s *SQLServer) parseConstraint(def []byte) sqlmapper.Constraint {
 			startIdx := bytes.Index(refPart, []byte("("))
 			endIdx := bytes.Index(refPart, []byte(")"))
 			if startIdx != -1 && endIdx != -1 {
-				tableName := bytes.TrimSpace(refPart[9:startIdx])
+				tableName := bytes.TrimSpace(refPart[len("REFERENCES"):startIdx])
 				// Remove schema prefix and brackets
 				if idx := bytes.LastIndex(tableName, []byte(".")); idx != -1 {
 					tableName = tableName[idx+1:]
@@ -327,6 +361,28 @@ func (s *SQLServer) extractColumns(def []byte, afterKeyword string) []string {
 }
 
 // splitAndTrim splits a string by commas and trims whitespace and brackets from each part.
+// splitDefinitions splits the column list of CREATE TABLE at the commas that
+// are outside parentheses and string literals, such as those of IDENTITY(1,1)
+func (s *SQLServer) splitDefinitions(list []byte) [][]byte {
+	var defs [][]byte
+	depth, start, quoted := 0, 0, false
+	for i, c := range list {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			defs = append(defs, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(defs, list[start:])
+}
+
 func (s *SQLServer) splitAndTrim(str string) []string {
 	parts := strings.Split(str, ",")
 	result := make([]string, len(parts))
@@ -354,9 +410,32 @@ func (s *SQLServer) Generate(schema *sqlmapper.Schema) (string, error) {
 		return "", errors.New("empty schema")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.SQLServer)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
 	s.buf.Reset()
 
+	// Sequences come first so column defaults can use them
+	for _, seq := range schema.Sequences {
+		s.buf.WriteString("CREATE SEQUENCE " + seq.Name + s.generateSequenceOptions(seq) + ";\n")
+	}
+
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		var foreignKeys []string
+		for _, constraint := range table.Constraints {
+			if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+				foreignKeys = append(foreignKeys, s.generateConstraintSQL(constraint))
+			}
+		}
+
 		s.buf.WriteString("CREATE TABLE ")
 		s.buf.WriteString(table.Name)
 		s.buf.WriteString(" (\n")
@@ -389,7 +468,16 @@ func (s *SQLServer) Generate(schema *sqlmapper.Schema) (string, error) {
 				s.buf.WriteString(" IDENTITY(1,1)")
 			}
 
-			if i < len(table.Columns)-1 {
+			if i < len(table.Columns)-1 || len(foreignKeys) > 0 {
+				s.buf.WriteByte(',')
+			}
+			s.buf.WriteByte('\n')
+		}
+
+		for i, fk := range foreignKeys {
+			s.buf.WriteString("    ")
+			s.buf.WriteString(fk)
+			if i < len(foreignKeys)-1 {
 				s.buf.WriteByte(',')
 			}
 			s.buf.WriteByte('\n')
@@ -413,6 +501,18 @@ func (s *SQLServer) Generate(schema *sqlmapper.Schema) (string, error) {
 		}
 	}
 
+	// Add table data
+	for _, table := range schema.Tables {
+		for _, stmt := range s.generateInsertSQL(table) {
+			s.buf.WriteString(stmt + ";\n")
+		}
+	}
+
+	// Add foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		fmt.Fprintf(s.buf, "ALTER TABLE %s ADD %s;\n", fk.Table, s.generateConstraintSQL(fk.Constraint))
+	}
+
 	return s.buf.String(), nil
 }
 
@@ -439,18 +539,24 @@ func (s *SQLServer) parseCreateIndex(stmt []byte) error {
 		return fmt.Errorf("invalid CREATE INDEX statement: missing table name")
 	}
 
+	// The column list may follow the table name without a space: ON users(email)
+	tablePart := parts[tableNamePos]
+	if idx := bytes.IndexByte(tablePart, '('); idx != -1 {
+		tablePart = tablePart[:idx]
+	}
+
 	indexName := string(bytes.Trim(parts[indexNamePos], "[]"))
-	tableName := string(bytes.Trim(parts[tableNamePos], "[]"))
+	tableName := string(bytes.Trim(tablePart, "[]"))
 
 	// Remove schema prefix if exists
-	if idx := bytes.LastIndex(parts[tableNamePos], []byte(".")); idx != -1 {
-		tableName = string(bytes.Trim(parts[tableNamePos][idx+1:], "[]"))
+	if idx := bytes.LastIndex(tablePart, []byte(".")); idx != -1 {
+		tableName = string(bytes.Trim(tablePart[idx+1:], "[]"))
 	}
 
 	// Extract columns
 	startIdx := bytes.LastIndex(stmt, []byte("("))
 	endIdx := bytes.LastIndex(stmt, []byte(")"))
-	if startIdx == -1 || endIdx == -1 {
+	if startIdx == -1 || endIdx < startIdx {
 		return fmt.Errorf("no columns found in CREATE INDEX statement")
 	}
 
@@ -505,7 +611,8 @@ func (s *SQLServer) parseAlterTable(stmt []byte) error {
 	switch {
 	case bytes.Contains(upperStmt, []byte("ADD CONSTRAINT")):
 		if idx := bytes.Index(upperStmt, []byte("ADD CONSTRAINT")); idx != -1 {
-			constraint := s.parseConstraint(stmt[idx:])
+			// Skip ADD, so that the definition starts with CONSTRAINT and its name
+			constraint := s.parseConstraint(stmt[idx+len("ADD "):])
 			s.schema.Tables[tableIndex].Constraints = append(s.schema.Tables[tableIndex].Constraints, constraint)
 		}
 
@@ -588,16 +695,17 @@ func (s *SQLServer) parseCreateTrigger(stmt []byte) (sqlmapper.Trigger, error) {
 		trigger.Event = "DELETE"
 	}
 
-	// Extract table name
-	if idx := bytes.Index(upperStmt, []byte(" ON ")); idx != -1 {
-		rest := stmt[idx+4:]
-		if spaceIdx := bytes.Index(rest, []byte(" ")); spaceIdx != -1 {
-			tableName := bytes.TrimSpace(rest[:spaceIdx])
+	// Extract table name from the first ON after the trigger name, which may
+	// start a line of its own
+	for i := 3; i+1 < len(parts); i++ {
+		if bytes.EqualFold(parts[i], []byte("ON")) {
+			tableName := parts[i+1]
 			// Remove schema prefix if exists
 			if dotIdx := bytes.LastIndex(tableName, []byte(".")); dotIdx != -1 {
 				tableName = tableName[dotIdx+1:]
 			}
 			trigger.Table = string(bytes.Trim(tableName, "[]"))
+			break
 		}
 	}
 
@@ -609,119 +717,6 @@ func (s *SQLServer) parseCreateTrigger(stmt []byte) (sqlmapper.Trigger, error) {
 	return trigger, nil
 }
 
-func (s *SQLServer) parseTables(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+TABLE\s+([.\w\[\]]+)\s*\((.*?)\)(?:\s+ON\s+(\w+))?`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 2 {
-		tableName := matches[1]
-		columnDefs := matches[2]
-
-		table := sqlmapper.Table{}
-
-		// Parse schema if exists
-		parts := strings.Split(strings.Trim(tableName, "[]"), ".")
-		if len(parts) > 1 {
-			table.Schema = parts[0]
-			table.Name = parts[1]
-		} else {
-			table.Name = tableName
-		}
-
-		// Parse filegroup if exists
-		if len(matches) > 3 && matches[3] != "" {
-			table.TableSpace = matches[3]
-		}
-
-		// Parse columns and constraints
-		columns := strings.Split(columnDefs, ",")
-		for _, col := range columns {
-			col = strings.TrimSpace(col)
-			if strings.HasPrefix(strings.ToUpper(col), "CONSTRAINT") {
-				continue // Skip constraints for now
-			}
-
-			parts := strings.Fields(col)
-			if len(parts) < 2 {
-				continue
-			}
-
-			column := sqlmapper.Column{
-				Name:       strings.Trim(parts[0], "[]"),
-				DataType:   parts[1],
-				IsNullable: true,
-			}
-
-			// Parse length/precision
-			if strings.Contains(column.DataType, "(") {
-				re := regexp.MustCompile(`(\w+)\((\d+|MAX)(?:,(\d+))?\)`)
-				if matches := re.FindStringSubmatch(column.DataType); len(matches) > 2 {
-					column.DataType = matches[1]
-					if matches[2] == "MAX" {
-						column.Length = -1
-					} else {
-						fmt.Sscanf(matches[2], "%d", &column.Length)
-					}
-					if len(matches) > 3 && matches[3] != "" {
-						fmt.Sscanf(matches[3], "%d", &column.Scale)
-					}
-				}
-			}
-
-			// Parse constraints
-			if strings.Contains(strings.ToUpper(col), "NOT NULL") {
-				column.IsNullable = false
-			}
-			if strings.Contains(strings.ToUpper(col), "PRIMARY KEY") {
-				column.IsPrimaryKey = true
-			}
-			if strings.Contains(strings.ToUpper(col), "IDENTITY") {
-				column.AutoIncrement = true
-			}
-			if strings.Contains(strings.ToUpper(col), "UNIQUE") {
-				column.IsUnique = true
-			}
-			if strings.Contains(strings.ToUpper(col), "DEFAULT") {
-				re := regexp.MustCompile(`DEFAULT\s+([^,\s]+)`)
-				if matches := re.FindStringSubmatch(col); len(matches) > 1 {
-					column.DefaultValue = matches[1]
-				}
-			}
-
-			table.Columns = append(table.Columns, column)
-		}
-
-		s.schema.Tables = append(s.schema.Tables, table)
-	}
-
-	return nil
-}
-
-func (s *SQLServer) parseViews(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+VIEW\s+([.\w\[\]]+)\s+AS\s+(.+)$`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 2 {
-		viewName := matches[1]
-		view := sqlmapper.View{
-			Definition: matches[2],
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(strings.Trim(viewName, "[]"), ".")
-		if len(parts) > 1 {
-			view.Schema = parts[0]
-			view.Name = parts[1]
-		} else {
-			view.Name = viewName
-		}
-
-		s.schema.Views = append(s.schema.Views, view)
-	}
-
-	return nil
-}
-
 func (s *SQLServer) parseFunctions(statement string) error {
 	re := regexp.MustCompile(`CREATE\s+(FUNCTION|PROCEDURE)\s+([.\w\[\]]+)\s*\((.*?)\)(?:\s+RETURNS\s+(\w+(?:\s*\([^)]*\))?))?\s+AS\s+BEGIN\s+(.*?)\s+END`)
 	matches := re.FindStringSubmatch(statement)
@@ -768,34 +763,6 @@ func (s *SQLServer) parseFunctions(statement string) error {
 	return nil
 }
 
-func (s *SQLServer) parseTriggers(statement string) error {
-	re := regexp.MustCompile(`CREATE\s+TRIGGER\s+([.\w\[\]]+)\s+ON\s+([.\w\[\]]+)\s+(AFTER|INSTEAD\s+OF|FOR)\s+(INSERT|UPDATE|DELETE)(?:\s*,\s*(INSERT|UPDATE|DELETE))*\s+AS\s+BEGIN\s+(.*?)\s+END`)
-	matches := re.FindStringSubmatch(statement)
-
-	if len(matches) > 6 {
-		triggerName := matches[1]
-		trigger := sqlmapper.Trigger{
-			Table:  matches[2],
-			Timing: matches[3],
-			Event:  matches[4],
-			Body:   matches[6],
-		}
-
-		// Parse schema if exists
-		parts := strings.Split(strings.Trim(triggerName, "[]"), ".")
-		if len(parts) > 1 {
-			trigger.Schema = parts[0]
-			trigger.Name = parts[1]
-		} else {
-			trigger.Name = triggerName
-		}
-
-		s.schema.Triggers = append(s.schema.Triggers, trigger)
-	}
-
-	return nil
-}
-
 func (s *SQLServer) parseIndexes(statement string) error {
 	re := regexp.MustCompile(`CREATE\s+(?:(UNIQUE|CLUSTERED|NONCLUSTERED)\s+)*INDEX\s+([.\w\[\]]+)\s+ON\s+([.\w\[\]]+)\s*\((.*?)\)(?:\s+INCLUDE\s*\((.*?)\))?(?:\s+WITH\s*\((.*?)\))?(?:\s+ON\s+(\w+))?`)
 	matches := re.FindStringSubmatch(statement)
@@ -840,47 +807,59 @@ func (s *SQLServer) parseIndexes(statement string) error {
 func (s *SQLServer) generateTableSQL(table sqlmapper.Table) string {
 	sql := "CREATE TABLE " + table.Name + " (\n"
 
-	// Generate columns
-	for i, col := range table.Columns {
-		sql += "    " + col.Name + " " + col.DataType
-		if col.Length > 0 {
-			if strings.ToUpper(col.DataType) == "NVARCHAR" || strings.ToUpper(col.DataType) == "NCHAR" {
-				if col.Length == -1 {
-					sql += "(MAX)"
-				} else {
-					sql += fmt.Sprintf("(%d)", col.Length)
-				}
-			} else {
-				sql += fmt.Sprintf("(%d", col.Length)
-				if col.Scale > 0 {
-					sql += fmt.Sprintf(",%d", col.Scale)
-				}
-				sql += ")"
-			}
+	// Generate columns and foreign keys
+	defs := make([]string, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		defs = append(defs, s.generateColumnSQL(col))
+	}
+	for _, constraint := range table.Constraints {
+		if strings.ToUpper(constraint.Type) == "FOREIGN KEY" {
+			defs = append(defs, s.generateConstraintSQL(constraint))
 		}
+	}
+	if len(defs) > 0 {
+		sql += "    " + strings.Join(defs, ",\n    ")
+	}
 
-		if col.IsPrimaryKey {
-			sql += " PRIMARY KEY"
-			if col.AutoIncrement {
-				sql += " IDENTITY(1,1)"
+	sql += "\n)"
+
+	return sql
+}
+
+// generateColumnSQL generates SQL for a column definition
+func (s *SQLServer) generateColumnSQL(col sqlmapper.Column) string {
+	sql := col.Name + " " + col.DataType
+	if col.Length > 0 {
+		if strings.ToUpper(col.DataType) == "NVARCHAR" || strings.ToUpper(col.DataType) == "NCHAR" {
+			if col.Length == -1 {
+				sql += "(MAX)"
+			} else {
+				sql += fmt.Sprintf("(%d)", col.Length)
 			}
+		} else {
+			sql += fmt.Sprintf("(%d", col.Length)
+			if col.Scale > 0 {
+				sql += fmt.Sprintf(",%d", col.Scale)
+			}
+			sql += ")"
 		}
-		if !col.IsNullable {
-			sql += " NOT NULL"
-		}
-		if col.IsUnique {
-			sql += " UNIQUE"
-		}
-		if col.DefaultValue != "" {
-			sql += " DEFAULT " + col.DefaultValue
-		}
+	}
 
-		if i < len(table.Columns)-1 {
-			sql += ",\n"
+	if col.IsPrimaryKey {
+		sql += " PRIMARY KEY"
+		if col.AutoIncrement {
+			sql += " IDENTITY(1,1)"
 		}
 	}
-
-	sql += "\n)"
+	if !col.IsNullable {
+		sql += " NOT NULL"
+	}
+	if col.IsUnique {
+		sql += " UNIQUE"
+	}
+	if col.DefaultValue != "" {
+		sql += " DEFAULT " + col.DefaultValue
+	}
 
 	return sql
 }
@@ -904,3 +883,48 @@ func (s *SQLServer) generateIndexSQL(tableName string, index sqlmapper.Index) st
 
 	return sql
 }
+
+// insertBatchSize is the maximum number of rows per generated INSERT statement.
+// SQL Server accepts at most 1000 rows in a VALUES list.
+const insertBatchSize = 100
+
+// formatValue encodes a data value as a SQL Server literal. Booleans become BIT
+// values, binary data is written as 0x constants, strings with non-ASCII
+// characters are Unicode literals and dates use the unambiguous ISO 8601 forms.
+func (s *SQLServer) formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		if len(v) == 0 {
+			return "0x"
+		}
+		return "0x" + strings.ToUpper(hex.EncodeToString(v))
+	case string:
+		for _, r := range v {
+			if r > unicode.MaxASCII {
+				return "N" + sqlmapper.QuoteString(v)
+			}
+		}
+		return sqlmapper.QuoteString(v)
+	case time.Time:
+		text := sqlmapper.FormatTimeLiteral(v)
+		if len(text) == len("2006-01-02") {
+			return "'" + v.Format("20060102") + "'"
+		}
+		text = v.Format("2006-01-02T15:04:05.9999999")
+		if v.Location() != time.UTC {
+			text += v.Format("-07:00")
+		}
+		return "'" + text + "'"
+	}
+	return sqlmapper.FormatLiteral(value)
+}
+
+// generateInsertSQL creates the INSERT statements for the data of a table
+func (s *SQLServer) generateInsertSQL(table sqlmapper.Table) []string {
+	return sqlmapper.InsertStatements(table, insertBatchSize, s.formatValue)
+}
@@ -0,0 +1,244 @@
+package sqlserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// GenerateMigration renders a list of schema changes, as produced by sqlmapper.Diff,
+// into a SQL Server migration script. Changes are rendered in the given order, and data types
+// of changes diffed from schemas of another dialect are translated to SQL Server.
+// Views, triggers and routines must be the only statement in their batch, so
+// they are separated by GO.
+//
+// Parameters:
+//   - changes: The ordered schema changes to render
+//
+// Returns:
+//   - string: The generated SQL Server migration statements
+//   - error: An error if a change carries an unexpected definition
+func (s *SQLServer) GenerateMigration(changes []sqlmapper.Change) (string, error) {
+	changes = sqlmapper.ConvertChanges(changes, sqlmapper.SQLServer)
+	var result strings.Builder
+
+	for _, change := range changes {
+		stmt, err := s.generateChangeSQL(change)
+		if err != nil {
+			return "", err
+		}
+		if stmt == "" {
+			continue
+		}
+		result.WriteString(stmt)
+		result.WriteString("\n")
+	}
+
+	return result.String(), nil
+}
+
+// generateChangeSQL renders a single schema change
+func (s *SQLServer) generateChangeSQL(change sqlmapper.Change) (string, error) {
+	switch change.Type {
+	case sqlmapper.CreateTable:
+		table, ok := change.New.(sqlmapper.Table)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		stmts := []string{s.generateTableSQL(table) + ";"}
+		for _, index := range table.Indexes {
+			stmts = append(stmts, s.generateIndexSQL(table.Name, index)+";")
+		}
+		return strings.Join(stmts, "\n"), nil
+
+	case sqlmapper.DropTable:
+		return fmt.Sprintf("DROP TABLE %s;", change.Name), nil
+
+	case sqlmapper.AddColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;", change.Table, s.generateColumnSQL(column)), nil
+
+	case sqlmapper.DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", change.Table, change.Name), nil
+
+	case sqlmapper.ModifyColumn:
+		column, ok := change.New.(sqlmapper.Column)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		// ALTER COLUMN accepts neither defaults nor key attributes
+		column.IsPrimaryKey = false
+		column.IsUnique = false
+		column.DefaultValue = ""
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s;", change.Table, s.generateColumnSQL(column)), nil
+
+	case sqlmapper.AddIndex:
+		index, ok := change.New.(sqlmapper.Index)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return s.generateIndexSQL(change.Table, index) + ";", nil
+
+	case sqlmapper.DropIndex:
+		if change.Name == "" {
+			return fmt.Sprintf("-- Unnamed index on %s must be dropped manually", change.Table), nil
+		}
+		return fmt.Sprintf("DROP INDEX %s ON %s;", change.Name, change.Table), nil
+
+	case sqlmapper.AddConstraint:
+		constraint, ok := change.New.(sqlmapper.Constraint)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;", change.Table, s.generateConstraintSQL(constraint)), nil
+
+	case sqlmapper.DropConstraint:
+		if change.Name == "" {
+			constraint, _ := change.Old.(sqlmapper.Constraint)
+			return fmt.Sprintf("-- Unnamed %s constraint on %s must be dropped manually", constraint.Type, change.Table), nil
+		}
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", change.Table, change.Name), nil
+
+	case sqlmapper.CreateView, sqlmapper.ModifyView:
+		view, ok := change.New.(sqlmapper.View)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return fmt.Sprintf("GO\nCREATE OR ALTER VIEW %s AS %s;\nGO", view.Name, view.Definition), nil
+
+	case sqlmapper.DropView:
+		return fmt.Sprintf("DROP VIEW %s;", change.Name), nil
+
+	case sqlmapper.CreateSequence:
+		seq, ok := change.New.(sqlmapper.Sequence)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return "CREATE SEQUENCE " + seq.Name + s.generateSequenceOptions(seq) + ";", nil
+
+	case sqlmapper.ModifySequence:
+		seq, ok := change.New.(sqlmapper.Sequence)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		options := strings.Replace(s.generateSequenceOptions(seq), " START WITH ", " RESTART WITH ", 1)
+		if !seq.Cycle {
+			options += " NO CYCLE"
+		}
+		return "ALTER SEQUENCE " + seq.Name + options + ";", nil
+
+	case sqlmapper.DropSequence:
+		return fmt.Sprintf("DROP SEQUENCE %s;", change.Name), nil
+
+	case sqlmapper.CreateTrigger, sqlmapper.ModifyTrigger:
+		trigger, ok := change.New.(sqlmapper.Trigger)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return "GO\n" + s.generateTriggerSQL(trigger) + "\nGO", nil
+
+	case sqlmapper.DropTrigger:
+		return fmt.Sprintf("DROP TRIGGER %s;", change.Name), nil
+
+	case sqlmapper.CreateFunction, sqlmapper.ModifyFunction:
+		fn, ok := change.New.(sqlmapper.Function)
+		if !ok {
+			return "", fmt.Errorf("invalid definition for %s %s", change.Type, change.Name)
+		}
+		return "GO\n" + s.generateFunctionSQL(fn) + "\nGO", nil
+
+	case sqlmapper.DropFunction:
+		fn, _ := change.Old.(sqlmapper.Function)
+		if fn.IsProc {
+			return fmt.Sprintf("DROP PROCEDURE %s;", change.Name), nil
+		}
+		return fmt.Sprintf("DROP FUNCTION %s;", change.Name), nil
+	}
+
+	return "", errors.New("unsupported change type: " + string(change.Type))
+}
+
+// generateConstraintSQL generates SQL for a table constraint clause
+func (s *SQLServer) generateConstraintSQL(constraint sqlmapper.Constraint) string {
+	sql := ""
+	if constraint.Name != "" {
+		sql = "CONSTRAINT " + constraint.Name + " "
+	}
+
+	if strings.ToUpper(constraint.Type) == "CHECK" {
+		return sql + "CHECK (" + constraint.CheckExpression + ")"
+	}
+
+	sql += constraint.Type + " (" + strings.Join(constraint.Columns, ", ") + ")"
+	if constraint.RefTable != "" {
+		sql += " REFERENCES " + constraint.RefTable + " (" + strings.Join(constraint.RefColumns, ", ") + ")"
+		if constraint.DeleteRule != "" {
+			sql += " ON DELETE " + constraint.DeleteRule
+		}
+		if constraint.UpdateRule != "" {
+			sql += " ON UPDATE " + constraint.UpdateRule
+		}
+	}
+
+	return sql
+}
+
+// generateSequenceOptions generates the option list of a CREATE or ALTER SEQUENCE statement
+func (s *SQLServer) generateSequenceOptions(seq sqlmapper.Sequence) string {
+	sql := ""
+	if seq.StartValue != 0 {
+		sql += fmt.Sprintf(" START WITH %d", seq.StartValue)
+	}
+	if seq.IncrementBy != 0 {
+		sql += fmt.Sprintf(" INCREMENT BY %d", seq.IncrementBy)
+	}
+	if seq.MinValue != 0 {
+		sql += fmt.Sprintf(" MINVALUE %d", seq.MinValue)
+	}
+	if seq.MaxValue != 0 {
+		sql += fmt.Sprintf(" MAXVALUE %d", seq.MaxValue)
+	}
+	if seq.Cache != 0 {
+		sql += fmt.Sprintf(" CACHE %d", seq.Cache)
+	}
+	if seq.Cycle {
+		sql += " CYCLE"
+	}
+	return sql
+}
+
+// generateTriggerSQL generates SQL for a trigger
+func (s *SQLServer) generateTriggerSQL(trigger sqlmapper.Trigger) string {
+	timing := trigger.Timing
+	if strings.EqualFold(timing, "BEFORE") {
+		timing = "INSTEAD OF" // SQL Server has no BEFORE triggers
+	}
+	return fmt.Sprintf("CREATE OR ALTER TRIGGER %s ON %s %s %s AS BEGIN %s END;",
+		trigger.Name, trigger.Table, timing, trigger.Event, trigger.Body)
+}
+
+// generateFunctionSQL generates SQL for a function or procedure
+func (s *SQLServer) generateFunctionSQL(fn sqlmapper.Function) string {
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = param.Name + " " + param.DataType
+		if strings.EqualFold(param.Direction, "OUT") || strings.EqualFold(param.Direction, "INOUT") {
+			params[i] += " OUTPUT"
+		}
+	}
+
+	if fn.IsProc {
+		signature := fn.Name
+		if len(params) > 0 {
+			signature += " " + strings.Join(params, ", ")
+		}
+		return fmt.Sprintf("CREATE OR ALTER PROCEDURE %s AS BEGIN %s END;", signature, fn.Body)
+	}
+	return fmt.Sprintf("CREATE OR ALTER FUNCTION %s(%s) RETURNS %s AS BEGIN %s END;",
+		fn.Name, strings.Join(params, ", "), fn.Returns, fn.Body)
+}
@@ -1,13 +1,14 @@
 package sqlserver
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
-	"sync"
 
 	"github.com/mstgnz/sqlmapper"
 	"github.com/mstgnz/sqlmapper/stream"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // SQLServerStreamParser implements the StreamParser interface for SQL Server
@@ -24,226 +25,104 @@ func NewSQLServerStreamParser() *SQLServerStreamParser {
 
 // ParseStream implements the StreamParser interface
 func (p *SQLServerStreamParser) ParseStream(reader io.Reader, callback func(stream.SchemaObject) error) error {
-	streamReader := stream.NewStreamReader(reader, "GO")
-
-	for {
-		statement, err := streamReader.ReadStatement()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading statement: %v", err)
-		}
-
-		statement = strings.TrimSpace(statement)
-		if statement == "" {
-			continue
-		}
-
-		// Parse CREATE TABLE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TABLE") {
-			table, err := p.parseTableStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.TableObject,
-				Data: table,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE VIEW statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE VIEW") {
-			view, err := p.parseViewStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.ViewObject,
-				Data: view,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		// Parse CREATE FUNCTION statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE FUNCTION") {
-			function, err := p.parseFunctionStatement(statement)
-			if err != nil {
-				return err
-			}
-
-			err = callback(stream.SchemaObject{
-				Type: stream.FunctionObject,
-				Data: function,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{})
+}
 
-		// Parse CREATE PROCEDURE statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE PROCEDURE") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE PROC") {
-			procedure, err := p.parseProcedureStatement(statement)
-			if err != nil {
-				return err
-			}
+// ParseStreamLenient implements the stream.LenientStreamParser interface
+func (p *SQLServerStreamParser) ParseStreamLenient(reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return p.ParseStreamOptions(reader, callback, stream.ParseOptions{Lenient: true})
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.ProcedureObject,
-				Data: procedure,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
+// ParseStreamOptions implements the stream.OptionsStreamParser interface
+func (p *SQLServerStreamParser) ParseStreamOptions(reader io.Reader, callback func(stream.SchemaObject) error, opts stream.ParseOptions) error {
+	return stream.ParseStatements(reader, tokenizer.SQLServer, p.parseStatement, callback, opts)
+}
 
-		// Parse CREATE TRIGGER statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE TRIGGER") {
-			trigger, err := p.parseTriggerStatement(statement)
-			if err != nil {
-				return err
-			}
+// ParseStreamParallel implements parallel processing for SQL Server stream parsing.
+// Objects are delivered in input order; each worker parses with its own parser.
+func (p *SQLServerStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
+	return stream.ParseStreamParallel(reader, tokenizer.SQLServer, func() stream.ParseFunc {
+		return NewSQLServerStreamParser().parseStatement
+	}, callback, stream.ParallelOptions{Workers: workers})
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.TriggerObject,
-				Data: trigger,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
+// ParseStreamContext implements the StreamParser interface
+func (p *SQLServerStreamParser) ParseStreamContext(ctx context.Context, reader io.Reader, callback func(stream.SchemaObject) error) error {
+	return stream.ParseContext(ctx, reader, callback, p.ParseStream)
+}
 
-		// Parse CREATE INDEX statements
-		if strings.HasPrefix(strings.ToUpper(statement), "CREATE INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE UNIQUE INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE CLUSTERED INDEX") ||
-			strings.HasPrefix(strings.ToUpper(statement), "CREATE NONCLUSTERED INDEX") {
-			index, err := p.parseIndexStatement(statement)
-			if err != nil {
-				return err
-			}
+// GenerateStreamContext implements the StreamParser interface
+func (p *SQLServerStreamParser) GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error {
+	return stream.GenerateContext(ctx, schema, writer, p.GenerateStream)
+}
 
-			err = callback(stream.SchemaObject{
-				Type: stream.IndexObject,
-				Data: index,
-			})
-			if err != nil {
-				return err
-			}
-			continue
-		}
+// GenerateObject implements the StreamParser interface
+func (p *SQLServerStreamParser) GenerateObject(obj stream.SchemaObject, writer io.Writer) error {
+	var stmt string
+	switch data := obj.Data.(type) {
+	case *sqlmapper.Index:
+		stmt = p.sqlserver.generateIndexSQL(obj.Table, *data)
+	case *sqlmapper.Constraint:
+		stmt = fmt.Sprintf("ALTER TABLE %s ADD %s", obj.Table, p.sqlserver.generateConstraintSQL(*data))
+	case *stream.Comment:
+		stmt = commentSQL(obj.Table, data)
+	case *stream.Unparsed:
+		stmt = data.SQL
+	default:
+		return p.GenerateStream(stream.ObjectSchema(obj), writer)
 	}
 
-	return nil
+	_, err := writer.Write([]byte(stmt + ";\n\n"))
+	return err
 }
 
-// ParseStreamParallel implements parallel processing for SQL Server stream parsing
-func (p *SQLServerStreamParser) ParseStreamParallel(reader io.Reader, callback func(stream.SchemaObject) error, workers int) error {
-	streamReader := stream.NewStreamReader(reader, "GO")
-	statements := make(chan string, workers)
-	results := make(chan stream.SchemaObject, workers)
-	errors := make(chan error, workers)
-	var wg sync.WaitGroup
-
-	// Start worker goroutines
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for statement := range statements {
-				obj, err := p.parseStatement(statement)
-				if err != nil {
-					errors <- err
-					return
-				}
-				if obj != nil {
-					results <- *obj
-				}
-			}
-		}()
+// commentSQL returns the sp_addextendedproperty call setting a comment as the
+// MS_Description property of a table or column. Tables without a schema are in dbo.
+func commentSQL(table string, comment *stream.Comment) string {
+	schemaName, tableName := "dbo", table
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		schemaName, tableName = table[:i], table[i+1:]
 	}
+	unquote := func(name string) string { return strings.Trim(name, `"[]`) }
+	nvarchar := func(s string) string { return "N" + sqlmapper.QuoteString(s) }
+
+	stmt := fmt.Sprintf("EXEC sp_addextendedproperty @name = N'MS_Description', @value = %s, "+
+		"@level0type = N'SCHEMA', @level0name = %s, @level1type = N'TABLE', @level1name = %s",
+		nvarchar(comment.Text), nvarchar(unquote(schemaName)), nvarchar(unquote(tableName)))
+	if comment.Column != "" {
+		stmt += fmt.Sprintf(", @level2type = N'COLUMN', @level2name = %s", nvarchar(unquote(comment.Column)))
+	}
+	return stmt
+}
 
-	// Start a goroutine to close results channel after all workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Start a goroutine to read statements and send them to workers
-	go func() {
-		for {
-			statement, err := streamReader.ReadStatement()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				errors <- fmt.Errorf("error reading statement: %v", err)
-				break
-			}
-
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
-				continue
-			}
-			statements <- statement
-		}
-		close(statements)
-	}()
+// Dialect implements the StreamParser interface
+func (p *SQLServerStreamParser) Dialect() sqlmapper.DatabaseType {
+	return sqlmapper.SQLServer
+}
 
-	// Process results and handle errors
-	for obj := range results {
-		if err := callback(obj); err != nil {
-			return err
-		}
+// parseStatement parses a single SQL statement with the batch parser and
+// returns the objects it defines. Routines and clustered indexes, which the
+// batch parser skips, are parsed by parseDefinition.
+func (p *SQLServerStreamParser) parseStatement(stmt tokenizer.Statement) ([]stream.SchemaObject, error) {
+	objects, err := stream.StatementObjects(stmt.Text, func(schema *sqlmapper.Schema) error {
+		return (&SQLServer{schema: schema}).parseTokenized(stmt)
+	})
+	if err != nil || len(objects) > 0 {
+		return objects, err
 	}
 
-	// Check for any errors from workers
-	select {
-	case err := <-errors:
-		return err
-	default:
-		return nil
+	obj, err := p.parseDefinition(stmt.Text)
+	if err != nil || obj == nil {
+		return nil, err
 	}
+	return []stream.SchemaObject{*obj}, nil
 }
 
-// parseStatement parses a single SQL statement and returns a SchemaObject
-func (p *SQLServerStreamParser) parseStatement(statement string) (*stream.SchemaObject, error) {
+// parseDefinition parses the routine and index statements the batch parser skips
+func (p *SQLServerStreamParser) parseDefinition(statement string) (*stream.SchemaObject, error) {
 	upperStatement := strings.ToUpper(statement)
 
 	switch {
-	case strings.HasPrefix(upperStatement, "CREATE TABLE"):
-		table, err := p.parseTableStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TableObject,
-			Data: table,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE VIEW"):
-		view, err := p.parseViewStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.ViewObject,
-			Data: view,
-		}, nil
-
 	case strings.HasPrefix(upperStatement, "CREATE FUNCTION"):
 		function, err := p.parseFunctionStatement(statement)
 		if err != nil {
@@ -264,27 +143,18 @@ func (p *SQLServerStreamParser) parseStatement(statement string) (*stream.Schema
 			Data: procedure,
 		}, nil
 
-	case strings.HasPrefix(upperStatement, "CREATE TRIGGER"):
-		trigger, err := p.parseTriggerStatement(statement)
-		if err != nil {
-			return nil, err
-		}
-		return &stream.SchemaObject{
-			Type: stream.TriggerObject,
-			Data: trigger,
-		}, nil
-
-	case strings.HasPrefix(upperStatement, "CREATE INDEX") ||
-		strings.HasPrefix(upperStatement, "CREATE UNIQUE INDEX") ||
-		strings.HasPrefix(upperStatement, "CREATE CLUSTERED INDEX") ||
-		strings.HasPrefix(upperStatement, "CREATE NONCLUSTERED INDEX"):
-		index, err := p.parseIndexStatement(statement)
+	case strings.HasPrefix(upperStatement, "CREATE CLUSTERED INDEX") ||
+		strings.HasPrefix(upperStatement, "CREATE NONCLUSTERED INDEX") ||
+		strings.HasPrefix(upperStatement, "CREATE UNIQUE CLUSTERED INDEX") ||
+		strings.HasPrefix(upperStatement, "CREATE UNIQUE NONCLUSTERED INDEX"):
+		index, table, err := p.parseIndexStatement(statement)
 		if err != nil {
 			return nil, err
 		}
 		return &stream.SchemaObject{
-			Type: stream.IndexObject,
-			Data: index,
+			Type:  stream.IndexObject,
+			Table: table,
+			Data:  index,
 		}, nil
 	}
 
@@ -297,8 +167,27 @@ func (p *SQLServerStreamParser) GenerateStream(schema *sqlmapper.Schema, writer
 		return fmt.Errorf("schema cannot be nil")
 	}
 
+	// Translate data types from the source dialect
+	schema = sqlmapper.ConvertTypes(schema, sqlmapper.SQLServer)
+
+	// Order objects so that referenced tables are created first
+	schema, deferred := sqlmapper.SortSchema(schema)
+
+	// Write sequences
+	for _, seq := range schema.Sequences {
+		stmt := "CREATE SEQUENCE " + seq.Name + p.sqlserver.generateSequenceOptions(seq)
+		if _, err := writer.Write([]byte(stmt + "\nGO\n\n")); err != nil {
+			return err
+		}
+	}
+
 	// Write tables
 	for _, table := range schema.Tables {
+		// Tables known only from their data have no definition to create
+		if len(table.Columns) == 0 {
+			continue
+		}
+
 		stmt := p.sqlserver.generateTableSQL(table)
 		if _, err := writer.Write([]byte(stmt + "\nGO\n\n")); err != nil {
 			return err
@@ -313,6 +202,23 @@ func (p *SQLServerStreamParser) GenerateStream(schema *sqlmapper.Schema, writer
 		}
 	}
 
+	// Write table data
+	for _, table := range schema.Tables {
+		for _, stmt := range p.sqlserver.generateInsertSQL(table) {
+			if _, err := writer.Write([]byte(stmt + ";\n\n")); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Write foreign keys that close a reference cycle
+	for _, fk := range deferred {
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD %s", fk.Table, p.sqlserver.generateConstraintSQL(fk.Constraint))
+		if _, err := writer.Write([]byte(stmt + "\nGO\n\n")); err != nil {
+			return err
+		}
+	}
+
 	// Write views
 	for _, view := range schema.Views {
 		stmt := fmt.Sprintf("CREATE VIEW %s AS\n%s", view.Name, view.Definition)
@@ -372,44 +278,12 @@ func (p *SQLServerStreamParser) GenerateStream(schema *sqlmapper.Schema, writer
 	return nil
 }
 
-// parseTableStatement parses a CREATE TABLE statement
-func (p *SQLServerStreamParser) parseTableStatement(statement string) (*sqlmapper.Table, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlserver.schema = tempSchema
-
-	if err := p.sqlserver.parseTables(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Tables) == 0 {
-		return nil, fmt.Errorf("no table found in statement")
-	}
-
-	return &tempSchema.Tables[0], nil
-}
-
-// parseViewStatement parses a CREATE VIEW statement
-func (p *SQLServerStreamParser) parseViewStatement(statement string) (*sqlmapper.View, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlserver.schema = tempSchema
-
-	if err := p.sqlserver.parseViews(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Views) == 0 {
-		return nil, fmt.Errorf("no view found in statement")
-	}
-
-	return &tempSchema.Views[0], nil
-}
-
 // parseFunctionStatement parses a CREATE FUNCTION statement
 func (p *SQLServerStreamParser) parseFunctionStatement(statement string) (*sqlmapper.Function, error) {
 	tempSchema := &sqlmapper.Schema{}
-	p.sqlserver.schema = tempSchema
+	parser := &SQLServer{schema: tempSchema}
 
-	if err := p.sqlserver.parseFunctions(statement); err != nil {
+	if err := parser.parseFunctions(statement); err != nil {
 		return nil, err
 	}
 
@@ -429,9 +303,9 @@ func (p *SQLServerStreamParser) parseFunctionStatement(statement string) (*sqlma
 // parseProcedureStatement parses a CREATE PROCEDURE statement
 func (p *SQLServerStreamParser) parseProcedureStatement(statement string) (*sqlmapper.Procedure, error) {
 	tempSchema := &sqlmapper.Schema{}
-	p.sqlserver.schema = tempSchema
+	parser := &SQLServer{schema: tempSchema}
 
-	if err := p.sqlserver.parseFunctions(statement); err != nil {
+	if err := parser.parseFunctions(statement); err != nil {
 		return nil, err
 	}
 
@@ -454,34 +328,18 @@ func (p *SQLServerStreamParser) parseProcedureStatement(statement string) (*sqlm
 	return nil, fmt.Errorf("no procedure found in statement")
 }
 
-// parseTriggerStatement parses a CREATE TRIGGER statement
-func (p *SQLServerStreamParser) parseTriggerStatement(statement string) (*sqlmapper.Trigger, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlserver.schema = tempSchema
+// parseIndexStatement parses a CREATE INDEX statement and returns the index and its table
+func (p *SQLServerStreamParser) parseIndexStatement(statement string) (*sqlmapper.Index, string, error) {
+	tempSchema := &sqlmapper.Schema{Tables: []sqlmapper.Table{{Name: stream.IndexTable(statement)}}}
+	parser := &SQLServer{schema: tempSchema}
 
-	if err := p.sqlserver.parseTriggers(statement); err != nil {
-		return nil, err
-	}
-
-	if len(tempSchema.Triggers) == 0 {
-		return nil, fmt.Errorf("no trigger found in statement")
-	}
-
-	return &tempSchema.Triggers[0], nil
-}
-
-// parseIndexStatement parses a CREATE INDEX statement
-func (p *SQLServerStreamParser) parseIndexStatement(statement string) (*sqlmapper.Index, error) {
-	tempSchema := &sqlmapper.Schema{}
-	p.sqlserver.schema = tempSchema
-
-	if err := p.sqlserver.parseIndexes(statement); err != nil {
-		return nil, err
+	if err := parser.parseIndexes(statement); err != nil {
+		return nil, "", err
 	}
 
 	if len(tempSchema.Tables) == 0 || len(tempSchema.Tables[0].Indexes) == 0 {
-		return nil, fmt.Errorf("no index found in statement")
+		return nil, "", fmt.Errorf("no index found in statement")
 	}
 
-	return &tempSchema.Tables[0].Indexes[0], nil
+	return &tempSchema.Tables[0].Indexes[0], tempSchema.Tables[0].Name, nil
 }
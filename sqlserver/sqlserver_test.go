@@ -80,6 +80,52 @@ func TestSQLServer_Parse(t *testing.T) {
 				// Additional validation logic for constraints can be added here
 			},
 		},
+		{
+			name: "GO batches and semicolons inside literals",
+			content: `CREATE TABLE category (id INT PRIMARY KEY, note NVARCHAR(50) DEFAULT N'GO; now')
+GO
+-- CREATE TABLE ignored (id INT);
+CREATE TABLE [order;items] (id INT PRIMARY KEY, category_id INT)
+GO 2
+CREATE INDEX idx_category ON category (note);`,
+			wantErr: false,
+			validate: func(t *testing.T, schema *sqlmapper.Schema) {
+				assert.Len(t, schema.Tables, 2)
+				assert.Equal(t, "category", schema.Tables[0].Name)
+				assert.Len(t, schema.Tables[0].Indexes, 1)
+			},
+		},
+		{
+			name: "Commas inside column types and triggers on schema tables",
+			content: `CREATE TABLE app.users (
+    id INT IDENTITY(1,1) PRIMARY KEY,
+    balance DECIMAL(10,2) NOT NULL,
+    status NVARCHAR(20) CHECK (status IN ('active', 'banned'))
+);
+CREATE INDEX idx_users_status ON app.users(status);
+CREATE TRIGGER app.users_touch
+ON app.users
+AFTER UPDATE
+AS
+BEGIN
+    UPDATE u SET balance = 0 FROM app.users u INNER JOIN inserted i ON u.id = i.id;
+END;`,
+			wantErr: false,
+			validate: func(t *testing.T, schema *sqlmapper.Schema) {
+				if assert.Len(t, schema.Tables, 1) {
+					var names []string
+					for _, col := range schema.Tables[0].Columns {
+						names = append(names, col.Name)
+					}
+					assert.Equal(t, []string{"id", "balance", "status"}, names)
+					assert.Len(t, schema.Tables[0].Indexes, 1)
+				}
+				if assert.Len(t, schema.Triggers, 1) {
+					assert.Equal(t, "users", schema.Triggers[0].Table)
+				}
+				assert.Empty(t, sqlmapper.Validate(schema))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -182,3 +228,55 @@ func TestSQLServer_Generate_ComplexSchema(t *testing.T) {
 	_, err := s.Generate(schema)
 	assert.NoError(t, err)
 }
+
+func TestSQLServer_GenerateMigration(t *testing.T) {
+	oldSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "name", DataType: "NVARCHAR", Length: 100},
+				},
+				Indexes: []sqlmapper.Index{
+					{Name: "idx_name", Columns: []string{"name"}},
+				},
+			},
+		},
+	}
+
+	newSchema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{
+				Name: "users",
+				Columns: []sqlmapper.Column{
+					{Name: "id", DataType: "INT", IsPrimaryKey: true},
+					{Name: "name", DataType: "NVARCHAR", Length: 200, DefaultValue: "''"},
+				},
+				Constraints: []sqlmapper.Constraint{
+					{Name: "uq_name", Type: "UNIQUE", Columns: []string{"name"}},
+				},
+			},
+		},
+		Views: []sqlmapper.View{
+			{Name: "user_names", Definition: "SELECT name FROM users"},
+		},
+		Procedures: []sqlmapper.Procedure{
+			{Name: "clear_users", Body: "DELETE FROM users"},
+		},
+	}
+
+	s := &SQLServer{}
+	got, err := s.GenerateMigration(sqlmapper.Diff(oldSchema, newSchema))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(`
+DROP INDEX idx_name ON users;
+ALTER TABLE users ALTER COLUMN name NVARCHAR(200) NOT NULL;
+ALTER TABLE users ADD CONSTRAINT uq_name UNIQUE (name);
+GO
+CREATE OR ALTER PROCEDURE clear_users AS BEGIN DELETE FROM users END;
+GO
+GO
+CREATE OR ALTER VIEW user_names AS SELECT name FROM users;
+GO`), strings.TrimSpace(got))
+}
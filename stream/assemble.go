@@ -0,0 +1,295 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// Assembler rebuilds a schema from the objects of a stream parse. Its Add method
+// can be passed as the callback of ParseStream and ParseStreamParallel, and may
+// be called from several goroutines.
+//
+// Objects can arrive in any order: Schema orders them by their position in the
+// input before attaching standalone indexes, constraints, data rows and comments
+// to the table they belong to, so that an index delivered before its table is
+// attached all the same. Objects whose table is not part of the input are
+// reported as orphans.
+type Assembler struct {
+	mu      sync.Mutex
+	dialect sqlmapper.DatabaseType
+	objects []SchemaObject
+}
+
+// NewAssembler creates an assembler for the objects of a dialect's stream parser
+//
+// Parameters:
+//   - dialect: The dialect of the parsed input, set as the schema's dialect
+//
+// Returns:
+//   - *Assembler: An assembler without objects
+func NewAssembler(dialect sqlmapper.DatabaseType) *Assembler {
+	return &Assembler{dialect: dialect}
+}
+
+// Add records an object of the schema. It never fails; the error lets it be
+// used as a ParseStream callback.
+func (a *Assembler) Add(obj SchemaObject) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.objects = append(a.objects, obj)
+	return nil
+}
+
+// Schema builds the schema of the objects added so far. Indexes, constraints
+// and comments are attached to the table named by their Table field, and data
+// rows are added with sqlmapper.AppendRows, so that they are stored under the
+// table's column names as Parse stores them. Rows of tables that are not part of
+// the input are kept in a table without columns.
+//
+// Indexes, constraints and comments whose table is unknown are left out and
+// reported as CodeOrphanObject warnings, as are triggers on unknown tables,
+// which are kept. Statements a lenient parse could not parse, and rows that do
+// not match the columns of their table, are reported as CodeUnparsedStatement
+// errors, as ParseLenient reports them.
+//
+// Returns:
+//   - *sqlmapper.Schema: The assembled schema
+//   - []sqlmapper.Diagnostic: The orphaned objects and unparsed statements, in input order
+func (a *Assembler) Schema() (*sqlmapper.Schema, []sqlmapper.Diagnostic) {
+	a.mu.Lock()
+	objects := append([]SchemaObject(nil), a.objects...)
+	a.mu.Unlock()
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return positionLess(objects[i].Pos, objects[j].Pos)
+	})
+
+	schema := &sqlmapper.Schema{Dialect: a.dialect}
+	var diagnostics []sqlmapper.Diagnostic
+	orphan := func(obj SchemaObject, path, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, orphanDiagnostic(obj, path, format, args...))
+	}
+
+	// Top-level objects first, so that dependents find their table whatever
+	// order they arrived in
+	for _, obj := range objects {
+		switch data := obj.Data.(type) {
+		case *Namespace:
+			schema.Name = data.Name
+		case *sqlmapper.Extension:
+			schema.Extensions = append(schema.Extensions, *data)
+		case *sqlmapper.Type:
+			schema.Types = append(schema.Types, *data)
+		case *sqlmapper.Sequence:
+			schema.Sequences = append(schema.Sequences, *data)
+		case *sqlmapper.Table:
+			if obj.Type != DataObject {
+				schema.Tables = append(schema.Tables, copyTable(*data))
+			}
+		case *sqlmapper.View:
+			schema.Views = append(schema.Views, *data)
+		case *sqlmapper.Function:
+			schema.Functions = append(schema.Functions, *data)
+		case *sqlmapper.Procedure:
+			schema.Procedures = append(schema.Procedures, *data)
+		case *sqlmapper.Trigger:
+			schema.Triggers = append(schema.Triggers, *data)
+		case *sqlmapper.Permission:
+			schema.Permissions = append(schema.Permissions, *data)
+		case *Unparsed:
+			if data.Err != nil {
+				diagnostics = append(diagnostics, sqlmapper.UnparsedStatement(obj.Pos, data.SQL, data.Err))
+			}
+		}
+	}
+
+	for _, obj := range objects {
+		switch data := obj.Data.(type) {
+		case *sqlmapper.Index:
+			if table := lookupTable(schema, obj.Table); table != nil {
+				table.Indexes = append(table.Indexes, *data)
+			} else {
+				orphan(obj, "tables."+obj.Table+".indexes."+data.Name, "index %s is on unknown table %s", data.Name, obj.Table)
+			}
+		case *sqlmapper.Constraint:
+			if table := lookupTable(schema, obj.Table); table != nil {
+				table.Constraints = append(table.Constraints, *data)
+			} else {
+				orphan(obj, "tables."+obj.Table+".constraints."+data.Name, "constraint %s is on unknown table %s", data.Name, obj.Table)
+			}
+		case *Comment:
+			attachComment(schema, obj, data, orphan)
+		case *sqlmapper.Table:
+			if obj.Type != DataObject {
+				continue
+			}
+			for _, row := range data.Data {
+				columns, values := rowValues(row)
+				if err := sqlmapper.AppendRows(schema, data.Schema, data.Name, columns, [][]interface{}{values}); err != nil {
+					diagnostics = append(diagnostics, sqlmapper.UnparsedStatement(obj.Pos, "", err))
+				}
+			}
+		}
+	}
+
+	for _, obj := range objects {
+		if trigger, ok := obj.Data.(*sqlmapper.Trigger); ok && !triggerTableKnown(schema, trigger.Table) {
+			orphan(obj, "triggers."+trigger.Name, "trigger %s is on unknown table %s", trigger.Name, trigger.Table)
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		var a, b sqlmapper.Position
+		if diagnostics[i].Pos != nil {
+			a = *diagnostics[i].Pos
+		}
+		if diagnostics[j].Pos != nil {
+			b = *diagnostics[j].Pos
+		}
+		return positionLess(a, b)
+	})
+	return schema, diagnostics
+}
+
+// positionLess orders positions by file name and offset
+func positionLess(a, b sqlmapper.Position) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	return a.Offset < b.Offset
+}
+
+// Assemble parses a dump with a stream parser and assembles its objects into a
+// schema
+//
+// Parameters:
+//   - parser: The stream parser of the dump's dialect
+//   - reader: The SQL dump
+//
+// Returns:
+//   - *sqlmapper.Schema: The assembled schema
+//   - []sqlmapper.Diagnostic: The orphaned objects and unparsed statements
+//   - error: An error if parsing fails
+func Assemble(parser StreamParser, reader io.Reader) (*sqlmapper.Schema, []sqlmapper.Diagnostic, error) {
+	assembler := NewAssembler(parser.Dialect())
+	if err := parser.ParseStream(reader, assembler.Add); err != nil {
+		return nil, nil, err
+	}
+	schema, diagnostics := assembler.Schema()
+	return schema, diagnostics, nil
+}
+
+// attachComment sets a comment on its table or column, or reports it as an orphan
+func attachComment(schema *sqlmapper.Schema, obj SchemaObject, comment *Comment, orphan func(SchemaObject, string, string, ...interface{})) {
+	table := lookupTable(schema, obj.Table)
+	if table == nil {
+		orphan(obj, "tables."+obj.Table+".comment", "comment is on unknown table %s", obj.Table)
+		return
+	}
+	if comment.Column == "" {
+		table.Comment = comment.Text
+		return
+	}
+	for i := range table.Columns {
+		if normalizeName(table.Columns[i].Name) == normalizeName(comment.Column) {
+			table.Columns[i].Comment = comment.Text
+			return
+		}
+	}
+	orphan(obj, "tables."+obj.Table+".columns."+comment.Column, "comment is on unknown column %s of table %s", comment.Column, obj.Table)
+}
+
+// lookupTable returns the table of a possibly qualified and quoted name, or nil
+func lookupTable(schema *sqlmapper.Schema, name string) *sqlmapper.Table {
+	table := unquotedTable(name)
+	return sqlmapper.FindTable(schema, table.Schema, table.Name)
+}
+
+// triggerTableKnown reports whether a trigger is on a table or view of the
+// schema. Triggers on the database or schema have no table to check.
+func triggerTableKnown(schema *sqlmapper.Schema, name string) bool {
+	switch normalizeName(name) {
+	case "", "database", "schema":
+		return true
+	}
+	if lookupTable(schema, name) != nil {
+		return true
+	}
+	view := unquotedTable(name)
+	for _, v := range schema.Views {
+		if normalizeName(v.Name) == normalizeName(view.Name) || normalizeName(v.Name) == normalizeName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// rowValues returns the column list and values of a data row. Rows stored under
+// their 1-based position, by INSERT statements without a column list, have no
+// column list.
+func rowValues(row sqlmapper.Row) ([]string, []interface{}) {
+	values := make([]interface{}, len(row.Values))
+	positional := true
+	for i := range values {
+		value, ok := row.Values[strconv.Itoa(i+1)]
+		if !ok {
+			positional = false
+			break
+		}
+		values[i] = value
+	}
+	if positional {
+		return nil, values
+	}
+
+	columns := make([]string, 0, len(row.Values))
+	for column := range row.Values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for i, column := range columns {
+		values[i] = row.Values[column]
+	}
+	return columns, values
+}
+
+// copyTable returns a table whose slices can be changed without changing the
+// object it was delivered in
+func copyTable(table sqlmapper.Table) sqlmapper.Table {
+	table.Columns = append([]sqlmapper.Column(nil), table.Columns...)
+	table.Indexes = append([]sqlmapper.Index(nil), table.Indexes...)
+	table.Constraints = append([]sqlmapper.Constraint(nil), table.Constraints...)
+	table.Data = append([]sqlmapper.Row(nil), table.Data...)
+	return table
+}
+
+// orphanDiagnostic returns the warning of an object whose table is unknown
+func orphanDiagnostic(obj SchemaObject, path, format string, args ...interface{}) sqlmapper.Diagnostic {
+	return sqlmapper.Diagnostic{
+		Severity: sqlmapper.SeverityWarning,
+		Code:     sqlmapper.CodeOrphanObject,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		Pos:      objectPosition(obj),
+	}
+}
+
+// objectPosition returns the position of an object, or nil if it has none
+func objectPosition(obj SchemaObject) *sqlmapper.Position {
+	if !obj.Pos.IsValid() {
+		return nil
+	}
+	pos := obj.Pos
+	return &pos
+}
+
+// normalizeName returns a name without quotes in lower case
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "`\"[]"))
+}
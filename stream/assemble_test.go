@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+// at returns the object with its position set to the given line
+func at(line int, obj SchemaObject) SchemaObject {
+	return obj.WithPosition(sqlmapper.Position{Line: line, Column: 1, Offset: line * 100})
+}
+
+func TestAssembler(t *testing.T) {
+	users := &sqlmapper.Table{Name: "users", Columns: []sqlmapper.Column{
+		{Name: "id", DataType: "INT"},
+		{Name: "created", DataType: "DATE"},
+	}}
+	objects := []SchemaObject{
+		at(1, SchemaObject{Type: NamespaceObject, Data: &Namespace{Name: "shop"}}),
+		at(2, SchemaObject{Type: TableObject, Data: users}),
+		at(3, SchemaObject{Type: TableObject, Data: &sqlmapper.Table{Name: "orders", Columns: []sqlmapper.Column{{Name: "user_id"}}}}),
+		at(4, SchemaObject{Type: IndexObject, Table: `"USERS"`, Data: &sqlmapper.Index{Name: "idx_created", Columns: []string{"created"}}}),
+		at(5, SchemaObject{Type: ConstraintObject, Table: "public.orders", Data: &sqlmapper.Constraint{Name: "fk_user", Type: "FOREIGN KEY"}}),
+		at(6, SchemaObject{Type: CommentObject, Table: "users", Data: &Comment{Text: "Accounts"}}),
+		at(7, SchemaObject{Type: CommentObject, Table: "users", Data: &Comment{Column: "ID", Text: "Key"}}),
+		at(8, SchemaObject{Type: DataObject, Table: "users", Data: &sqlmapper.Table{Name: "users", Data: []sqlmapper.Row{
+			{Values: map[string]interface{}{"1": int64(1), "2": "2024-01-02"}},
+		}}}),
+		at(9, SchemaObject{Type: DataObject, Table: "users", Data: &sqlmapper.Table{Name: "users", Data: []sqlmapper.Row{
+			{Values: map[string]interface{}{"ID": int64(2)}},
+		}}}),
+		at(10, SchemaObject{Type: TriggerObject, Data: &sqlmapper.Trigger{Name: "trg", Table: "orders"}}),
+	}
+
+	// Objects are added concurrently and in reverse order
+	assembler := NewAssembler(sqlmapper.PostgreSQL)
+	var wg sync.WaitGroup
+	for i := len(objects) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(obj SchemaObject) {
+			defer wg.Done()
+			assert.NoError(t, assembler.Add(obj))
+		}(objects[i])
+	}
+	wg.Wait()
+
+	schema, diagnostics := assembler.Schema()
+	assert.Empty(t, diagnostics)
+	assert.Equal(t, sqlmapper.PostgreSQL, schema.Dialect)
+	assert.Equal(t, "shop", schema.Name)
+	if assert.Len(t, schema.Tables, 2) {
+		table := schema.Tables[0]
+		assert.Equal(t, "users", table.Name)
+		assert.Equal(t, "Accounts", table.Comment)
+		assert.Equal(t, "Key", table.Columns[0].Comment)
+		assert.Equal(t, "idx_created", table.Indexes[0].Name)
+
+		// Rows are stored under the column names, with dates converted
+		if assert.Len(t, table.Data, 2) {
+			assert.Equal(t, int64(1), table.Data[0].Values["id"])
+			assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), table.Data[0].Values["created"])
+			assert.Equal(t, map[string]interface{}{"id": int64(2)}, table.Data[1].Values)
+		}
+		assert.Equal(t, "fk_user", schema.Tables[1].Constraints[0].Name)
+	}
+	assert.Len(t, schema.Triggers, 1)
+
+	// The delivered objects are left unchanged
+	assert.Empty(t, users.Indexes)
+	assert.Empty(t, users.Columns[0].Comment)
+
+	// Building the schema again gives the same result
+	again, _ := assembler.Schema()
+	assert.Equal(t, schema, again)
+}
+
+func TestAssembler_Orphans(t *testing.T) {
+	assembler := NewAssembler(sqlmapper.MySQL)
+	for _, obj := range []SchemaObject{
+		at(1, SchemaObject{Type: TableObject, Data: &sqlmapper.Table{Name: "users", Columns: []sqlmapper.Column{{Name: "id"}}}}),
+		at(2, SchemaObject{Type: IndexObject, Table: "missing", Data: &sqlmapper.Index{Name: "idx"}}),
+		at(3, SchemaObject{Type: ConstraintObject, Table: "missing", Data: &sqlmapper.Constraint{Name: "pk"}}),
+		at(4, SchemaObject{Type: CommentObject, Table: "users", Data: &Comment{Column: "email", Text: "x"}}),
+		at(5, SchemaObject{Type: TriggerObject, Data: &sqlmapper.Trigger{Name: "trg", Table: "missing"}}),
+		at(6, SchemaObject{Type: UnparsedObject, Data: &Unparsed{SQL: "CREATE FOO", Err: assert.AnError}}),
+		at(7, SchemaObject{Type: UnparsedObject, Data: &Unparsed{SQL: "SET x = 1"}}),
+		at(8, SchemaObject{Type: DataObject, Table: "logs", Data: &sqlmapper.Table{Name: "logs", Data: []sqlmapper.Row{
+			{Values: map[string]interface{}{"1": "a"}},
+		}}}),
+		at(9, SchemaObject{Type: DataObject, Table: "users", Data: &sqlmapper.Table{Name: "users", Data: []sqlmapper.Row{
+			{Values: map[string]interface{}{"1": "a", "2": "b"}},
+		}}}),
+	} {
+		assert.NoError(t, assembler.Add(obj))
+	}
+
+	schema, diagnostics := assembler.Schema()
+	var codes, paths []string
+	for _, d := range diagnostics {
+		codes = append(codes, string(d.Code))
+		paths = append(paths, d.Path)
+	}
+	assert.Equal(t, []string{"ORPHAN_OBJECT", "ORPHAN_OBJECT", "ORPHAN_OBJECT", "ORPHAN_OBJECT", "UNPARSED_STATEMENT", "UNPARSED_STATEMENT"}, codes)
+	assert.Equal(t, []string{"tables.missing.indexes.idx", "tables.missing.constraints.pk", "tables.users.columns.email", "triggers.trg", "", ""}, paths)
+	assert.Equal(t, sqlmapper.SeverityWarning, diagnostics[0].Severity)
+	assert.Equal(t, 2, diagnostics[0].Pos.Line)
+	assert.Equal(t, 9, diagnostics[5].Pos.Line)
+
+	// Triggers and rows of unknown tables are kept
+	assert.Len(t, schema.Triggers, 1)
+	if assert.Len(t, schema.Tables, 2) {
+		assert.Empty(t, schema.Tables[0].Data)
+		assert.Equal(t, "logs", schema.Tables[1].Name)
+		assert.Equal(t, map[string]interface{}{"1": "a"}, schema.Tables[1].Data[0].Values)
+	}
+}
+
+func TestAssemble(t *testing.T) {
+	parser := &dialectParser{dialect: "assemble", MockStreamParser: &MockStreamParser{
+		parseStreamFunc: func(reader io.Reader, callback func(SchemaObject) error) error {
+			return ParseStatements(reader, tokenizer.Generic, tableParser(), callback, ParseOptions{})
+		},
+	}}
+	schema, diagnostics, err := Assemble(parser, strings.NewReader("CREATE TABLE a;\nCREATE TABLE b;\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, diagnostics)
+	assert.Equal(t, sqlmapper.DatabaseType("assemble"), schema.Dialect)
+	assert.Len(t, schema.Tables, 2)
+}
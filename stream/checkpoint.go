@@ -0,0 +1,201 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// CheckpointOptions configures ConvertResumable
+type CheckpointOptions struct {
+	// Path is the file the checkpoint is persisted to. It is removed once the
+	// conversion completes.
+	Path string
+
+	// Every is the number of statements between two checkpoints.
+	// Values below 1 use 1000.
+	Every int
+
+	// Resume continues from the checkpoint at Path, if there is one
+	Resume bool
+}
+
+// Checkpoint is the persisted state of a conversion. The output holds exactly
+// the objects of the statements before Offset.
+type Checkpoint struct {
+	ReadState
+	Source     sqlmapper.DatabaseType `json:"source"`
+	Target     sqlmapper.DatabaseType `json:"target"`
+	Objects    int64                  `json:"objects"`     // Objects written
+	OutputSize int64                  `json:"output_size"` // Bytes of output written
+}
+
+// ResumableWriter is an output a conversion can be resumed into, such as an
+// *os.File
+type ResumableWriter interface {
+	io.Writer
+	io.Seeker
+	Truncate(size int64) error
+}
+
+// ConvertResumable is ConvertContext for conversions that may be interrupted.
+// Every opts.Every statements it flushes the output and persists a Checkpoint to
+// opts.Path; when the conversion fails, it persists the state after the last
+// statement that completed. With opts.Resume, a conversion starts from the
+// checkpoint: the input is seeked to its offset, and the output is truncated to
+// the size it records, dropping what was written after it, and appended to.
+//
+// The source parser must implement OptionsStreamParser, which all the dialect
+// parsers do. Objects are written as soon as they are parsed, so the only state
+// carried between statements is the terminator set by DELIMITER commands; it is
+// part of the checkpoint.
+//
+// Parameters:
+//   - ctx: The context controlling cancellation
+//   - src: The stream parser of the source dialect
+//   - dst: The stream parser of the target dialect
+//   - input: The source dump
+//   - output: The destination of the converted SQL
+//   - opts: The checkpoint file, its frequency and whether to resume
+//
+// Returns:
+//   - error: An error if parsing, writing or persisting the checkpoint fails
+func ConvertResumable(ctx context.Context, src, dst StreamParser, input io.ReadSeeker, output ResumableWriter, opts CheckpointOptions) error {
+	parser, ok := src.(OptionsStreamParser)
+	if !ok {
+		return fmt.Errorf("stream parser for %s does not support resuming", src.Dialect())
+	}
+	every := int64(opts.Every)
+	if every < 1 {
+		every = 1000
+	}
+
+	from, to := src.Dialect(), dst.Dialect()
+	checkpoint := Checkpoint{Source: from, Target: to}
+	var resume *ReadState
+
+	if opts.Resume {
+		saved, err := LoadCheckpoint(opts.Path)
+		switch {
+		case err == nil:
+			if saved.Source != from || saved.Target != to {
+				return fmt.Errorf("checkpoint is for a conversion from %s to %s", saved.Source, saved.Target)
+			}
+			checkpoint = *saved
+			resume = &checkpoint.ReadState
+		case !errors.Is(err, fs.ErrNotExist):
+			return err
+		}
+	}
+
+	if _, err := input.Seek(checkpoint.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking input: %v", err)
+	}
+	if err := output.Truncate(checkpoint.OutputSize); err != nil {
+		return fmt.Errorf("error truncating output: %v", err)
+	}
+	if _, err := output.Seek(checkpoint.OutputSize, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking output: %v", err)
+	}
+
+	written := &countingWriter{writer: output, n: checkpoint.OutputSize}
+	out := bufio.NewWriter(written)
+
+	// The state after the last statement that completed
+	last := checkpoint
+	saved := checkpoint.Statements
+
+	callback := func(obj SchemaObject) error {
+		if err := dst.GenerateObject(ConvertObjectTypes(obj, from, to), out); err != nil {
+			return err
+		}
+		checkpoint.Objects++
+		return nil
+	}
+
+	onStatement := func(state ReadState) error {
+		checkpoint.ReadState = state
+		last = checkpoint
+		last.OutputSize = written.n + int64(out.Buffered())
+
+		if state.Statements-saved < every {
+			return nil
+		}
+		saved = state.Statements
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		return SaveCheckpoint(opts.Path, &last)
+	}
+
+	err := ParseContext(ctx, input, callback, func(r io.Reader, cb func(SchemaObject) error) error {
+		return parser.ParseStreamOptions(r, cb, ParseOptions{Resume: resume, OnStatement: onStatement})
+	})
+	if err != nil {
+		// Keep what completed, unless the output cannot be written at all
+		if out.Flush() == nil {
+			_ = SaveCheckpoint(opts.Path, &last)
+		}
+		return err
+	}
+
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if err := os.Remove(opts.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error removing checkpoint: %v", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint reads a checkpoint persisted by ConvertResumable. The error
+// wraps fs.ErrNotExist if there is none.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("error decoding checkpoint: %v", err)
+	}
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint persists a checkpoint. The file is replaced atomically, so a
+// crash leaves either the previous checkpoint or the new one.
+func SaveCheckpoint(path string, checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %v", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing checkpoint: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing checkpoint: %v", err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	writer io.Writer
+	n      int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,222 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+// optionsParser is a mock OptionsStreamParser parsing tableDump statements
+type optionsParser struct {
+	*dialectParser
+}
+
+func (p *optionsParser) ParseStreamOptions(reader io.Reader, callback func(SchemaObject) error, opts ParseOptions) error {
+	return ParseStatements(reader, tokenizer.Generic, func(stmt tokenizer.Statement) ([]SchemaObject, error) {
+		name, ok := strings.CutPrefix(stmt.Text, "CREATE TABLE ")
+		if !ok {
+			return nil, nil
+		}
+		return []SchemaObject{{Type: TableObject, Data: &sqlmapper.Table{Name: name}}}, nil
+	}, callback, opts)
+}
+
+// checkpointParsers returns the source and target parsers of the checkpoint tests
+func checkpointParsers() (StreamParser, StreamParser) {
+	src := &optionsParser{&dialectParser{dialect: "checkpoint-src", MockStreamParser: &MockStreamParser{}}}
+	dst := &dialectParser{dialect: "checkpoint-dst", MockStreamParser: &MockStreamParser{
+		generateStreamFunc: func(schema *sqlmapper.Schema, writer io.Writer) error {
+			for _, table := range schema.Tables {
+				if _, err := fmt.Fprintf(writer, "table %s\n", table.Name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}}
+	return src, dst
+}
+
+// convertFile converts input into a new file with ConvertResumable
+func convertFile(t *testing.T, input, outputPath string, opts CheckpointOptions) error {
+	output, err := os.OpenFile(outputPath, os.O_RDWR|os.O_CREATE, 0644)
+	if !assert.NoError(t, err) {
+		return err
+	}
+	defer output.Close()
+
+	src, dst := checkpointParsers()
+	return ConvertResumable(context.Background(), src, dst, strings.NewReader(input), output, opts)
+}
+
+// failingReader fails once n bytes have been read
+type failingReader struct {
+	reader io.Reader
+	n      int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errors.New("read failed")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n, err := r.reader.Read(p)
+	r.n -= n
+	return n, err
+}
+
+func (r *failingReader) Seek(offset int64, whence int) (int64, error) {
+	return r.reader.(io.Seeker).Seek(offset, whence)
+}
+
+func TestConvertResumable(t *testing.T) {
+	dir := t.TempDir()
+	input := tableDump(500)
+
+	want := filepath.Join(dir, "want.sql")
+	assert.NoError(t, convertFile(t, input, want, CheckpointOptions{Path: filepath.Join(dir, "want.json")}))
+	expected, _ := os.ReadFile(want)
+	assert.Equal(t, "table t0\ntable t1\n", string(expected[:len("table t0\ntable t1\n")]))
+	assert.NoFileExists(t, filepath.Join(dir, "want.json"), "removed on completion")
+
+	// A read error halfway through keeps the state of the completed statements
+	gotPath := filepath.Join(dir, "got.sql")
+	checkpointPath := filepath.Join(dir, "got.json")
+	output, err := os.OpenFile(gotPath, os.O_RDWR|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	src, dst := checkpointParsers()
+	err = ConvertResumable(context.Background(), src, dst, &failingReader{reader: strings.NewReader(input), n: len(input) / 2}, output, CheckpointOptions{Path: checkpointPath, Every: 100})
+	assert.EqualError(t, err, "read failed")
+	output.Close()
+
+	checkpoint, err := LoadCheckpoint(checkpointPath)
+	if assert.NoError(t, err) {
+		assert.Greater(t, checkpoint.Statements, int64(0))
+		assert.Less(t, checkpoint.Offset, int64(len(input)))
+		assert.Equal(t, sqlmapper.DatabaseType("checkpoint-src"), checkpoint.Source)
+	}
+
+	assert.NoError(t, convertFile(t, input, gotPath, CheckpointOptions{Path: checkpointPath, Resume: true}))
+	got, _ := os.ReadFile(gotPath)
+	assert.Equal(t, string(expected), string(got))
+	assert.NoFileExists(t, checkpointPath)
+
+	// Resuming without a checkpoint starts from the beginning
+	assert.NoError(t, convertFile(t, input, gotPath, CheckpointOptions{Path: checkpointPath, Resume: true}))
+	got, _ = os.ReadFile(gotPath)
+	assert.Equal(t, string(expected), string(got))
+}
+
+func TestConvertResumable_Errors(t *testing.T) {
+	dir := t.TempDir()
+	checkpointPath := filepath.Join(dir, "checkpoint.json")
+
+	// The checkpoint must be for the same dialects
+	assert.NoError(t, SaveCheckpoint(checkpointPath, &Checkpoint{Source: "mysql", Target: "oracle"}))
+	err := convertFile(t, tableDump(10), filepath.Join(dir, "out.sql"), CheckpointOptions{Path: checkpointPath, Resume: true})
+	assert.EqualError(t, err, "checkpoint is for a conversion from mysql to oracle")
+
+	// Corrupt checkpoints are reported
+	assert.NoError(t, os.WriteFile(checkpointPath, []byte("{"), 0644))
+	err = convertFile(t, tableDump(10), filepath.Join(dir, "out.sql"), CheckpointOptions{Path: checkpointPath, Resume: true})
+	assert.Error(t, err)
+
+	// The source parser must accept options
+	output, _ := os.Create(filepath.Join(dir, "other.sql"))
+	defer output.Close()
+	_, dst := checkpointParsers()
+	err = ConvertResumable(context.Background(), &MockStreamParser{}, dst, strings.NewReader(""), output, CheckpointOptions{Path: checkpointPath})
+	assert.Error(t, err)
+}
+
+// killingFile is an output that exits the process in the middle of a write once
+// limit bytes have been written, as if the process had been killed
+type killingFile struct {
+	*os.File
+	limit int
+}
+
+func (f *killingFile) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		_, _ = f.File.Write(p[:f.limit])
+		os.Exit(3)
+	}
+	f.limit -= len(p)
+	return f.File.Write(p)
+}
+
+// TestConvertResumable_KillHelper runs the conversion that TestConvertResumable_Kill kills
+func TestConvertResumable_KillHelper(t *testing.T) {
+	outputPath := os.Getenv("CHECKPOINT_KILL_OUTPUT")
+	if outputPath == "" {
+		t.Skip("run by TestConvertResumable_Kill")
+	}
+	limit, _ := strconv.Atoi(os.Getenv("CHECKPOINT_KILL_LIMIT"))
+
+	output, err := os.OpenFile(outputPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, dst := checkpointParsers()
+	err = ConvertResumable(context.Background(), src, dst, strings.NewReader(tableDump(5000)), &killingFile{File: output, limit: limit}, CheckpointOptions{
+		Path:   os.Getenv("CHECKPOINT_KILL_PATH"),
+		Every:  250,
+		Resume: true,
+	})
+	t.Fatalf("conversion was not killed: %v", err)
+}
+
+func TestConvertResumable_Kill(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts a process")
+	}
+	dir := t.TempDir()
+	input := tableDump(5000)
+
+	want := filepath.Join(dir, "want.sql")
+	assert.NoError(t, convertFile(t, input, want, CheckpointOptions{Path: filepath.Join(dir, "want.json")}))
+	expected, _ := os.ReadFile(want)
+
+	gotPath := filepath.Join(dir, "got.sql")
+	checkpointPath := filepath.Join(dir, "got.json")
+
+	// Kill the conversion twice, each time in the middle of a write, and resume
+	// from the checkpoint each killed run leaves until it completes
+	for _, limit := range []int{len(expected) / 3, len(expected) / 2} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestConvertResumable_KillHelper$")
+		cmd.Env = append(os.Environ(),
+			"CHECKPOINT_KILL_OUTPUT="+gotPath,
+			"CHECKPOINT_KILL_PATH="+checkpointPath,
+			"CHECKPOINT_KILL_LIMIT="+strconv.Itoa(limit),
+		)
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if assert.True(t, errors.As(err, &exitErr), "%v", err) {
+			assert.Equal(t, 3, exitErr.ExitCode())
+		}
+
+		checkpoint, err := LoadCheckpoint(checkpointPath)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(0), checkpoint.Statements%250)
+			info, _ := os.Stat(gotPath)
+			assert.GreaterOrEqual(t, info.Size(), checkpoint.OutputSize)
+		}
+	}
+
+	assert.NoError(t, convertFile(t, input, gotPath, CheckpointOptions{Path: checkpointPath, Resume: true}))
+	got, _ := os.ReadFile(gotPath)
+	assert.Equal(t, string(expected), string(got))
+}
@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Compression identifies the compression format of a dump
+type Compression int
+
+const (
+	NoCompression Compression = iota
+	Gzip
+	Bzip2
+	Zlib
+)
+
+// String returns the lower-case name of the compression format
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Bzip2:
+		return "bzip2"
+	case Zlib:
+		return "zlib"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+// DetectCompression identifies the compression format from the first bytes of
+// the input. Input shorter than a format's magic bytes is not compressed.
+func DetectCompression(header []byte) Compression {
+	switch {
+	case len(header) >= 2 && header[0] == 0x1f && header[1] == 0x8b:
+		return Gzip
+	case len(header) >= 4 && header[0] == 'B' && header[1] == 'Z' && header[2] == 'h' && header[3] >= '1' && header[3] <= '9':
+		return Bzip2
+	case len(header) >= 2 && header[0] == 0x78 && header[1]&0x20 == 0 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0:
+		// Deflate with a 32 KiB window, no preset dictionary and a valid header
+		// checksum, as written by every common zlib encoder
+		return Zlib
+	default:
+		return NoCompression
+	}
+}
+
+// Decompress sniffs the magic bytes of the input and returns a reader of its
+// decompressed content. Uncompressed input is returned as is.
+//
+// Parameters:
+//   - reader: The dump, compressed with gzip, bzip2 or zlib, or not at all
+//
+// Returns:
+//   - io.Reader: The decompressed dump, to be passed to ParseStream
+//   - Compression: The format detected
+//   - error: An error if the compressed header is invalid
+func Decompress(reader io.Reader) (io.Reader, Compression, error) {
+	buffered := bufio.NewReader(reader)
+	header, err := buffered.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, NoCompression, fmt.Errorf("error reading input: %v", err)
+	}
+
+	compression := DetectCompression(header)
+	switch compression {
+	case Gzip:
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, compression, fmt.Errorf("error reading gzip header: %v", err)
+		}
+		return gz, compression, nil
+	case Bzip2:
+		return bzip2.NewReader(buffered), compression, nil
+	case Zlib:
+		zr, err := zlib.NewReader(buffered)
+		if err != nil {
+			return nil, compression, fmt.Errorf("error reading zlib header: %v", err)
+		}
+		return zr, compression, nil
+	default:
+		return buffered, compression, nil
+	}
+}
+
+// Compress returns a writer that compresses what is written to it into writer,
+// such as the output of GenerateStream. The returned writer must be closed to
+// flush the compressed stream; closing it does not close writer.
+//
+// Parameters:
+//   - writer: The destination of the compressed output
+//   - compression: Gzip, Zlib or NoCompression; the standard library cannot write bzip2
+//
+// Returns:
+//   - io.WriteCloser: The compressing writer
+//   - error: An error if the format cannot be written
+func Compress(writer io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case NoCompression:
+		return nopWriteCloser{writer}, nil
+	case Gzip:
+		return gzip.NewWriter(writer), nil
+	case Zlib:
+		return zlib.NewWriter(writer), nil
+	default:
+		return nil, fmt.Errorf("unsupported output compression: %s", compression)
+	}
+}
+
+// nopWriteCloser adds a no-op Close to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
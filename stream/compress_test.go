@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+const compressInput = "CREATE TABLE a;\nCREATE TABLE b;\n"
+
+// bzip2Input is compressInput compressed with bzip2, which the standard library
+// cannot write
+const bzip2Input = "425a6839314159265359442c0c070000075f000010400000083a0414003000200020aa8007a840d0342cd1245db697791e446d57c5dc914e1424110b0301c0"
+
+func compressed(t *testing.T, compression Compression) []byte {
+	if compression == Bzip2 {
+		data, err := hex.DecodeString(bzip2Input)
+		assert.NoError(t, err)
+		return data
+	}
+
+	var buf bytes.Buffer
+	w, err := Compress(&buf, compression)
+	assert.NoError(t, err)
+	_, err = io.WriteString(w, compressInput)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	for _, compression := range []Compression{NoCompression, Gzip, Bzip2, Zlib} {
+		t.Run(compression.String(), func(t *testing.T) {
+			data := compressed(t, compression)
+			assert.Equal(t, compression, DetectCompression(data))
+
+			reader, detected, err := Decompress(bytes.NewReader(data))
+			assert.NoError(t, err)
+			assert.Equal(t, compression, detected)
+
+			var names []string
+			err = ParseStatements(reader, tokenizer.Generic, tableParser(), func(obj SchemaObject) error {
+				names = append(names, obj.Data.(*sqlmapper.Table).Name)
+				return nil
+			}, ParseOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, names)
+		})
+	}
+}
+
+func TestDetectCompression(t *testing.T) {
+	assert.Equal(t, NoCompression, DetectCompression(nil))
+	assert.Equal(t, NoCompression, DetectCompression([]byte{0x1f}))
+	assert.Equal(t, NoCompression, DetectCompression([]byte("BZh")))
+	assert.Equal(t, NoCompression, DetectCompression([]byte("xyz")))
+
+	var buf bytes.Buffer
+	w, _ := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	w.Close()
+	assert.Equal(t, Zlib, DetectCompression(buf.Bytes()))
+}
+
+func TestDecompress_Errors(t *testing.T) {
+	// A gzip magic number followed by a truncated header
+	_, _, err := Decompress(bytes.NewReader([]byte{0x1f, 0x8b, 0x08}))
+	assert.Error(t, err)
+
+	// Corrupt data is reported on read
+	reader, _, err := Decompress(bytes.NewReader([]byte("BZh9garbage")))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+
+	// Short uncompressed input is returned as is
+	reader, compression, err := Decompress(strings.NewReader("x"))
+	assert.NoError(t, err)
+	assert.Equal(t, NoCompression, compression)
+	data, _ := io.ReadAll(reader)
+	assert.Equal(t, "x", string(data))
+}
+
+func TestCompress(t *testing.T) {
+	_, err := Compress(io.Discard, Bzip2)
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	w, err := Compress(&buf, Gzip)
+	assert.NoError(t, err)
+	_, _ = io.WriteString(w, compressInput)
+	assert.NoError(t, w.Close())
+
+	gz, err := gzip.NewReader(&buf)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(gz)
+	assert.Equal(t, compressInput, string(data))
+}
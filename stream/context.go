@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"context"
+	"io"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// ParseContext runs a ParseStream implementation under a context. Reading stops
+// at the next read of the input once ctx is done, no objects are delivered after
+// that, and ctx.Err() is returned.
+//
+// A read that is blocked when ctx ends, such as on a network connection or a
+// pipe, only returns if reader implements io.Closer: it is then closed, so
+// ParseContext returns promptly. Other readers are stopped at their next read.
+//
+// Parameters:
+//   - ctx: The context controlling cancellation
+//   - reader: The SQL dump to parse
+//   - callback: Called with each parsed object
+//   - parse: The ParseStream method of a stream parser
+//
+// Returns:
+//   - error: ctx.Err() if the context ended, otherwise the error of parse
+func ParseContext(ctx context.Context, reader io.Reader, callback func(SchemaObject) error, parse func(io.Reader, func(SchemaObject) error) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer closeOnDone(ctx, reader)()
+
+	err := parse(&contextReader{ctx: ctx, reader: reader}, func(obj SchemaObject) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return callback(obj)
+	})
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// GenerateContext runs a GenerateStream implementation under a context. Writing
+// stops at the next statement once ctx is done and ctx.Err() is returned.
+//
+// Parameters:
+//   - ctx: The context controlling cancellation
+//   - schema: The schema to generate
+//   - writer: The destination of the generated SQL
+//   - generate: The GenerateStream method of a stream parser
+//
+// Returns:
+//   - error: ctx.Err() if the context ended, otherwise the error of generate
+func GenerateContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer, generate func(*sqlmapper.Schema, io.Writer) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := generate(schema, &contextWriter{ctx: ctx, writer: writer})
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// closeOnDone closes reader when ctx ends, if it is an io.Closer, to unblock
+// a read in progress. It looks through the readers this package wraps inputs
+// in. The returned function stops watching ctx; it must be called once reading
+// is over.
+func closeOnDone(ctx context.Context, reader io.Reader) func() bool {
+	for {
+		switch r := reader.(type) {
+		case io.Closer:
+			return context.AfterFunc(ctx, func() { r.Close() })
+		case *progressReader:
+			reader = r.reader
+		case *contextReader:
+			reader = r.reader
+		default:
+			return func() bool { return false }
+		}
+	}
+}
+
+// contextReader fails reads once its context is done. A read interrupted by
+// closeOnDone fails with ctx.Err() as well.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.reader.Read(p)
+	if err != nil && r.ctx.Err() != nil {
+		return n, r.ctx.Err()
+	}
+	return n, err
+}
+
+// contextWriter fails writes once its context is done
+type contextWriter struct {
+	ctx    context.Context
+	writer io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.writer.Write(p)
+}
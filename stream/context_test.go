@@ -0,0 +1,272 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkGoroutines fails the test if goroutines started during it are still
+// running when it ends. Like goleak, it retries for a while so that goroutines
+// that are about to exit are not reported.
+func checkGoroutines(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<20)
+				n := runtime.Stack(buf, true)
+				t.Errorf("%d goroutines leaked:\n%s", runtime.NumGoroutine()-before, buf[:n])
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// statementParser returns a mock parser that emits one table object per
+// statement, named after the statement, after the given delay
+func statementParser(delay time.Duration) *MockStreamParser {
+	return &MockStreamParser{
+		parseStreamFunc: func(reader io.Reader, callback func(SchemaObject) error) error {
+			streamReader := NewStreamReader(reader, ";")
+			for {
+				statement, err := streamReader.ReadStatement()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				time.Sleep(delay)
+				if err := callback(SchemaObject{Type: TableObject, Data: strings.TrimSpace(statement)}); err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
+
+func statements(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "s%d;\n", i)
+	}
+	return sb.String()
+}
+
+func TestWorkerPool_ProcessContext(t *testing.T) {
+	t.Run("callback error", func(t *testing.T) {
+		checkGoroutines(t)
+
+		stop := errors.New("stop")
+		var names []string
+		err := NewWorkerPool(4, statementParser(0)).ProcessContext(context.Background(), strings.NewReader(statements(1000)), func(obj SchemaObject) error {
+			names = append(names, obj.Data.(string))
+			if len(names) == 3 {
+				return stop
+			}
+			return nil
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, []string{"s0", "s1", "s2"}, names)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		checkGoroutines(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		count := 0
+		err := NewWorkerPool(4, statementParser(0)).ProcessContext(ctx, strings.NewReader(statements(1000)), func(obj SchemaObject) error {
+			count++
+			if count == 5 {
+				cancel()
+			}
+			return nil
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		checkGoroutines(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := NewWorkerPool(2, statementParser(5*time.Millisecond)).ProcessContext(ctx, strings.NewReader(statements(1000)), func(obj SchemaObject) error {
+			return nil
+		})
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		checkGoroutines(t)
+
+		count := 0
+		err := NewWorkerPool(4, statementParser(0)).Process(strings.NewReader(statements(100)), func(obj SchemaObject) error {
+			assert.Equal(t, fmt.Sprintf("s%d", count), obj.Data)
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 100, count)
+	})
+}
+
+func TestParseContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	err := statementParser(0).ParseStreamContext(ctx, strings.NewReader(statements(100)), func(obj SchemaObject) error {
+		count++
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, count)
+
+	// A context that is already done stops parsing before anything is read
+	err = statementParser(0).ParseStreamContext(ctx, strings.NewReader(statements(1)), func(obj SchemaObject) error {
+		t.Error("unexpected object")
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
+
+// cancelWriter cancels a context on its first write
+type cancelWriter struct {
+	cancel context.CancelFunc
+	writes int
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.cancel()
+	return len(p), nil
+}
+
+func TestGenerateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parser := &MockStreamParser{
+		generateStreamFunc: func(schema *sqlmapper.Schema, writer io.Writer) error {
+			for _, table := range schema.Tables {
+				if _, err := writer.Write([]byte("CREATE TABLE " + table.Name + ";\n")); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	writer := &cancelWriter{cancel: cancel}
+	schema := &sqlmapper.Schema{Tables: []sqlmapper.Table{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	err := parser.GenerateStreamContext(ctx, schema, writer)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, writer.writes)
+}
+
+func TestParseStreamParallel_NoLeak(t *testing.T) {
+	checkGoroutines(t)
+
+	stop := errors.New("stop")
+	err := ParseStreamParallel(strings.NewReader(tableDump(1000)), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		return stop
+	}, ParallelOptions{Workers: 8})
+	assert.Equal(t, stop, err)
+}
+
+// blockingInput returns a pipe that delivers the given statements and then
+// blocks until it is closed, like a network connection that went quiet
+func blockingInput(t *testing.T, input string) *io.PipeReader {
+	reader, writer := io.Pipe()
+	go func() {
+		_, _ = writer.Write([]byte(input))
+	}()
+	t.Cleanup(func() { writer.Close() })
+	return reader
+}
+
+func TestContext_BlockedRead(t *testing.T) {
+	checkGoroutines(t)
+
+	// Closable inputs are closed when the context ends, so blocked reads return
+	t.Run("ParseContext", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := statementParser(0).ParseStreamContext(ctx, blockingInput(t, statements(3)), func(obj SchemaObject) error {
+			return nil
+		})
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("ParseStreamParallelContext", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := ParseStreamParallelContext(ctx, blockingInput(t, tableDump(3)), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+			return nil
+		}, ParallelOptions{Workers: 2})
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("ProcessContext", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := NewWorkerPool(2, statementParser(0)).ProcessContext(ctx, blockingInput(t, statements(3)), func(obj SchemaObject) error {
+			return nil
+		})
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
+
+func TestWorkerPool_ErrorsDoNotStopWorkers(t *testing.T) {
+	checkGoroutines(t)
+
+	pool := NewWorkerPool(2, &MockStreamParser{
+		parseStreamFunc: func(reader io.Reader, callback func(SchemaObject) error) error {
+			return errors.New("invalid SQL syntax")
+		},
+	})
+	pool.Start()
+
+	errs := make(chan int)
+	go func() {
+		count := 0
+		for range pool.Errors() {
+			count++
+		}
+		errs <- count
+	}()
+
+	// More statements than workers fail; Submit must not block
+	for i := 0; i < 10; i++ {
+		pool.Submit(fmt.Sprintf("s%d;", i))
+	}
+	pool.Wait()
+	assert.Equal(t, 10, <-errs)
+}
@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// Convert translates a SQL dump from the dialect of src to the dialect of dst.
+// Each object is written as soon as it is parsed, so the dump is never held in
+// memory as a whole: memory use depends on the largest statement, not on the
+// size of the dump. Objects are written in input order; indexes and constraints
+// defined after their table are written as standalone statements.
+//
+// Parameters:
+//   - src: The stream parser of the source dialect
+//   - dst: The stream parser of the target dialect
+//   - r: The source dump
+//   - w: The destination of the converted SQL
+//
+// Returns:
+//   - error: An error if parsing or writing fails
+func Convert(src, dst StreamParser, r io.Reader, w io.Writer) error {
+	return ConvertContext(context.Background(), src, dst, r, w)
+}
+
+// ConvertContext is Convert that stops and returns ctx.Err() once ctx is done
+func ConvertContext(ctx context.Context, src, dst StreamParser, r io.Reader, w io.Writer) error {
+	from, to := src.Dialect(), dst.Dialect()
+	out := bufio.NewWriter(w)
+
+	err := src.ParseStreamContext(ctx, r, func(obj SchemaObject) error {
+		// Table holds the name as written in the source, with the quotes of its dialect
+		if obj.Table != "" {
+			table := unquotedTable(obj.Table)
+			obj.Table = qualifiedName(table.Schema, table.Name)
+		}
+		return dst.GenerateObject(ConvertObjectTypes(obj, from, to), out)
+	})
+	if err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// ConvertObjectTypes returns a copy of the object whose column and routine
+// parameter data types have been translated from one dialect to another, as
+// sqlmapper.ConvertTypes does for a schema
+func ConvertObjectTypes(obj SchemaObject, from, to sqlmapper.DatabaseType) SchemaObject {
+	schema := ObjectSchema(obj)
+	schema.Dialect = from
+	converted := sqlmapper.ConvertTypes(schema, to)
+
+	switch obj.Data.(type) {
+	case *sqlmapper.Table:
+		obj.Data = &converted.Tables[0]
+	case *sqlmapper.Function:
+		obj.Data = &converted.Functions[0]
+	case *sqlmapper.Procedure:
+		obj.Data = &converted.Procedures[0]
+	}
+	return obj
+}
+
+// ObjectSchema returns a schema holding only the given object. Indexes and
+// constraints are added to a table without columns named by obj.Table, and a
+// namespace sets the schema name.
+func ObjectSchema(obj SchemaObject) *sqlmapper.Schema {
+	schema := &sqlmapper.Schema{}
+	switch data := obj.Data.(type) {
+	case *sqlmapper.Table:
+		schema.Tables = []sqlmapper.Table{*data}
+	case *sqlmapper.View:
+		schema.Views = []sqlmapper.View{*data}
+	case *sqlmapper.Function:
+		schema.Functions = []sqlmapper.Function{*data}
+	case *sqlmapper.Procedure:
+		schema.Procedures = []sqlmapper.Procedure{*data}
+	case *sqlmapper.Trigger:
+		schema.Triggers = []sqlmapper.Trigger{*data}
+	case *sqlmapper.Sequence:
+		schema.Sequences = []sqlmapper.Sequence{*data}
+	case *sqlmapper.Type:
+		schema.Types = []sqlmapper.Type{*data}
+	case *sqlmapper.Permission:
+		schema.Permissions = []sqlmapper.Permission{*data}
+	case *sqlmapper.Extension:
+		schema.Extensions = []sqlmapper.Extension{*data}
+	case *Namespace:
+		schema.Name = data.Name
+	case *sqlmapper.Index:
+		schema.Tables = []sqlmapper.Table{{Name: obj.Table, Indexes: []sqlmapper.Index{*data}}}
+	case *sqlmapper.Constraint:
+		schema.Tables = []sqlmapper.Table{{Name: obj.Table, Constraints: []sqlmapper.Constraint{*data}}}
+	}
+	return schema
+}
+
+// indexTableRe captures the table name of a CREATE INDEX statement as written
+var indexTableRe = regexp.MustCompile(`(?is)\bINDEX\b.*?\bON\s+([^\s(]+)`)
+
+// IndexTable returns the table name of a CREATE INDEX statement as it is written
+// in the statement, or an empty string. The dialect parsers only attach indexes
+// to tables they know, so stream parsers use it to provide the table.
+func IndexTable(statement string) string {
+	if match := indexTableRe.FindStringSubmatch(statement); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/stretchr/testify/assert"
+)
+
+// dialectParser is a mock parser that reports a dialect
+type dialectParser struct {
+	*MockStreamParser
+	dialect sqlmapper.DatabaseType
+}
+
+func (p *dialectParser) Dialect() sqlmapper.DatabaseType {
+	return p.dialect
+}
+
+func TestConvert(t *testing.T) {
+	sqlmapper.RegisterTypeMapping("convert-src", "convert-dst", map[string]string{"number": "integer"})
+
+	objects := []SchemaObject{
+		{Type: TableObject, Data: &sqlmapper.Table{Name: "users", Columns: []sqlmapper.Column{{Name: "id", DataType: "NUMBER"}}}},
+		{Type: IndexObject, Table: "users", Data: &sqlmapper.Index{Name: "idx_users_id", Columns: []string{"id"}}},
+		{Type: ConstraintObject, Table: "users", Data: &sqlmapper.Constraint{Name: "pk_users", Type: "PRIMARY KEY", Columns: []string{"id"}}},
+	}
+
+	src := &dialectParser{dialect: "convert-src", MockStreamParser: &MockStreamParser{
+		parseStreamFunc: func(reader io.Reader, callback func(SchemaObject) error) error {
+			for _, obj := range objects {
+				if err := callback(obj); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}}
+
+	dst := &dialectParser{dialect: "convert-dst", MockStreamParser: &MockStreamParser{
+		generateStreamFunc: func(schema *sqlmapper.Schema, writer io.Writer) error {
+			for _, table := range schema.Tables {
+				for _, col := range table.Columns {
+					fmt.Fprintf(writer, "column %s.%s %s\n", table.Name, col.Name, col.DataType)
+				}
+				for _, index := range table.Indexes {
+					fmt.Fprintf(writer, "index %s.%s\n", table.Name, index.Name)
+				}
+				for _, constraint := range table.Constraints {
+					fmt.Fprintf(writer, "constraint %s.%s\n", table.Name, constraint.Name)
+				}
+			}
+			return nil
+		},
+	}}
+
+	var out strings.Builder
+	assert.NoError(t, Convert(src, dst, strings.NewReader(""), &out))
+	assert.Equal(t, "column users.id integer\nindex users.idx_users_id\nconstraint users.pk_users\n", out.String())
+
+	// The source objects are not modified
+	assert.Equal(t, "NUMBER", objects[0].Data.(*sqlmapper.Table).Columns[0].DataType)
+
+	// Write errors are returned
+	err := Convert(src, dst, strings.NewReader(""), failingWriter{})
+	assert.Error(t, err)
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestIndexTable(t *testing.T) {
+	tests := map[string]string{
+		"CREATE INDEX idx ON users (email)":                  "users",
+		"CREATE UNIQUE INDEX idx ON app.users(email)":        "app.users",
+		"create index idx\n  on [dbo].[users] (email)":       "[dbo].[users]",
+		"CREATE NONCLUSTERED INDEX idx ON users (a) ON [PR]": "users",
+		"CREATE TABLE users (id INT)":                        "",
+	}
+	for statement, want := range tests {
+		assert.Equal(t, want, IndexTable(statement), statement)
+	}
+}
@@ -0,0 +1,188 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+const (
+	// ManifestFile lists the object files written by GenerateDirectory, one
+	// path per line, in the order they are to be run
+	ManifestFile = "manifest.txt"
+
+	// IncludeFile is the script written by GenerateDirectory that runs all
+	// object files with the include command of the target dialect's client
+	IncludeFile = "00_all.sql"
+)
+
+// GenerateDirectory is GenerateStream that writes every object to a file of
+// its own, so that a schema can be reviewed and versioned object by object.
+// Files are grouped by object type and named after the object:
+//
+//	types/<name>.sql
+//	sequences/<name>.sql
+//	tables/<name>.sql               table, indexes and data
+//	constraints/<table>.<name>.sql  foreign keys that close a reference cycle
+//	views/<name>.sql
+//	functions/<name>.sql
+//	procedures/<name>.sql
+//	triggers/<name>.sql
+//
+// Objects are ordered so that every object comes after the objects it depends
+// on, as GenerateStream orders them. The order is recorded in ManifestFile, and
+// IncludeFile runs the files in that order with the include command of the
+// dialect's client, such as psql's \i or SQL*Plus's @, to be run from dir. For
+// dialects registered without an IncludeFormat, IncludeFile holds the
+// statements of all files instead.
+//
+// Data types are translated from the dialect of the schema. Objects the target
+// dialect writes nothing for get no file, and names that are not valid file
+// names, or that differ only in case, are made unique. Files of earlier runs
+// that are not written again are left in place.
+//
+// Parameters:
+//   - parser: The stream parser of the target dialect
+//   - schema: The schema to generate
+//   - dir: The output directory, created if it does not exist
+//
+// Returns:
+//   - []string: The object files written, relative to dir and in run order
+//   - error: An error if generation or writing fails
+func GenerateDirectory(parser StreamParser, schema *sqlmapper.Schema, dir string) ([]string, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema cannot be nil")
+	}
+	from, to := schema.Dialect, parser.Dialect()
+	sorted, deferred := sqlmapper.SortSchema(schema)
+
+	var files []string
+	var all bytes.Buffer
+	used := make(map[string]bool)
+
+	write := func(kind, name string, obj SchemaObject) error {
+		var buf bytes.Buffer
+		if err := parser.GenerateObject(ConvertObjectTypes(obj, from, to), &buf); err != nil {
+			return err
+		}
+		if strings.TrimSpace(buf.String()) == "" {
+			return nil
+		}
+
+		file := uniqueFile(used, kind, name)
+		if err := os.MkdirAll(filepath.Join(dir, kind), 0755); err != nil {
+			return fmt.Errorf("error creating directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(file)), buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("error writing %s: %v", file, err)
+		}
+		files = append(files, file)
+		all.Write(buf.Bytes())
+		return nil
+	}
+
+	for i := range sorted.Types {
+		if err := write("types", sorted.Types[i].Name, SchemaObject{Type: TypeObject, Data: &sorted.Types[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range sorted.Sequences {
+		if err := write("sequences", sorted.Sequences[i].Name, SchemaObject{Type: SequenceObject, Data: &sorted.Sequences[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range sorted.Tables {
+		table := &sorted.Tables[i]
+		if err := write("tables", qualifiedName(table.Schema, table.Name), SchemaObject{Type: TableObject, Data: table}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range deferred {
+		fk := &deferred[i]
+		if err := write("constraints", fk.Table+"."+fk.Constraint.Name, SchemaObject{Type: ConstraintObject, Table: fk.Table, Data: &fk.Constraint}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range sorted.Views {
+		if err := write("views", sorted.Views[i].Name, SchemaObject{Type: ViewObject, Data: &sorted.Views[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for _, procs := range []bool{false, true} {
+		kind, typ := "functions", FunctionObject
+		if procs {
+			kind, typ = "procedures", ProcedureObject
+		}
+		for i := range sorted.Functions {
+			if sorted.Functions[i].IsProc == procs {
+				if err := write(kind, sorted.Functions[i].Name, SchemaObject{Type: typ, Data: &sorted.Functions[i]}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for i := range sorted.Procedures {
+		if err := write("procedures", sorted.Procedures[i].Name, SchemaObject{Type: ProcedureObject, Data: &sorted.Procedures[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range sorted.Triggers {
+		if err := write("triggers", sorted.Triggers[i].Name, SchemaObject{Type: TriggerObject, Data: &sorted.Triggers[i]}); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := strings.Join(files, "\n")
+	if manifest != "" {
+		manifest += "\n"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644); err != nil {
+		return nil, fmt.Errorf("error writing manifest: %v", err)
+	}
+
+	include := all.Bytes()
+	if _, dialect, ok := sqlmapper.LookupDialect(string(to)); ok && dialect.IncludeFormat != "" {
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "-- Runs the %s object scripts in dependency order; run it from this directory\n", to)
+		for _, file := range files {
+			fmt.Fprintf(&buf, dialect.IncludeFormat+"\n", file)
+		}
+		include = buf.Bytes()
+	}
+	if err := os.WriteFile(filepath.Join(dir, IncludeFile), include, 0644); err != nil {
+		return nil, fmt.Errorf("error writing include script: %v", err)
+	}
+
+	return files, nil
+}
+
+// uniqueFile returns the slash-separated path of an object file that is not yet
+// used, compared case-insensitively, and marks it used
+func uniqueFile(used map[string]bool, kind, name string) string {
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.', r == '$':
+			return r
+		}
+		return '_'
+	}, strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(name))
+	base = strings.Trim(base, ".")
+	if base == "" {
+		base = "unnamed"
+	}
+
+	file := path.Join(kind, base+".sql")
+	for n := 2; used[strings.ToLower(file)]; n++ {
+		file = path.Join(kind, fmt.Sprintf("%s_%d.sql", base, n))
+	}
+	used[strings.ToLower(file)] = true
+	return file
+}
@@ -0,0 +1,58 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDirectory(t *testing.T) {
+	// A dialect without an include command; views are not written
+	parser := &dialectParser{dialect: "directory", MockStreamParser: &MockStreamParser{
+		generateStreamFunc: func(schema *sqlmapper.Schema, writer io.Writer) error {
+			for _, table := range schema.Tables {
+				fmt.Fprintf(writer, "table %s;\n", table.Name)
+			}
+			for _, function := range schema.Functions {
+				fmt.Fprintf(writer, "function %s;\n", function.Name)
+			}
+			return nil
+		},
+	}}
+	schema := &sqlmapper.Schema{
+		Tables: []sqlmapper.Table{
+			{Name: "Users"},
+			{Name: "users"},
+			{Schema: "app", Name: `"my/table"`},
+		},
+		Views:     []sqlmapper.View{{Name: "v"}},
+		Functions: []sqlmapper.Function{{Name: "p", IsProc: true}, {Name: "f"}},
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	files, err := GenerateDirectory(parser, schema, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"tables/Users.sql",
+		"tables/users_2.sql",
+		"tables/app.my_table.sql",
+		"functions/f.sql",
+		"procedures/p.sql",
+	}, files)
+
+	users, _ := os.ReadFile(filepath.Join(dir, "tables", "users_2.sql"))
+	assert.Equal(t, "table users;\n", string(users))
+	assert.NoDirExists(t, filepath.Join(dir, "views"))
+
+	// Without an include command the script holds the statements themselves
+	include, _ := os.ReadFile(filepath.Join(dir, IncludeFile))
+	assert.Equal(t, "table Users;\ntable users;\ntable \"my/table\";\nfunction f;\nfunction p;\n", string(include))
+
+	_, err = GenerateDirectory(parser, nil, dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package stream
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// Namespace is the data of a NamespaceObject
+type Namespace struct {
+	Name string // Name of the database or schema
+}
+
+// Comment is the data of a CommentObject
+type Comment struct {
+	Column string // Column the comment is on, or empty for a table comment
+	Text   string
+}
+
+// Statement returns the COMMENT ON statement setting the comment on a table,
+// without terminator
+func (c *Comment) Statement(table string) string {
+	text := strings.ReplaceAll(c.Text, "'", "''")
+	if c.Column == "" {
+		return fmt.Sprintf("COMMENT ON TABLE %s IS '%s'", table, text)
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s'", table, c.Column, text)
+}
+
+// LineComment returns the COMMENT ON statement as a single-line SQL comment
+// prefixed with reason, for dialects that cannot set the comment
+func (c *Comment) LineComment(table, reason string) string {
+	stmt := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(c.Statement(table))
+	return fmt.Sprintf("-- %s: %s", reason, stmt)
+}
+
+var (
+	// commentRe captures the target and text of a COMMENT ON TABLE or COLUMN statement
+	commentRe = regexp.MustCompile(`(?is)^\s*COMMENT\s+ON\s+(TABLE|COLUMN)\s+(\S+)\s+IS\s+'((?:[^']|'')*)'\s*$`)
+
+	// createIndexRe matches CREATE INDEX statements, whatever their index options
+	createIndexRe = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:\w+\s+)*?INDEX\b`)
+
+	// alterTableRe captures the table name of an ALTER TABLE statement as written
+	alterTableRe = regexp.MustCompile(`(?is)^\s*ALTER\s+TABLE\s+(?:ONLY\s+)?(?:IF\s+EXISTS\s+)?([^\s(]+)`)
+)
+
+// StatementObjects runs the batch parser of a dialect on a single statement and
+// returns the objects it added to an empty schema, so that a stream parser
+// delivers the same objects as Parse.
+//
+// Statements that change a table defined earlier need no knowledge of it: the
+// indexes of CREATE INDEX and the constraints of ALTER TABLE are returned as
+// IndexObject and ConstraintObject objects, and the rows of INSERT as a
+// DataObject holding a table without columns. Their Table field is the table
+// name as written in the statement. Columns added by ALTER TABLE yield no
+// object, as a table cannot be changed once it has been delivered.
+//
+// COMMENT ON TABLE and COMMENT ON COLUMN statements are not passed to parse: the
+// batch parsers only see the comments of tables in the same statement. They are
+// returned as a CommentObject on the table named by Table.
+//
+// Parameters:
+//   - statement: The statement text, used to find the table it changes
+//   - parse: Parses the statement into the given schema
+//
+// Returns:
+//   - []SchemaObject: The objects defined by the statement
+//   - error: The error returned by parse
+func StatementObjects(statement string, parse func(schema *sqlmapper.Schema) error) ([]SchemaObject, error) {
+	if match := commentRe.FindStringSubmatch(statement); match != nil {
+		comment := &Comment{Text: strings.ReplaceAll(match[3], "''", "'")}
+		table := match[2]
+		if strings.EqualFold(match[1], "COLUMN") {
+			i := strings.LastIndex(table, ".")
+			if i == -1 {
+				return nil, fmt.Errorf("COMMENT ON COLUMN %s has no table name", table)
+			}
+			table, comment.Column = table[:i], strings.Trim(table[i+1:], "`\"[]")
+		}
+		return []SchemaObject{{Type: CommentObject, Table: table, Data: comment}}, nil
+	}
+
+	schema := &sqlmapper.Schema{}
+
+	// The dialect parsers only attach indexes and constraints to tables they know
+	var changed string
+	if createIndexRe.MatchString(statement) {
+		changed = IndexTable(statement)
+	} else if match := alterTableRe.FindStringSubmatch(statement); match != nil {
+		changed = match[1]
+	}
+	if changed != "" {
+		schema.Tables = []sqlmapper.Table{unquotedTable(changed)}
+	}
+
+	if err := parse(schema); err != nil {
+		return nil, err
+	}
+
+	var objects []SchemaObject
+	add := func(typ SchemaObjectType, table string, data interface{}) {
+		objects = append(objects, SchemaObject{Type: typ, Table: table, Data: data})
+	}
+
+	if schema.Name != "" {
+		add(NamespaceObject, "", &Namespace{Name: schema.Name})
+	}
+	for i := range schema.Extensions {
+		add(ExtensionObject, "", &schema.Extensions[i])
+	}
+	for i := range schema.Types {
+		add(TypeObject, "", &schema.Types[i])
+	}
+	for i := range schema.Sequences {
+		add(SequenceObject, "", &schema.Sequences[i])
+	}
+	for i := range schema.Tables {
+		table := &schema.Tables[i]
+		switch {
+		case i == 0 && changed != "":
+			for j := range table.Indexes {
+				add(IndexObject, changed, &table.Indexes[j])
+			}
+			for j := range table.Constraints {
+				add(ConstraintObject, changed, &table.Constraints[j])
+			}
+		case len(table.Columns) > 0:
+			add(TableObject, "", table)
+		case len(table.Data) > 0:
+			add(DataObject, qualifiedName(table.Schema, table.Name), table)
+		}
+	}
+	for i := range schema.Views {
+		add(ViewObject, "", &schema.Views[i])
+	}
+	for i := range schema.Functions {
+		// Dialects that parse procedures as functions mark them with IsProc
+		typ := FunctionObject
+		if schema.Functions[i].IsProc {
+			typ = ProcedureObject
+		}
+		add(typ, "", &schema.Functions[i])
+	}
+	for i := range schema.Procedures {
+		add(ProcedureObject, "", &schema.Procedures[i])
+	}
+	for i := range schema.Triggers {
+		add(TriggerObject, "", &schema.Triggers[i])
+	}
+	for i := range schema.Permissions {
+		add(PermissionObject, "", &schema.Permissions[i])
+	}
+	return objects, nil
+}
+
+// unquotedTable returns a table named by a possibly qualified and quoted name
+func unquotedTable(name string) sqlmapper.Table {
+	unquote := func(part string) string {
+		return strings.Trim(part, "`\"[]")
+	}
+	if i := strings.LastIndex(name, "."); i != -1 {
+		return sqlmapper.Table{Schema: unquote(name[:i]), Name: unquote(name[i+1:])}
+	}
+	return sqlmapper.Table{Name: unquote(name)}
+}
+
+// qualifiedName returns name prefixed with its schema, if it has one
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
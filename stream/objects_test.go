@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementObjects(t *testing.T) {
+	// Indexes are attached to the table named by CREATE INDEX, unquoted
+	objects, err := StatementObjects("CREATE UNIQUE INDEX idx ON [dbo].[users] (email)", func(schema *sqlmapper.Schema) error {
+		assert.Equal(t, []sqlmapper.Table{{Schema: "dbo", Name: "users"}}, schema.Tables)
+		schema.Tables[0].Indexes = append(schema.Tables[0].Indexes, sqlmapper.Index{Name: "idx"})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []SchemaObject{{Type: IndexObject, Table: "[dbo].[users]", Data: &sqlmapper.Index{Name: "idx"}}}, objects)
+
+	// Constraints are attached to the table named by ALTER TABLE
+	objects, err = StatementObjects("ALTER TABLE ONLY orders ADD CONSTRAINT pk PRIMARY KEY (id)", func(schema *sqlmapper.Schema) error {
+		schema.Tables[0].Constraints = append(schema.Tables[0].Constraints, sqlmapper.Constraint{Name: "pk"})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []SchemaObject{{Type: ConstraintObject, Table: "orders", Data: &sqlmapper.Constraint{Name: "pk"}}}, objects)
+
+	// Added columns yield no object
+	objects, err = StatementObjects("ALTER TABLE orders ADD note TEXT", func(schema *sqlmapper.Schema) error {
+		schema.Tables[0].Columns = append(schema.Tables[0].Columns, sqlmapper.Column{Name: "note"})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+
+	// Every object of a statement is returned, procedures parsed as functions included
+	objects, err = StatementObjects("-", func(schema *sqlmapper.Schema) error {
+		assert.Empty(t, schema.Tables)
+		schema.Name = "shop"
+		schema.Extensions = []sqlmapper.Extension{{Name: "pg_trgm"}}
+		schema.Tables = []sqlmapper.Table{
+			{Name: "users", Columns: []sqlmapper.Column{{Name: "id"}}},
+			{Schema: "app", Name: "orders", Data: []sqlmapper.Row{{Values: map[string]interface{}{"id": int64(1)}}}},
+			{Name: "empty"},
+		}
+		schema.Functions = []sqlmapper.Function{{Name: "f"}, {Name: "p", IsProc: true}}
+		schema.Permissions = []sqlmapper.Permission{{Type: "GRANT"}}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	var types []string
+	var tables []string
+	for _, obj := range objects {
+		types = append(types, obj.Type.String())
+		tables = append(tables, obj.Table)
+	}
+	assert.Equal(t, []string{"namespace", "extension", "table", "data", "function", "procedure", "permission"}, types)
+	assert.Equal(t, []string{"", "", "", "app.orders", "", "", ""}, tables)
+	assert.Equal(t, &Namespace{Name: "shop"}, objects[0].Data)
+	assert.Equal(t, "p", objects[5].Data.(*sqlmapper.Function).Name)
+
+	// Comments are returned without running the parser
+	noParse := func(*sqlmapper.Schema) error {
+		t.Error("comment passed to the parser")
+		return nil
+	}
+	objects, err = StatementObjects("COMMENT ON COLUMN public.users.\"email\" IS 'User''s address'", noParse)
+	assert.NoError(t, err)
+	assert.Equal(t, []SchemaObject{{Type: CommentObject, Table: "public.users", Data: &Comment{Column: "email", Text: "User's address"}}}, objects)
+	assert.Equal(t, `COMMENT ON COLUMN public.users.email IS 'User''s address'`, objects[0].Data.(*Comment).Statement("public.users"))
+
+	multiline := &Comment{Text: "Line one\nline two"}
+	assert.Equal(t, "-- Not supported: COMMENT ON TABLE users IS 'Line one line two'", multiline.LineComment("users", "Not supported"))
+
+	objects, err = StatementObjects("comment on table users is 'Accounts'", noParse)
+	assert.NoError(t, err)
+	assert.Equal(t, []SchemaObject{{Type: CommentObject, Table: "users", Data: &Comment{Text: "Accounts"}}}, objects)
+
+	_, err = StatementObjects("COMMENT ON COLUMN email IS 'x'", noParse)
+	assert.Error(t, err)
+
+	// Parse errors are returned as is
+	parseErr := errors.New("bad statement")
+	_, err = StatementObjects("CREATE TABLE", func(*sqlmapper.Schema) error { return parseErr })
+	assert.Equal(t, parseErr, err)
+}
+
+func TestObjectSchema(t *testing.T) {
+	schema := ObjectSchema(SchemaObject{Type: NamespaceObject, Data: &Namespace{Name: "shop"}})
+	assert.Equal(t, "shop", schema.Name)
+
+	schema = ObjectSchema(SchemaObject{Type: ExtensionObject, Data: &sqlmapper.Extension{Name: "postgis"}})
+	assert.Equal(t, []sqlmapper.Extension{{Name: "postgis"}}, schema.Extensions)
+
+	data := &sqlmapper.Table{Name: "users", Data: []sqlmapper.Row{{Values: map[string]interface{}{"1": "a"}}}}
+	schema = ObjectSchema(SchemaObject{Type: DataObject, Table: "users", Data: data})
+	assert.Equal(t, []sqlmapper.Table{*data}, schema.Tables)
+}
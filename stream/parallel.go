@@ -0,0 +1,236 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/mstgnz/sqlmapper/tokenizer"
+)
+
+// ParseFunc parses a single statement and returns the objects it defines, or
+// none for statements that define no object
+type ParseFunc func(stmt tokenizer.Statement) ([]SchemaObject, error)
+
+// ParallelOptions configures ParseStreamParallel
+type ParallelOptions struct {
+	// Workers is the number of goroutines parsing statements.
+	// Values below 1 use runtime.NumCPU().
+	Workers int
+
+	// Window is the maximum number of statements read ahead of the oldest
+	// statement whose object has not been delivered yet. It bounds the memory
+	// held for reordering. Values below 1 use four times the number of workers.
+	Window int
+
+	// Lenient delivers the statements that fail to parse, and those that define
+	// no supported object, as UnparsedObject objects, as in ParseOptions
+	Lenient bool
+
+	// Progress tracks the bytes read and the objects delivered, and receives the
+	// number of statements held in the window. Optional.
+	Progress *ProgressTracker
+}
+
+// ParseStreamParallel reads statements with the lexical rules of a dialect and
+// parses them on a pool of workers. Objects are delivered to callback in the
+// order of their statements, with their source positions set. newParse is called
+// once per worker, so that workers never share parser state.
+//
+// Parsing stops at the first error; objects of the statements before the failed
+// statement are delivered first. An error returned by callback stops parsing and
+// is returned as is.
+//
+// Parameters:
+//   - reader: The SQL dump to parse
+//   - dialect: The lexical rules used to split statements
+//   - newParse: Creates the parse function of a worker
+//   - callback: Called with each parsed object, in input order
+//   - opts: The number of workers, the reorder window and progress tracking
+//
+// Returns:
+//   - error: A *sqlmapper.ParseError for malformed input, or the callback's error
+func ParseStreamParallel(reader io.Reader, dialect tokenizer.Dialect, newParse func() ParseFunc, callback func(SchemaObject) error, opts ParallelOptions) error {
+	return ParseStreamParallelContext(context.Background(), reader, dialect, newParse, callback, opts)
+}
+
+// ParseStreamParallelContext is ParseStreamParallel under a context. Once ctx is
+// done, no objects are delivered, the workers stop and ctx.Err() is returned.
+// As in ParseContext, a read blocked at that time is interrupted by closing
+// reader if it implements io.Closer.
+//
+// Parameters:
+//   - ctx: The context controlling cancellation
+//   - reader: The SQL dump to parse
+//   - dialect: The lexical rules used to split statements
+//   - newParse: Creates the parse function of a worker
+//   - callback: Called with each parsed object, in input order
+//   - opts: The number of workers, the reorder window and progress tracking
+//
+// Returns:
+//   - error: ctx.Err() if the context ended, otherwise as ParseStreamParallel
+func ParseStreamParallelContext(ctx context.Context, reader io.Reader, dialect tokenizer.Dialect, newParse func() ParseFunc, callback func(SchemaObject) error, opts ParallelOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer closeOnDone(ctx, reader)()
+
+	reader, callback, tracker := track(&contextReader{ctx: ctx, reader: reader}, callback, opts.Progress)
+	statementReader := tokenizer.NewStatementReader(reader, dialect)
+
+	next := func() (tokenizer.Statement, error) {
+		stmt, err := statementReader.Next()
+		if err != nil && err != io.EOF {
+			return stmt, tokenizer.AsParseError(err)
+		}
+		return stmt, err
+	}
+
+	newWorker := func() func(tokenizer.Statement) ([]SchemaObject, error) {
+		parse := newParse()
+		return func(stmt tokenizer.Statement) ([]SchemaObject, error) {
+			return parseStatement(parse, stmt, opts.Lenient)
+		}
+	}
+
+	emit := func(objects []SchemaObject) error {
+		for _, obj := range objects {
+			if err := callback(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return orderedMap(ctx, next, newWorker, emit, opts.Workers, opts.Window, tracker.observer())
+}
+
+// sequenced is a work item or result tagged with its input position
+type sequenced[T any] struct {
+	seq   int
+	value T
+	err   error
+}
+
+// orderedMap applies a function to the items returned by next on a pool of
+// workers and passes the results to emit in input order. next returns io.EOF
+// after the last item. At most window items are held between being read and
+// being emitted. Each worker gets its own function from newWorker. If observe is
+// not nil, it is called with the number of items held whenever that changes.
+//
+// The first error from next, a worker or emit stops the pipeline, as does the end
+// of ctx, in which case ctx.Err() is returned. All goroutines have exited when
+// orderedMap returns, so it waits for a call of next in progress: next must
+// return soon after ctx ends, see closeOnDone.
+func orderedMap[In, Out any](ctx context.Context, next func() (In, error), newWorker func() func(In) (Out, error), emit func(Out) error, workers, window int, observe func(int)) error {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if window < 1 {
+		window = 4 * workers
+	}
+
+	done := make(chan struct{})
+	slots := make(chan struct{}, window)
+	jobs := make(chan sequenced[In])
+
+	// Every item in flight holds a slot, so the results buffer never fills up
+	results := make(chan sequenced[Out], window)
+
+	var wg sync.WaitGroup
+
+	// Reader: acquires a slot per item and hands it to the workers
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			if observe != nil {
+				observe(len(slots))
+			}
+
+			item, err := next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				results <- sequenced[Out]{seq: seq, err: err}
+				return
+			}
+
+			select {
+			case jobs <- sequenced[In]{seq: seq, value: item}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(fn func(In) (Out, error)) {
+			defer wg.Done()
+			for job := range jobs {
+				value, err := fn(job.value)
+				results <- sequenced[Out]{seq: job.seq, value: value, err: err}
+			}
+		}(newWorker())
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// On an early return, stop the reader and wait for the workers to finish
+	defer func() {
+		close(done)
+		for range results {
+		}
+	}()
+
+	pending := make(map[int]sequenced[Out])
+	expected := 0
+	for {
+		var result sequenced[Out]
+		select {
+		case r, ok := <-results:
+			if !ok {
+				return nil
+			}
+			result = r
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		pending[result.seq] = result
+		for {
+			ready, ok := pending[expected]
+			if !ok {
+				break
+			}
+			delete(pending, expected)
+			expected++
+			<-slots
+			if observe != nil {
+				observe(len(slots))
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if ready.err != nil {
+				return ready.err
+			}
+			if err := emit(ready.value); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+// tableParser returns a ParseFunc that turns "CREATE TABLE name" statements into
+// table objects after a random delay, and fails on statements starting with "!"
+func tableParser() ParseFunc {
+	return func(stmt tokenizer.Statement) ([]SchemaObject, error) {
+		time.Sleep(time.Duration(rand.Intn(200)) * time.Microsecond)
+		if strings.HasPrefix(stmt.Text, "!") {
+			return nil, errors.New("invalid statement")
+		}
+		name, ok := strings.CutPrefix(stmt.Text, "CREATE TABLE ")
+		if !ok {
+			return nil, nil
+		}
+		return []SchemaObject{{Type: TableObject, Data: &sqlmapper.Table{Name: name}}}, nil
+	}
+}
+
+func tableDump(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "CREATE TABLE t%d;\n", i)
+		if i%10 == 0 {
+			sb.WriteString("DROP TABLE x;\n")
+		}
+	}
+	return sb.String()
+}
+
+func TestParseStreamParallel_Order(t *testing.T) {
+	var names []string
+	err := ParseStreamParallel(strings.NewReader(tableDump(500)), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		names = append(names, obj.Data.(*sqlmapper.Table).Name)
+		return nil
+	}, ParallelOptions{Workers: 8, Window: 16})
+	assert.NoError(t, err)
+
+	if assert.Len(t, names, 500) {
+		for i, name := range names {
+			assert.Equal(t, fmt.Sprintf("t%d", i), name)
+		}
+	}
+}
+
+func TestParseStreamParallel_Positions(t *testing.T) {
+	var positions []string
+	err := ParseStreamParallel(strings.NewReader("CREATE TABLE a;\n  CREATE TABLE b;"), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		positions = append(positions, obj.Pos.String(), obj.Data.(*sqlmapper.Table).Pos.String())
+		return nil
+	}, ParallelOptions{Workers: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1:1", "1:1", "2:3", "2:3"}, positions)
+}
+
+func TestParseStreamParallel_Errors(t *testing.T) {
+	// Objects before the failing statement are delivered, later ones are not
+	var names []string
+	err := ParseStreamParallel(strings.NewReader(tableDump(50)+"!broken;\n"+tableDump(50)), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		names = append(names, obj.Data.(*sqlmapper.Table).Name)
+		return nil
+	}, ParallelOptions{Workers: 4})
+
+	var parseErr *sqlmapper.ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, 56, parseErr.Pos.Line)
+	}
+	assert.Len(t, names, 50)
+
+	// Errors of the callback are returned unchanged
+	stop := errors.New("stop")
+	calls := 0
+	err = ParseStreamParallel(strings.NewReader(tableDump(100)), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		calls++
+		if calls == 3 {
+			return stop
+		}
+		return nil
+	}, ParallelOptions{Workers: 4})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 3, calls)
+
+	// Lexical errors are reported after the statements before them
+	err = ParseStreamParallel(strings.NewReader("CREATE TABLE a;\nCREATE TABLE 'b"), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		return nil
+	}, ParallelOptions{})
+	assert.True(t, errors.As(err, &parseErr))
+}
+
+func TestOrderedMap_Window(t *testing.T) {
+	const window = 5
+	var read, emitted, maxAhead int64
+
+	i := 0
+	next := func() (int, error) {
+		if i == 200 {
+			return 0, io.EOF
+		}
+		i++
+		if ahead := atomic.AddInt64(&read, 1) - atomic.LoadInt64(&emitted); ahead > atomic.LoadInt64(&maxAhead) {
+			atomic.StoreInt64(&maxAhead, ahead)
+		}
+		return i, nil
+	}
+	square := func() func(int) (int, error) {
+		return func(n int) (int, error) {
+			time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+			return n * n, nil
+		}
+	}
+
+	var maxHeld int64
+	observe := func(n int) {
+		if int64(n) > atomic.LoadInt64(&maxHeld) {
+			atomic.StoreInt64(&maxHeld, int64(n))
+		}
+	}
+
+	var got []int
+	err := orderedMap(context.Background(), next, square, func(n int) error {
+		atomic.AddInt64(&emitted, 1)
+		got = append(got, n)
+		return nil
+	}, 4, window, observe)
+	assert.NoError(t, err)
+	assert.Len(t, got, 200)
+	assert.Equal(t, 40000, got[199])
+	assert.LessOrEqual(t, maxAhead, int64(window))
+	assert.LessOrEqual(t, atomic.LoadInt64(&maxHeld), int64(window))
+	assert.Greater(t, atomic.LoadInt64(&maxHeld), int64(0))
+}
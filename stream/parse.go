@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mstgnz/sqlmapper/tokenizer"
+)
+
+// ParseOptions configures ParseStatements
+type ParseOptions struct {
+	// Lenient delivers the statements that fail to parse, and those that define
+	// no supported object, as UnparsedObject objects instead of stopping at the
+	// first failure
+	Lenient bool
+
+	// Progress tracks the bytes read and the objects delivered. Optional.
+	Progress *ProgressTracker
+
+	// Resume continues an earlier parse of the same input from a state passed
+	// to OnStatement. The reader must start at Resume.Offset of that input.
+	Resume *ReadState
+
+	// OnStatement is called after the objects of each statement have been
+	// delivered, with the state to resume from. An error stops parsing and is
+	// returned as is.
+	OnStatement func(ReadState) error
+}
+
+// ReadState is how far a parse has read its input. Parsing can be resumed from
+// it with ParseOptions.Resume.
+type ReadState struct {
+	Offset     int64  `json:"offset"`     // Bytes consumed by the statements read
+	Line       int    `json:"line"`       // Line of the first unread byte
+	Column     int    `json:"column"`     // Column of the first unread byte
+	Statements int64  `json:"statements"` // Statements read
+	Delimiter  string `json:"delimiter"`  // Terminator in effect, set by DELIMITER commands
+}
+
+// OptionsStreamParser is implemented by stream parsers that accept ParseOptions,
+// which is required to resume a parse
+type OptionsStreamParser interface {
+	// ParseStreamOptions parses like ParseStream with the given options
+	ParseStreamOptions(reader io.Reader, callback func(SchemaObject) error, opts ParseOptions) error
+}
+
+// Unparsed is the data of an UnparsedObject: a statement passed through as is
+type Unparsed struct {
+	SQL string // Statement text without its terminator
+	Err error  // Why parsing failed; nil if the statement defines no supported object
+}
+
+// LenientStreamParser is implemented by stream parsers that can deliver the
+// statements they cannot parse as UnparsedObject objects
+type LenientStreamParser interface {
+	// ParseStreamLenient parses like ParseStream, but passes every statement that
+	// yields no object to callback as an UnparsedObject and goes on
+	ParseStreamLenient(reader io.Reader, callback func(SchemaObject) error) error
+}
+
+// ParseStatements reads statements with the lexical rules of a dialect, parses
+// them one at a time and delivers their objects to callback with their source
+// positions set. It is the sequential counterpart of ParseStreamParallel.
+//
+// Parameters:
+//   - reader: The SQL dump to parse
+//   - dialect: The lexical rules used to split statements
+//   - parse: Parses a single statement
+//   - callback: Called with each parsed object, in input order
+//   - opts: Whether statements that fail to parse stop parsing, and progress tracking
+//
+// Returns:
+//   - error: A *sqlmapper.ParseError for malformed input, or the callback's error.
+//     Lexical errors such as an unterminated string stop parsing in lenient mode too.
+func ParseStatements(reader io.Reader, dialect tokenizer.Dialect, parse ParseFunc, callback func(SchemaObject) error, opts ParseOptions) error {
+	reader, callback, _ = track(reader, callback, opts.Progress)
+
+	var state ReadState
+	statementReader := tokenizer.NewStatementReader(reader, dialect)
+	if opts.Resume != nil {
+		state = *opts.Resume
+		dialect.Delimiter = state.Delimiter
+		statementReader = tokenizer.NewStatementReaderAt(reader, dialect, tokenizer.Position{
+			Offset: int(state.Offset),
+			Line:   state.Line,
+			Column: state.Column,
+		})
+	}
+
+	for {
+		stmt, err := statementReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return tokenizer.AsParseError(err)
+		}
+
+		objects, err := parseStatement(parse, stmt, opts.Lenient)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objects {
+			if err := callback(obj); err != nil {
+				return err
+			}
+		}
+
+		if opts.OnStatement != nil {
+			pos := statementReader.Position()
+			state.Offset, state.Line, state.Column = int64(pos.Offset), pos.Line, pos.Column
+			state.Statements++
+			state.Delimiter = statementReader.Delimiter()
+			if err := opts.OnStatement(state); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseStatement parses a statement into objects positioned at it. In lenient
+// mode, statements that fail to parse or yield no object become UnparsedObject
+// objects.
+func parseStatement(parse ParseFunc, stmt tokenizer.Statement, lenient bool) ([]SchemaObject, error) {
+	objects, err := parseRecovered(parse, stmt)
+	if err != nil {
+		if !lenient {
+			return nil, stmt.Wrap(err)
+		}
+		objects = nil
+	}
+	if len(objects) == 0 {
+		if !lenient {
+			return nil, nil
+		}
+		objects = []SchemaObject{{Type: UnparsedObject, Data: &Unparsed{SQL: stmt.Text, Err: err}}}
+	}
+
+	pos := stmt.Pos.Source()
+	for i := range objects {
+		objects[i] = objects[i].WithPosition(pos)
+	}
+	return objects, nil
+}
+
+// parseRecovered calls parse, turning a panic on input the parser does not
+// expect into an error of the statement
+func parseRecovered(parse ParseFunc, stmt tokenizer.Statement) (objects []SchemaObject, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			objects, err = nil, fmt.Errorf("internal parser error: %v", r)
+		}
+	}()
+	return parse(stmt)
+}
@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatements(t *testing.T) {
+	input := "CREATE TABLE a;\n!broken;\nSET x = 1;\nCREATE TABLE b;"
+
+	var names []string
+	err := ParseStatements(strings.NewReader(input), tokenizer.Generic, tableParser(), func(obj SchemaObject) error {
+		names = append(names, obj.Data.(*sqlmapper.Table).Name)
+		return nil
+	}, ParseOptions{})
+
+	var parseErr *sqlmapper.ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, 2, parseErr.Pos.Line)
+	}
+	assert.Equal(t, []string{"a"}, names)
+}
+
+func TestParseStatements_Lenient(t *testing.T) {
+	input := "CREATE TABLE a;\n!broken;\nSET x = 1;\nCREATE TABLE b;"
+
+	var objects []SchemaObject
+	err := ParseStatements(strings.NewReader(input), tokenizer.Generic, tableParser(), func(obj SchemaObject) error {
+		objects = append(objects, obj)
+		return nil
+	}, ParseOptions{Lenient: true})
+	assert.NoError(t, err)
+
+	if assert.Len(t, objects, 4) {
+		assert.Equal(t, TableObject, objects[0].Type)
+
+		assert.Equal(t, UnparsedObject, objects[1].Type)
+		assert.Equal(t, 2, objects[1].Pos.Line)
+		assert.Equal(t, "!broken", objects[1].Data.(*Unparsed).SQL)
+		assert.EqualError(t, objects[1].Data.(*Unparsed).Err, "invalid statement")
+
+		// Statements without a supported object are passed through too
+		assert.Equal(t, UnparsedObject, objects[2].Type)
+		assert.Equal(t, &Unparsed{SQL: "SET x = 1"}, objects[2].Data)
+
+		assert.Equal(t, TableObject, objects[3].Type)
+		assert.Equal(t, 4, objects[3].Pos.Line)
+	}
+
+	// Lexical errors still stop parsing
+	err = ParseStatements(strings.NewReader("CREATE TABLE a;\nCREATE TABLE 'b"), tokenizer.Generic, tableParser(), func(SchemaObject) error {
+		return nil
+	}, ParseOptions{Lenient: true})
+	assert.Error(t, err)
+}
+
+func TestParseStreamParallel_Lenient(t *testing.T) {
+	var types []SchemaObjectType
+	err := ParseStreamParallel(strings.NewReader(tableDump(20)+"!broken;\n"+tableDump(20)), tokenizer.Generic, tableParser, func(obj SchemaObject) error {
+		types = append(types, obj.Type)
+		return nil
+	}, ParallelOptions{Workers: 4, Lenient: true})
+	assert.NoError(t, err)
+
+	// tableDump adds a DROP TABLE statement after every tenth table
+	if assert.Len(t, types, 20+2+1+20+2) {
+		assert.Equal(t, UnparsedObject, types[1])
+		assert.Equal(t, UnparsedObject, types[22])
+		assert.Equal(t, UnparsedObject, types[24])
+	}
+}
@@ -0,0 +1,245 @@
+package stream
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsRecorder receives the metrics of a tracked parse.
+// *monitoring.MetricsCollector implements it.
+type MetricsRecorder interface {
+	IncrementProcessedObjects()
+	RecordProcessingTime(duration time.Duration)
+	SetChannelBufferUsage(usage int64)
+}
+
+// ProgressOptions configures a ProgressTracker
+type ProgressOptions struct {
+	// TotalBytes is the size of the input. When it is known, progress reports
+	// include the percent complete and an estimate of the remaining time.
+	TotalBytes int64
+
+	// Interval is the minimum time between two progress reports.
+	// Values below 1 use one second.
+	Interval time.Duration
+
+	// OnProgress is called with the progress of the parse, from the goroutine
+	// that delivers the objects
+	OnProgress func(Progress)
+
+	// Metrics receives every delivered object, the time it took to produce,
+	// and the number of statements held by parallel parsing
+	Metrics MetricsRecorder
+}
+
+// Progress is a snapshot of the progress of a parse
+type Progress struct {
+	BytesRead  int64                      // Bytes read from the input so far
+	TotalBytes int64                      // Size of the input; 0 if unknown
+	Objects    map[SchemaObjectType]int64 // Objects delivered so far, by type
+	Elapsed    time.Duration              // Time since the tracker was created
+	Done       bool                       // Set on the report of Finish
+}
+
+// TotalObjects returns the number of objects delivered so far
+func (p Progress) TotalObjects() int64 {
+	var total int64
+	for _, n := range p.Objects {
+		total += n
+	}
+	return total
+}
+
+// Percent returns how much of the input has been read, from 0 to 100.
+// It returns 0 when the size of the input is unknown.
+func (p Progress) Percent() float64 {
+	if p.TotalBytes <= 0 {
+		return 0
+	}
+	if p.BytesRead >= p.TotalBytes {
+		return 100
+	}
+	return float64(p.BytesRead) / float64(p.TotalBytes) * 100
+}
+
+// ETA estimates the time left from the read rate so far. It returns 0 when the
+// size of the input is unknown or nothing has been read yet.
+func (p Progress) ETA() time.Duration {
+	if p.TotalBytes <= 0 || p.BytesRead <= 0 || p.BytesRead >= p.TotalBytes {
+		return 0
+	}
+	return time.Duration(float64(p.Elapsed) * float64(p.TotalBytes-p.BytesRead) / float64(p.BytesRead))
+}
+
+// ObjectsPerSecond returns the average object throughput so far
+func (p Progress) ObjectsPerSecond() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.TotalObjects()) / p.Elapsed.Seconds()
+}
+
+// BytesPerSecond returns the average read throughput so far
+func (p Progress) BytesPerSecond() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.BytesRead) / p.Elapsed.Seconds()
+}
+
+// ProgressTracker measures the progress of a parse through the input reader and
+// the object callback it wraps, so that it works with every stream parser:
+//
+//	tracker := stream.NewProgressTracker(stream.ProgressOptions{TotalBytes: size, OnProgress: report})
+//	err := parser.ParseStreamParallel(tracker.Reader(file), tracker.Callback(handle), 8)
+//	tracker.Finish()
+type ProgressTracker struct {
+	opts     ProgressOptions
+	now      func() time.Time
+	bytes    int64 // Accessed atomically, the reader may run on another goroutine
+	mu       sync.Mutex
+	objects  map[SchemaObjectType]int64
+	start    time.Time
+	last     time.Time // When the previous object was delivered
+	reported time.Time
+}
+
+// NewProgressTracker creates a progress tracker; the elapsed time starts now
+func NewProgressTracker(opts ProgressOptions) *ProgressTracker {
+	if opts.Interval < 1 {
+		opts.Interval = time.Second
+	}
+	t := &ProgressTracker{
+		opts:    opts,
+		now:     time.Now,
+		objects: make(map[SchemaObjectType]int64),
+	}
+	t.start = t.now()
+	t.last = t.start
+	t.reported = t.start
+	return t
+}
+
+// Reader returns a reader that counts the bytes read from r
+func (t *ProgressTracker) Reader(r io.Reader) io.Reader {
+	return &progressReader{reader: r, tracker: t}
+}
+
+// Callback returns a callback that records each object, feeds the metrics and
+// reports progress when the interval has passed, then calls callback
+func (t *ProgressTracker) Callback(callback func(SchemaObject) error) func(SchemaObject) error {
+	return func(obj SchemaObject) error {
+		t.record(obj.Type)
+		return callback(obj)
+	}
+}
+
+// Progress returns the current progress
+func (t *ProgressTracker) Progress() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshot(t.now())
+}
+
+// Finish reports the final progress, with Done set, regardless of the interval
+func (t *ProgressTracker) Finish() {
+	t.mu.Lock()
+	progress := t.snapshot(t.now())
+	t.mu.Unlock()
+
+	progress.Done = true
+	if t.opts.OnProgress != nil {
+		t.opts.OnProgress(progress)
+	}
+}
+
+// record counts an object of the given type
+func (t *ProgressTracker) record(typ SchemaObjectType) {
+	t.mu.Lock()
+	now := t.now()
+	elapsed := now.Sub(t.last)
+	t.last = now
+	t.objects[typ]++
+
+	var progress *Progress
+	if t.opts.OnProgress != nil && now.Sub(t.reported) >= t.opts.Interval {
+		t.reported = now
+		snapshot := t.snapshot(now)
+		progress = &snapshot
+	}
+	t.mu.Unlock()
+
+	if t.opts.Metrics != nil {
+		t.opts.Metrics.IncrementProcessedObjects()
+		t.opts.Metrics.RecordProcessingTime(elapsed)
+	}
+	if progress != nil {
+		t.opts.OnProgress(*progress)
+	}
+}
+
+// observer returns the function that reports how many statements parallel
+// parsing holds, or nil if there is nowhere to report them
+func (t *ProgressTracker) observer() func(int) {
+	if t == nil || t.opts.Metrics == nil {
+		return nil
+	}
+	return func(n int) {
+		t.opts.Metrics.SetChannelBufferUsage(int64(n))
+	}
+}
+
+// snapshot returns the progress at now; t.mu must be held
+func (t *ProgressTracker) snapshot(now time.Time) Progress {
+	objects := make(map[SchemaObjectType]int64, len(t.objects))
+	for typ, n := range t.objects {
+		objects[typ] = n
+	}
+	return Progress{
+		BytesRead:  atomic.LoadInt64(&t.bytes),
+		TotalBytes: t.opts.TotalBytes,
+		Objects:    objects,
+		Elapsed:    now.Sub(t.start),
+	}
+}
+
+// progressReader counts the bytes read through it
+type progressReader struct {
+	reader  io.Reader
+	tracker *ProgressTracker
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(&r.tracker.bytes, int64(n))
+	return n, err
+}
+
+// track wraps reader and callback with tracker. Without a tracker it returns
+// them as is, along with the tracker the reader was already wrapped by, if any.
+func track(reader io.Reader, callback func(SchemaObject) error, tracker *ProgressTracker) (io.Reader, func(SchemaObject) error, *ProgressTracker) {
+	if tracker == nil {
+		return reader, callback, trackerOf(reader)
+	}
+	if trackerOf(reader) != tracker {
+		reader = tracker.Reader(reader)
+	}
+	return reader, tracker.Callback(callback), tracker
+}
+
+// trackerOf returns the progress tracker reading through r, looking through
+// the readers this package wraps inputs in, or nil
+func trackerOf(r io.Reader) *ProgressTracker {
+	for {
+		switch reader := r.(type) {
+		case *progressReader:
+			return reader.tracker
+		case *contextReader:
+			r = reader.reader
+		default:
+			return nil
+		}
+	}
+}
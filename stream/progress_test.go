@@ -0,0 +1,137 @@
+package stream
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper/monitoring"
+	"github.com/mstgnz/sqlmapper/tokenizer"
+	"github.com/stretchr/testify/assert"
+)
+
+// bufferMetrics records the highest channel buffer usage it is given
+type bufferMetrics struct {
+	*monitoring.MetricsCollector
+	maxBuffered int64
+}
+
+func (m *bufferMetrics) SetChannelBufferUsage(usage int64) {
+	m.MetricsCollector.SetChannelBufferUsage(usage)
+	if usage > atomic.LoadInt64(&m.maxBuffered) {
+		atomic.StoreInt64(&m.maxBuffered, usage)
+	}
+}
+
+func TestStreamReader_Counts(t *testing.T) {
+	input := "CREATE TABLE a;\n-- comment\nCREATE TABLE b;\n"
+	reader := NewStreamReader(strings.NewReader(input), ";")
+
+	_, err := reader.ReadStatement()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), reader.Statements())
+	assert.Equal(t, int64(len("CREATE TABLE a;")), reader.BytesRead())
+
+	_, err = reader.ReadStatement()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), reader.Statements())
+
+	_, err = reader.ReadStatement()
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), reader.Statements())
+	assert.Equal(t, int64(len(input)), reader.BytesRead())
+}
+
+func TestProgressTracker_Parallel(t *testing.T) {
+	input := tableDump(100) + "!broken;\n"
+	metrics := &bufferMetrics{MetricsCollector: monitoring.NewMetricsCollector()}
+
+	var final Progress
+	tracker := NewProgressTracker(ProgressOptions{
+		TotalBytes: int64(len(input)),
+		Metrics:    metrics,
+		OnProgress: func(p Progress) { final = p },
+	})
+
+	err := ParseStreamParallel(strings.NewReader(input), tokenizer.Generic, tableParser, func(SchemaObject) error {
+		return nil
+	}, ParallelOptions{Workers: 4, Window: 8, Lenient: true, Progress: tracker})
+	assert.NoError(t, err)
+	tracker.Finish()
+
+	assert.True(t, final.Done)
+	assert.Equal(t, int64(len(input)), final.BytesRead)
+	assert.Equal(t, float64(100), final.Percent())
+	assert.Equal(t, time.Duration(0), final.ETA())
+	assert.Equal(t, map[SchemaObjectType]int64{TableObject: 100, UnparsedObject: 11}, final.Objects)
+	assert.Equal(t, int64(111), final.TotalObjects())
+
+	assert.Equal(t, int64(111), metrics.TotalObjects())
+	assert.Greater(t, atomic.LoadInt64(&metrics.maxBuffered), int64(0))
+	assert.LessOrEqual(t, atomic.LoadInt64(&metrics.maxBuffered), int64(8))
+}
+
+func TestProgressTracker_Reader(t *testing.T) {
+	// A tracker wrapping the input and the callback works with any parser
+	input := tableDump(10)
+	metrics := monitoring.NewMetricsCollector()
+	tracker := NewProgressTracker(ProgressOptions{Metrics: metrics})
+
+	err := ParseStatements(tracker.Reader(strings.NewReader(input)), tokenizer.Generic, tableParser(), tracker.Callback(func(SchemaObject) error {
+		return nil
+	}), ParseOptions{})
+	assert.NoError(t, err)
+
+	progress := tracker.Progress()
+	assert.Equal(t, int64(len(input)), progress.BytesRead)
+	assert.Equal(t, int64(10), progress.Objects[TableObject])
+	assert.Equal(t, float64(0), progress.Percent(), "unknown size")
+	assert.Equal(t, int64(10), metrics.TotalObjects())
+}
+
+func TestProgressTracker_Interval(t *testing.T) {
+	var reports []Progress
+	tracker := NewProgressTracker(ProgressOptions{
+		TotalBytes: 1000,
+		Interval:   time.Second,
+		OnProgress: func(p Progress) { reports = append(reports, p) },
+	})
+	clock := tracker.start
+	tracker.now = func() time.Time { return clock }
+
+	callback := tracker.Callback(func(SchemaObject) error { return nil })
+	advance := func(d time.Duration) {
+		clock = clock.Add(d)
+		assert.NoError(t, callback(SchemaObject{Type: TableObject}))
+	}
+
+	advance(300 * time.Millisecond)
+	advance(300 * time.Millisecond)
+	assert.Empty(t, reports)
+
+	tracker.bytes = 250
+	advance(400 * time.Millisecond)
+	if assert.Len(t, reports, 1) {
+		assert.Equal(t, int64(3), reports[0].TotalObjects())
+		assert.Equal(t, float64(25), reports[0].Percent())
+		assert.Equal(t, 3*time.Second, reports[0].ETA())
+		assert.Equal(t, float64(3), reports[0].ObjectsPerSecond())
+		assert.False(t, reports[0].Done)
+	}
+
+	advance(500 * time.Millisecond)
+	assert.Len(t, reports, 1)
+
+	tracker.Finish()
+	if assert.Len(t, reports, 2) {
+		assert.True(t, reports[1].Done)
+		assert.Equal(t, int64(4), reports[1].TotalObjects())
+	}
+}
+
+func TestSchemaObjectType_String(t *testing.T) {
+	assert.Equal(t, "table", TableObject.String())
+	assert.Equal(t, "unparsed", UnparsedObject.String())
+	assert.Equal(t, "SchemaObjectType(99)", SchemaObjectType(99).String())
+}
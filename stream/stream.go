@@ -1,13 +1,14 @@
 package stream
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"strings"
 	"sync"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 )
 
 // StreamParser represents an interface for streaming database dump operations
@@ -20,6 +21,37 @@ type StreamParser interface {
 
 	// GenerateStream generates SQL statements for schema objects and writes them to the writer
 	GenerateStream(schema *sqlmapper.Schema, writer io.Writer) error
+
+	// ParseStreamContext is ParseStream that stops reading and returns ctx.Err() once ctx is done
+	ParseStreamContext(ctx context.Context, reader io.Reader, callback func(SchemaObject) error) error
+
+	// GenerateStreamContext is GenerateStream that stops writing and returns ctx.Err() once ctx is done
+	GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error
+
+	// GenerateObject writes the SQL statements of a single schema object. Indexes and
+	// constraints are written as standalone statements on the table named by obj.Table.
+	GenerateObject(obj SchemaObject, writer io.Writer) error
+
+	// Dialect returns the database type the parser reads and writes
+	Dialect() sqlmapper.DatabaseType
+}
+
+// NewStreamParser creates the stream parser of a dialect registered with
+// sqlmapper.RegisterDialect. The dialect can be given by its database type or an alias.
+func NewStreamParser(dbType sqlmapper.DatabaseType) (StreamParser, error) {
+	_, dialect, ok := sqlmapper.LookupDialect(string(dbType))
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type: %s (forgotten import?)", dbType)
+	}
+	if dialect.NewStreamParser == nil {
+		return nil, fmt.Errorf("database type %s does not support stream parsing", dbType)
+	}
+
+	parser, ok := dialect.NewStreamParser().(StreamParser)
+	if !ok {
+		return nil, fmt.Errorf("database type %s registered an invalid stream parser", dbType)
+	}
+	return parser, nil
 }
 
 // WorkerPool represents a pool of workers for parallel processing
@@ -61,9 +93,9 @@ func (wp *WorkerPool) worker() {
 			return nil
 		})
 
+		// Keep taking jobs after an error, so that Submit never blocks
 		if err != nil {
 			wp.errors <- fmt.Errorf("error processing statement: %v", err)
-			return
 		}
 	}
 }
@@ -93,45 +125,69 @@ func (wp *WorkerPool) Wait() {
 
 // Process processes a stream of SQL statements in parallel
 func (wp *WorkerPool) Process(reader io.Reader, callback func(SchemaObject) error) error {
-	// Start the worker pool
-	wp.Start()
+	return wp.ProcessContext(context.Background(), reader, callback)
+}
 
-	// Start a goroutine to read statements and submit them to workers
-	go func() {
-		streamReader := NewStreamReader(reader, ";")
+// ProcessContext processes a stream of SQL statements in parallel until the
+// input ends, an error occurs or ctx is done. Objects are delivered in input
+// order. All workers have stopped when it returns, also when the callback
+// returns an error. As in ParseContext, a read blocked when ctx ends is
+// interrupted by closing reader if it implements io.Closer.
+//
+// Parameters:
+//   - ctx: The context controlling cancellation
+//   - reader: The SQL statements to process
+//   - callback: Called with each parsed object
+//
+// Returns:
+//   - error: ctx.Err() if the context ended, or the first processing or callback error
+func (wp *WorkerPool) ProcessContext(ctx context.Context, reader io.Reader, callback func(SchemaObject) error) error {
+	defer closeOnDone(ctx, reader)()
+	statementReader := tokenizer.NewStatementReader(&contextReader{ctx: ctx, reader: reader}, tokenizer.ForDatabase(wp.parser.Dialect()))
+
+	next := func() (tokenizer.Statement, error) {
 		for {
-			statement, err := streamReader.ReadStatement()
+			statement, err := statementReader.Next()
 			if err == io.EOF {
-				break
+				return statement, err
 			}
 			if err != nil {
-				wp.errors <- fmt.Errorf("error reading statement: %v", err)
-				break
+				return statement, fmt.Errorf("error reading statement: %v", err)
 			}
 
-			statement = strings.TrimSpace(statement)
-			if statement == "" {
-				continue
+			if strings.TrimSpace(statement.Text) != "" {
+				return statement, nil
 			}
-			wp.Submit(statement)
 		}
-		wp.Wait()
-	}()
-
-	// Process results and handle errors
-	for {
-		select {
-		case obj, ok := <-wp.Results():
-			if !ok {
+	}
+
+	// Each statement is parsed on its own, so its objects are moved from the
+	// start of that text to the position of the statement in the input
+	newWorker := func() func(tokenizer.Statement) ([]SchemaObject, error) {
+		return func(statement tokenizer.Statement) ([]SchemaObject, error) {
+			var objects []SchemaObject
+			pos := statement.Pos.Source()
+			err := wp.parser.ParseStreamContext(ctx, strings.NewReader(statement.Text), func(obj SchemaObject) error {
+				objects = append(objects, obj.WithPosition(pos))
 				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error processing statement: %v", err)
 			}
+			return objects, nil
+		}
+	}
+
+	emit := func(objects []SchemaObject) error {
+		for _, obj := range objects {
 			if err := callback(obj); err != nil {
 				return err
 			}
-		case err := <-wp.Errors():
-			return err
 		}
+		return nil
 	}
+
+	return orderedMap(ctx, next, newWorker, emit, wp.workers, 0, trackerOf(reader).observer())
 }
 
 // SchemaObjectType represents the type of schema object
@@ -148,108 +204,119 @@ const (
 	SequenceObject
 	TypeObject
 	PermissionObject
+	UnparsedObject  // A statement passed through as is by a lenient parse
+	ExtensionObject // An extension installed by CREATE EXTENSION
+	DataObject      // Rows of an INSERT statement, held by a table without columns
+	NamespaceObject // A database or schema created by CREATE DATABASE or CREATE SCHEMA
+	CommentObject   // A table or column comment set by COMMENT ON
 )
 
+var schemaObjectTypeNames = map[SchemaObjectType]string{
+	TableObject:      "table",
+	ViewObject:       "view",
+	FunctionObject:   "function",
+	ProcedureObject:  "procedure",
+	TriggerObject:    "trigger",
+	IndexObject:      "index",
+	ConstraintObject: "constraint",
+	SequenceObject:   "sequence",
+	TypeObject:       "type",
+	PermissionObject: "permission",
+	UnparsedObject:   "unparsed",
+	ExtensionObject:  "extension",
+	DataObject:       "data",
+	NamespaceObject:  "namespace",
+	CommentObject:    "comment",
+}
+
+// String returns the lower-case name of the object type
+func (t SchemaObjectType) String() string {
+	if name, ok := schemaObjectTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("SchemaObjectType(%d)", int(t))
+}
+
 // SchemaObject represents a parsed database object
 type SchemaObject struct {
-	Type SchemaObjectType
-	Data interface{} // Table, View, Function, etc.
+	Type  SchemaObjectType
+	Table string             // Table an index, constraint, data row or comment belongs to
+	Data  interface{}        // Table, View, Function, etc.
+	Pos   sqlmapper.Position // Source location of the statement the object was parsed from
+}
+
+// WithPosition returns the object with pos set on it and on its data
+func (o SchemaObject) WithPosition(pos sqlmapper.Position) SchemaObject {
+	o.Pos = pos
+	p := &pos
+	switch data := o.Data.(type) {
+	case *sqlmapper.Table:
+		data.Pos = p
+	case *sqlmapper.View:
+		data.Pos = p
+	case *sqlmapper.Function:
+		data.Pos = p
+	case *sqlmapper.Procedure:
+		data.Pos = p
+	case *sqlmapper.Trigger:
+		data.Pos = p
+	case *sqlmapper.Index:
+		data.Pos = p
+	case *sqlmapper.Sequence:
+		data.Pos = p
+	case *sqlmapper.Type:
+		data.Pos = p
+	case *sqlmapper.Permission:
+		data.Pos = p
+	case *sqlmapper.Extension:
+		data.Pos = p
+	}
+	return o
 }
 
-// StreamReader provides buffered reading of SQL statements
+// StreamReader splits a stream of SQL into statements with the lexical rules of
+// a dialect: its quoting, comments and statement terminators such as MySQL's
+// DELIMITER command, SQL Server's GO batch separator and Oracle's "/" line.
+// It reads the input in constant memory.
 type StreamReader struct {
-	reader    *bufio.Reader
-	delimiter string
-	buffer    []byte
+	statements *tokenizer.StatementReader
+	count      int64
 }
 
-// NewStreamReader creates a new StreamReader with the given reader and delimiter
+// NewStreamReader creates a StreamReader that ends statements at the given
+// delimiter and otherwise follows standard SQL lexical rules
 func NewStreamReader(reader io.Reader, delimiter string) *StreamReader {
+	return NewDialectStreamReader(reader, tokenizer.Dialect{Delimiter: delimiter})
+}
+
+// NewDialectStreamReader creates a StreamReader with the lexical rules of a
+// dialect, such as tokenizer.ForDatabase(sqlmapper.MySQL)
+func NewDialectStreamReader(reader io.Reader, dialect tokenizer.Dialect) *StreamReader {
 	return &StreamReader{
-		reader:    bufio.NewReader(reader),
-		delimiter: delimiter,
-		buffer:    make([]byte, 0, 4096),
+		statements: tokenizer.NewStatementReader(reader, dialect),
 	}
 }
 
-// ReadStatement reads the next SQL statement from the reader
+// ReadStatement reads the next SQL statement from the reader. The statement is
+// returned without comments and without its terminator; empty statements are
+// skipped. It returns io.EOF at the end of the input and a *tokenizer.Error for
+// unterminated literals and comments.
 func (sr *StreamReader) ReadStatement() (string, error) {
-	var statement []byte
-	inString := false
-	inComment := false
-	lineComment := false
-	escaped := false
-
-	for {
-		b, err := sr.reader.ReadByte()
-		if err != nil {
-			if err == io.EOF && len(statement) > 0 {
-				return string(statement), nil
-			}
-			return "", err
-		}
-
-		// Handle string literals
-		if b == '\'' && !inComment && !escaped {
-			inString = !inString
-		}
-
-		// Handle escape characters
-		if b == '\\' && !inComment {
-			escaped = !escaped
-		} else {
-			escaped = false
-		}
-
-		// Handle comments
-		if !inString && !inComment && b == '-' {
-			nextByte, err := sr.reader.ReadByte()
-			if err == nil && nextByte == '-' {
-				lineComment = true
-				inComment = true
-				continue
-			}
-			sr.reader.UnreadByte()
-		}
-
-		if !inString && !inComment && b == '/' {
-			nextByte, err := sr.reader.ReadByte()
-			if err == nil && nextByte == '*' {
-				inComment = true
-				continue
-			}
-			sr.reader.UnreadByte()
-		}
-
-		if inComment && !lineComment && b == '*' {
-			nextByte, err := sr.reader.ReadByte()
-			if err == nil && nextByte == '/' {
-				inComment = false
-				continue
-			}
-			sr.reader.UnreadByte()
-		}
-
-		if lineComment && b == '\n' {
-			inComment = false
-			lineComment = false
-			continue
-		}
-
-		// Skip comments
-		if inComment {
-			continue
-		}
+	stmt, err := sr.statements.Next()
+	if err != nil {
+		return "", err
+	}
+	sr.count++
+	return stmt.Text, nil
+}
 
-		// Add character to statement
-		statement = append(statement, b)
+// BytesRead returns the number of bytes of the input consumed by the
+// statements read so far
+func (sr *StreamReader) BytesRead() int64 {
+	return int64(sr.statements.Offset())
+}
 
-		// Check for delimiter
-		if !inString && len(statement) >= len(sr.delimiter) {
-			lastIdx := len(statement) - len(sr.delimiter)
-			if string(statement[lastIdx:]) == sr.delimiter {
-				return string(statement[:lastIdx]), nil
-			}
-		}
-	}
+// Statements returns the number of statements read so far
+func (sr *StreamReader) Statements() int64 {
+	return sr.count
 }
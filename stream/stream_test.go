@@ -2,12 +2,14 @@ package stream
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"strings"
 	"testing"
 
 	"github.com/mstgnz/sqlmapper"
+	"github.com/mstgnz/sqlmapper/tokenizer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,6 +41,22 @@ func (m *MockStreamParser) GenerateStream(schema *sqlmapper.Schema, writer io.Wr
 	return nil
 }
 
+func (m *MockStreamParser) ParseStreamContext(ctx context.Context, reader io.Reader, callback func(SchemaObject) error) error {
+	return ParseContext(ctx, reader, callback, m.ParseStream)
+}
+
+func (m *MockStreamParser) GenerateStreamContext(ctx context.Context, schema *sqlmapper.Schema, writer io.Writer) error {
+	return GenerateContext(ctx, schema, writer, m.GenerateStream)
+}
+
+func (m *MockStreamParser) GenerateObject(obj SchemaObject, writer io.Writer) error {
+	return m.GenerateStream(ObjectSchema(obj), writer)
+}
+
+func (m *MockStreamParser) Dialect() sqlmapper.DatabaseType {
+	return ""
+}
+
 func TestStreamReader_ReadStatement(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -132,6 +150,66 @@ func TestStreamReader_ReadStatement(t *testing.T) {
 	}
 }
 
+func TestNewDialectStreamReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqlmapper.DatabaseType
+		input   string
+		want    []string
+	}{
+		{
+			name:    "MySQL DELIMITER",
+			dialect: sqlmapper.MySQL,
+			input: "DELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 'a;b'; SELECT \"c\\\";d\"; END$$\nDELIMITER ;\n" +
+				"CREATE TABLE `t;` (id INT); # comment;\n",
+			want: []string{
+				"CREATE PROCEDURE p() BEGIN SELECT 'a;b'; SELECT \"c\\\";d\"; END",
+				"CREATE TABLE `t;` (id INT)",
+			},
+		},
+		{
+			name:    "SQL Server GO",
+			dialect: sqlmapper.SQLServer,
+			input:   "CREATE TABLE [a;b] (id INT)\nGO\nCREATE VIEW v AS SELECT 1 AS [x]\n  go 3  \nCREATE TABLE c (GOAL INT)\n/* outer /* inner; */ still; */\nGO\n",
+			want:    []string{"CREATE TABLE [a;b] (id INT)", "CREATE VIEW v AS SELECT 1 AS [x]", "CREATE TABLE c (GOAL INT)"},
+		},
+		{
+			name:    "Oracle slash",
+			dialect: sqlmapper.Oracle,
+			input:   "CREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND;\n/\nCREATE TABLE \"T;1\" (id NUMBER)\n/\n",
+			want:    []string{"CREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND", "CREATE TABLE \"T;1\" (id NUMBER)"},
+		},
+		{
+			name:    "PostgreSQL dollar quotes and nested comments",
+			dialect: sqlmapper.PostgreSQL,
+			input:   "CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;\n/* a /* b; */ c; */\nCREATE TABLE \"x;y\" (id int);",
+			want:    []string{"CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql", "CREATE TABLE \"x;y\" (id int)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewDialectStreamReader(strings.NewReader(tt.input), tokenizer.ForDatabase(tt.dialect))
+			var got []string
+			for {
+				stmt, err := reader.ReadStatement()
+				if err == io.EOF {
+					break
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				got = append(got, stmt)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Unterminated literals are reported
+	_, err := NewStreamReader(strings.NewReader("SELECT 'a;"), ";").ReadStatement()
+	assert.Error(t, err)
+}
+
 func TestWorkerPool(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1,11 +1,16 @@
 package benchmark
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
 	"testing"
 
 	"github.com/mstgnz/sqlmapper/mysql"
 	"github.com/mstgnz/sqlmapper/postgres"
 	"github.com/mstgnz/sqlmapper/sqlite"
+	"github.com/mstgnz/sqlmapper/stream"
 )
 
 var complexMySQLSchema = `
@@ -182,3 +187,61 @@ SELECT * FROM products WHERE stock > 0;
 		}
 	}
 }
+
+// dumpReader generates a SQLite dump of n tables with an index each as it is
+// read, so that the dump itself takes no memory
+type dumpReader struct {
+	n, next int
+	buf     bytes.Buffer
+}
+
+func (r *dumpReader) Read(p []byte) (int, error) {
+	for r.buf.Len() < len(p) && r.next < r.n {
+		fmt.Fprintf(&r.buf, "CREATE TABLE t%d (id INTEGER PRIMARY KEY, name TEXT NOT NULL, score REAL);\n", r.next)
+		fmt.Fprintf(&r.buf, "CREATE INDEX idx_t%d_name ON t%d (name);\n", r.next, r.next)
+		r.next++
+	}
+	if r.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return r.buf.Read(p)
+}
+
+// heapWriter discards its input and samples the heap size on every write.
+// Convert buffers its output, so writes are large and infrequent.
+type heapWriter struct {
+	peak uint64
+}
+
+func (w *heapWriter) Write(p []byte) (int, error) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.HeapAlloc > w.peak {
+		w.peak = stats.HeapAlloc
+	}
+	return len(p), nil
+}
+
+// BenchmarkStreamConvert converts dumps of growing size from SQLite to
+// PostgreSQL. The peak heap reported should stay flat as the dump grows.
+func BenchmarkStreamConvert(b *testing.B) {
+	for _, tables := range []int{1000, 10000, 50000} {
+		b.Run(fmt.Sprintf("tables=%d", tables), func(b *testing.B) {
+			src := sqlite.NewSQLiteStreamParser()
+			dst := postgres.NewPostgreSQLStreamParser()
+
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				writer := &heapWriter{}
+				if err := stream.Convert(src, dst, &dumpReader{n: tables}, writer); err != nil {
+					b.Fatal(err)
+				}
+				if writer.peak > peak {
+					peak = writer.peak
+				}
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tokenizer
+
+import "github.com/mstgnz/sqlmapper"
+
+// Dialect configures the lexical rules of a SQL dialect: how identifiers and
+// strings are quoted, which comment styles exist, and how statements end.
+type Dialect struct {
+	// Delimiter is the statement terminator at the start of the input. It is
+	// ";" when empty.
+	Delimiter string
+
+	// BacktickIdentifiers enables `quoted` identifiers
+	BacktickIdentifiers bool
+
+	// BracketIdentifiers enables [quoted] identifiers
+	BracketIdentifiers bool
+
+	// DoubleQuotedStrings lexes "text" as a string instead of an identifier
+	DoubleQuotedStrings bool
+
+	// BackslashEscapes makes a backslash escape the next character in strings
+	BackslashEscapes bool
+
+	// EscapeStrings enables E'...' strings with backslash escapes
+	EscapeStrings bool
+
+	// DollarQuoting enables $$...$$ and $tag$...$tag$ strings
+	DollarQuoting bool
+
+	// QuoteOperator enables q'[...]' style strings with a custom quote character
+	QuoteOperator bool
+
+	// HashComments enables # line comments
+	HashComments bool
+
+	// NestedComments allows /* */ comments to nest
+	NestedComments bool
+
+	// DelimiterCommand enables the DELIMITER client command that changes the
+	// statement terminator
+	DelimiterCommand bool
+
+	// BatchSeparator is a keyword that ends a batch when it stands alone on a
+	// line, such as GO
+	BatchSeparator string
+
+	// SlashTerminator makes a line holding only "/" end the current statement
+	SlashTerminator bool
+
+	// BlockKeywords keeps semicolons inside the BEGIN...END blocks of routines
+	// and triggers from ending the statement
+	BlockKeywords bool
+
+	// RoutineUntilEnd makes a routine or trigger end only once its outermost
+	// BEGIN...END block closes, so semicolons in the declarations before the
+	// first BEGIN do not end it. Package bodies and type bodies end at a "/" line.
+	RoutineUntilEnd bool
+}
+
+// Predefined dialects
+var (
+	// Generic splits on semicolons and understands standard quoting and comments
+	Generic = Dialect{}
+
+	MySQL = Dialect{
+		BacktickIdentifiers: true,
+		DoubleQuotedStrings: true,
+		BackslashEscapes:    true,
+		HashComments:        true,
+		DelimiterCommand:    true,
+		BlockKeywords:       true,
+	}
+
+	PostgreSQL = Dialect{
+		EscapeStrings:  true,
+		DollarQuoting:  true,
+		NestedComments: true,
+		BlockKeywords:  true,
+	}
+
+	SQLite = Dialect{
+		BacktickIdentifiers: true,
+		BracketIdentifiers:  true,
+		BlockKeywords:       true,
+	}
+
+	SQLServer = Dialect{
+		BracketIdentifiers: true,
+		NestedComments:     true,
+		BatchSeparator:     "GO",
+		BlockKeywords:      true,
+		RoutineUntilEnd:    true,
+	}
+
+	Oracle = Dialect{
+		QuoteOperator:   true,
+		SlashTerminator: true,
+		BlockKeywords:   true,
+		RoutineUntilEnd: true,
+	}
+)
+
+// ForDatabase returns the predefined dialect of a database type, or Generic
+// if the type is unknown
+func ForDatabase(dbType sqlmapper.DatabaseType) Dialect {
+	switch dbType {
+	case sqlmapper.MySQL:
+		return MySQL
+	case sqlmapper.PostgreSQL:
+		return PostgreSQL
+	case sqlmapper.SQLite:
+		return SQLite
+	case sqlmapper.SQLServer:
+		return SQLServer
+	case sqlmapper.Oracle:
+		return Oracle
+	}
+	return Generic
+}
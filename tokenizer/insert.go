@@ -0,0 +1,477 @@
+package tokenizer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// Insert holds the table, column list and decoded rows of an
+// INSERT ... VALUES statement
+type Insert struct {
+	Schema  string          // Schema or database qualifier of the table, if any
+	Table   string          // Table name without quotes
+	Columns []string        // Column names without quotes; empty without a column list
+	Rows    [][]interface{} // Decoded values, one slice per row
+}
+
+// IsInsert reports whether a statement is an INSERT or MySQL REPLACE statement
+func IsInsert(stmt Statement) bool {
+	if len(stmt.Tokens) == 0 || stmt.Tokens[0].Type != Word {
+		return false
+	}
+	first := strings.ToUpper(stmt.Tokens[0].Text)
+	return first == "INSERT" || first == "REPLACE"
+}
+
+// ParseInsert decodes the rows of an INSERT ... VALUES statement. Literals are
+// decoded with the quoting rules of the dialect into the value types of
+// sqlmapper.Row: nil, bool, int64, float64, string, []byte for hex and bit
+// literals, time.Time for typed date literals, and sqlmapper.Expr for anything
+// else. It returns nil for INSERT statements without a VALUES list, such as
+// INSERT ... SELECT.
+//
+// Parameters:
+//   - stmt: The INSERT statement
+//   - dialect: The dialect the statement is written in
+//
+// Returns:
+//   - *Insert: The decoded statement, or nil
+//   - error: An error if the statement is malformed
+func ParseInsert(stmt Statement, dialect Dialect) (*Insert, error) {
+	p := &insertParser{tokens: stmt.Tokens, dialect: dialect}
+	if !IsInsert(stmt) {
+		return nil, fmt.Errorf("not an INSERT statement")
+	}
+	p.pos++
+
+	// Modifiers such as IGNORE, OR REPLACE and INTO
+	for p.isWord("IGNORE", "LOW_PRIORITY", "DELAYED", "HIGH_PRIORITY", "OR", "REPLACE", "ROLLBACK", "ABORT", "FAIL", "INTO") {
+		p.pos++
+	}
+
+	insert := &Insert{}
+	name, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	insert.Table = name[len(name)-1]
+	if len(name) > 1 {
+		insert.Schema = name[len(name)-2]
+	}
+
+	if p.isPunct("(") && !p.isWordAt(p.pos+1, "SELECT", "WITH") {
+		p.pos++
+		for {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			insert.Columns = append(insert.Columns, column)
+			if p.isPunct(",") {
+				p.pos++
+				continue
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if !p.isWord("VALUES", "VALUE") {
+		return nil, nil // INSERT ... SELECT or DEFAULT VALUES
+	}
+	p.pos++
+
+	for {
+		row, err := p.row()
+		if err != nil {
+			return nil, err
+		}
+		insert.Rows = append(insert.Rows, row)
+		if !p.isPunct(",") {
+			break // ON DUPLICATE KEY UPDATE, ON CONFLICT and RETURNING clauses are ignored
+		}
+		p.pos++
+	}
+	return insert, nil
+}
+
+// insertParser walks the tokens of an INSERT statement
+type insertParser struct {
+	tokens  []Token
+	pos     int
+	dialect Dialect
+}
+
+func (p *insertParser) isWord(words ...string) bool {
+	return p.isWordAt(p.pos, words...)
+}
+
+func (p *insertParser) isWordAt(i int, words ...string) bool {
+	if i >= len(p.tokens) || p.tokens[i].Type != Word {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(p.tokens[i].Text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *insertParser) isPunct(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].Type == Punctuation && p.tokens[p.pos].Text == text
+}
+
+func (p *insertParser) expect(text string) error {
+	if !p.isPunct(text) {
+		return p.errorf("expected %q", text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *insertParser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if p.pos < len(p.tokens) {
+		token := p.tokens[p.pos]
+		return fmt.Errorf("%s at line %d, column %d near %q", msg, token.Pos.Line, token.Pos.Column, token.Text)
+	}
+	return fmt.Errorf("%s at end of statement", msg)
+}
+
+// identifier reads a plain or quoted identifier and returns it without quotes
+func (p *insertParser) identifier() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", p.errorf("expected identifier")
+	}
+	token := p.tokens[p.pos]
+	switch token.Type {
+	case Word:
+		p.pos++
+		return token.Text, nil
+	case QuotedIdentifier:
+		p.pos++
+		return unquoteIdentifier(token.Text), nil
+	case String:
+		// MySQL reads "name" as a string, but accepts it where an identifier is expected
+		if p.dialect.DoubleQuotedStrings && strings.HasPrefix(token.Text, `"`) {
+			p.pos++
+			return unquoteIdentifier(token.Text), nil
+		}
+	}
+	return "", p.errorf("expected identifier")
+}
+
+// qualifiedName reads a dotted name such as db.schema.table
+func (p *insertParser) qualifiedName() ([]string, error) {
+	var parts []string
+	for {
+		part, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		if !p.isPunct(".") {
+			return parts, nil
+		}
+		p.pos++
+	}
+}
+
+// row reads a parenthesized list of values
+func (p *insertParser) row() ([]interface{}, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for {
+		start, depth := p.pos, 0
+		for p.pos < len(p.tokens) {
+			token := p.tokens[p.pos]
+			if token.Type == Punctuation {
+				if depth == 0 && (token.Text == "," || token.Text == ")") {
+					break
+				}
+				switch token.Text {
+				case "(":
+					depth++
+				case ")":
+					depth--
+				}
+			}
+			p.pos++
+		}
+		if p.pos >= len(p.tokens) {
+			return nil, p.errorf("unterminated VALUES row")
+		}
+		if start == p.pos {
+			return nil, p.errorf("expected value")
+		}
+
+		value, err := decodeValue(p.tokens[start:p.pos], p.dialect)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("%v", err)
+		}
+		values = append(values, value)
+
+		if p.tokens[p.pos].Text == ")" {
+			p.pos++
+			return values, nil
+		}
+		p.pos++
+	}
+}
+
+// decodeValue converts the tokens of a single value expression
+func decodeValue(tokens []Token, dialect Dialect) (interface{}, error) {
+	first := tokens[0]
+	upper := strings.ToUpper(first.Text)
+
+	switch len(tokens) {
+	case 1:
+		switch first.Type {
+		case Number:
+			return decodeNumber(first.Text)
+		case String:
+			return decodeString(first.Text, dialect)
+		case Word:
+			switch upper {
+			case "NULL":
+				return nil, nil
+			case "TRUE":
+				return true, nil
+			case "FALSE":
+				return false, nil
+			}
+		}
+
+	case 2:
+		second := tokens[1]
+		switch {
+		case first.Type == Punctuation && (first.Text == "-" || first.Text == "+") && second.Type == Number:
+			return decodeNumber(first.Text + second.Text)
+		case first.Type == Word && second.Type == String && strings.HasPrefix(first.Text, "_"):
+			// MySQL character set introducer such as _utf8mb4'text' or _binary'data'
+			value, err := decodeString(second.Text, dialect)
+			if text, ok := value.(string); ok && upper == "_BINARY" {
+				return []byte(text), err
+			}
+			return value, err
+		case first.Type == Word && second.Type == String && (upper == "DATE" || upper == "TIMESTAMP" || upper == "DATETIME"):
+			if value, err := decodeString(second.Text, dialect); err == nil {
+				if text, ok := value.(string); ok {
+					if t, ok := sqlmapper.ParseTimeLiteral(text); ok {
+						return t, nil
+					}
+				}
+			}
+		}
+	}
+
+	if first.Type == String && len(tokens) > 3 && tokens[1].Text == ":" && tokens[2].Text == ":" {
+		// PostgreSQL cast such as '\x00ff'::bytea or '2024-01-01'::date
+		value, err := decodeString(first.Text, dialect)
+		if err != nil {
+			return nil, err
+		}
+		return castValue(value, joinTokens(tokens[3:])), nil
+	}
+
+	if first.Type == Word && len(tokens) >= 4 && tokens[1].Text == "(" && tokens[len(tokens)-1].Text == ")" && tokens[2].Type == String {
+		args := tokens[3 : len(tokens)-1]
+		value, err := decodeString(tokens[2].Text, dialect)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case (upper == "TO_DATE" || upper == "TO_TIMESTAMP") && (len(args) == 0 || (len(args) == 2 && args[0].Text == "," && args[1].Type == String)):
+			// The format mask is not interpreted; ISO 8601 values are recognized
+			if text, ok := value.(string); ok {
+				if t, ok := sqlmapper.ParseTimeLiteral(text); ok {
+					return t, nil
+				}
+			}
+		case upper == "HEXTORAW" && len(args) == 0:
+			if text, ok := value.(string); ok {
+				if data, err := hex.DecodeString(text); err == nil {
+					return data, nil
+				}
+			}
+		case upper == "CAST" && len(args) > 1 && strings.EqualFold(args[0].Text, "AS"):
+			return castValue(value, joinTokens(args[1:])), nil
+		}
+	}
+
+	return sqlmapper.Expr(joinTokens(tokens)), nil
+}
+
+// castValue converts a decoded string to the type named in a cast
+func castValue(value interface{}, typeName string) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	upper := strings.ToUpper(typeName)
+	switch {
+	case upper == "BYTEA" && strings.HasPrefix(text, `\x`):
+		if data, err := hex.DecodeString(text[2:]); err == nil {
+			return data
+		}
+	case upper == "BOOLEAN" || upper == "BOOL":
+		switch strings.ToLower(strings.TrimSpace(text)) {
+		case "t", "true", "y", "yes", "on", "1":
+			return true
+		case "f", "false", "n", "no", "off", "0":
+			return false
+		}
+	case strings.HasPrefix(upper, "DATE") || strings.HasPrefix(upper, "TIMESTAMP"):
+		if t, ok := sqlmapper.ParseTimeLiteral(text); ok {
+			return t
+		}
+	}
+	return text
+}
+
+// decodeNumber converts a numeric literal to int64, float64, or []byte for 0x
+// and 0b literals
+func decodeNumber(text string) (interface{}, error) {
+	if len(text) > 2 && text[0] == '0' {
+		switch text[1] {
+		case 'x', 'X':
+			return decodeHex(text[2:])
+		case 'b', 'B':
+			return decodeBits(text[2:])
+		}
+	}
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s", text)
+	}
+	return f, nil
+}
+
+// decodeString decodes a string literal token into a string, or into []byte
+// for X'...' and B'...' literals
+func decodeString(text string, dialect Dialect) (interface{}, error) {
+	if strings.HasPrefix(text, "$") {
+		tagEnd := strings.Index(text[1:], "$") + 2
+		return text[tagEnd : len(text)-tagEnd], nil
+	}
+
+	quote := strings.IndexAny(text, `'"`)
+	prefix := strings.ToUpper(text[:quote])
+	body := text[quote+1 : len(text)-1]
+
+	switch prefix {
+	case "X":
+		return decodeHex(body)
+	case "B":
+		return decodeBits(body)
+	case "Q", "NQ":
+		return body[1 : len(body)-1], nil
+	case "E":
+		return unescape(body, '\''), nil
+	}
+
+	q := text[quote]
+	if dialect.BackslashEscapes {
+		return unescape(body, q), nil
+	}
+	return strings.ReplaceAll(body, string([]byte{q, q}), string(q)), nil
+}
+
+// unescape resolves backslash escapes and doubled quotes in a string body
+func unescape(body string, quote byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == quote && i+1 < len(body) && body[i+1] == quote:
+			i++
+		case c == '\\' && i+1 < len(body):
+			i++
+			switch body[i] {
+			case '0':
+				c = 0
+			case 'b':
+				c = '\b'
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'Z':
+				c = 0x1a
+			case '%', '_':
+				// Kept escaped, as they are only special in LIKE patterns
+				sb.WriteByte('\\')
+				c = body[i]
+			default:
+				c = body[i]
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func decodeHex(digits string) ([]byte, error) {
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex literal %s", digits)
+	}
+	return data, nil
+}
+
+// decodeBits converts a string of binary digits to bytes, padding on the left
+func decodeBits(digits string) ([]byte, error) {
+	if pad := len(digits) % 8; pad != 0 {
+		digits = strings.Repeat("0", 8-pad) + digits
+	}
+	data := make([]byte, len(digits)/8)
+	for i := range data {
+		b, err := strconv.ParseUint(digits[i*8:i*8+8], 2, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bit literal %s", digits)
+		}
+		data[i] = byte(b)
+	}
+	return data, nil
+}
+
+// unquoteIdentifier removes the quotes of a quoted identifier
+func unquoteIdentifier(text string) string {
+	if len(text) < 2 {
+		return text
+	}
+	open, close := text[0], text[len(text)-1]
+	body := text[1 : len(text)-1]
+	switch {
+	case open == '[' && close == ']':
+		return strings.ReplaceAll(body, "]]", "]")
+	case (open == '"' || open == '`') && close == open:
+		return strings.ReplaceAll(body, string([]byte{open, open}), string(open))
+	}
+	return text
+}
+
+// joinTokens returns the text of tokens, separated by a space where the input had a gap
+func joinTokens(tokens []Token) string {
+	return Statement{Tokens: tokens}.Compact()
+}
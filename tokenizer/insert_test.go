@@ -0,0 +1,128 @@
+package tokenizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInsert(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	stamp := time.Date(2024, 3, 1, 10, 30, 0, 500000000, time.UTC)
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		sql     string
+		want    *Insert
+	}{
+		{
+			name:    "MySQL escapes and hex",
+			dialect: MySQL,
+			sql:     "INSERT IGNORE INTO `shop`.`users` (`id`, name, bio, avatar, active) VALUES (1, 'O\\'Brien', 'a\\nb', 0xCAFE, TRUE), (-2, \"say \"\"hi\"\"\", NULL, _binary'ab', FALSE);",
+			want: &Insert{
+				Schema:  "shop",
+				Table:   "users",
+				Columns: []string{"id", "name", "bio", "avatar", "active"},
+				Rows: [][]interface{}{
+					{int64(1), "O'Brien", "a\nb", []byte{0xCA, 0xFE}, true},
+					{int64(-2), `say "hi"`, nil, []byte("ab"), false},
+				},
+			},
+		},
+		{
+			name:    "MySQL trailing clauses",
+			dialect: MySQL,
+			sql:     "REPLACE INTO t VALUES (1.5, b'101', NOW()) ON DUPLICATE KEY UPDATE a = 1;",
+			want: &Insert{
+				Table: "t",
+				Rows:  [][]interface{}{{1.5, []byte{5}, sqlmapper.Expr("NOW()")}},
+			},
+		},
+		{
+			name:    "PostgreSQL casts and dollar quotes",
+			dialect: PostgreSQL,
+			sql:     `INSERT INTO public."Files" (data, created, flag, note, path) VALUES ('\x00ff'::bytea, '2024-03-01'::date, 't'::boolean, $$it's$$, E'C:\\tmp') RETURNING id;`,
+			want: &Insert{
+				Schema:  "public",
+				Table:   "Files",
+				Columns: []string{"data", "created", "flag", "note", "path"},
+				Rows:    [][]interface{}{{[]byte{0x00, 0xFF}, date, true, "it's", `C:\tmp`}},
+			},
+		},
+		{
+			name:    "PostgreSQL keeps backslashes in standard strings",
+			dialect: PostgreSQL,
+			sql:     `INSERT INTO t (p) VALUES ('C:\tmp');`,
+			want:    &Insert{Table: "t", Columns: []string{"p"}, Rows: [][]interface{}{{`C:\tmp`}}},
+		},
+		{
+			name:    "SQLite blobs",
+			dialect: SQLite,
+			sql:     `INSERT OR REPLACE INTO "t" ("a", [b]) VALUES (X'0A0B', 'x''y');`,
+			want:    &Insert{Table: "t", Columns: []string{"a", "b"}, Rows: [][]interface{}{{[]byte{0x0A, 0x0B}, "x'y"}}},
+		},
+		{
+			name:    "SQL Server Unicode and binary",
+			dialect: SQLServer,
+			sql:     "INSERT INTO [dbo].[t] ([name], [data], [at]) VALUES (N'Ça''y', 0x0102, CAST('2024-03-01 10:30:00.5' AS DATETIME2))\nGO",
+			want: &Insert{
+				Schema:  "dbo",
+				Table:   "t",
+				Columns: []string{"name", "data", "at"},
+				Rows:    [][]interface{}{{"Ça'y", []byte{1, 2}, stamp}},
+			},
+		},
+		{
+			name:    "Oracle typed literals",
+			dialect: Oracle,
+			sql:     "INSERT INTO hr.t (d, ts, raw, q) VALUES (DATE '2024-03-01', TO_TIMESTAMP('2024-03-01 10:30:00.5', 'YYYY-MM-DD HH24:MI:SS.FF'), HEXTORAW('CAFE'), q'[it's]');",
+			want: &Insert{
+				Schema:  "hr",
+				Table:   "t",
+				Columns: []string{"d", "ts", "raw", "q"},
+				Rows:    [][]interface{}{{date, stamp, []byte{0xCA, 0xFE}, "it's"}},
+			},
+		},
+		{
+			name:    "INSERT SELECT",
+			dialect: Generic,
+			sql:     "INSERT INTO t (a) SELECT a FROM s;",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := Split(tt.sql, tt.dialect)
+			assert.NoError(t, err)
+			assert.Len(t, statements, 1)
+			assert.True(t, IsInsert(statements[0]))
+
+			insert, err := ParseInsert(statements[0], tt.dialect)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, insert)
+		})
+	}
+}
+
+func TestParseInsert_Errors(t *testing.T) {
+	for _, sql := range []string{
+		"INSERT INTO t (a VALUES (1);",
+		"INSERT INTO t (a) VALUES 1;",
+		"INSERT INTO t (a) VALUES (1, );",
+		"INSERT INTO t (a) VALUES (X'ZZ');",
+		"INSERT INTO (a) VALUES (1);",
+	} {
+		statements, err := Split(sql, Generic)
+		assert.NoError(t, err, sql)
+
+		_, err = ParseInsert(statements[0], Generic)
+		assert.Error(t, err, sql)
+	}
+
+	statements, _ := Split("CREATE TABLE t (a INT);", Generic)
+	assert.False(t, IsInsert(statements[0]))
+}
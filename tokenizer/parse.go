@@ -0,0 +1,73 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// ParseStatements splits content into statements with the rules of a dialect
+// and calls parse for each of them. The objects parse adds to schema get the
+// position of their statement.
+//
+// Without lenient, the first error is returned as a *sqlmapper.ParseError. In
+// lenient mode, a statement that fails to parse is recorded as a diagnostic, the
+// objects it added are removed from schema and parsing goes on with the next
+// statement. A lexical error, such as an unterminated string, leaves the rest of
+// the content unsplittable; it is recorded as a diagnostic holding that rest. A
+// panic in parse is an error of its statement.
+//
+// Parameters:
+//   - content: The SQL content to parse
+//   - dialect: The lexical rules used to split statements
+//   - schema: The schema parse adds objects to
+//   - parse: Parses a single statement into schema
+//   - lenient: Whether to skip statements that fail to parse
+//
+// Returns:
+//   - []sqlmapper.Diagnostic: The statements skipped in lenient mode, in input order
+//   - error: The first error when not lenient
+func ParseStatements(content string, dialect Dialect, schema *sqlmapper.Schema, parse func(Statement) error, lenient bool) ([]sqlmapper.Diagnostic, error) {
+	statements, splitErr := Split(content, dialect)
+	if splitErr != nil && !lenient {
+		return nil, AsParseError(splitErr)
+	}
+
+	var diagnostics []sqlmapper.Diagnostic
+	for _, stmt := range statements {
+		mark := sqlmapper.MarkPositions(schema)
+		if err := parseRecovered(parse, stmt); err != nil {
+			if !lenient {
+				return nil, stmt.Wrap(err)
+			}
+			mark.Rollback(schema)
+			diagnostics = append(diagnostics, sqlmapper.UnparsedStatement(stmt.Pos.Source(), stmt.Text, err))
+			continue
+		}
+		mark.Apply(schema, stmt.Pos.Source())
+	}
+
+	if splitErr != nil {
+		var lexErr *Error
+		if errors.As(splitErr, &lexErr) {
+			rest := strings.TrimSpace(content[lexErr.Pos.Offset:])
+			diagnostics = append(diagnostics, sqlmapper.UnparsedStatement(lexErr.Pos.Source(), rest, errors.New(lexErr.Message)))
+		} else {
+			diagnostics = append(diagnostics, sqlmapper.UnparsedStatement(sqlmapper.Position{}, "", splitErr))
+		}
+	}
+	return diagnostics, nil
+}
+
+// parseRecovered calls parse, turning a panic on input the parser does not
+// expect into an error of the statement
+func parseRecovered(parse func(Statement) error, stmt Statement) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal parser error: %v", r)
+		}
+	}()
+	return parse(stmt)
+}
@@ -0,0 +1,290 @@
+package tokenizer
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// Statement is a single SQL statement read from the input
+type Statement struct {
+	// Text is the statement source without comments and without its
+	// terminator, with leading and trailing whitespace removed
+	Text string
+
+	// Tokens holds the significant tokens of the statement: everything
+	// except whitespace and comments
+	Tokens []Token
+
+	// Pos is the position of the first token of the statement
+	Pos Position
+}
+
+// Compact returns the statement text with every run of whitespace and
+// comments outside literals collapsed into a single space
+func (s Statement) Compact() string {
+	var sb strings.Builder
+	for i, token := range s.Tokens {
+		if i > 0 {
+			prev := s.Tokens[i-1]
+			if prev.Pos.Offset+len(prev.Text) < token.Pos.Offset {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(token.Text)
+	}
+	return sb.String()
+}
+
+// Wrap returns err as a *sqlmapper.ParseError located at the statement
+func (s Statement) Wrap(err error) error {
+	return sqlmapper.NewParseError(s.Pos.Source(), s.Text, err)
+}
+
+// routineKind classifies statements by how their end is found
+type routineKind int
+
+const (
+	unknownKind      routineKind = iota // Not classified yet
+	plainKind                           // Ends at the next terminator
+	routineKindBlock                    // Routine or trigger that may hold BEGIN...END blocks
+	untilSlashKind                      // Package or type body that ends at a "/" line
+)
+
+// StatementReader splits an input stream into statements
+type StatementReader struct {
+	tokenizer *Tokenizer
+	dialect   Dialect
+}
+
+// NewStatementReader creates a statement reader for the given dialect
+func NewStatementReader(r io.Reader, dialect Dialect) *StatementReader {
+	return &StatementReader{
+		tokenizer: New(r, dialect),
+		dialect:   dialect,
+	}
+}
+
+// NewStatementReaderAt creates a statement reader for input that continues an
+// earlier input at pos, the position just past a statement of that input, as
+// returned by Position. The dialect's Delimiter must be the terminator in effect
+// there, as returned by Delimiter.
+func NewStatementReaderAt(r io.Reader, dialect Dialect, pos Position) *StatementReader {
+	return &StatementReader{
+		tokenizer: NewAt(r, dialect, pos),
+		dialect:   dialect,
+	}
+}
+
+// Offset returns the number of bytes of the input consumed so far. After Next
+// it is the offset just past the terminator of the returned statement.
+func (sr *StatementReader) Offset() int {
+	return sr.tokenizer.Offset()
+}
+
+// Position returns the position just past the input consumed so far
+func (sr *StatementReader) Position() Position {
+	return sr.tokenizer.Position()
+}
+
+// Delimiter returns the statement terminator in effect, which a DELIMITER
+// command may have changed
+func (sr *StatementReader) Delimiter() string {
+	return sr.tokenizer.Delimiter()
+}
+
+// Split splits the content into statements. Empty statements are skipped.
+func Split(content string, dialect Dialect) ([]Statement, error) {
+	reader := NewStatementReader(strings.NewReader(content), dialect)
+	var statements []Statement
+	for {
+		stmt, err := reader.Next()
+		if err == io.EOF {
+			return statements, nil
+		}
+		if err != nil {
+			return statements, err
+		}
+		statements = append(statements, stmt)
+	}
+}
+
+// Next returns the next non-empty statement, or io.EOF at the end of the input.
+// A statement ends at a terminator outside any literal, comment or routine
+// block: a semicolon or the delimiter set with DELIMITER, a batch separator
+// line such as GO, or a "/" line.
+func (sr *StatementReader) Next() (Statement, error) {
+	var (
+		text     strings.Builder
+		pending  strings.Builder // Whitespace not yet known to be inside the statement
+		stmt     Statement
+		kind     routineKind
+		words    []string // Leading words used to classify the statement
+		depth    int      // Open BEGIN and CASE blocks
+		seenBody bool     // A BEGIN...END block has been opened
+		afterEnd bool     // The previous word was END
+		afterBeg bool     // The previous word was BEGIN
+	)
+
+	finish := func() Statement {
+		stmt.Text = text.String()
+		return stmt
+	}
+
+	for {
+		token, err := sr.tokenizer.Next()
+		if err == io.EOF {
+			if len(stmt.Tokens) > 0 {
+				return finish(), nil
+			}
+			return Statement{}, io.EOF
+		}
+		if err != nil {
+			return Statement{}, err
+		}
+
+		switch token.Type {
+		case Whitespace:
+			if len(stmt.Tokens) > 0 {
+				pending.WriteString(token.Text)
+			}
+			continue
+		case Comment:
+			if len(stmt.Tokens) > 0 && pending.Len() == 0 {
+				pending.WriteByte(' ')
+			}
+			continue
+		case Directive:
+			if len(stmt.Tokens) > 0 {
+				return finish(), nil
+			}
+			continue
+		}
+
+		upper := ""
+		if token.Type == Word {
+			upper = strings.ToUpper(token.Text)
+		}
+
+		// Resolve a BEGIN or END whose meaning depends on the following word
+		if afterBeg {
+			afterBeg = false
+			switch upper {
+			case "TRAN", "TRANSACTION", "WORK", "DISTRIBUTED":
+			default:
+				if token.Type != Delimiter {
+					depth++
+					seenBody = true
+				}
+			}
+		}
+		if afterEnd {
+			afterEnd = false
+			switch upper {
+			case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+				// Closes a construct that is not counted
+			default:
+				if depth > 0 {
+					depth--
+				}
+			}
+		}
+
+		if token.Type == Delimiter {
+			if len(stmt.Tokens) == 0 {
+				continue
+			}
+			if token.Text == ";" && !sr.endsAtSemicolon(kind, depth, seenBody) {
+				text.WriteString(pending.String())
+				pending.Reset()
+				text.WriteString(token.Text)
+				stmt.Tokens = append(stmt.Tokens, token)
+				continue
+			}
+			return finish(), nil
+		}
+
+		if len(stmt.Tokens) == 0 {
+			stmt.Pos = token.Pos
+		}
+		text.WriteString(pending.String())
+		pending.Reset()
+		text.WriteString(token.Text)
+		stmt.Tokens = append(stmt.Tokens, token)
+
+		if !sr.dialect.BlockKeywords {
+			continue
+		}
+		if kind == unknownKind && token.Type == Word {
+			words = append(words, upper)
+			kind = sr.classify(words)
+		}
+		if kind == routineKindBlock || kind == untilSlashKind {
+			switch upper {
+			case "BEGIN":
+				afterBeg = true
+			case "CASE":
+				depth++
+			case "END":
+				afterEnd = true
+			}
+		}
+	}
+}
+
+// endsAtSemicolon reports whether a semicolon ends a statement of the given kind
+func (sr *StatementReader) endsAtSemicolon(kind routineKind, depth int, seenBody bool) bool {
+	switch kind {
+	case untilSlashKind:
+		return false
+	case routineKindBlock:
+		if depth > 0 {
+			return false
+		}
+		return seenBody || !sr.dialect.RoutineUntilEnd
+	}
+	return true
+}
+
+// classify determines the kind of a statement from its leading words. It
+// returns unknownKind while more words are needed.
+func (sr *StatementReader) classify(words []string) routineKind {
+	first := words[0]
+	if sr.dialect.RoutineUntilEnd && (first == "DECLARE" || (first == "BEGIN" && sr.dialect.SlashTerminator)) {
+		return routineKindBlock
+	}
+	if first != "CREATE" && first != "ALTER" {
+		return plainKind
+	}
+
+	last := words[len(words)-1]
+	switch last {
+	case "FUNCTION", "PROCEDURE", "PROC", "TRIGGER", "EVENT":
+		return routineKindBlock
+	case "PACKAGE":
+		if sr.dialect.SlashTerminator {
+			return untilSlashKind
+		}
+		return plainKind
+	case "BODY":
+		if sr.dialect.SlashTerminator && len(words) > 1 && words[len(words)-2] == "TYPE" {
+			return untilSlashKind
+		}
+		return plainKind
+	case "TYPE":
+		if sr.dialect.SlashTerminator {
+			return unknownKind // TYPE BODY is a PL/SQL unit, TYPE alone is not
+		}
+		return plainKind
+	case "CREATE", "ALTER", "OR", "REPLACE", "DEFINER", "EDITIONABLE", "NONEDITIONABLE",
+		"DEFINITIONAL", "AGGREGATE", "CONSTRAINT", "TEMPORARY", "TEMP", "SQL", "SECURITY", "INVOKER":
+		return unknownKind
+	}
+
+	// MySQL DEFINER = user@host clauses
+	if (len(words) > 1 && words[len(words)-2] == "DEFINER") || strings.HasPrefix(last, "@") {
+		return unknownKind
+	}
+	return plainKind
+}
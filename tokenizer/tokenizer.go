@@ -0,0 +1,624 @@
+// Package tokenizer provides a dialect-configurable SQL lexer and statement
+// splitter shared by all parsers. It understands quoted identifiers, string
+// literals including dollar-quoted and q'[...]' strings, nested block comments,
+// and client-side terminators such as DELIMITER, GO and the Oracle "/" line,
+// so that semicolons inside literals, comments and routine bodies never split
+// a statement. ParseInsert decodes the literals of INSERT statements.
+package tokenizer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mstgnz/sqlmapper"
+)
+
+// TokenType represents the kind of a token
+type TokenType int
+
+const (
+	Whitespace TokenType = iota
+	Comment
+	Word             // Keywords and unquoted identifiers
+	QuotedIdentifier // "name", `name` or [name]
+	String           // String literals, including prefixed and dollar-quoted ones
+	Number
+	Punctuation // Operators and punctuation
+	Delimiter   // Statement terminators: ";", a custom delimiter, GO or "/"
+	Directive   // Client commands such as DELIMITER
+)
+
+var tokenTypeNames = map[TokenType]string{
+	Whitespace:       "whitespace",
+	Comment:          "comment",
+	Word:             "word",
+	QuotedIdentifier: "quoted identifier",
+	String:           "string",
+	Number:           "number",
+	Punctuation:      "punctuation",
+	Delimiter:        "delimiter",
+	Directive:        "directive",
+}
+
+// String returns the name of the token type
+func (t TokenType) String() string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TokenType(%d)", int(t))
+}
+
+// Position is a location in the input. Offset is in bytes from the start of
+// the input; Line and Column are 1-based, with columns counted in characters.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// String formats the position as "line:column"
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Source converts the position to the position type used by schema objects
+func (p Position) Source() sqlmapper.Position {
+	return sqlmapper.Position{Line: p.Line, Column: p.Column, Offset: p.Offset}
+}
+
+// Token is a lexical element of the input
+type Token struct {
+	Type TokenType
+	Text string
+	Pos  Position
+}
+
+// Error is a lexical error, such as an unterminated string or comment
+type Error struct {
+	Pos     Position
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Pos.Line, e.Pos.Column, e.Message)
+}
+
+// AsParseError converts a lexical error into a *sqlmapper.ParseError so that
+// callers see the same error type for lexical and syntax errors. Other errors
+// are returned unchanged.
+func AsParseError(err error) error {
+	var lexErr *Error
+	if errors.As(err, &lexErr) {
+		return sqlmapper.NewParseError(lexErr.Pos.Source(), "", errors.New(lexErr.Message))
+	}
+	return err
+}
+
+var (
+	delimiterCommandRe = regexp.MustCompile(`(?i)^[ \t]*DELIMITER[ \t]+(\S+)[ \t]*\r?$`)
+	slashLineRe        = regexp.MustCompile(`^[ \t]*/[ \t]*\r?$`)
+)
+
+// Tokenizer reads tokens from an input stream. It holds at most one line of
+// look-ahead, so arbitrarily large inputs are processed in constant memory.
+type Tokenizer struct {
+	r           *bufio.Reader
+	src         *errorReader
+	dialect     Dialect
+	delimiter   string // Current statement terminator
+	pos         Position
+	lineStart   bool // Only whitespace has been read on the current line
+	batchLineRe *regexp.Regexp
+	text        strings.Builder
+}
+
+// New creates a tokenizer reading from r with the rules of the given dialect
+func New(r io.Reader, dialect Dialect) *Tokenizer {
+	return NewAt(r, dialect, Position{Line: 1, Column: 1})
+}
+
+// NewAt creates a tokenizer for input that continues an earlier input at pos,
+// such as a file read from an offset. Tokens are positioned in the earlier
+// input, and pos must be the start of a token.
+func NewAt(r io.Reader, dialect Dialect, pos Position) *Tokenizer {
+	src := &errorReader{r: r}
+	t := &Tokenizer{
+		r:         bufio.NewReader(src),
+		src:       src,
+		dialect:   dialect,
+		delimiter: ";",
+		pos:       pos,
+		lineStart: pos.Column == 1,
+	}
+	if dialect.Delimiter != "" {
+		t.delimiter = dialect.Delimiter
+	}
+	if dialect.BatchSeparator != "" {
+		t.batchLineRe = regexp.MustCompile(`(?i)^[ \t]*` + regexp.QuoteMeta(dialect.BatchSeparator) + `(?:[ \t]+\d+)?[ \t]*\r?$`)
+	}
+	return t
+}
+
+// Tokenize splits the input into tokens, including whitespace and comments
+func Tokenize(input string, dialect Dialect) ([]Token, error) {
+	t := New(strings.NewReader(input), dialect)
+	var tokens []Token
+	for {
+		token, err := t.Next()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// Delimiter returns the current statement terminator
+func (t *Tokenizer) Delimiter() string {
+	return t.delimiter
+}
+
+// Offset returns the number of bytes of the input consumed so far
+func (t *Tokenizer) Offset() int {
+	return t.pos.Offset
+}
+
+// Position returns the position of the next token
+func (t *Tokenizer) Position() Position {
+	return t.pos
+}
+
+// Next returns the next token. It returns io.EOF at the end of the input, an
+// *Error for unterminated literals and comments, and the error of the input
+// reader once reading has failed.
+func (t *Tokenizer) Next() (Token, error) {
+	token, err := t.next()
+	if t.src.err != nil && t.r.Buffered() == 0 {
+		// The token may have been cut short by the failed read
+		return Token{}, t.src.err
+	}
+	return token, err
+}
+
+// next reads the next token, taking a failed read for the end of the input
+func (t *Tokenizer) next() (Token, error) {
+	t.text.Reset()
+	start := t.pos
+
+	if t.lineStart {
+		if token, ok := t.lineDirective(); ok {
+			return token, nil
+		}
+	}
+
+	c, ok := t.peekByte(0)
+	if !ok {
+		return Token{}, io.EOF
+	}
+
+	if t.delimiter != ";" && t.hasPrefix(t.delimiter) {
+		t.readN(len(t.delimiter))
+		return t.token(Delimiter, start), nil
+	}
+
+	switch {
+	case c == '\n' || c == '\r' || c == ' ' || c == '\t' || c == '\f' || c == '\v':
+		for {
+			c, ok := t.peekByte(0)
+			if !ok || !(c == '\n' || c == '\r' || c == ' ' || c == '\t' || c == '\f' || c == '\v') {
+				break
+			}
+			t.read()
+		}
+		return t.token(Whitespace, start), nil
+
+	case c == '-' && t.hasPrefix("--"), c == '#' && t.dialect.HashComments:
+		for {
+			c, ok := t.peekByte(0)
+			if !ok || c == '\n' {
+				break
+			}
+			t.read()
+		}
+		return t.token(Comment, start), nil
+
+	case c == '/' && t.hasPrefix("/*"):
+		return t.blockComment(start)
+
+	case c == '\'':
+		return t.quoted(String, '\'', '\'', t.dialect.BackslashEscapes, start)
+
+	case c == '"':
+		if t.dialect.DoubleQuotedStrings {
+			return t.quoted(String, '"', '"', t.dialect.BackslashEscapes, start)
+		}
+		return t.quoted(QuotedIdentifier, '"', '"', false, start)
+
+	case c == '`' && t.dialect.BacktickIdentifiers:
+		return t.quoted(QuotedIdentifier, '`', '`', false, start)
+
+	case c == '[' && t.dialect.BracketIdentifiers:
+		return t.quoted(QuotedIdentifier, '[', ']', false, start)
+
+	case c == '$' && t.dialect.DollarQuoting:
+		if tag, ok := t.dollarTag(); ok {
+			return t.dollarQuoted(tag, start)
+		}
+		t.read()
+		return t.token(Punctuation, start), nil
+
+	case c == ';' && t.delimiter == ";":
+		t.read()
+		return t.token(Delimiter, start), nil
+
+	case c >= '0' && c <= '9', c == '.' && t.nextIsDigit():
+		return t.number(start), nil
+
+	case isWordStart(t.peekRune()):
+		return t.word(start)
+	}
+
+	t.read()
+	return t.token(Punctuation, start), nil
+}
+
+// lineDirective recognizes client commands that take up a whole line:
+// DELIMITER, the batch separator and the "/" terminator
+func (t *Tokenizer) lineDirective() (Token, bool) {
+	line := t.peekLine()
+	if strings.TrimSpace(line) == "" {
+		return Token{}, false
+	}
+
+	start := t.pos
+	switch {
+	case t.dialect.DelimiterCommand && delimiterCommandRe.MatchString(line):
+		t.delimiter = delimiterCommandRe.FindStringSubmatch(line)[1]
+		t.readN(len(strings.TrimRight(line, "\r")))
+		return t.token(Directive, start), true
+
+	case t.batchLineRe != nil && t.batchLineRe.MatchString(line),
+		t.dialect.SlashTerminator && slashLineRe.MatchString(line):
+		t.readN(len(strings.TrimRight(line, "\r")))
+		token := t.token(Delimiter, start)
+		token.Text = strings.TrimSpace(token.Text)
+		return token, true
+	}
+	return Token{}, false
+}
+
+// blockComment reads a /* */ comment, honoring nesting when the dialect allows it
+func (t *Tokenizer) blockComment(start Position) (Token, error) {
+	t.readN(2)
+	depth := 1
+	for depth > 0 {
+		if _, ok := t.peekByte(0); !ok {
+			return Token{}, &Error{Pos: start, Message: "unterminated block comment"}
+		}
+		switch {
+		case t.hasPrefix("*/"):
+			t.readN(2)
+			depth--
+		case t.dialect.NestedComments && t.hasPrefix("/*"):
+			t.readN(2)
+			depth++
+		default:
+			t.read()
+		}
+	}
+	return t.token(Comment, start), nil
+}
+
+// quoted reads a literal enclosed in the given quote characters. A doubled
+// closing quote stands for the quote itself.
+func (t *Tokenizer) quoted(typ TokenType, open, close byte, backslash bool, start Position) (Token, error) {
+	t.read() // Opening quote
+	for {
+		c, ok := t.peekByte(0)
+		if !ok {
+			return Token{}, &Error{Pos: start, Message: "unterminated " + typ.String()}
+		}
+		t.read()
+		switch {
+		case backslash && c == '\\':
+			if _, ok := t.peekByte(0); ok {
+				t.read()
+			}
+		case c == close:
+			if next, ok := t.peekByte(0); ok && next == close {
+				t.read()
+				continue
+			}
+			return t.token(typ, start), nil
+		}
+	}
+}
+
+// dollarTag reports whether the input starts a dollar-quoted string and returns its tag
+func (t *Tokenizer) dollarTag() (string, bool) {
+	buf, _ := t.r.Peek(64)
+	if len(buf) < 2 || buf[0] != '$' {
+		return "", false
+	}
+	end := bytes.IndexByte(buf[1:], '$')
+	if end < 0 {
+		return "", false
+	}
+	tag := string(buf[1 : end+1])
+	for i, r := range tag {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return "", false
+		}
+	}
+	return "$" + tag + "$", true
+}
+
+// dollarQuoted reads a $tag$...$tag$ string
+func (t *Tokenizer) dollarQuoted(tag string, start Position) (Token, error) {
+	t.readN(len(tag))
+	for {
+		if _, ok := t.peekByte(0); !ok {
+			return Token{}, &Error{Pos: start, Message: "unterminated dollar-quoted string"}
+		}
+		if t.hasPrefix(tag) {
+			t.readN(len(tag))
+			return t.token(String, start), nil
+		}
+		t.read()
+	}
+}
+
+// quoteOperator reads the rest of a q'[...]' string after its prefix
+func (t *Tokenizer) quoteOperator(start Position) (Token, error) {
+	t.read() // Opening quote
+	open, ok := t.peekByte(0)
+	if !ok {
+		return Token{}, &Error{Pos: start, Message: "unterminated string"}
+	}
+	t.read()
+
+	close := open
+	switch open {
+	case '[':
+		close = ']'
+	case '{':
+		close = '}'
+	case '(':
+		close = ')'
+	case '<':
+		close = '>'
+	}
+
+	for {
+		if _, ok := t.peekByte(0); !ok {
+			return Token{}, &Error{Pos: start, Message: "unterminated string"}
+		}
+		if t.hasPrefix(string([]byte{close, '\''})) {
+			t.readN(2)
+			return t.token(String, start), nil
+		}
+		t.read()
+	}
+}
+
+// number reads a numeric literal, including 0x hexadecimal and 0b binary literals
+func (t *Tokenizer) number(start Position) Token {
+	if prefixed, ok := t.radixDigits(); ok {
+		t.readN(2)
+		for {
+			c, ok := t.peekByte(0)
+			if !ok || !prefixed(c) {
+				return t.token(Number, start)
+			}
+			t.read()
+		}
+	}
+
+	seenDot, seenExp := false, false
+	for {
+		c, ok := t.peekByte(0)
+		if !ok {
+			break
+		}
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' && !seenDot && !seenExp:
+			seenDot = true
+		case (c == 'e' || c == 'E') && !seenExp && t.expDigits():
+			seenExp = true
+			t.read()
+			if c, _ := t.peekByte(0); c == '+' || c == '-' {
+				t.read()
+			}
+			continue
+		default:
+			return t.token(Number, start)
+		}
+		t.read()
+	}
+	return t.token(Number, start)
+}
+
+// word reads a keyword or identifier, or a string literal with a prefix such
+// as N'...', E'...' or q'[...]'
+func (t *Tokenizer) word(start Position) (Token, error) {
+	for {
+		r := t.peekRune()
+		if r < 0 || !t.isWordPart(r) {
+			break
+		}
+		// A custom delimiter may directly follow a word, as in END$$
+		if t.delimiter != ";" && t.hasPrefix(t.delimiter) {
+			break
+		}
+		t.read()
+	}
+
+	if next, ok := t.peekByte(0); ok && next == '\'' {
+		prefix := strings.ToUpper(t.text.String())
+		switch {
+		case t.dialect.QuoteOperator && (prefix == "Q" || prefix == "NQ"):
+			return t.quoteOperator(start)
+		case t.dialect.EscapeStrings && prefix == "E":
+			return t.quoted(String, '\'', '\'', true, start)
+		case prefix == "N" || prefix == "X" || prefix == "B":
+			return t.quoted(String, '\'', '\'', t.dialect.BackslashEscapes, start)
+		}
+	}
+	return t.token(Word, start), nil
+}
+
+func (t *Tokenizer) token(typ TokenType, start Position) Token {
+	return Token{Type: typ, Text: t.text.String(), Pos: start}
+}
+
+// errorReader keeps the first error of a reader other than io.EOF, which the
+// look-ahead of the tokenizer would otherwise take for the end of the input
+type errorReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errorReader) Read(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n, err := e.r.Read(p)
+	if err != nil && err != io.EOF {
+		e.err = err
+	}
+	return n, err
+}
+
+// read consumes one character, appending it to the current token and advancing the position
+func (t *Tokenizer) read() {
+	buf, _ := t.r.Peek(utf8.UTFMax)
+	if len(buf) == 0 {
+		return
+	}
+	// Invalid UTF-8 is copied through byte by byte
+	r, size := utf8.DecodeRune(buf)
+	t.text.Write(buf[:size])
+	t.r.Discard(size)
+	t.pos.Offset += size
+	if r == '\n' {
+		t.pos.Line++
+		t.pos.Column = 1
+		t.lineStart = true
+		return
+	}
+	t.pos.Column++
+	if r != ' ' && r != '\t' && r != '\r' {
+		t.lineStart = false
+	}
+}
+
+// readN consumes n bytes of ASCII input
+func (t *Tokenizer) readN(n int) {
+	for start := t.pos.Offset; t.pos.Offset-start < n; {
+		if _, ok := t.peekByte(0); !ok {
+			return
+		}
+		t.read()
+	}
+}
+
+func (t *Tokenizer) peekByte(i int) (byte, bool) {
+	buf, err := t.r.Peek(i + 1)
+	if err != nil || len(buf) <= i {
+		return 0, false
+	}
+	return buf[i], true
+}
+
+// peekRune returns the next character without consuming it, or -1 at the end of input
+func (t *Tokenizer) peekRune() rune {
+	buf, _ := t.r.Peek(utf8.UTFMax)
+	if len(buf) == 0 {
+		return -1
+	}
+	r, _ := utf8.DecodeRune(buf)
+	return r
+}
+
+func (t *Tokenizer) hasPrefix(s string) bool {
+	buf, _ := t.r.Peek(len(s))
+	return string(buf) == s
+}
+
+// peekLine returns the rest of the current line without consuming it. Lines
+// longer than the read buffer are truncated, which is harmless since no
+// directive is that long.
+func (t *Tokenizer) peekLine() string {
+	for n := 64; ; n *= 2 {
+		buf, err := t.r.Peek(n)
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			return string(buf[:i])
+		}
+		if err != nil {
+			return string(buf)
+		}
+	}
+}
+
+func (t *Tokenizer) nextIsDigit() bool {
+	c, ok := t.peekByte(1)
+	return ok && c >= '0' && c <= '9'
+}
+
+// radixDigits reports whether the input starts with a 0x or 0b prefix followed
+// by a digit, and returns the digit class of the prefix
+func (t *Tokenizer) radixDigits() (func(byte) bool, bool) {
+	if !t.hasPrefix("0") {
+		return nil, false
+	}
+	marker, _ := t.peekByte(1)
+	digit, ok := t.peekByte(2)
+	if !ok {
+		return nil, false
+	}
+	switch marker {
+	case 'x', 'X':
+		return isHexDigit, isHexDigit(digit)
+	case 'b', 'B':
+		return isBinaryDigit, isBinaryDigit(digit)
+	}
+	return nil, false
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isBinaryDigit(c byte) bool {
+	return c == '0' || c == '1'
+}
+
+// expDigits reports whether the exponent marker at the current position is followed by digits
+func (t *Tokenizer) expDigits() bool {
+	c, ok := t.peekByte(1)
+	if ok && (c == '+' || c == '-') {
+		c, ok = t.peekByte(2)
+	}
+	return ok && c >= '0' && c <= '9'
+}
+
+func isWordStart(r rune) bool {
+	return r == '_' || r == '@' || unicode.IsLetter(r)
+}
+
+func (t *Tokenizer) isWordPart(r rune) bool {
+	if r == '#' {
+		return !t.dialect.HashComments
+	}
+	return r == '_' || r == '$' || r == '@' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,391 @@
+package tokenizer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mstgnz/sqlmapper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		input   string
+		want    []Token
+	}{
+		{
+			name:    "Words, numbers and punctuation",
+			dialect: Generic,
+			input:   "SELECT a1, 1.5e3 FROM t;",
+			want: []Token{
+				{Word, "SELECT", Position{0, 1, 1}},
+				{Whitespace, " ", Position{6, 1, 7}},
+				{Word, "a1", Position{7, 1, 8}},
+				{Punctuation, ",", Position{9, 1, 10}},
+				{Whitespace, " ", Position{10, 1, 11}},
+				{Number, "1.5e3", Position{11, 1, 12}},
+				{Whitespace, " ", Position{16, 1, 17}},
+				{Word, "FROM", Position{17, 1, 18}},
+				{Whitespace, " ", Position{21, 1, 22}},
+				{Word, "t", Position{22, 1, 23}},
+				{Delimiter, ";", Position{23, 1, 24}},
+			},
+		},
+		{
+			name:    "Comments and positions across lines",
+			dialect: MySQL,
+			input:   "-- a;b\n# c;d\n/* e;\nf */x",
+			want: []Token{
+				{Comment, "-- a;b", Position{0, 1, 1}},
+				{Whitespace, "\n", Position{6, 1, 7}},
+				{Comment, "# c;d", Position{7, 2, 1}},
+				{Whitespace, "\n", Position{12, 2, 6}},
+				{Comment, "/* e;\nf */", Position{13, 3, 1}},
+				{Word, "x", Position{23, 4, 5}},
+			},
+		},
+		{
+			name:    "Nested block comments",
+			dialect: PostgreSQL,
+			input:   "/* a /* b; */ c; */;",
+			want: []Token{
+				{Comment, "/* a /* b; */ c; */", Position{0, 1, 1}},
+				{Delimiter, ";", Position{19, 1, 20}},
+			},
+		},
+		{
+			name:    "MySQL quoting",
+			dialect: MySQL,
+			input:   "`a;b` 'it''s;' \"x\\\";\" N'n;'",
+			want: []Token{
+				{QuotedIdentifier, "`a;b`", Position{0, 1, 1}},
+				{Whitespace, " ", Position{5, 1, 6}},
+				{String, "'it''s;'", Position{6, 1, 7}},
+				{Whitespace, " ", Position{14, 1, 15}},
+				{String, "\"x\\\";\"", Position{15, 1, 16}},
+				{Whitespace, " ", Position{21, 1, 22}},
+				{String, "N'n;'", Position{22, 1, 23}},
+			},
+		},
+		{
+			name:    "SQL Server brackets and batch separator",
+			dialect: SQLServer,
+			input:   "[a;]]b] \"c\"\n  go 2\nGOTO",
+			want: []Token{
+				{QuotedIdentifier, "[a;]]b]", Position{0, 1, 1}},
+				{Whitespace, " ", Position{7, 1, 8}},
+				{QuotedIdentifier, "\"c\"", Position{8, 1, 9}},
+				{Whitespace, "\n  ", Position{11, 1, 12}},
+				{Delimiter, "go 2", Position{14, 2, 3}},
+				{Whitespace, "\n", Position{18, 2, 7}},
+				{Word, "GOTO", Position{19, 3, 1}},
+			},
+		},
+		{
+			name:    "Dollar quoting",
+			dialect: PostgreSQL,
+			input:   "$$a;$b$$ $fn$ $$; $fn$ $1 E'x\\';'",
+			want: []Token{
+				{String, "$$a;$b$$", Position{0, 1, 1}},
+				{Whitespace, " ", Position{8, 1, 9}},
+				{String, "$fn$ $$; $fn$", Position{9, 1, 10}},
+				{Whitespace, " ", Position{22, 1, 23}},
+				{Punctuation, "$", Position{23, 1, 24}},
+				{Number, "1", Position{24, 1, 25}},
+				{Whitespace, " ", Position{25, 1, 26}},
+				{String, "E'x\\';'", Position{26, 1, 27}},
+			},
+		},
+		{
+			name:    "Oracle quote operator and slash line",
+			dialect: Oracle,
+			input:   "q'[it's; ok]' Q'!x!'\n/\na/b",
+			want: []Token{
+				{String, "q'[it's; ok]'", Position{0, 1, 1}},
+				{Whitespace, " ", Position{13, 1, 14}},
+				{String, "Q'!x!'", Position{14, 1, 15}},
+				{Whitespace, "\n", Position{20, 1, 21}},
+				{Delimiter, "/", Position{21, 2, 1}},
+				{Whitespace, "\n", Position{22, 2, 2}},
+				{Word, "a", Position{23, 3, 1}},
+				{Punctuation, "/", Position{24, 3, 2}},
+				{Word, "b", Position{25, 3, 3}},
+			},
+		},
+		{
+			name:    "Non-ASCII identifiers",
+			dialect: Generic,
+			input:   "kullanıcı;",
+			want: []Token{
+				{Word, "kullanıcı", Position{0, 1, 1}},
+				{Delimiter, ";", Position{11, 1, 10}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := Tokenize(tt.input, tt.dialect)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tokens)
+		})
+	}
+}
+
+func TestTokenize_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		input   string
+		want    string
+	}{
+		{"Unterminated string", Generic, "SELECT\n  'abc", "line 2, column 3: unterminated string"},
+		{"Unterminated identifier", SQLServer, "[abc", "line 1, column 1: unterminated quoted identifier"},
+		{"Unterminated comment", PostgreSQL, "/* /* */", "line 1, column 1: unterminated block comment"},
+		{"Unterminated dollar quote", PostgreSQL, "$$ abc", "line 1, column 1: unterminated dollar-quoted string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Tokenize(tt.input, tt.dialect)
+			assert.EqualError(t, err, tt.want)
+			assert.IsType(t, &Error{}, err)
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		input   string
+		want    []string
+	}{
+		{
+			name:    "Semicolons in literals and comments",
+			dialect: Generic,
+			input:   "INSERT INTO t VALUES ('a;b'); -- c;d\nSELECT \"x;y\" /* ; */ FROM t;\n\n;",
+			want:    []string{"INSERT INTO t VALUES ('a;b')", "SELECT \"x;y\"  FROM t"},
+		},
+		{
+			name:    "MySQL DELIMITER",
+			dialect: MySQL,
+			input:   "DELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END$$\nDELIMITER ;\nCREATE TABLE t (id INT);",
+			want:    []string{"CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", "CREATE TABLE t (id INT)"},
+		},
+		{
+			name:    "Initial delimiter",
+			dialect: Dialect{Delimiter: "$$"},
+			input:   "CREATE TABLE a (id INT; x)$$\nCREATE TABLE b (id INT)$$",
+			want:    []string{"CREATE TABLE a (id INT; x)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:    "MySQL routine without DELIMITER",
+			dialect: MySQL,
+			input: "CREATE DEFINER=`root`@`localhost` TRIGGER trg BEFORE INSERT ON t FOR EACH ROW BEGIN\n" +
+				"  IF NEW.a IS NULL THEN SET NEW.a = CASE WHEN 1 THEN 2 END; END IF;\n  SET NEW.b = 1;\nEND;\n" +
+				"CREATE FUNCTION f() RETURNS INT RETURN 1;\nSELECT 1;",
+			want: []string{
+				"CREATE DEFINER=`root`@`localhost` TRIGGER trg BEFORE INSERT ON t FOR EACH ROW BEGIN\n" +
+					"  IF NEW.a IS NULL THEN SET NEW.a = CASE WHEN 1 THEN 2 END; END IF;\n  SET NEW.b = 1;\nEND",
+				"CREATE FUNCTION f() RETURNS INT RETURN 1",
+				"SELECT 1",
+			},
+		},
+		{
+			name:    "SQLite trigger",
+			dialect: SQLite,
+			input:   "CREATE TEMP TRIGGER trg AFTER INSERT ON t BEGIN UPDATE t SET a = 1; DELETE FROM u; END;\nBEGIN TRANSACTION;\nCOMMIT;",
+			want:    []string{"CREATE TEMP TRIGGER trg AFTER INSERT ON t BEGIN UPDATE t SET a = 1; DELETE FROM u; END", "BEGIN TRANSACTION", "COMMIT"},
+		},
+		{
+			name:    "PostgreSQL dollar-quoted body",
+			dialect: PostgreSQL,
+			input:   "CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.a := 1;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\nCREATE TABLE t (a int);",
+			want:    []string{"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.a := 1;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql", "CREATE TABLE t (a int)"},
+		},
+		{
+			name:    "SQL Server batches",
+			dialect: SQLServer,
+			input: "CREATE TABLE [t;] (id INT); CREATE TABLE u (id INT)\nGO\n" +
+				"CREATE PROCEDURE p AS\nBEGIN\n  SET NOCOUNT ON;\n  BEGIN TRY SELECT 1; END TRY BEGIN CATCH SELECT 2; END CATCH\nEND\nGO\n" +
+				"CREATE PROCEDURE q AS SET NOCOUNT ON; SELECT 1;\nGO 2\nCREATE TABLE [category] (GOTO INT)",
+			want: []string{
+				"CREATE TABLE [t;] (id INT)",
+				"CREATE TABLE u (id INT)",
+				"CREATE PROCEDURE p AS\nBEGIN\n  SET NOCOUNT ON;\n  BEGIN TRY SELECT 1; END TRY BEGIN CATCH SELECT 2; END CATCH\nEND",
+				"CREATE PROCEDURE q AS SET NOCOUNT ON; SELECT 1;",
+				"CREATE TABLE [category] (GOTO INT)",
+			},
+		},
+		{
+			name:    "Oracle PL/SQL units",
+			dialect: Oracle,
+			input: "CREATE TABLE t (a VARCHAR2(10) DEFAULT q'[x;y]');\n" +
+				"CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\n  v NUMBER;\nBEGIN\n  v := 1;\n  RETURN v;\nEND;\n/\n" +
+				"CREATE PACKAGE pkg AS\n  PROCEDURE a;\n  PROCEDURE b;\nEND pkg;\n/\n" +
+				"CREATE TYPE typ AS OBJECT (a NUMBER);\n" +
+				"BEGIN\n  NULL;\nEND;\n/",
+			want: []string{
+				"CREATE TABLE t (a VARCHAR2(10) DEFAULT q'[x;y]')",
+				"CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\n  v NUMBER;\nBEGIN\n  v := 1;\n  RETURN v;\nEND",
+				"CREATE PACKAGE pkg AS\n  PROCEDURE a;\n  PROCEDURE b;\nEND pkg;",
+				"CREATE TYPE typ AS OBJECT (a NUMBER)",
+				"BEGIN\n  NULL;\nEND",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := Split(tt.input, tt.dialect)
+			assert.NoError(t, err)
+
+			var got []string
+			for _, stmt := range statements {
+				got = append(got, stmt.Text)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	content := "CREATE TABLE a;\nCREATE TABLE !b;\nCREATE TABLE c;\nCREATE TABLE 'd"
+	parse := func(schema *sqlmapper.Schema) func(Statement) error {
+		return func(stmt Statement) error {
+			name := strings.TrimPrefix(stmt.Text, "CREATE TABLE ")
+			schema.Tables = append(schema.Tables, sqlmapper.Table{Name: name})
+			if strings.HasPrefix(name, "!") {
+				return errors.New("invalid name")
+			}
+			return nil
+		}
+	}
+
+	schema := &sqlmapper.Schema{}
+	_, err := ParseStatements(content, Generic, schema, parse(schema), false)
+	assert.EqualError(t, err, `4:14: unterminated string`)
+
+	schema = &sqlmapper.Schema{}
+	_, err = ParseStatements("CREATE TABLE a;\nCREATE TABLE !b;", Generic, schema, parse(schema), false)
+	assert.EqualError(t, err, `2:1: invalid name (near "CREATE TABLE !b")`)
+
+	// Lenient parsing drops what failed statements added and reports them
+	schema = &sqlmapper.Schema{}
+	diagnostics, err := ParseStatements(content, Generic, schema, parse(schema), true)
+	assert.NoError(t, err)
+	if assert.Len(t, schema.Tables, 2) {
+		assert.Equal(t, "a", schema.Tables[0].Name)
+		assert.Equal(t, "c", schema.Tables[1].Name)
+		assert.Equal(t, 3, schema.Tables[1].Pos.Line)
+	}
+	if assert.Len(t, diagnostics, 2) {
+		assert.Equal(t, "CREATE TABLE !b", diagnostics[0].Statement)
+		assert.Equal(t, "2:1: error [UNPARSED_STATEMENT] invalid name", diagnostics[0].String())
+		assert.Equal(t, "'d", diagnostics[1].Statement)
+		assert.Equal(t, "unterminated string", diagnostics[1].Message)
+		assert.Equal(t, 4, diagnostics[1].Pos.Line)
+	}
+}
+
+func TestParseStatements_Panic(t *testing.T) {
+	parse := func(stmt Statement) error {
+		if stmt.Text == "CREATE TABLE b" {
+			var names []string
+			_ = names[1]
+		}
+		return nil
+	}
+
+	_, err := ParseStatements("CREATE TABLE a;\nCREATE TABLE b;", Generic, &sqlmapper.Schema{}, parse, false)
+	var parseErr *sqlmapper.ParseError
+	if assert.ErrorAs(t, err, &parseErr) {
+		assert.Equal(t, 2, parseErr.Pos.Line)
+		assert.Contains(t, err.Error(), "internal parser error")
+	}
+
+	diagnostics, err := ParseStatements("CREATE TABLE a;\nCREATE TABLE b;", Generic, &sqlmapper.Schema{}, parse, true)
+	assert.NoError(t, err)
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, "CREATE TABLE b", diagnostics[0].Statement)
+	}
+}
+
+func TestStatement_Compact(t *testing.T) {
+	statements, err := Split("CREATE  TABLE t (\n  a VARCHAR(10) DEFAULT 'x  y', -- note\n  b INT /* c */\n);", MySQL)
+	assert.NoError(t, err)
+	assert.Len(t, statements, 1)
+	assert.Equal(t, "CREATE TABLE t ( a VARCHAR(10) DEFAULT 'x  y', b INT )", statements[0].Compact())
+	assert.Equal(t, Position{Offset: 0, Line: 1, Column: 1}, statements[0].Pos)
+}
+
+func TestStatementReader_Positions(t *testing.T) {
+	reader := NewStatementReader(strings.NewReader("-- header\nCREATE TABLE a (id INT);\n\n  CREATE TABLE b (id INT);"), Generic)
+
+	first, err := reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, Position{Offset: 10, Line: 2, Column: 1}, first.Pos)
+
+	second, err := reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, Position{Offset: 38, Line: 4, Column: 3}, second.Pos)
+	assert.Equal(t, "CREATE TABLE b (id INT)", second.Text)
+}
+
+func TestNewStatementReaderAt(t *testing.T) {
+	input := "CREATE TABLE a (id INT);\nDELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 1; END//\nCREATE TABLE b (id INT)//\n"
+
+	reader := NewStatementReader(strings.NewReader(input), MySQL)
+	all, err := readAll(reader)
+	assert.NoError(t, err)
+
+	// Resume after the procedure, with the delimiter it was read with
+	reader = NewStatementReader(strings.NewReader(input), MySQL)
+	for i := 0; i < 2; i++ {
+		_, err := reader.Next()
+		assert.NoError(t, err)
+	}
+	pos, delimiter := reader.Position(), reader.Delimiter()
+	assert.Equal(t, "//", delimiter)
+	assert.Equal(t, pos.Offset, reader.Offset())
+
+	dialect := MySQL
+	dialect.Delimiter = delimiter
+	resumed, err := readAll(NewStatementReaderAt(strings.NewReader(input[pos.Offset:]), dialect, pos))
+	assert.NoError(t, err)
+	assert.Equal(t, all[2:], resumed)
+}
+
+// readAll reads every statement of a reader
+func readAll(reader *StatementReader) ([]Statement, error) {
+	var statements []Statement
+	for {
+		stmt, err := reader.Next()
+		if err == io.EOF {
+			return statements, nil
+		}
+		if err != nil {
+			return statements, err
+		}
+		statements = append(statements, stmt)
+	}
+}
+
+func TestStatementReader_ReadError(t *testing.T) {
+	readErr := errors.New("read failed")
+	reader := NewStatementReader(io.MultiReader(strings.NewReader("CREATE TABLE a (id INT);\nCREATE TA"), iotest.ErrReader(readErr)), Generic)
+
+	first, err := reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE a (id INT)", first.Text)
+
+	// A failed read is not taken for the end of the input
+	_, err = reader.Next()
+	assert.Equal(t, readErr, err)
+}
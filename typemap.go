@@ -0,0 +1,219 @@
+package sqlmapper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// typeMappings holds the registered data type conversion tables indexed by
+// source dialect and then by target dialect. Keys and values of the inner
+// maps are stored with lower-case keys so lookups are case-insensitive.
+var (
+	typeMappings   = map[DatabaseType]map[DatabaseType]map[string]string{}
+	typeMappingsMu sync.RWMutex
+)
+
+// lengthlessTypes lists target data types that do not accept a length or
+// precision modifier. When a column is mapped to one of these types its
+// Length and Scale are cleared so generators do not emit e.g. "integer(11)".
+var lengthlessTypes = map[string]bool{
+	"int":              true,
+	"integer":          true,
+	"smallint":         true,
+	"bigint":           true,
+	"tinyint":          true,
+	"mediumint":        true,
+	"real":             true,
+	"double":           true,
+	"double precision": true,
+	"binary_float":     true,
+	"binary_double":    true,
+	"text":             true,
+	"tinytext":         true,
+	"mediumtext":       true,
+	"longtext":         true,
+	"ntext":            true,
+	"clob":             true,
+	"nclob":            true,
+	"blob":             true,
+	"longblob":         true,
+	"bytea":            true,
+	"image":            true,
+	"boolean":          true,
+	"bool":             true,
+	"bit":              true,
+	"date":             true,
+	"datetime":         true,
+	"timestamp":        true,
+	"json":             true,
+	"jsonb":            true,
+	"xml":              true,
+	"xmltype":          true,
+	"uuid":             true,
+	"uniqueidentifier": true,
+	"money":            true,
+}
+
+var typeModifierRe = regexp.MustCompile(`^\s*([^(]+?)\s*\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\)\s*$`)
+
+// RegisterTypeMapping registers data type conversions from the src dialect to
+// the dst dialect. Entries are merged into any previously registered table, so
+// callers can override individual conversions of the built-in tables.
+//
+// Keys may carry a length modifier (e.g. "NUMBER(1)") to match a specific
+// source size before falling back to the bare type name.
+func RegisterTypeMapping(src, dst DatabaseType, mappings map[string]string) {
+	typeMappingsMu.Lock()
+	defer typeMappingsMu.Unlock()
+
+	if typeMappings[src] == nil {
+		typeMappings[src] = make(map[DatabaseType]map[string]string)
+	}
+	if typeMappings[src][dst] == nil {
+		typeMappings[src][dst] = make(map[string]string)
+	}
+	for from, to := range mappings {
+		typeMappings[src][dst][strings.ToLower(strings.TrimSpace(from))] = to
+	}
+}
+
+// TypeMapping returns a copy of the conversion table registered from src to dst.
+// It returns nil if no table has been registered for the pair.
+func TypeMapping(src, dst DatabaseType) map[string]string {
+	typeMappingsMu.RLock()
+	defer typeMappingsMu.RUnlock()
+
+	m := typeMappings[src][dst]
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// MapDataType translates a data type together with its length and scale from
+// the src dialect to the dst dialect. Types without a registered conversion,
+// and conversions where src is empty or equal to dst, are returned unchanged.
+func MapDataType(src, dst DatabaseType, dataType string, length, scale int) (string, int, int) {
+	if src == "" || src == dst || dataType == "" {
+		return dataType, length, scale
+	}
+
+	typeMappingsMu.RLock()
+	mapping := typeMappings[src][dst]
+	typeMappingsMu.RUnlock()
+	if mapping == nil {
+		return dataType, length, scale
+	}
+
+	origLength, origScale := length, scale
+
+	// Split an inline modifier such as NUMBER(10,2) into its parts
+	base := strings.ToLower(strings.TrimSpace(dataType))
+	if matches := typeModifierRe.FindStringSubmatch(base); len(matches) > 2 {
+		base = matches[1]
+		if length == 0 {
+			fmt.Sscanf(matches[2], "%d", &length)
+			if matches[3] != "" {
+				fmt.Sscanf(matches[3], "%d", &scale)
+			}
+		}
+	}
+
+	var candidates []string
+	if length > 0 {
+		if scale > 0 {
+			candidates = append(candidates, fmt.Sprintf("%s(%d,%d)", base, length, scale))
+		}
+		candidates = append(candidates, fmt.Sprintf("%s(%d)", base, length))
+	}
+	candidates = append(candidates, base)
+
+	for _, candidate := range candidates {
+		target, ok := mapping[candidate]
+		if !ok {
+			continue
+		}
+		if strings.Contains(target, "(") || lengthlessTypes[strings.ToLower(target)] {
+			return target, 0, 0
+		}
+		return target, length, scale
+	}
+
+	return dataType, origLength, origScale
+}
+
+// ConvertTypes returns a copy of the schema whose column and routine parameter
+// data types have been translated from the schema's source dialect to dst.
+// The original schema is not modified. If the schema has no source dialect or
+// already targets dst, it is returned as is.
+func ConvertTypes(schema *Schema, dst DatabaseType) *Schema {
+	if schema == nil || schema.Dialect == "" || schema.Dialect == dst {
+		return schema
+	}
+
+	converted := *schema
+	converted.Dialect = dst
+
+	if schema.Tables != nil {
+		converted.Tables = make([]Table, len(schema.Tables))
+		for i, table := range schema.Tables {
+			table.Columns = append([]Column(nil), table.Columns...)
+			for j, col := range table.Columns {
+				col.DataType, col.Length, col.Scale = MapDataType(schema.Dialect, dst, col.DataType, col.Length, col.Scale)
+				table.Columns[j] = col
+			}
+			converted.Tables[i] = table
+		}
+	}
+
+	if schema.Functions != nil {
+		converted.Functions = make([]Function, len(schema.Functions))
+		for i, fn := range schema.Functions {
+			fn.Parameters = convertParameters(schema.Dialect, dst, fn.Parameters)
+			fn.Returns = mapTypeString(schema.Dialect, dst, fn.Returns)
+			converted.Functions[i] = fn
+		}
+	}
+
+	if schema.Procedures != nil {
+		converted.Procedures = make([]Procedure, len(schema.Procedures))
+		for i, proc := range schema.Procedures {
+			proc.Parameters = convertParameters(schema.Dialect, dst, proc.Parameters)
+			converted.Procedures[i] = proc
+		}
+	}
+
+	return &converted
+}
+
+// convertParameters translates the data types of routine parameters.
+func convertParameters(src, dst DatabaseType, params []Parameter) []Parameter {
+	if params == nil {
+		return nil
+	}
+	result := make([]Parameter, len(params))
+	for i, param := range params {
+		param.DataType = mapTypeString(src, dst, param.DataType)
+		result[i] = param
+	}
+	return result
+}
+
+// mapTypeString translates a data type written as a single string, such as a
+// routine parameter or return type, keeping any length modifier it carries.
+func mapTypeString(src, dst DatabaseType, dataType string) string {
+	mapped, length, scale := MapDataType(src, dst, dataType, 0, 0)
+	if mapped == dataType || length == 0 {
+		return mapped
+	}
+	if scale > 0 {
+		return fmt.Sprintf("%s(%d,%d)", mapped, length, scale)
+	}
+	return fmt.Sprintf("%s(%d)", mapped, length)
+}
@@ -0,0 +1,594 @@
+package sqlmapper
+
+// Built-in data type conversion tables between the supported dialects. They are
+// registered here rather than by the dialect packages, so that a schema can be
+// converted between any two dialects whichever dialect packages are imported.
+var (
+	// mysqlToPostgreSQL Data type conversions from MySQL to PostgreSQL
+	mysqlToPostgreSQL = map[string]string{
+		"tinyint":    "smallint",
+		"smallint":   "smallint",
+		"mediumint":  "integer",
+		"int":        "integer",
+		"bigint":     "bigint",
+		"float":      "real",
+		"double":     "double precision",
+		"decimal":    "decimal",
+		"char":       "char",
+		"varchar":    "varchar",
+		"tinytext":   "text",
+		"text":       "text",
+		"mediumtext": "text",
+		"longtext":   "text",
+		"json":       "jsonb",
+		"datetime":   "timestamp",
+		"timestamp":  "timestamp",
+		"date":       "date",
+		"time":       "time",
+		"blob":       "bytea",
+		"tinyblob":   "bytea",
+		"mediumblob": "bytea",
+		"longblob":   "bytea",
+		"enum":       "text",
+		"set":        "text[]",
+		"bool":       "boolean",
+		"boolean":    "boolean",
+	}
+
+	// mysqlToSQLServer Data type conversions from MySQL to SQL Server
+	mysqlToSQLServer = map[string]string{
+		"tinyint":    "tinyint",
+		"smallint":   "smallint",
+		"mediumint":  "int",
+		"int":        "int",
+		"bigint":     "bigint",
+		"float":      "float",
+		"double":     "float",
+		"decimal":    "decimal",
+		"char":       "char",
+		"varchar":    "varchar",
+		"tinytext":   "varchar(max)",
+		"text":       "varchar(max)",
+		"mediumtext": "varchar(max)",
+		"longtext":   "varchar(max)",
+		"json":       "nvarchar(max)",
+		"datetime":   "datetime2",
+		"timestamp":  "datetime2",
+		"date":       "date",
+		"time":       "time",
+		"blob":       "varbinary(max)",
+		"tinyblob":   "varbinary(max)",
+		"mediumblob": "varbinary(max)",
+		"longblob":   "varbinary(max)",
+		"enum":       "varchar(255)",
+		"set":        "varchar(max)",
+		"bool":       "bit",
+		"boolean":    "bit",
+	}
+
+	// mysqlToOracle Data type conversions from MySQL to Oracle
+	mysqlToOracle = map[string]string{
+		"tinyint":    "NUMBER(3)",
+		"smallint":   "NUMBER(5)",
+		"mediumint":  "NUMBER(7)",
+		"int":        "NUMBER(10)",
+		"bigint":     "NUMBER(19)",
+		"float":      "FLOAT",
+		"double":     "FLOAT",
+		"decimal":    "NUMBER",
+		"char":       "CHAR",
+		"varchar":    "VARCHAR2",
+		"tinytext":   "CLOB",
+		"text":       "CLOB",
+		"mediumtext": "CLOB",
+		"longtext":   "CLOB",
+		"json":       "CLOB",
+		"datetime":   "TIMESTAMP",
+		"timestamp":  "TIMESTAMP",
+		"date":       "DATE",
+		"time":       "TIMESTAMP",
+		"blob":       "BLOB",
+		"tinyblob":   "BLOB",
+		"mediumblob": "BLOB",
+		"longblob":   "BLOB",
+		"enum":       "VARCHAR2(255)",
+		"set":        "VARCHAR2(4000)",
+		"bool":       "NUMBER(1)",
+		"boolean":    "NUMBER(1)",
+	}
+
+	// mysqlToSQLite Data type conversions from MySQL to SQLite
+	mysqlToSQLite = map[string]string{
+		"tinyint":    "INTEGER",
+		"smallint":   "INTEGER",
+		"mediumint":  "INTEGER",
+		"int":        "INTEGER",
+		"bigint":     "INTEGER",
+		"float":      "REAL",
+		"double":     "REAL",
+		"decimal":    "REAL",
+		"char":       "TEXT",
+		"varchar":    "TEXT",
+		"tinytext":   "TEXT",
+		"text":       "TEXT",
+		"mediumtext": "TEXT",
+		"longtext":   "TEXT",
+		"json":       "TEXT",
+		"datetime":   "TEXT",
+		"timestamp":  "TEXT",
+		"date":       "TEXT",
+		"time":       "TEXT",
+		"blob":       "BLOB",
+		"tinyblob":   "BLOB",
+		"mediumblob": "BLOB",
+		"longblob":   "BLOB",
+		"enum":       "TEXT",
+		"set":        "TEXT",
+		"bool":       "INTEGER",
+		"boolean":    "INTEGER",
+	}
+
+	// postgreSQLToMySQL Data type conversions from PostgreSQL to MySQL
+	postgreSQLToMySQL = map[string]string{
+		"smallint":         "smallint",
+		"integer":          "int",
+		"bigint":           "bigint",
+		"real":             "float",
+		"double precision": "double",
+		"decimal":          "decimal",
+		"numeric":          "decimal",
+		"char":             "char",
+		"varchar":          "varchar",
+		"text":             "text",
+		"jsonb":            "json",
+		"json":             "json",
+		"timestamp":        "datetime",
+		"date":             "date",
+		"time":             "time",
+		"bytea":            "blob",
+		"boolean":          "boolean",
+		"uuid":             "varchar(36)",
+		"inet":             "varchar(45)",
+		"cidr":             "varchar(45)",
+		"macaddr":          "varchar(17)",
+		"point":            "point",
+		"line":             "linestring",
+		"lseg":             "linestring",
+		"box":              "polygon",
+		"path":             "linestring",
+		"polygon":          "polygon",
+		"circle":           "polygon",
+		"interval":         "varchar(255)",
+	}
+
+	// postgreSQLToSQLServer Data type conversions from PostgreSQL to SQL Server
+	postgreSQLToSQLServer = map[string]string{
+		"smallint":         "smallint",
+		"integer":          "int",
+		"bigint":           "bigint",
+		"real":             "real",
+		"double precision": "float",
+		"decimal":          "decimal",
+		"numeric":          "decimal",
+		"char":             "char",
+		"varchar":          "varchar",
+		"text":             "varchar(max)",
+		"jsonb":            "nvarchar(max)",
+		"json":             "nvarchar(max)",
+		"timestamp":        "datetime2",
+		"date":             "date",
+		"time":             "time",
+		"bytea":            "varbinary(max)",
+		"boolean":          "bit",
+		"uuid":             "uniqueidentifier",
+		"inet":             "varchar(45)",
+		"cidr":             "varchar(45)",
+		"macaddr":          "varchar(17)",
+		"point":            "geometry",
+		"line":             "geometry",
+		"lseg":             "geometry",
+		"box":              "geometry",
+		"path":             "geometry",
+		"polygon":          "geometry",
+		"circle":           "geometry",
+		"interval":         "varchar(255)",
+	}
+
+	// postgreSQLToOracle Data type conversions from PostgreSQL to Oracle
+	postgreSQLToOracle = map[string]string{
+		"smallint":         "NUMBER(5)",
+		"integer":          "NUMBER(10)",
+		"bigint":           "NUMBER(19)",
+		"real":             "BINARY_FLOAT",
+		"double precision": "BINARY_DOUBLE",
+		"decimal":          "NUMBER",
+		"numeric":          "NUMBER",
+		"char":             "CHAR",
+		"varchar":          "VARCHAR2",
+		"text":             "CLOB",
+		"jsonb":            "CLOB",
+		"json":             "CLOB",
+		"timestamp":        "TIMESTAMP",
+		"date":             "DATE",
+		"time":             "TIMESTAMP",
+		"bytea":            "BLOB",
+		"boolean":          "NUMBER(1)",
+		"uuid":             "VARCHAR2(36)",
+		"inet":             "VARCHAR2(45)",
+		"cidr":             "VARCHAR2(45)",
+		"macaddr":          "VARCHAR2(17)",
+		"point":            "SDO_GEOMETRY",
+		"line":             "SDO_GEOMETRY",
+		"lseg":             "SDO_GEOMETRY",
+		"box":              "SDO_GEOMETRY",
+		"path":             "SDO_GEOMETRY",
+		"polygon":          "SDO_GEOMETRY",
+		"circle":           "SDO_GEOMETRY",
+		"interval":         "INTERVAL DAY TO SECOND",
+	}
+
+	// postgreSQLToSQLite Data type conversions from PostgreSQL to SQLite
+	postgreSQLToSQLite = map[string]string{
+		"smallint":         "INTEGER",
+		"integer":          "INTEGER",
+		"bigint":           "INTEGER",
+		"real":             "REAL",
+		"double precision": "REAL",
+		"decimal":          "REAL",
+		"numeric":          "REAL",
+		"char":             "TEXT",
+		"varchar":          "TEXT",
+		"text":             "TEXT",
+		"jsonb":            "TEXT",
+		"json":             "TEXT",
+		"timestamp":        "TEXT",
+		"date":             "TEXT",
+		"time":             "TEXT",
+		"bytea":            "BLOB",
+		"boolean":          "INTEGER",
+		"uuid":             "TEXT",
+		"inet":             "TEXT",
+		"cidr":             "TEXT",
+		"macaddr":          "TEXT",
+		"point":            "TEXT",
+		"line":             "TEXT",
+		"lseg":             "TEXT",
+		"box":              "TEXT",
+		"path":             "TEXT",
+		"polygon":          "TEXT",
+		"circle":           "TEXT",
+		"interval":         "TEXT",
+	}
+
+	// sqlServerToMySQL Data type conversions from SQL Server to MySQL
+	sqlServerToMySQL = map[string]string{
+		"tinyint":          "tinyint",
+		"smallint":         "smallint",
+		"int":              "int",
+		"bigint":           "bigint",
+		"decimal":          "decimal",
+		"numeric":          "decimal",
+		"float":            "double",
+		"real":             "float",
+		"money":            "decimal(19,4)",
+		"smallmoney":       "decimal(10,4)",
+		"char":             "char",
+		"varchar":          "varchar",
+		"text":             "text",
+		"nchar":            "char",
+		"nvarchar":         "varchar",
+		"ntext":            "text",
+		"binary":           "binary",
+		"varbinary":        "varbinary",
+		"image":            "longblob",
+		"datetime":         "datetime",
+		"datetime2":        "datetime",
+		"smalldatetime":    "datetime",
+		"date":             "date",
+		"time":             "time",
+		"datetimeoffset":   "datetime",
+		"timestamp":        "binary(8)",
+		"bit":              "boolean",
+		"uniqueidentifier": "char(36)",
+		"xml":              "text",
+		"sql_variant":      "text",
+	}
+
+	// sqlServerToPostgreSQL Data type conversions from SQL Server to PostgreSQL
+	sqlServerToPostgreSQL = map[string]string{
+		"tinyint":          "smallint",
+		"smallint":         "smallint",
+		"int":              "integer",
+		"bigint":           "bigint",
+		"decimal":          "decimal",
+		"numeric":          "numeric",
+		"float":            "double precision",
+		"real":             "real",
+		"money":            "money",
+		"smallmoney":       "money",
+		"char":             "char",
+		"varchar":          "varchar",
+		"text":             "text",
+		"nchar":            "char",
+		"nvarchar":         "varchar",
+		"ntext":            "text",
+		"binary":           "bytea",
+		"varbinary":        "bytea",
+		"image":            "bytea",
+		"datetime":         "timestamp",
+		"datetime2":        "timestamp",
+		"smalldatetime":    "timestamp",
+		"date":             "date",
+		"time":             "time",
+		"datetimeoffset":   "timestamp with time zone",
+		"timestamp":        "bytea",
+		"bit":              "boolean",
+		"uniqueidentifier": "uuid",
+		"xml":              "xml",
+		"sql_variant":      "text",
+	}
+
+	// sqlServerToOracle Data type conversions from SQL Server to Oracle
+	sqlServerToOracle = map[string]string{
+		"tinyint":          "NUMBER(3)",
+		"smallint":         "NUMBER(5)",
+		"int":              "NUMBER(10)",
+		"bigint":           "NUMBER(19)",
+		"decimal":          "NUMBER",
+		"numeric":          "NUMBER",
+		"float":            "FLOAT",
+		"real":             "FLOAT",
+		"money":            "NUMBER(19,4)",
+		"smallmoney":       "NUMBER(10,4)",
+		"char":             "CHAR",
+		"varchar":          "VARCHAR2",
+		"text":             "CLOB",
+		"nchar":            "NCHAR",
+		"nvarchar":         "NVARCHAR2",
+		"ntext":            "NCLOB",
+		"binary":           "RAW",
+		"varbinary":        "BLOB",
+		"image":            "BLOB",
+		"datetime":         "TIMESTAMP",
+		"datetime2":        "TIMESTAMP",
+		"smalldatetime":    "TIMESTAMP",
+		"date":             "DATE",
+		"time":             "TIMESTAMP",
+		"datetimeoffset":   "TIMESTAMP WITH TIME ZONE",
+		"timestamp":        "RAW(8)",
+		"bit":              "NUMBER(1)",
+		"uniqueidentifier": "RAW(16)",
+		"xml":              "XMLTYPE",
+		"sql_variant":      "CLOB",
+	}
+
+	// sqlServerToSQLite Data type conversions from SQL Server to SQLite
+	sqlServerToSQLite = map[string]string{
+		"tinyint":          "INTEGER",
+		"smallint":         "INTEGER",
+		"int":              "INTEGER",
+		"bigint":           "INTEGER",
+		"decimal":          "REAL",
+		"numeric":          "REAL",
+		"float":            "REAL",
+		"real":             "REAL",
+		"money":            "REAL",
+		"smallmoney":       "REAL",
+		"char":             "TEXT",
+		"varchar":          "TEXT",
+		"text":             "TEXT",
+		"nchar":            "TEXT",
+		"nvarchar":         "TEXT",
+		"ntext":            "TEXT",
+		"binary":           "BLOB",
+		"varbinary":        "BLOB",
+		"image":            "BLOB",
+		"datetime":         "TEXT",
+		"datetime2":        "TEXT",
+		"smalldatetime":    "TEXT",
+		"date":             "TEXT",
+		"time":             "TEXT",
+		"datetimeoffset":   "TEXT",
+		"timestamp":        "BLOB",
+		"bit":              "INTEGER",
+		"uniqueidentifier": "TEXT",
+		"xml":              "TEXT",
+		"sql_variant":      "TEXT",
+	}
+
+	// oracleToMySQL Data type conversions from Oracle to MySQL
+	oracleToMySQL = map[string]string{
+		"NUMBER":        "decimal",
+		"NUMBER(1)":     "boolean",
+		"NUMBER(3)":     "tinyint",
+		"NUMBER(5)":     "smallint",
+		"NUMBER(10)":    "int",
+		"NUMBER(19)":    "bigint",
+		"BINARY_FLOAT":  "float",
+		"BINARY_DOUBLE": "double",
+		"FLOAT":         "double",
+		"CHAR":          "char",
+		"NCHAR":         "char",
+		"VARCHAR2":      "varchar",
+		"NVARCHAR2":     "varchar",
+		"CLOB":          "text",
+		"NCLOB":         "text",
+		"LONG":          "text",
+		"BLOB":          "longblob",
+		"RAW":           "binary",
+		"LONG RAW":      "longblob",
+		"DATE":          "datetime",
+		"TIMESTAMP":     "datetime",
+		"INTERVAL YEAR": "varchar(100)",
+		"INTERVAL DAY":  "varchar(100)",
+		"XMLTYPE":       "text",
+		"ROWID":         "char(18)",
+		"UROWID":        "varchar(4000)",
+	}
+
+	// oracleToPostgreSQL Data type conversions from Oracle to PostgreSQL
+	oracleToPostgreSQL = map[string]string{
+		"NUMBER":        "numeric",
+		"NUMBER(1)":     "boolean",
+		"NUMBER(3)":     "smallint",
+		"NUMBER(5)":     "smallint",
+		"NUMBER(10)":    "integer",
+		"NUMBER(19)":    "bigint",
+		"BINARY_FLOAT":  "real",
+		"BINARY_DOUBLE": "double precision",
+		"FLOAT":         "double precision",
+		"CHAR":          "char",
+		"NCHAR":         "char",
+		"VARCHAR2":      "varchar",
+		"NVARCHAR2":     "varchar",
+		"CLOB":          "text",
+		"NCLOB":         "text",
+		"LONG":          "text",
+		"BLOB":          "bytea",
+		"RAW":           "bytea",
+		"LONG RAW":      "bytea",
+		"DATE":          "timestamp",
+		"TIMESTAMP":     "timestamp",
+		"INTERVAL YEAR": "interval",
+		"INTERVAL DAY":  "interval",
+		"XMLTYPE":       "xml",
+		"ROWID":         "char(18)",
+		"UROWID":        "varchar(4000)",
+	}
+
+	// oracleToSQLServer Data type conversions from Oracle to SQL Server
+	oracleToSQLServer = map[string]string{
+		"NUMBER":        "decimal",
+		"NUMBER(1)":     "bit",
+		"NUMBER(3)":     "tinyint",
+		"NUMBER(5)":     "smallint",
+		"NUMBER(10)":    "int",
+		"NUMBER(19)":    "bigint",
+		"BINARY_FLOAT":  "real",
+		"BINARY_DOUBLE": "float",
+		"FLOAT":         "float",
+		"CHAR":          "char",
+		"NCHAR":         "nchar",
+		"VARCHAR2":      "varchar",
+		"NVARCHAR2":     "nvarchar",
+		"CLOB":          "varchar(max)",
+		"NCLOB":         "nvarchar(max)",
+		"LONG":          "varchar(max)",
+		"BLOB":          "varbinary(max)",
+		"RAW":           "varbinary",
+		"LONG RAW":      "varbinary(max)",
+		"DATE":          "datetime2",
+		"TIMESTAMP":     "datetime2",
+		"INTERVAL YEAR": "varchar(100)",
+		"INTERVAL DAY":  "varchar(100)",
+		"XMLTYPE":       "xml",
+		"ROWID":         "char(18)",
+		"UROWID":        "varchar(4000)",
+	}
+
+	// oracleToSQLite Data type conversions from Oracle to SQLite
+	oracleToSQLite = map[string]string{
+		"NUMBER":        "REAL",
+		"NUMBER(1)":     "INTEGER",
+		"NUMBER(3)":     "INTEGER",
+		"NUMBER(5)":     "INTEGER",
+		"NUMBER(10)":    "INTEGER",
+		"NUMBER(19)":    "INTEGER",
+		"BINARY_FLOAT":  "REAL",
+		"BINARY_DOUBLE": "REAL",
+		"FLOAT":         "REAL",
+		"CHAR":          "TEXT",
+		"NCHAR":         "TEXT",
+		"VARCHAR2":      "TEXT",
+		"NVARCHAR2":     "TEXT",
+		"CLOB":          "TEXT",
+		"NCLOB":         "TEXT",
+		"LONG":          "TEXT",
+		"BLOB":          "BLOB",
+		"RAW":           "BLOB",
+		"LONG RAW":      "BLOB",
+		"DATE":          "TEXT",
+		"TIMESTAMP":     "TEXT",
+		"INTERVAL YEAR": "TEXT",
+		"INTERVAL DAY":  "TEXT",
+		"XMLTYPE":       "TEXT",
+		"ROWID":         "TEXT",
+		"UROWID":        "TEXT",
+	}
+
+	// sqliteToMySQL Data type conversions from SQLite to MySQL
+	sqliteToMySQL = map[string]string{
+		"INTEGER":  "int",
+		"REAL":     "double",
+		"TEXT":     "text",
+		"BLOB":     "blob",
+		"NUMERIC":  "decimal",
+		"BOOLEAN":  "boolean",
+		"DATETIME": "datetime",
+		"DATE":     "date",
+		"TIME":     "time",
+	}
+
+	// sqliteToPostgreSQL Data type conversions from SQLite to PostgreSQL
+	sqliteToPostgreSQL = map[string]string{
+		"INTEGER":  "integer",
+		"REAL":     "double precision",
+		"TEXT":     "text",
+		"BLOB":     "bytea",
+		"NUMERIC":  "numeric",
+		"BOOLEAN":  "boolean",
+		"DATETIME": "timestamp",
+		"DATE":     "date",
+		"TIME":     "time",
+	}
+
+	// sqliteToSQLServer Data type conversions from SQLite to SQL Server
+	sqliteToSQLServer = map[string]string{
+		"INTEGER":  "int",
+		"REAL":     "float",
+		"TEXT":     "nvarchar(max)",
+		"BLOB":     "varbinary(max)",
+		"NUMERIC":  "decimal",
+		"BOOLEAN":  "bit",
+		"DATETIME": "datetime2",
+		"DATE":     "date",
+		"TIME":     "time",
+	}
+
+	// sqliteToOracle Data type conversions from SQLite to Oracle
+	sqliteToOracle = map[string]string{
+		"INTEGER":  "NUMBER(10)",
+		"REAL":     "BINARY_DOUBLE",
+		"TEXT":     "CLOB",
+		"BLOB":     "BLOB",
+		"NUMERIC":  "NUMBER",
+		"BOOLEAN":  "NUMBER(1)",
+		"DATETIME": "TIMESTAMP",
+		"DATE":     "DATE",
+		"TIME":     "TIMESTAMP",
+	}
+)
+
+// init registers the built-in conversion tables
+func init() {
+	RegisterTypeMapping(MySQL, PostgreSQL, mysqlToPostgreSQL)
+	RegisterTypeMapping(MySQL, SQLServer, mysqlToSQLServer)
+	RegisterTypeMapping(MySQL, Oracle, mysqlToOracle)
+	RegisterTypeMapping(MySQL, SQLite, mysqlToSQLite)
+	RegisterTypeMapping(PostgreSQL, MySQL, postgreSQLToMySQL)
+	RegisterTypeMapping(PostgreSQL, SQLServer, postgreSQLToSQLServer)
+	RegisterTypeMapping(PostgreSQL, Oracle, postgreSQLToOracle)
+	RegisterTypeMapping(PostgreSQL, SQLite, postgreSQLToSQLite)
+	RegisterTypeMapping(SQLServer, MySQL, sqlServerToMySQL)
+	RegisterTypeMapping(SQLServer, PostgreSQL, sqlServerToPostgreSQL)
+	RegisterTypeMapping(SQLServer, Oracle, sqlServerToOracle)
+	RegisterTypeMapping(SQLServer, SQLite, sqlServerToSQLite)
+	RegisterTypeMapping(Oracle, MySQL, oracleToMySQL)
+	RegisterTypeMapping(Oracle, PostgreSQL, oracleToPostgreSQL)
+	RegisterTypeMapping(Oracle, SQLServer, oracleToSQLServer)
+	RegisterTypeMapping(Oracle, SQLite, oracleToSQLite)
+	RegisterTypeMapping(SQLite, MySQL, sqliteToMySQL)
+	RegisterTypeMapping(SQLite, PostgreSQL, sqliteToPostgreSQL)
+	RegisterTypeMapping(SQLite, SQLServer, sqliteToSQLServer)
+	RegisterTypeMapping(SQLite, Oracle, sqliteToOracle)
+}
@@ -0,0 +1,32 @@
+package sqlmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeMapping_BuiltIn(t *testing.T) {
+	// The tables are available without importing any dialect package
+	dialects := []DatabaseType{MySQL, PostgreSQL, SQLServer, Oracle, SQLite}
+	for _, src := range dialects {
+		for _, dst := range dialects {
+			if src != dst {
+				assert.NotEmpty(t, TypeMapping(src, dst), "%s -> %s", src, dst)
+			}
+		}
+	}
+
+	schema := &Schema{
+		Dialect: MySQL,
+		Tables: []Table{{Name: "posts", Columns: []Column{
+			{Name: "body", DataType: "LONGTEXT"},
+			{Name: "title", DataType: "varchar", Length: 200},
+		}}},
+	}
+	converted := ConvertTypes(schema, Oracle)
+	assert.Equal(t, "CLOB", converted.Tables[0].Columns[0].DataType)
+	assert.Equal(t, "VARCHAR2", converted.Tables[0].Columns[1].DataType)
+	assert.Equal(t, 200, converted.Tables[0].Columns[1].Length)
+	assert.Equal(t, "LONGTEXT", schema.Tables[0].Columns[0].DataType)
+}
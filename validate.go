@@ -0,0 +1,402 @@
+package sqlmapper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severity represents how serious a validation finding is
+type Severity string
+
+const (
+	// SeverityError marks a schema that cannot be created as described
+	SeverityError Severity = "error"
+	// SeverityWarning marks a construct that is valid but likely to cause problems
+	SeverityWarning Severity = "warning"
+)
+
+// DiagnosticCode identifies the kind of a validation finding
+type DiagnosticCode string
+
+const (
+	CodeDuplicateName       DiagnosticCode = "DUPLICATE_NAME"
+	CodeDuplicateIndex      DiagnosticCode = "DUPLICATE_INDEX"
+	CodeMultiplePrimaryKeys DiagnosticCode = "MULTIPLE_PRIMARY_KEYS"
+	CodeUnknownColumn       DiagnosticCode = "UNKNOWN_COLUMN"
+	CodeUnknownRefTable     DiagnosticCode = "UNKNOWN_REF_TABLE"
+	CodeUnknownRefColumn    DiagnosticCode = "UNKNOWN_REF_COLUMN"
+	CodeForeignKeyMismatch  DiagnosticCode = "FOREIGN_KEY_MISMATCH"
+	CodeUnknownTriggerTable DiagnosticCode = "UNKNOWN_TRIGGER_TABLE"
+	CodeUnknownSequence     DiagnosticCode = "UNKNOWN_SEQUENCE"
+
+	// CodeUnparsedStatement marks a statement that a lenient parse skipped
+	CodeUnparsedStatement DiagnosticCode = "UNPARSED_STATEMENT"
+
+	// CodeOrphanObject marks an object delivered by a stream parser whose table
+	// is not part of the input
+	CodeOrphanObject DiagnosticCode = "ORPHAN_OBJECT"
+)
+
+// Diagnostic is a single finding of Validate or of a lenient parse.
+// Path locates the offending object in the schema, such as
+// "tables.orders.constraints.fk_customer". Pos is the source location of
+// the object when the schema was parsed from SQL. Statement holds the source
+// of a statement that a lenient parse skipped.
+type Diagnostic struct {
+	Severity  Severity
+	Code      DiagnosticCode
+	Path      string
+	Message   string
+	Pos       *Position
+	Statement string
+}
+
+// String formats the diagnostic as "severity [CODE] path: message", prefixed
+// with "file:line:col: " when the position is known
+func (d Diagnostic) String() string {
+	msg := fmt.Sprintf("%s [%s] %s: %s", d.Severity, d.Code, d.Path, d.Message)
+	if d.Path == "" {
+		msg = fmt.Sprintf("%s [%s] %s", d.Severity, d.Code, d.Message)
+	}
+	if d.Pos != nil && d.Pos.IsValid() {
+		msg = d.Pos.String() + ": " + msg
+	}
+	return msg
+}
+
+// HasErrors reports whether any of the diagnostics is an error
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	nextvalRe     = regexp.MustCompile(`(?i)\bnextval\s*\(\s*'([^']+)'`)
+	nextValueRe   = regexp.MustCompile(`(?i)\bnext\s+value\s+for\s+([\w$#."\[\]]+)`)
+	dotNextvalRe  = regexp.MustCompile(`(?i)([\w$#."]+)\.nextval\b`)
+	indexColumnRe = regexp.MustCompile(`(?i)^(.+?)(?:\s*\(\s*\d+\s*\))?(?:\s+(?:ASC|DESC))?$`)
+)
+
+// Validate checks the referential integrity of a schema model: that foreign
+// keys point at existing tables and columns, that index and constraint columns
+// exist on their table, that object names are unique, that each table has at
+// most one primary key, that triggers belong to existing tables or views, and
+// that sequences referenced in column defaults exist.
+// Names are compared case-insensitively. Diagnostics are returned in schema order.
+func Validate(schema *Schema) []Diagnostic {
+	if schema == nil {
+		return nil
+	}
+
+	v := &validator{
+		relations: make(map[string]int),
+		tables:    make(map[string]int),
+		sequences: make(map[string]int),
+	}
+
+	v.checkNames(schema)
+	for i := range schema.Tables {
+		v.checkTable(schema, schema.Tables[i])
+	}
+	v.checkIndexNames(schema)
+	for _, trigger := range schema.Triggers {
+		if trigger.Table == "" || strings.EqualFold(trigger.Table, "DATABASE") || strings.EqualFold(trigger.Table, "SCHEMA") {
+			continue
+		}
+		if _, ok := lookupName(v.relations, trigger.Table); !ok {
+			v.report(trigger.Pos, SeverityError, CodeUnknownTriggerTable, "triggers."+trigger.Name,
+				"trigger is defined on unknown table %s", trigger.Table)
+		}
+	}
+
+	return v.diagnostics
+}
+
+// validator holds the name lookups and findings of a Validate run
+type validator struct {
+	relations   map[string]int // Tables and views
+	tables      map[string]int // Tables only, by position in the schema
+	sequences   map[string]int
+	diagnostics []Diagnostic
+}
+
+func (v *validator) report(pos *Position, severity Severity, code DiagnosticCode, path, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		Pos:      pos,
+	})
+}
+
+// checkNames registers the schema-level objects and reports duplicate names.
+// Tables and views share a namespace.
+func (v *validator) checkNames(schema *Schema) {
+	for i, table := range schema.Tables {
+		key := normalizeIdentifier(qualifiedKey(table.Schema, table.Name))
+		if _, ok := v.relations[key]; ok {
+			v.report(table.Pos, SeverityError, CodeDuplicateName, "tables."+table.Name, "duplicate table name %s", table.Name)
+			continue
+		}
+		v.relations[key] = i
+		v.tables[key] = i
+	}
+	for i, view := range schema.Views {
+		key := normalizeIdentifier(qualifiedKey(view.Schema, view.Name))
+		if _, ok := v.relations[key]; ok {
+			v.report(view.Pos, SeverityError, CodeDuplicateName, "views."+view.Name, "name %s is already used by another table or view", view.Name)
+			continue
+		}
+		v.relations[key] = i
+	}
+	for i, seq := range schema.Sequences {
+		key := normalizeIdentifier(qualifiedKey(seq.Schema, seq.Name))
+		if _, ok := v.sequences[key]; ok {
+			v.report(seq.Pos, SeverityError, CodeDuplicateName, "sequences."+seq.Name, "duplicate sequence name %s", seq.Name)
+			continue
+		}
+		v.sequences[key] = i
+	}
+
+	triggers := make(map[string]bool)
+	for _, trigger := range schema.Triggers {
+		key := normalizeIdentifier(trigger.Name)
+		if triggers[key] {
+			v.report(trigger.Pos, SeverityError, CodeDuplicateName, "triggers."+trigger.Name, "duplicate trigger name %s", trigger.Name)
+		}
+		triggers[key] = true
+	}
+}
+
+// checkTable validates the columns, primary keys, constraints and indexes of a table
+func (v *validator) checkTable(schema *Schema, table Table) {
+	path := "tables." + table.Name
+
+	columns := make(map[string]bool)
+	for _, column := range table.Columns {
+		key := normalizeIdentifier(column.Name)
+		if columns[key] {
+			v.report(table.Pos, SeverityError, CodeDuplicateName, path+".columns."+column.Name, "duplicate column name %s", column.Name)
+		}
+		columns[key] = true
+	}
+
+	hasColumn := func(name string) bool {
+		return columns[normalizeIdentifier(name)]
+	}
+
+	// Primary keys, from constraints and from column flags not covered by one
+	var primaryKeys []string
+	covered := make(map[string]bool)
+	constraintNames := make(map[string]bool)
+	for i, constraint := range table.Constraints {
+		constraintPath := path + ".constraints." + constraint.Name
+		if constraint.Name == "" {
+			constraintPath = fmt.Sprintf("%s.constraints[%d]", path, i)
+		} else {
+			key := normalizeIdentifier(constraint.Name)
+			if constraintNames[key] {
+				v.report(table.Pos, SeverityError, CodeDuplicateName, constraintPath, "duplicate constraint name %s", constraint.Name)
+			}
+			constraintNames[key] = true
+		}
+
+		upperType := strings.ToUpper(constraint.Type)
+		if upperType == "CHECK" {
+			continue
+		}
+		for _, name := range constraint.Columns {
+			if !hasColumn(name) {
+				v.report(table.Pos, SeverityError, CodeUnknownColumn, constraintPath,
+					"%s constraint references unknown column %s", upperType, strings.TrimSpace(name))
+			}
+		}
+
+		switch upperType {
+		case "PRIMARY KEY":
+			names := make([]string, len(constraint.Columns))
+			for j, name := range constraint.Columns {
+				names[j] = normalizeIdentifier(name)
+				covered[names[j]] = true
+			}
+			sort.Strings(names)
+			primaryKeys = appendUnique(primaryKeys, strings.Join(names, ","))
+		case "FOREIGN KEY":
+			v.checkForeignKey(schema, table.Pos, constraintPath, constraint)
+		}
+	}
+	for _, column := range table.Columns {
+		if column.IsPrimaryKey && !covered[normalizeIdentifier(column.Name)] {
+			primaryKeys = appendUnique(primaryKeys, normalizeIdentifier(column.Name))
+		}
+	}
+	if len(primaryKeys) > 1 {
+		v.report(table.Pos, SeverityError, CodeMultiplePrimaryKeys, path,
+			"table has %d primary keys (%s)", len(primaryKeys), strings.Join(primaryKeys, "; "))
+	}
+
+	for _, column := range table.Columns {
+		if column.AutoIncrement {
+			continue // SERIAL and identity columns own an implicit sequence
+		}
+		for _, seq := range sequenceReferences(column.DefaultValue) {
+			if _, ok := lookupName(v.sequences, seq); !ok {
+				v.report(table.Pos, SeverityError, CodeUnknownSequence, path+".columns."+column.Name,
+					"default value references unknown sequence %s", seq)
+			}
+		}
+	}
+
+	for _, index := range table.Indexes {
+		for _, name := range index.Columns {
+			name, ok := indexColumnName(name)
+			if ok && !hasColumn(name) {
+				v.report(firstPosition(index.Pos, table.Pos), SeverityError, CodeUnknownColumn, path+".indexes."+index.Name,
+					"index references unknown column %s", name)
+			}
+		}
+	}
+}
+
+// checkForeignKey validates the referenced table and columns of a foreign key
+func (v *validator) checkForeignKey(schema *Schema, pos *Position, path string, constraint Constraint) {
+	if constraint.RefTable == "" {
+		return
+	}
+	position, ok := lookupName(v.tables, constraint.RefTable)
+	if !ok {
+		v.report(pos, SeverityError, CodeUnknownRefTable, path, "foreign key references unknown table %s", constraint.RefTable)
+		return
+	}
+
+	refTable := schema.Tables[position]
+	for _, name := range constraint.RefColumns {
+		found := false
+		for _, column := range refTable.Columns {
+			if normalizeIdentifier(column.Name) == normalizeIdentifier(name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(pos, SeverityError, CodeUnknownRefColumn, path,
+				"foreign key references unknown column %s.%s", refTable.Name, strings.TrimSpace(name))
+		}
+	}
+	if len(constraint.RefColumns) > 0 && len(constraint.RefColumns) != len(constraint.Columns) {
+		v.report(pos, SeverityError, CodeForeignKeyMismatch, path,
+			"foreign key has %d columns but references %d", len(constraint.Columns), len(constraint.RefColumns))
+	}
+}
+
+// checkIndexNames reports index names used more than once. A repeated name on
+// the same table is an error; across tables it is a warning, since dialects
+// such as PostgreSQL and Oracle require index names to be unique per schema.
+func (v *validator) checkIndexNames(schema *Schema) {
+	owners := make(map[string]string)
+	for _, table := range schema.Tables {
+		for _, index := range table.Indexes {
+			if index.Name == "" {
+				continue
+			}
+			key := normalizeIdentifier(qualifiedKey(table.Schema, index.Name))
+			owner, ok := owners[key]
+			switch {
+			case !ok:
+				owners[key] = table.Name
+			case strings.EqualFold(owner, table.Name):
+				v.report(firstPosition(index.Pos, table.Pos), SeverityError, CodeDuplicateName, "tables."+table.Name+".indexes."+index.Name,
+					"duplicate index name %s", index.Name)
+			default:
+				v.report(firstPosition(index.Pos, table.Pos), SeverityWarning, CodeDuplicateIndex, "tables."+table.Name+".indexes."+index.Name,
+					"index name %s is also used on table %s", index.Name, owner)
+			}
+		}
+	}
+}
+
+// firstPosition returns the first of the positions that is set
+func firstPosition(positions ...*Position) *Position {
+	for _, pos := range positions {
+		if pos != nil {
+			return pos
+		}
+	}
+	return nil
+}
+
+// sequenceReferences returns the sequences referenced in an expression through
+// nextval('seq'), NEXT VALUE FOR seq or seq.NEXTVAL
+func sequenceReferences(expr string) []string {
+	if expr == "" {
+		return nil
+	}
+	var names []string
+	for _, re := range []*regexp.Regexp{nextvalRe, nextValueRe, dotNextvalRe} {
+		for _, match := range re.FindAllStringSubmatch(expr, -1) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// indexColumnName extracts the column name of an index entry, dropping a
+// prefix length and sort direction. It reports false for expressions.
+func indexColumnName(entry string) (string, bool) {
+	match := indexColumnRe.FindStringSubmatch(strings.TrimSpace(entry))
+	if match == nil || strings.ContainsAny(match[1], "()") {
+		return "", false
+	}
+	return match[1], true
+}
+
+// lookupName resolves a possibly schema-qualified name. A qualified name falls
+// back to its unqualified form, and an unqualified name matches the first
+// qualified name, in sorted order, that ends with it.
+func lookupName(names map[string]int, name string) (int, bool) {
+	name = normalizeIdentifier(name)
+	if value, ok := names[name]; ok {
+		return value, true
+	}
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+		if value, ok := names[name]; ok {
+			return value, true
+		}
+	}
+
+	var matches []string
+	for key := range names {
+		if strings.HasSuffix(key, "."+name) {
+			matches = append(matches, key)
+		}
+	}
+	if len(matches) == 0 {
+		return 0, false
+	}
+	sort.Strings(matches)
+	return names[matches[0]], true
+}
+
+// normalizeIdentifier lower-cases a name and strips identifier quoting
+func normalizeIdentifier(name string) string {
+	name = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(strings.TrimSpace(name))
+	return strings.ToLower(name)
+}
+
+// appendUnique appends a value unless the slice already holds it
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
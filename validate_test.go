@@ -0,0 +1,145 @@
+package sqlmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() *Schema {
+		return &Schema{
+			Tables: []Table{
+				{
+					Name: "customers",
+					Columns: []Column{
+						{Name: "id", DataType: "INT", IsPrimaryKey: true, AutoIncrement: true, DefaultValue: "nextval('customers_id_seq'::regclass)"},
+						{Name: "email", DataType: "VARCHAR"},
+					},
+					Constraints: []Constraint{{Type: "PRIMARY KEY", Columns: []string{"id"}}},
+					Indexes:     []Index{{Name: "idx_email", Columns: []string{"email(20) DESC"}}, {Name: "idx_lower", Columns: []string{"lower(email)"}}},
+				},
+				{
+					Name:    "orders",
+					Schema:  "sales",
+					Columns: []Column{{Name: "id", DataType: "INT", DefaultValue: "nextval('order_seq'::regclass)"}, {Name: "customer_id", DataType: "INT"}},
+					Constraints: []Constraint{
+						{Name: "pk_orders", Type: "PRIMARY KEY", Columns: []string{"id"}},
+						{Name: "fk_customer", Type: "FOREIGN KEY", Columns: []string{"customer_id"}, RefTable: `"Customers"`, RefColumns: []string{"ID"}},
+					},
+				},
+			},
+			Views:     []View{{Name: "order_view", Definition: "SELECT * FROM orders"}},
+			Sequences: []Sequence{{Name: "order_seq"}},
+			Triggers:  []Trigger{{Name: "orders_ai", Table: "sales.orders"}, {Name: "view_io", Table: "order_view"}, {Name: "logon", Table: "DATABASE"}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Schema)
+		want   []Diagnostic
+	}{
+		{
+			name:   "Valid schema",
+			modify: func(*Schema) {},
+		},
+		{
+			name: "Unknown referenced table",
+			modify: func(s *Schema) {
+				s.Tables[1].Constraints[1].RefTable = "clients"
+			},
+			want: []Diagnostic{{SeverityError, CodeUnknownRefTable, "tables.orders.constraints.fk_customer", "foreign key references unknown table clients", nil, ""}},
+		},
+		{
+			name: "Unknown referenced column and column count mismatch",
+			modify: func(s *Schema) {
+				s.Tables[1].Constraints[1].RefColumns = []string{"id", "code"}
+			},
+			want: []Diagnostic{
+				{SeverityError, CodeUnknownRefColumn, "tables.orders.constraints.fk_customer", "foreign key references unknown column customers.code", nil, ""},
+				{SeverityError, CodeForeignKeyMismatch, "tables.orders.constraints.fk_customer", "foreign key has 1 columns but references 2", nil, ""},
+			},
+		},
+		{
+			name: "Unknown index and constraint columns",
+			modify: func(s *Schema) {
+				s.Tables[0].Indexes[0].Columns = []string{"mail"}
+				s.Tables[0].Constraints = append(s.Tables[0].Constraints, Constraint{Type: "UNIQUE", Columns: []string{"phone"}})
+			},
+			want: []Diagnostic{
+				{SeverityError, CodeUnknownColumn, "tables.customers.constraints[1]", "UNIQUE constraint references unknown column phone", nil, ""},
+				{SeverityError, CodeUnknownColumn, "tables.customers.indexes.idx_email", "index references unknown column mail", nil, ""},
+			},
+		},
+		{
+			name: "Duplicate names",
+			modify: func(s *Schema) {
+				s.Tables[0].Columns = append(s.Tables[0].Columns, Column{Name: "EMAIL", DataType: "TEXT"})
+				s.Views = append(s.Views, View{Name: "customers"})
+				s.Sequences = append(s.Sequences, Sequence{Name: "order_seq"})
+				s.Tables[1].Schema = ""
+				s.Tables[1].Indexes = []Index{{Name: "idx_email", Columns: []string{"id"}}}
+			},
+			want: []Diagnostic{
+				{SeverityError, CodeDuplicateName, "views.customers", "name customers is already used by another table or view", nil, ""},
+				{SeverityError, CodeDuplicateName, "sequences.order_seq", "duplicate sequence name order_seq", nil, ""},
+				{SeverityError, CodeDuplicateName, "tables.customers.columns.EMAIL", "duplicate column name EMAIL", nil, ""},
+				{SeverityWarning, CodeDuplicateIndex, "tables.orders.indexes.idx_email", "index name idx_email is also used on table customers", nil, ""},
+			},
+		},
+		{
+			name: "Multiple primary keys",
+			modify: func(s *Schema) {
+				s.Tables[0].Columns[1].IsPrimaryKey = true
+			},
+			want: []Diagnostic{{SeverityError, CodeMultiplePrimaryKeys, "tables.customers", "table has 2 primary keys (id; email)", nil, ""}},
+		},
+		{
+			name: "Trigger on missing table",
+			modify: func(s *Schema) {
+				s.Triggers[0].Table = "invoices"
+			},
+			want: []Diagnostic{{SeverityError, CodeUnknownTriggerTable, "triggers.orders_ai", "trigger is defined on unknown table invoices", nil, ""}},
+		},
+		{
+			name: "Unknown sequence in default",
+			modify: func(s *Schema) {
+				s.Sequences = nil
+				s.Tables[0].Columns[0].AutoIncrement = false
+				s.Tables[0].Columns[0].DefaultValue = "NEXT VALUE FOR [dbo].[customer_seq]"
+			},
+			want: []Diagnostic{
+				{SeverityError, CodeUnknownSequence, "tables.customers.columns.id", "default value references unknown sequence [dbo].[customer_seq]", nil, ""},
+				{SeverityError, CodeUnknownSequence, "tables.orders.columns.id", "default value references unknown sequence order_seq", nil, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := valid()
+			tt.modify(schema)
+
+			diagnostics := Validate(schema)
+			assert.Equal(t, tt.want, diagnostics)
+			assert.Equal(t, len(tt.want) > 0, HasErrors(diagnostics))
+		})
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	d := Diagnostic{Severity: SeverityWarning, Code: CodeDuplicateIndex, Path: "tables.orders.indexes.idx", Message: "index name idx is also used on table users"}
+	assert.Equal(t, "warning [DUPLICATE_INDEX] tables.orders.indexes.idx: index name idx is also used on table users", d.String())
+}
+
+func TestDiagnostic_StringWithPosition(t *testing.T) {
+	d := Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeUnknownRefTable,
+		Path:     "tables.orders.constraints.fk_customer",
+		Message:  "foreign key references unknown table clients",
+		Pos:      &Position{File: "dump.sql", Line: 12, Column: 1, Offset: 340},
+	}
+	assert.Equal(t, "dump.sql:12:1: error [UNKNOWN_REF_TABLE] tables.orders.constraints.fk_customer: foreign key references unknown table clients", d.String())
+}